@@ -7,10 +7,13 @@ import (
 	"github.com/himynamej/todo/app/domain/authapp"
 	"github.com/himynamej/todo/app/domain/checkapp"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/orgbus/stores/orgdb"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/domain/userbus/stores/usercache"
 	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
 	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/sqldb"
 	"github.com/himynamej/todo/foundation/web"
 )
 
@@ -29,6 +32,7 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	// sames instances for the different set of domain apis.
 	delegate := delegate.New(cfg.Log)
 	userBus := userbus.NewBusiness(cfg.Log, delegate, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), time.Minute))
+	orgBus := orgbus.NewBusiness(cfg.Log, delegate, orgdb.NewStore(cfg.Log, cfg.DB), sqldb.NewBeginner(cfg.DB))
 
 	checkapp.Routes(app, checkapp.Config{
 		Build: cfg.Build,
@@ -37,7 +41,9 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	})
 
 	authapp.Routes(app, authapp.Config{
-		UserBus: userBus,
-		Auth:    cfg.Auth,
+		UserBus:    userBus,
+		OrgBus:     orgBus,
+		SessionBus: cfg.SessionBus,
+		Auth:       cfg.Auth,
 	})
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"expvar"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,6 +18,9 @@ import (
 	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/debug"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessioncache"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessiondb"
 	"github.com/himynamej/todo/business/sdk/sqldb"
 	"github.com/himynamej/todo/foundation/keystore"
 	"github.com/himynamej/todo/foundation/logger"
@@ -73,9 +77,13 @@ func run(ctx context.Context, log *logger.Logger) error {
 		}
 		Auth struct {
 			KeysEnvVar string
-			KeysFolder string `conf:"default:zarf/keys/"`
-			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
-			Issuer     string `conf:"default:service project"`
+			KeysFolder string        `conf:"default:zarf/keys/"`
+			ActiveKID  string        `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"` // Signs when several keys are active and keys.json does not say which
+			KeysReload time.Duration `conf:"default:30s"`                                  // How often the keys folder is checked for changes, SIGHUP checks at once
+			Issuer     string        `conf:"default:service project"`
+			AccessTTL  time.Duration `conf:"default:15m"`
+			RefreshTTL time.Duration `conf:"default:720h"`
+			RevokedTTL time.Duration `conf:"default:10s"` // How long other instances may take to see a revoked token
 		}
 		DB struct {
 			User         string `conf:"default:postgres"`
@@ -155,6 +163,7 @@ func run(ctx context.Context, log *logger.Logger) error {
 	// concern.
 
 	ks := keystore.New()
+	ks.SetDefaultKID(cfg.Auth.ActiveKID)
 
 	n1, err := ks.LoadByJSON(cfg.Auth.KeysEnvVar)
 	if err != nil {
@@ -170,11 +179,35 @@ func run(ctx context.Context, log *logger.Logger) error {
 		return fmt.Errorf("no keys exist: %w", err)
 	}
 
+	activeKID, err := ks.ActiveKID()
+	if err != nil {
+		return fmt.Errorf("finding active key: %w", err)
+	}
+
+	log.Info(ctx, "startup", "status", "keys loaded", "keys", n1+n2, "activeKID", activeKID)
+
+	// Keys are rotated by adding a new one to the folder and updating its
+	// manifest, which every instance picks up without a restart.
+
+	reloadDone := make(chan struct{})
+	defer close(reloadDone)
+
+	go reloadKeys(ctx, log, ks, os.DirFS(cfg.Auth.KeysFolder), cfg.Auth.KeysReload, reloadDone)
+
+	// The session business is shared by auth and the routes, so tokens
+	// revoked here are refused at once instead of after the cache expires.
+
+	sessionBus := sessionbus.NewBusiness(log, nil, sessioncache.NewStore(log, sessiondb.NewStore(log, db), cfg.Auth.RevokedTTL), sessionbus.Config{
+		TTL: cfg.Auth.RefreshTTL,
+	})
+
 	authCfg := auth.Config{
-		Log:       log,
-		DB:        db,
-		KeyLookup: ks,
-		Issuer:    cfg.Auth.Issuer,
+		Log:        log,
+		DB:         db,
+		KeyLookup:  ks,
+		Issuer:     cfg.Auth.Issuer,
+		AccessTTL:  cfg.Auth.AccessTTL,
+		SessionBus: sessionBus,
 	}
 
 	ath, err := auth.New(authCfg)
@@ -229,7 +262,8 @@ func run(ctx context.Context, log *logger.Logger) error {
 		DB:     db,
 		Tracer: tracer,
 		AuthConfig: mux.AuthConfig{
-			Auth: ath,
+			Auth:       ath,
+			SessionBus: sessionBus,
 		},
 	}
 
@@ -272,3 +306,46 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	return nil
 }
+
+// reloadKeys loads the keys folder again whenever it changes, checking on
+// every tick and when the process gets SIGHUP, until done is closed. With no
+// interval the folder is only checked on SIGHUP.
+func reloadKeys(ctx context.Context, log *logger.Logger, ks *keystore.KeyStore, fsys fs.FS, interval time.Duration, done <-chan struct{}) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-hup:
+		case <-tick:
+		}
+
+		reloaded, err := ks.Reload(fsys)
+		if err != nil {
+			log.Error(ctx, "keys", "status", "reloading keys", "err", err)
+			continue
+		}
+
+		if !reloaded {
+			continue
+		}
+
+		activeKID, err := ks.ActiveKID()
+		if err != nil {
+			log.Error(ctx, "keys", "status", "keys reloaded without an active key", "err", err)
+			continue
+		}
+
+		log.Info(ctx, "keys", "status", "keys reloaded", "activeKID", activeKID)
+	}
+}
@@ -0,0 +1,152 @@
+package token_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	authbuild "github.com/himynamej/todo/api/services/auth/build/all"
+	"github.com/himynamej/todo/api/tooling/admin/commands"
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessiondb"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/foundation/keystore"
+)
+
+func Test_Token(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Token")
+
+	keysFolder := t.TempDir()
+	oldKID := "54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"
+	writeKey(t, filepath.Join(keysFolder, oldKID+".pem"))
+
+	ks := keystore.New()
+	if _, err := ks.LoadByFileSystem(os.DirFS(keysFolder)); err != nil {
+		t.Fatalf("Should be able to load the keys : %s", err)
+	}
+
+	sessionBus := sessionbus.NewBusiness(db.Log, nil, sessiondb.NewStore(db.Log, db.DB), sessionbus.Config{})
+
+	ath, err := auth.New(auth.Config{
+		Log:        db.Log,
+		DB:         db.DB,
+		KeyLookup:  ks,
+		SessionBus: sessionBus,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create an authenticator : %s", err)
+	}
+
+	api := mux.WebAPI(mux.Config{
+		Log: db.Log,
+		DB:  db.DB,
+		AuthConfig: mux.AuthConfig{
+			Auth:       ath,
+			SessionBus: sessionBus,
+		},
+	}, authbuild.Routes())
+
+	nu := userbus.TestNewUsers(1, role.User)[0]
+	if _, err := db.BusDomain.User.Create(context.Background(), nu); err != nil {
+		t.Fatalf("Should be able to seed the user : %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if kid := requestToken(t, api, ath, nu, oldKID); kid != oldKID {
+		t.Errorf("Should sign with the only key : got %s, exp %s", kid, oldKID)
+	}
+
+	if err := commands.RotateKey(keysFolder, "1h", "1ms"); err != nil {
+		t.Fatalf("Should be able to rotate the keys : %s", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := ks.Reload(os.DirFS(keysFolder)); err != nil {
+		t.Fatalf("Should be able to reload the keys : %s", err)
+	}
+
+	newKID, err := ks.ActiveKID()
+	if err != nil {
+		t.Fatalf("Should be able to find the active key : %s", err)
+	}
+
+	if newKID == oldKID {
+		t.Fatalf("Should sign with the new key after the rotation")
+	}
+
+	if kid := requestToken(t, api, ath, nu, oldKID); kid != newKID {
+		t.Errorf("Should sign with the new key when asked with the old kid : got %s, exp %s", kid, newKID)
+	}
+}
+
+// requestToken asks for a token with the kid in the path, checks the token
+// authenticates, and returns the kid it was signed with.
+func requestToken(t *testing.T, api http.Handler, ath *auth.Auth, nu userbus.NewUser, kid string) string {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/auth/token/"+kid, nil)
+	r.SetBasicAuth(nu.Email.Address, nu.Password)
+	w := httptest.NewRecorder()
+
+	api.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Should receive a status code of %d for the response : %d : %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Should be able to unmarshal the response : %s", err)
+	}
+
+	if _, err := ath.Authenticate(context.Background(), "Bearer "+resp.Token); err != nil {
+		t.Fatalf("Should be able to authenticate the token : %s", err)
+	}
+
+	tkn, _, err := jwt.NewParser().ParseUnverified(resp.Token, &auth.Claims{})
+	if err != nil {
+		t.Fatalf("Should be able to parse the token : %s", err)
+	}
+
+	signedWith, _ := tkn.Header["kid"].(string)
+
+	return signedWith
+}
+
+func writeKey(t *testing.T, name string) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Should be able to generate a key : %s", err)
+	}
+
+	block := pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}
+
+	if err := os.WriteFile(name, pem.EncodeToMemory(&block), 0600); err != nil {
+		t.Fatalf("Should be able to write the key : %s", err)
+	}
+}
@@ -5,16 +5,54 @@ import (
 	"time"
 
 	"github.com/himynamej/todo/app/domain/checkapp"
+	"github.com/himynamej/todo/app/domain/commentapp"
+	"github.com/himynamej/todo/app/domain/orgapp"
+	"github.com/himynamej/todo/app/domain/passwordapp"
+	"github.com/himynamej/todo/app/domain/quotaapp"
 	"github.com/himynamej/todo/app/domain/rawapp"
+	"github.com/himynamej/todo/app/domain/reportingapp"
+	"github.com/himynamej/todo/app/domain/signupapp"
+	"github.com/himynamej/todo/app/domain/streamapp"
+	"github.com/himynamej/todo/app/domain/templateapp"
+	"github.com/himynamej/todo/app/domain/timeapp"
 	"github.com/himynamej/todo/app/domain/todoapp"
+	"github.com/himynamej/todo/app/domain/todogrpc"
 	"github.com/himynamej/todo/app/domain/userapp"
+	"github.com/himynamej/todo/app/domain/webhookapp"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/auditbus"
+	"github.com/himynamej/todo/business/domain/auditbus/stores/auditdb"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/domain/commentbus/stores/commentdb"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/domain/idempotencybus/stores/idempotencydb"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/orgbus/stores/orgdb"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/passwordbus/stores/passworddb"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/quotabus/stores/quotadb"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/domain/reportingbus/stores/reportingdb"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessiondb"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/domain/signupbus/stores/signupdb"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/streambus/stores/streamdb"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/domain/templatebus/stores/templatedb"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/domain/timebus/stores/timedb"
 	"github.com/himynamej/todo/business/domain/todobus"
 	"github.com/himynamej/todo/business/domain/todobus/stores/itemdb"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/domain/userbus/stores/usercache"
 	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/domain/webhookbus/stores/webhookdb"
 	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/sqldb"
 	"github.com/himynamej/todo/foundation/web"
 )
 
@@ -33,7 +71,21 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	// sames instances for the different set of domain apis.
 	delegate := delegate.New(cfg.Log)
 	userBus := userbus.NewBusiness(cfg.Log, delegate, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), time.Minute))
-	todoBus := todobus.NewBusiness(cfg.Log, itemdb.NewStore(cfg.Log, cfg.DB), cfg.SQSClient, cfg.S3Client)
+	orgBus := orgbus.NewBusiness(cfg.Log, delegate, orgdb.NewStore(cfg.Log, cfg.DB), sqldb.NewBeginner(cfg.DB))
+	quotaBus := quotabus.NewBusiness(cfg.Log, quotadb.NewStore(cfg.Log, cfg.DB), cfg.Quota)
+	todoBus := todobus.NewBusiness(cfg.Log, delegate, userBus, quotaBus, itemdb.NewStore(cfg.Log, cfg.DB), cfg.SQSClient, cfg.S3Client, cfg.Uploader, cfg.Worker, sqldb.NewBeginner(cfg.DB))
+	commentBus := commentbus.NewBusiness(cfg.Log, delegate, userBus, todoBus, commentdb.NewStore(cfg.Log, cfg.DB))
+	reportingBus := reportingbus.NewBusiness(cfg.Log, reportingdb.NewStore(cfg.Log, cfg.ReportDB))
+	idempotencyBus := idempotencybus.NewBusiness(cfg.Log, idempotencydb.NewStore(cfg.Log, cfg.DB))
+	webhookBus := webhookbus.NewBusiness(cfg.Log, delegate, webhookdb.NewStore(cfg.Log, cfg.DB), cfg.Worker, cfg.Webhook)
+	streamBus := streambus.NewBusiness(cfg.Log, delegate, streamdb.NewStore(cfg.Log, cfg.DB), cfg.Stream)
+	templateBus := templatebus.NewBusiness(cfg.Log, todoBus, templatedb.NewStore(cfg.Log, cfg.DB))
+	timeBus := timebus.NewBusiness(cfg.Log, timedb.NewStore(cfg.Log, cfg.DB))
+	signupBus := signupbus.NewBusiness(cfg.Log, userBus, cfg.Mailer, signupdb.NewStore(cfg.Log, cfg.DB), cfg.Signup, sqldb.NewBeginner(cfg.DB))
+	auditBus := auditbus.NewBusiness(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB))
+	sessionBus := sessionbus.NewBusiness(cfg.Log, delegate, sessiondb.NewStore(cfg.Log, cfg.DB), sessionbus.Config{})
+	passwordBus := passwordbus.NewBusiness(cfg.Log, userBus, auditBus, sessionBus, cfg.Mailer, passworddb.NewStore(cfg.Log, cfg.DB), cfg.Password, sqldb.NewBeginner(cfg.DB))
+
 	checkapp.Routes(app, checkapp.Config{
 		Build: cfg.Build,
 		Log:   cfg.Log,
@@ -43,14 +95,100 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	rawapp.Routes(app)
 
 	userapp.Routes(app, userapp.Config{
+		Log:            cfg.Log,
+		UserBus:        userBus,
+		IdempotencyBus: idempotencyBus,
+		AuthClient:     cfg.AuthClient,
+		CursorKey:      cfg.CursorKey,
+	})
+	todoapp.Routes(app, todoapp.Config{
+		Log:            cfg.Log,
+		TodoBus:        todoBus,
+		UserBus:        userBus,
+		IdempotencyBus: idempotencyBus,
+		AuthClient:     cfg.AuthClient,
+		CursorKey:      cfg.CursorKey,
+	})
+
+	if cfg.RPC != nil {
+		todogrpc.Routes(cfg.RPC, todogrpc.Config{
+			Log:        cfg.Log,
+			TodoBus:    todoBus,
+			UserBus:    userBus,
+			StreamBus:  streamBus,
+			AuthClient: cfg.AuthClient,
+			Shutdown:   cfg.Shutdown,
+		})
+	}
+
+	streamapp.Routes(app, streamapp.Config{
 		Log:        cfg.Log,
+		StreamBus:  streamBus,
+		AuthClient: cfg.AuthClient,
+		Heartbeat:  cfg.Heartbeat,
+		Shutdown:   cfg.Shutdown,
+	})
+
+	quotaapp.Routes(app, quotaapp.Config{
+		Log:        cfg.Log,
+		QuotaBus:   quotaBus,
 		UserBus:    userBus,
 		AuthClient: cfg.AuthClient,
 	})
-	todoapp.Routes(app, todoapp.Config{
+
+	commentapp.Routes(app, commentapp.Config{
 		Log:        cfg.Log,
+		CommentBus: commentBus,
 		TodoBus:    todoBus,
 		AuthClient: cfg.AuthClient,
 	})
 
+	orgapp.Routes(app, orgapp.Config{
+		Log:        cfg.Log,
+		OrgBus:     orgBus,
+		UserBus:    userBus,
+		AuthClient: cfg.AuthClient,
+	})
+
+	signupapp.Routes(app, signupapp.Config{
+		Log:       cfg.Log,
+		SignupBus: signupBus,
+		Limiter:   cfg.SignupLimit,
+		Proxies:   cfg.Proxies,
+	})
+
+	passwordapp.Routes(app, passwordapp.Config{
+		Log:         cfg.Log,
+		PasswordBus: passwordBus,
+		UserBus:     userBus,
+		AuthClient:  cfg.AuthClient,
+		Limiter:     cfg.PasswordLimit,
+		Proxies:     cfg.Proxies,
+	})
+
+	reportingapp.Routes(app, reportingapp.Config{
+		Log:          cfg.Log,
+		ReportingBus: reportingBus,
+		AuthClient:   cfg.AuthClient,
+	})
+
+	webhookapp.Routes(app, webhookapp.Config{
+		Log:        cfg.Log,
+		WebhookBus: webhookBus,
+		AuthClient: cfg.AuthClient,
+	})
+
+	templateapp.Routes(app, templateapp.Config{
+		Log:            cfg.Log,
+		TemplateBus:    templateBus,
+		IdempotencyBus: idempotencyBus,
+		AuthClient:     cfg.AuthClient,
+	})
+
+	timeapp.Routes(app, timeapp.Config{
+		Log:        cfg.Log,
+		TimeBus:    timeBus,
+		TodoBus:    todoBus,
+		AuthClient: cfg.AuthClient,
+	})
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/himynamej/todo/app/domain/checkapp"
 	"github.com/himynamej/todo/app/domain/userapp"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/domain/idempotencybus/stores/idempotencydb"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/domain/userbus/stores/usercache"
 	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
@@ -29,6 +31,7 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	// sames instances for the different set of domain apis.
 	delegate := delegate.New(cfg.Log)
 	userBus := userbus.NewBusiness(cfg.Log, delegate, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), time.Minute))
+	idempotencyBus := idempotencybus.NewBusiness(cfg.Log, idempotencydb.NewStore(cfg.Log, cfg.DB))
 
 	checkapp.Routes(app, checkapp.Config{
 		Build: cfg.Build,
@@ -37,7 +40,10 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	})
 
 	userapp.Routes(app, userapp.Config{
-		UserBus:    userBus,
-		AuthClient: cfg.AuthClient,
+		Log:            cfg.Log,
+		UserBus:        userBus,
+		IdempotencyBus: idempotencyBus,
+		AuthClient:     cfg.AuthClient,
+		CursorKey:      cfg.CursorKey,
 	})
 }
@@ -3,7 +3,10 @@ package reporting
 
 import (
 	"github.com/himynamej/todo/app/domain/checkapp"
+	"github.com/himynamej/todo/app/domain/reportingapp"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/domain/reportingbus/stores/reportingdb"
 	"github.com/himynamej/todo/foundation/web"
 )
 
@@ -20,6 +23,7 @@ func (add) Add(app *web.App, cfg mux.Config) {
 
 	// Construct the business domain packages we need here so we are using the
 	// sames instances for the different set of domain apis.
+	reportingBus := reportingbus.NewBusiness(cfg.Log, reportingdb.NewStore(cfg.Log, cfg.ReportDB))
 
 	checkapp.Routes(app, checkapp.Config{
 		Build: cfg.Build,
@@ -27,4 +31,9 @@ func (add) Add(app *web.App, cfg mux.Config) {
 		DB:    cfg.DB,
 	})
 
+	reportingapp.Routes(app, reportingapp.Config{
+		Log:          cfg.Log,
+		ReportingBus: reportingBus,
+		AuthClient:   cfg.AuthClient,
+	})
 }
@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"expvar"
 	"fmt"
+	"net"
 	"net/http"
+	"net/mail"
+	"net/netip"
 	"os"
 	"os/signal"
 	"runtime"
@@ -18,10 +22,34 @@ import (
 	"github.com/himynamej/todo/api/services/sales/build/reporting"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/debug"
+	"github.com/himynamej/todo/app/sdk/mid"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/domain/idempotencybus/stores/idempotencydb"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/quotabus/stores/quotadb"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/streambus/stores/streamdb"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/todobus/s3"
+	"github.com/himynamej/todo/business/domain/todobus/stores/itemdb"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/domain/webhookbus/stores/webhookdb"
+	"github.com/himynamej/todo/business/sdk/mailer"
+	"github.com/himynamej/todo/business/sdk/mailer/outbox"
+	"github.com/himynamej/todo/business/sdk/mailer/smtp"
 	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/sdk/upload/clamd"
+	"github.com/himynamej/todo/business/types/role"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/himynamej/todo/foundation/otel"
+	"github.com/himynamej/todo/foundation/ratelimit"
+	"github.com/himynamej/todo/foundation/worker"
 )
 
 /*
@@ -75,19 +103,95 @@ func run(ctx context.Context, log *logger.Logger) error {
 			ShutdownTimeout    time.Duration `conf:"default:20s"`
 			APIHost            string        `conf:"default:0.0.0.0:3000"`
 			DebugHost          string        `conf:"default:0.0.0.0:3010"`
+			GRPCHost           string        `conf:"default:0.0.0.0:3020"`
+			GRPCReflection     bool          // Serves reflection to authenticated callers
 			CORSAllowedOrigins []string      `conf:"default:*"`
+			TrustedProxies     []string      // CIDRs of the load balancers in front, rate limits go by RemoteAddr when empty
+			CursorKey          string        `conf:"mask"` // Cursors only work on the instance that made them when empty
 		}
 		Auth struct {
 			Host string `conf:"default:http://auth-service:6000"`
 		}
 		DB struct {
-			User         string `conf:"default:postgres"`
-			Password     string `conf:"default:postgres,mask"`
+			User         string `conf:"default:sales_api"`
+			Password     string `conf:"default:sales_api,mask"`
 			Host         string `conf:"default:database-service"`
 			Name         string `conf:"default:postgres"`
 			MaxIdleConns int    `conf:"default:0"`
 			MaxOpenConns int    `conf:"default:0"`
 			DisableTLS   bool   `conf:"default:true"`
+			ReplicaHost  string
+			JobUser      string `conf:"default:sales_worker"`
+			JobPassword  string `conf:"default:sales_worker,mask"`
+			JobRole      string `conf:"default:sales_jobs"`
+		}
+		Upload struct {
+			MaxSizeUser     int           `conf:"default:5242880"`
+			MaxSizeAdmin    int           `conf:"default:26214400"`
+			MaxArchiveSize  int64         `conf:"default:104857600"`
+			MaxArchiveItems int           `conf:"default:1000"`
+			MaxArchiveRatio int64         `conf:"default:100"`
+			MaxArchiveDepth int           `conf:"default:2"`
+			ClamdHost       string        // host:port or unix:///path
+			ClamdTimeout    time.Duration `conf:"default:30s"`
+			ScanDisabled    bool          // Must be set to run without ClamdHost
+		}
+		S3 struct {
+			Bucket   string // Files are not stored when empty
+			Region   string `conf:"default:us-east-1"`
+			Endpoint string
+		}
+		Orphans struct {
+			Interval    time.Duration `conf:"default:24h"` // Zero turns collection off
+			GracePeriod time.Duration `conf:"default:24h"`
+			Timeout     time.Duration `conf:"default:30m"`
+			Prefix      string        `conf:"default:blobs/"`
+			DryRun      bool          `conf:"default:true"` // Only report until deleting is turned on
+		}
+		Worker struct {
+			MaxRunningJobs int `conf:"default:4"`
+		}
+		Webhook struct {
+			Timeout      time.Duration `conf:"default:10s"`
+			MaxAttempts  int           `conf:"default:8"`
+			Backoff      time.Duration `conf:"default:30s"`
+			MaxBackoff   time.Duration `conf:"default:6h"`
+			PollInterval time.Duration `conf:"default:30s"`
+		}
+		Stream struct {
+			Heartbeat     time.Duration `conf:"default:15s"`
+			Buffer        int           `conf:"default:64"`
+			Retention     time.Duration `conf:"default:24h"`
+			PurgeInterval time.Duration `conf:"default:1h"`
+		}
+		Board struct {
+			RebalanceInterval time.Duration `conf:"default:1h"` // Zero turns rebalancing off
+		}
+		Mail struct {
+			Host     string // host:port of the SMTP server, mail goes to the outbox table when empty
+			From     string `conf:"default:noreply@example.com"`
+			Username string
+			Password string        `conf:"mask"`
+			Timeout  time.Duration `conf:"default:10s"`
+		}
+		Signup struct {
+			Key            string        `conf:"mask"` // Verification links only work on the instance that sent them when empty
+			TTL            time.Duration `conf:"default:24h"`
+			VerifyURL      string        `conf:"default:http://localhost:3000/v1/signup/verify"`
+			AllowedDomains []string      // Any email domain may sign up when empty
+			RateLimit      int           `conf:"default:5"`
+			RateWindow     time.Duration `conf:"default:1h"`
+		}
+		Password struct {
+			TTL        time.Duration `conf:"default:30m"`
+			ResetURL   string        `conf:"default:http://localhost:3000/reset-password"`
+			RateLimit  int           `conf:"default:5"`
+			RateWindow time.Duration `conf:"default:1h"`
+		}
+		Quota struct {
+			User       int64 `conf:"default:1073741824"`  // Zero means no limit
+			Admin      int64 `conf:"default:10737418240"` // Zero means no limit
+			Department int64 `conf:"default:53687091200"` // Zero means no limit
 		}
 		Tempo struct {
 			Host        string  `conf:"default:tempo:4317"`
@@ -150,6 +254,285 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	defer db.Close()
 
+	// The reporting APIs read from a replica when one is configured. Without
+	// one they share the primary, but the connection is still read only.
+	replicaHost := cfg.DB.ReplicaHost
+	if replicaHost == "" {
+		replicaHost = cfg.DB.Host
+	}
+
+	log.Info(ctx, "startup", "status", "initializing reporting database support", "hostport", replicaHost)
+
+	reportDB, err := sqldb.Open(sqldb.Config{
+		User:         cfg.DB.User,
+		Password:     cfg.DB.Password,
+		Host:         replicaHost,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+		ReadOnly:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("connecting to reporting db: %w", err)
+	}
+
+	defer reportDB.Close()
+
+	// Background work spans organizations, so it connects with a login of its
+	// own that takes the role the row-level security policies don't hold to
+	// one. The requests never get to take that role.
+	log.Info(ctx, "startup", "status", "initializing job database support", "user", cfg.DB.JobUser, "role", cfg.DB.JobRole)
+
+	jobDB, err := sqldb.Open(sqldb.Config{
+		User:         cfg.DB.JobUser,
+		Password:     cfg.DB.JobPassword,
+		Host:         cfg.DB.Host,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+		Role:         cfg.DB.JobRole,
+	})
+	if err != nil {
+		return fmt.Errorf("connecting to job db: %w", err)
+	}
+
+	defer jobDB.Close()
+
+	// -------------------------------------------------------------------------
+	// Upload Support
+
+	log.Info(ctx, "startup", "status", "initializing upload support", "clamd", cfg.Upload.ClamdHost)
+
+	validators := []upload.Validator{
+		upload.Limit(map[role.Role]int{
+			role.User:  cfg.Upload.MaxSizeUser,
+			role.Admin: cfg.Upload.MaxSizeAdmin,
+		}, cfg.Upload.MaxSizeUser),
+		upload.SafeName(255),
+		upload.Allow(upload.DefaultAllowed...),
+		upload.MatchExtension(),
+		upload.GuardArchive(upload.ArchiveLimits{
+			MaxEntries: cfg.Upload.MaxArchiveItems,
+			MaxSize:    cfg.Upload.MaxArchiveSize,
+			MaxRatio:   cfg.Upload.MaxArchiveRatio,
+			MaxDepth:   cfg.Upload.MaxArchiveDepth,
+		}),
+	}
+
+	switch {
+	case cfg.Upload.ScanDisabled:
+		log.Warn(ctx, "startup", "status", "malware scanning of uploads is disabled")
+
+	case cfg.Upload.ClamdHost == "":
+		return errors.New("upload scanning requires a clamd host, set upload-scan-disabled to run without it")
+
+	default:
+		scanner, err := clamd.New(cfg.Upload.ClamdHost, cfg.Upload.ClamdTimeout)
+		if err != nil {
+			return fmt.Errorf("constructing clamd client: %w", err)
+		}
+		validators = append(validators, upload.Scan(scanner))
+	}
+
+	uploader := upload.NewPipeline(validators...)
+
+	quotaLimits := quotabus.Limits{
+		Roles: map[role.Role]int64{
+			role.User:  cfg.Quota.User,
+			role.Admin: cfg.Quota.Admin,
+		},
+		Department: cfg.Quota.Department,
+	}
+
+	// -------------------------------------------------------------------------
+	// Background Job Support
+
+	log.Info(ctx, "startup", "status", "initializing worker support", "maxRunningJobs", cfg.Worker.MaxRunningJobs)
+
+	wrk, err := worker.New(cfg.Worker.MaxRunningJobs)
+	if err != nil {
+		return fmt.Errorf("constructing worker: %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// File Storage Support
+
+	var s3Client todobus.S3Client
+
+	switch cfg.S3.Bucket {
+	case "":
+		log.Warn(ctx, "startup", "status", "file storage is disabled")
+
+	default:
+		log.Info(ctx, "startup", "status", "initializing file storage support", "bucket", cfg.S3.Bucket)
+
+		client, err := s3.Open(s3.Config{
+			Bucket:   cfg.S3.Bucket,
+			Region:   cfg.S3.Region,
+			Endpoint: cfg.S3.Endpoint,
+		})
+		if err != nil {
+			return fmt.Errorf("constructing s3 client: %w", err)
+		}
+		s3Client = client
+	}
+
+	// -------------------------------------------------------------------------
+	// Orphaned File Collection
+
+	if s3Client != nil && cfg.Orphans.Interval > 0 {
+		log.Info(ctx, "startup", "status", "initializing orphaned file collection", "interval", cfg.Orphans.Interval, "dryRun", cfg.Orphans.DryRun)
+
+		// Removing an unlinked upload refunds the quota of its owner.
+		userBus := userbus.NewBusiness(log, nil, userdb.NewStore(log, jobDB))
+		quotaBus := quotabus.NewBusiness(log, quotadb.NewStore(log, jobDB), quotaLimits)
+		todoBus := todobus.NewBusiness(log, nil, userBus, quotaBus, itemdb.NewStore(log, jobDB), nil, s3Client, nil, nil, sqldb.NewBeginner(jobDB))
+
+		opts := todobus.OrphanOptions{
+			Prefix:      cfg.Orphans.Prefix,
+			GracePeriod: cfg.Orphans.GracePeriod,
+			DryRun:      cfg.Orphans.DryRun,
+		}
+
+		job := func(ctx context.Context) {
+			if _, err := todoBus.CollectOrphans(ctx, opts); err != nil {
+				log.Error(ctx, "orphans", "status", "collection failed", "msg", err)
+			}
+		}
+
+		gcCtx, gcCancel := context.WithCancel(ctx)
+		defer gcCancel()
+
+		go func() {
+			ticker := time.NewTicker(cfg.Orphans.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-gcCtx.Done():
+					return
+				case <-ticker.C:
+				}
+
+				ctx, cancel := context.WithTimeout(gcCtx, cfg.Orphans.Timeout)
+				if _, err := wrk.Start(ctx, job); err != nil {
+					log.Error(ctx, "orphans", "status", "could not start collection", "msg", err)
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// -------------------------------------------------------------------------
+	// Board Rebalancing
+
+	if cfg.Board.RebalanceInterval > 0 {
+		log.Info(ctx, "startup", "status", "initializing board rebalancing", "interval", cfg.Board.RebalanceInterval)
+
+		todoBus := todobus.NewBusiness(log, nil, nil, nil, itemdb.NewStore(log, jobDB), nil, nil, nil, nil, sqldb.NewBeginner(jobDB))
+
+		boardCtx, boardCancel := context.WithCancel(ctx)
+		defer boardCancel()
+
+		go func() {
+			ticker := time.NewTicker(cfg.Board.RebalanceInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-boardCtx.Done():
+					return
+				case <-ticker.C:
+				}
+
+				ctx, cancel := context.WithTimeout(boardCtx, cfg.Board.RebalanceInterval)
+				if _, err := todoBus.RebalanceBoards(ctx); err != nil {
+					log.Error(ctx, "board", "status", "rebalance failed", "msg", err)
+				}
+				cancel()
+			}
+		}()
+	}
+
+	// -------------------------------------------------------------------------
+	// Webhook Delivery
+
+	log.Info(ctx, "startup", "status", "initializing webhook delivery", "pollInterval", cfg.Webhook.PollInterval)
+
+	webhookCfg := webhookbus.Config{
+		Client:      webhookbus.NewClient(cfg.Webhook.Timeout),
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		Backoff:     cfg.Webhook.Backoff,
+		MaxBackoff:  cfg.Webhook.MaxBackoff,
+	}
+
+	// New deliveries are sent as the events happen. Retries, and deliveries
+	// the worker had no room for, are picked up here.
+	webhookBus := webhookbus.NewBusiness(log, nil, webhookdb.NewStore(log, jobDB), wrk, webhookCfg)
+
+	webhookCtx, webhookCancel := context.WithCancel(ctx)
+	defer webhookCancel()
+
+	go func() {
+		ticker := time.NewTicker(cfg.Webhook.PollInterval)
+		defer ticker.Stop()
+
+		job := func(ctx context.Context) {
+			if _, err := webhookBus.DeliverDue(ctx); err != nil {
+				log.Error(ctx, "webhooks", "status", "delivery failed", "msg", err)
+			}
+		}
+
+		for {
+			select {
+			case <-webhookCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(webhookCtx, cfg.Webhook.PollInterval)
+			if _, err := wrk.Start(ctx, job); err != nil {
+				log.Error(ctx, "webhooks", "status", "could not start delivery", "msg", err)
+			}
+			cancel()
+		}
+	}()
+
+	// -------------------------------------------------------------------------
+	// Todo Event And Idempotency Key Retention
+
+	log.Info(ctx, "startup", "status", "initializing todo event retention", "retention", cfg.Stream.Retention)
+
+	streamBus := streambus.NewBusiness(log, nil, streamdb.NewStore(log, jobDB), streambus.Config{})
+	idempotencyBus := idempotencybus.NewBusiness(log, idempotencydb.NewStore(log, jobDB))
+
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	defer streamCancel()
+
+	go func() {
+		ticker := time.NewTicker(cfg.Stream.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-streamCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(streamCtx, cfg.Stream.PurgeInterval)
+			if _, err := streamBus.Purge(ctx, time.Now().Add(-cfg.Stream.Retention)); err != nil {
+				log.Error(ctx, "stream", "status", "purge failed", "msg", err)
+			}
+			if err := idempotencyBus.Purge(ctx, time.Now()); err != nil {
+				log.Error(ctx, "idempotency", "status", "purge failed", "msg", err)
+			}
+			cancel()
+		}
+	}()
+
 	// -------------------------------------------------------------------------
 	// Initialize authentication support
 
@@ -190,6 +573,78 @@ func run(ctx context.Context, log *logger.Logger) error {
 		}
 	}()
 
+	// -------------------------------------------------------------------------
+	// Paging Support
+
+	// Every instance behind the load balancer has to share the key for the
+	// cursors it hands out to be read by the others.
+	cursorKey := []byte(cfg.Web.CursorKey)
+	if len(cursorKey) == 0 {
+		cursorKey = make([]byte, 32)
+		if _, err := rand.Read(cursorKey); err != nil {
+			return fmt.Errorf("generating cursor key: %w", err)
+		}
+		log.Info(ctx, "startup", "status", "no cursor key set, cursors are only valid on this instance until it restarts")
+	}
+
+	// -------------------------------------------------------------------------
+	// Mail Support
+
+	var mailr mailer.Mailer
+
+	switch cfg.Mail.Host {
+	case "":
+		log.Warn(ctx, "startup", "status", "no smtp server set, mail is written to the mail_outbox table")
+		mailr = outbox.New(log, db)
+
+	default:
+		log.Info(ctx, "startup", "status", "initializing mail support", "host", cfg.Mail.Host)
+
+		from, err := mail.ParseAddress(cfg.Mail.From)
+		if err != nil {
+			return fmt.Errorf("parsing mail from: %w", err)
+		}
+
+		mailr, err = smtp.New(smtp.Config{
+			Host:     cfg.Mail.Host,
+			From:     *from,
+			Username: cfg.Mail.Username,
+			Password: cfg.Mail.Password,
+			Timeout:  cfg.Mail.Timeout,
+		})
+		if err != nil {
+			return fmt.Errorf("constructing smtp mailer: %w", err)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Signup Support
+
+	signupKey := []byte(cfg.Signup.Key)
+	if len(signupKey) == 0 {
+		signupKey = make([]byte, 32)
+		if _, err := rand.Read(signupKey); err != nil {
+			return fmt.Errorf("generating signup key: %w", err)
+		}
+		log.Info(ctx, "startup", "status", "no signup key set, verification links are only valid on this instance until it restarts")
+	}
+
+	signupCfg := signupbus.Config{
+		Key:            signupKey,
+		TTL:            cfg.Signup.TTL,
+		VerifyURL:      cfg.Signup.VerifyURL,
+		AllowedDomains: cfg.Signup.AllowedDomains,
+	}
+
+	proxies := make([]netip.Prefix, len(cfg.Web.TrustedProxies))
+	for i, v := range cfg.Web.TrustedProxies {
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return fmt.Errorf("parsing trusted proxy[%s]: %w", v, err)
+		}
+		proxies[i] = p
+	}
+
 	// -------------------------------------------------------------------------
 	// Start API Service
 
@@ -198,16 +653,50 @@ func run(ctx context.Context, log *logger.Logger) error {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
+	// Streams stay open until the client goes away, so they are told to end
+	// when the servers shut down or they would wait on them for good.
+	streamsDone := make(chan struct{})
+
 	cfgMux := mux.Config{
-		Build:  build,
-		Log:    log,
-		DB:     db,
-		Tracer: tracer,
+		Build:    build,
+		Log:      log,
+		DB:       db,
+		ReportDB: reportDB,
+		Tracer:   tracer,
+		Uploader: uploader,
+		Worker:   wrk,
+		Quota:    quotaLimits,
+		S3Client: s3Client,
+		Webhook:  webhookCfg,
+		Stream: streambus.Config{
+			Buffer: cfg.Stream.Buffer,
+		},
+		Heartbeat: cfg.Stream.Heartbeat,
+		Shutdown:  streamsDone,
+		CursorKey: cursorKey,
+		Mailer:    mailr,
+		Signup:    signupCfg,
+		Password: passwordbus.Config{
+			TTL:      cfg.Password.TTL,
+			ResetURL: cfg.Password.ResetURL,
+		},
+		SignupLimit:   ratelimit.New(cfg.Signup.RateLimit, cfg.Signup.RateWindow),
+		PasswordLimit: ratelimit.New(cfg.Password.RateLimit, cfg.Password.RateWindow),
+		Proxies:       proxies,
 		SalesConfig: mux.SalesConfig{
 			AuthClient: authClient,
 		},
 	}
 
+	// The gRPC services are bound along with the web routes, so both apis
+	// share the same business packages.
+	rpcAPI := mux.RPCAPI(cfgMux)
+	cfgMux.RPC = rpcAPI
+
+	if cfg.Web.GRPCReflection {
+		rpcAPI.EnableReflection(mid.Authenticate(authClient))
+	}
+
 	webAPI := mux.WebAPI(cfgMux,
 		buildRoutes(),
 		mux.WithCORS(cfg.Web.CORSAllowedOrigins),
@@ -222,7 +711,12 @@ func run(ctx context.Context, log *logger.Logger) error {
 		ErrorLog:     logger.NewStdLogger(log, logger.LevelError),
 	}
 
-	serverErrors := make(chan error, 1)
+	lis, err := net.Listen("tcp", cfg.Web.GRPCHost)
+	if err != nil {
+		return fmt.Errorf("grpc listen: %w", err)
+	}
+
+	serverErrors := make(chan error, 2)
 
 	go func() {
 		log.Info(ctx, "startup", "status", "api router started", "host", api.Addr)
@@ -230,6 +724,12 @@ func run(ctx context.Context, log *logger.Logger) error {
 		serverErrors <- api.ListenAndServe()
 	}()
 
+	go func() {
+		log.Info(ctx, "startup", "status", "grpc server started", "host", cfg.Web.GRPCHost)
+
+		serverErrors <- rpcAPI.Serve(lis)
+	}()
+
 	// -------------------------------------------------------------------------
 	// Shutdown
 
@@ -244,10 +744,32 @@ func run(ctx context.Context, log *logger.Logger) error {
 		ctx, cancel := context.WithTimeout(ctx, cfg.Web.ShutdownTimeout)
 		defer cancel()
 
+		close(streamsDone)
+
+		// Both servers stop taking new calls at once and get the same time
+		// to finish the ones in flight.
+		rpcStopped := make(chan struct{})
+		go func() {
+			rpcAPI.GracefulStop()
+			close(rpcStopped)
+		}()
+
 		if err := api.Shutdown(ctx); err != nil {
 			api.Close()
+			rpcAPI.Stop()
 			return fmt.Errorf("could not stop server gracefully: %w", err)
 		}
+
+		select {
+		case <-rpcStopped:
+		case <-ctx.Done():
+			rpcAPI.Stop()
+			return fmt.Errorf("could not stop grpc server gracefully: %w", ctx.Err())
+		}
+
+		if err := wrk.Shutdown(ctx); err != nil {
+			return fmt.Errorf("could not stop background jobs gracefully: %w", err)
+		}
 	}
 
 	return nil
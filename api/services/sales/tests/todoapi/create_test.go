@@ -21,13 +21,16 @@ func createTodoItem200(sd apitest.SeedData) []apitest.Table {
 			Input: &todoapp.NewTodoItem{
 				Description: "Test Todo Item",
 				DueDate:     time.Now().Add(72 * time.Hour).Format(time.RFC3339),
-				FileID:      "mock-file-id",
 			},
 			GotResp: &todoapp.TodoItem{},
 			ExpResp: &todoapp.TodoItem{
+				UserID:      sd.Admins[1].ID.String(),
 				Description: "Test Todo Item",
 				DueDate:     time.Now().Add(72 * time.Hour).Format(time.RFC3339),
-				FileID:      "mock-file-id",
+				Status:      "OPEN",
+				Priority:    "P4",
+				Labels:      []string{},
+				Checklist:   []todoapp.ChecklistItem{},
 			},
 			CmpFunc: func(got any, exp any) string {
 				gotResp, exists := got.(*todoapp.TodoItem)
@@ -39,6 +42,9 @@ func createTodoItem200(sd apitest.SeedData) []apitest.Table {
 
 				// Adjust dynamic fields
 				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Rank = gotResp.Rank
 
 				return cmp.Diff(gotResp, expResp)
 			},
@@ -58,7 +64,7 @@ func createTodoItem400(sd apitest.SeedData) []apitest.Table {
 			StatusCode: http.StatusBadRequest,
 			Input:      &todoapp.NewTodoItem{},
 			GotResp:    &errs.Error{},
-			ExpResp:    errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"description\",\"error\":\"description is a required field\"},{\"field\":\"dueDate\",\"error\":\"dueDate is a required field\"}]"),
+			ExpResp:    errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"description\",\"error\":\"description is a required field\"}]"),
 			CmpFunc: func(got any, exp any) string {
 				return cmp.Diff(got, exp)
 			},
@@ -0,0 +1,105 @@
+package todoapi
+
+import (
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/app/domain/todoapp"
+	"github.com/himynamej/todo/app/sdk/apitest"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/todobus"
+)
+
+func quickAdd200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "create",
+			URL:        "/v1/todo/quick",
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input:      &todoapp.QuickAdd{Text: "Renew passport 2030-01-02 every year #errands !p2"},
+			GotResp:    &todoapp.TodoItem{},
+			ExpResp: &todoapp.TodoItem{
+				UserID:      sd.Users[0].ID.String(),
+				Description: "Renew passport",
+				DueDate:     "2030-01-02",
+				AllDay:      true,
+				Status:      "OPEN",
+				Priority:    "P2",
+				Recurrence:  "FREQ=YEARLY;INTERVAL=1;BYMONTHDAY=2",
+				Labels:      []string{"errands"},
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*todoapp.TodoItem)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*todoapp.TodoItem)
+
+				// Adjust dynamic fields
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Rank = gotResp.Rank
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:       "preview",
+			URL:        "/v1/todo/quick/preview",
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input:      &todoapp.QuickAdd{Text: "Book flights on 2030-03-04 @travel"},
+			GotResp:    &todoapp.QuickAddPreview{},
+			ExpResp: &todoapp.QuickAddPreview{
+				Description: "Book flights",
+				DueDate:     "2030-03-04",
+				AllDay:      true,
+				Priority:    "P4",
+				Labels:      []string{"travel"},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func quickAdd400(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "missing-input",
+			URL:        "/v1/todo/quick",
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      &todoapp.QuickAdd{},
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"text\",\"error\":\"text is a required field\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "no-description",
+			URL:        "/v1/todo/quick/preview",
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      &todoapp.QuickAdd{Text: "tomorrow 6pm #home"},
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "%s", errs.NewFieldsError("text", todobus.ErrQuickAddEmpty)),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -25,6 +25,13 @@ func Test_TodoApp(t *testing.T) {
 	test.Run(t, createTodoItem400(sd), "createtodoitem-400")
 	test.Run(t, createTodoItem401(), "createtodoitem-401")
 
+	// -------------------------------------------------------------------------
+	// Run test cases for QuickAddTodoItem
+	// -------------------------------------------------------------------------
+
+	test.Run(t, quickAdd200(sd), "quickadd-200")
+	test.Run(t, quickAdd400(sd), "quickadd-400")
+
 	// -------------------------------------------------------------------------
 	// Run test cases for File Upload and Download
 	// -------------------------------------------------------------------------
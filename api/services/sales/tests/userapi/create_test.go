@@ -31,6 +31,7 @@ func create200(sd apitest.SeedData) []apitest.Table {
 				Email:      "javadah1376@gmail.com",
 				Roles:      []string{"ADMIN"},
 				Department: "ITO",
+				TimeZone:   "UTC",
 				Enabled:    true,
 			},
 			CmpFunc: func(got any, exp any) string {
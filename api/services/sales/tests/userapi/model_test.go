@@ -16,6 +16,7 @@ func toAppUser(bus userbus.User) userapp.User {
 		Roles:        role.ParseToString(bus.Roles),
 		PasswordHash: nil, // This field is not marshalled.
 		Department:   bus.Department.String(),
+		TimeZone:     bus.TimeZone.String(),
 		Enabled:      bus.Enabled,
 		DateCreated:  bus.DateCreated.Format(time.RFC3339),
 		DateUpdated:  bus.DateUpdated.Format(time.RFC3339),
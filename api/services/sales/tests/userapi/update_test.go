@@ -24,6 +24,7 @@ func update200(sd apitest.SeedData) []apitest.Table {
 				Name:            dbtest.StringPointer("Jack Kennedy"),
 				Email:           dbtest.StringPointer("jack@gmail.com"),
 				Department:      dbtest.StringPointer("ITO"),
+				TimeZone:        dbtest.StringPointer("Asia/Tokyo"),
 				Password:        dbtest.StringPointer("123"),
 				PasswordConfirm: dbtest.StringPointer("123"),
 			},
@@ -34,6 +35,7 @@ func update200(sd apitest.SeedData) []apitest.Table {
 				Email:       "jack@gmail.com",
 				Roles:       []string{"USER"},
 				Department:  "ITO",
+				TimeZone:    "Asia/Tokyo",
 				Enabled:     true,
 				DateCreated: sd.Users[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].DateUpdated.Format(time.RFC3339),
@@ -66,6 +68,7 @@ func update200(sd apitest.SeedData) []apitest.Table {
 				Email:       sd.Admins[0].Email.Address,
 				Roles:       []string{"USER"},
 				Department:  sd.Admins[0].Department.String(),
+				TimeZone:    sd.Admins[0].TimeZone.String(),
 				Enabled:     true,
 				DateCreated: sd.Admins[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Admins[0].DateUpdated.Format(time.RFC3339),
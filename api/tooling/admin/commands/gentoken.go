@@ -2,11 +2,16 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/orgbus/stores/orgdb"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessiondb"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
 	"github.com/himynamej/todo/business/sdk/sqldb"
@@ -17,13 +22,25 @@ import (
 	"github.com/google/uuid"
 )
 
-// GenToken generates a JWT for the specified user.
-func GenToken(log *logger.Logger, dbConfig sqldb.Config, keyPath string, userID uuid.UUID, kid string) error {
+// maxTokenTTL bounds how long a generated token can work, since nobody holds
+// it to sign out with.
+const maxTokenTTL = 24 * time.Hour
+
+// GenToken generates a JWT for the specified user in the organization, or
+// the one the user joined first when it is zero. The user must be a member
+// of it. The token works for the ttl, or as long as the tokens handed out at
+// sign in when it is zero. It belongs to a session like the ones handed out
+// at sign in, so it stops working when the sessions of the user are ended.
+func GenToken(log *logger.Logger, dbConfig sqldb.Config, keyPath string, userID uuid.UUID, orgID uuid.UUID, kid string, ttl time.Duration) error {
 	if kid == "" {
-		fmt.Println("help: gentoken <user_id> <kid>")
+		fmt.Println("help: gentoken [--org <org_id>] <user_id> <kid> [ttl]")
 		return ErrHelp
 	}
 
+	if ttl < 0 || ttl > maxTokenTTL {
+		return fmt.Errorf("ttl[%s] must be between 0 and %s", ttl, maxTokenTTL)
+	}
+
 	db, err := sqldb.Open(dbConfig)
 	if err != nil {
 		return fmt.Errorf("connect database: %w", err)
@@ -40,6 +57,29 @@ func GenToken(log *logger.Logger, dbConfig sqldb.Config, keyPath string, userID
 		return fmt.Errorf("retrieve user: %w", err)
 	}
 
+	orgBus := orgbus.NewBusiness(log, nil, orgdb.NewStore(log, db), sqldb.NewBeginner(db))
+
+	if orgID == uuid.Nil {
+		orgs, err := orgBus.QueryByUserID(ctx, usr.ID)
+		if err != nil {
+			return fmt.Errorf("retrieve organizations: %w", err)
+		}
+
+		if len(orgs) == 0 {
+			return fmt.Errorf("user[%s] does not belong to an organization", usr.ID)
+		}
+
+		orgID = orgs[0].ID
+	}
+
+	mbr, err := orgBus.QueryMember(ctx, orgID, usr.ID)
+	if err != nil {
+		if errors.Is(err, orgbus.ErrMemberNotFound) {
+			return fmt.Errorf("user[%s] is not a member of org[%s]", usr.ID, orgID)
+		}
+		return fmt.Errorf("retrieve membership: %w", err)
+	}
+
 	ks := keystore.New()
 
 	n1, err := ks.LoadByJSON("SALAES_PEM")
@@ -68,9 +108,13 @@ func GenToken(log *logger.Logger, dbConfig sqldb.Config, keyPath string, userID
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	if ttl == 0 {
+		ttl = ath.AccessTTL()
+	}
+
 	// Generating a token requires defining a set of claims. In this applications
 	// case, we only care about defining the subject and the user in question and
-	// the roles they have on the database. This token will expire in a year.
+	// the roles they have on the database. This token expires after the ttl.
 	//
 	// iss (issuer): Issuer of the JWT
 	// sub (subject): Subject of the JWT (the user)
@@ -81,12 +125,18 @@ func GenToken(log *logger.Logger, dbConfig sqldb.Config, keyPath string, userID
 	// jti (JWT ID): Unique identifier; can be used to prevent the JWT from being replayed (allows a token to be used only once)
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   usr.ID.String(),
 			Issuer:    ath.Issuer(),
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(8760 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 		Roles: role.ParseToString(usr.Roles),
+		OrgID: mbr.OrgID.String(),
+	}
+
+	if mbr.Role == orgbus.RoleAdmin {
+		claims.Roles = append(claims.Roles, auth.RoleOrgAdmin)
 	}
 
 	// This will generate a JWT with the claims embedded in them. The database
@@ -98,6 +148,22 @@ func GenToken(log *logger.Logger, dbConfig sqldb.Config, keyPath string, userID
 		return fmt.Errorf("generating token: %w", err)
 	}
 
+	// The session is what revocation looks for, its refresh token is never
+	// handed out.
+
+	sessionBus := sessionbus.NewBusiness(log, nil, sessiondb.NewStore(log, db), sessionbus.Config{})
+
+	nrt := sessionbus.NewRefreshToken{
+		UserID:        usr.ID,
+		OrgID:         mbr.OrgID,
+		AccessID:      claims.ID,
+		AccessExpires: claims.ExpiresAt.Time,
+	}
+
+	if _, _, err := sessionBus.Issue(ctx, nrt); err != nil {
+		return fmt.Errorf("issuing session: %w", err)
+	}
+
 	fmt.Printf("-----BEGIN TOKEN-----\n%s\n-----END TOKEN-----\n", token)
 	return nil
 }
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/quotabus/stores/quotadb"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/todobus/s3"
+	"github.com/himynamej/todo/business/domain/todobus/stores/itemdb"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+)
+
+// Orphans finds the uploads no todo item took up and the files in the bucket
+// nothing refers to any more. In delete mode they are removed, in dry-run mode
+// they are only counted and in report mode every file is listed as well.
+func Orphans(log *logger.Logger, cfg sqldb.Config, s3Cfg s3.Config, mode string, grace string) error {
+	opts := todobus.OrphanOptions{
+		GracePeriod: 24 * time.Hour,
+	}
+
+	switch mode {
+	case "delete":
+	case "dry-run":
+		opts.DryRun = true
+	case "report":
+		opts.DryRun = true
+		opts.Report = true
+	default:
+		fmt.Println("help: orphans <delete|dry-run|report> [grace period, default 24h]")
+		return ErrHelp
+	}
+
+	if grace != "" {
+		d, err := time.ParseDuration(grace)
+		if err != nil {
+			return fmt.Errorf("parsing grace period: %w", err)
+		}
+		opts.GracePeriod = d
+	}
+
+	if s3Cfg.Bucket == "" {
+		return fmt.Errorf("no bucket configured")
+	}
+
+	// The collection spans organizations.
+	cfg.Role = sqldb.JobRole
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	s3Client, err := s3.Open(s3Cfg)
+	if err != nil {
+		return fmt.Errorf("connect s3: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	// Removing an unlinked upload refunds the quota of its owner.
+	userBus := userbus.NewBusiness(log, nil, userdb.NewStore(log, db))
+	quotaBus := quotabus.NewBusiness(log, quotadb.NewStore(log, db), quotabus.Limits{})
+	todoBus := todobus.NewBusiness(log, nil, userBus, quotaBus, itemdb.NewStore(log, db), nil, s3Client, nil, nil, sqldb.NewBeginner(db))
+
+	report, err := todoBus.CollectOrphans(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("collect orphans: %w", err)
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(report)
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/foundation/keystore"
+)
+
+// RotateKey generates a new signing key in the keys folder and schedules it
+// to become the active one once the delay is over. Until then the key is
+// pending: it is published and verifies tokens but signs none, so every
+// running service has picked it up before the first token signed with it
+// reaches them. The delay must be longer than the services take to reload
+// the keys folder. The keys that were active until then only verify the
+// tokens they signed from then on, and are retired once the grace period
+// after it is over.
+func RotateKey(keysFolder string, grace string, delay string) error {
+	if grace == "" {
+		grace = "1h"
+	}
+
+	if delay == "" {
+		delay = "5m"
+	}
+
+	graceDur, err := time.ParseDuration(grace)
+	if err != nil {
+		fmt.Println("help: rotatekey <grace period, longer than an access token lives> <delay, longer than the keys reload interval>")
+		return ErrHelp
+	}
+
+	delayDur, err := time.ParseDuration(delay)
+	if err != nil || delayDur <= 0 {
+		fmt.Println("help: rotatekey <grace period, longer than an access token lives> <delay, longer than the keys reload interval>")
+		return ErrHelp
+	}
+
+	ks := keystore.New()
+	if _, err := ks.LoadByFileSystem(os.DirFS(keysFolder)); err != nil {
+		return fmt.Errorf("loading keys: %w", err)
+	}
+
+	// Write the key before the manifest so an auth service reloading in
+	// between never finds an active key it can't load.
+
+	kid := uuid.NewString()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	privateBlock := pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}
+
+	if err := writeFile(filepath.Join(keysFolder, kid+".pem"), pem.EncodeToMemory(&privateBlock)); err != nil {
+		return fmt.Errorf("writing key: %w", err)
+	}
+
+	activateAt := time.Now().UTC().Add(delayDur)
+	retireAt := activateAt.Add(graceDur)
+
+	// The keys signing now, or about to, keep signing until the new key
+	// takes over.
+
+	m := ks.Manifest()
+	for i, st := range m.Keys {
+		switch st.Status {
+		case keystore.StatusPending, keystore.StatusActive:
+			m.Keys[i].RetiringAt = &activateAt
+			m.Keys[i].RetireAt = &retireAt
+		}
+	}
+
+	m.Keys = append(m.Keys, keystore.KeyState{
+		KID:       kid,
+		Status:    keystore.StatusPending,
+		Activated: activateAt,
+	})
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	if err := writeFile(filepath.Join(keysFolder, keystore.ManifestFile), data); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	fmt.Printf("pending kid: %s\n", kid)
+	fmt.Printf("signs from: %s\n", activateAt.Format(time.RFC3339))
+	fmt.Printf("previous keys retire at: %s\n", retireAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// writeFile replaces the file in one step, so a reader never sees it half
+// written.
+func writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/quotabus/stores/quotadb"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/foundation/logger"
+)
+
+// Storage reports the storage used by a user or a department. When a quota is
+// provided, it becomes the quota of the user or department first.
+func Storage(log *logger.Logger, cfg sqldb.Config, limits quotabus.Limits, scope string, owner string, quota string) error {
+	if owner == "" {
+		fmt.Println("help: storage <user|department> <user_id|department> [quota bytes]")
+		return ErrHelp
+	}
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userBus := userbus.NewBusiness(log, nil, userdb.NewStore(log, db))
+	quotaBus := quotabus.NewBusiness(log, quotadb.NewStore(log, db), limits)
+
+	switch scope {
+	case quotabus.ScopeUser:
+		userID, err := uuid.Parse(owner)
+		if err != nil {
+			return fmt.Errorf("parsing user id: %w", err)
+		}
+
+		usr, err := userBus.QueryByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("retrieve user: %w", err)
+		}
+
+		if quota != "" {
+			n, err := strconv.ParseInt(quota, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing quota: %w", err)
+			}
+
+			if _, err := quotaBus.SetUserQuota(ctx, usr, n); err != nil {
+				return fmt.Errorf("set quota: %w", err)
+			}
+		}
+
+		usgs, err := quotaBus.QueryByUser(ctx, usr)
+		if err != nil {
+			return fmt.Errorf("retrieve usage: %w", err)
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(usgs)
+
+	case quotabus.ScopeDepartment:
+		department, err := name.Parse(owner)
+		if err != nil {
+			return fmt.Errorf("parsing department: %w", err)
+		}
+
+		if quota != "" {
+			n, err := strconv.ParseInt(quota, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing quota: %w", err)
+			}
+
+			if _, err := quotaBus.SetDepartmentQuota(ctx, department, n); err != nil {
+				return fmt.Errorf("set quota: %w", err)
+			}
+		}
+
+		usg, err := quotaBus.QueryByDepartment(ctx, department)
+		if err != nil {
+			return fmt.Errorf("retrieve usage: %w", err)
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(usg)
+	}
+
+	return fmt.Errorf("unknown scope %q, expected %s or %s", scope, quotabus.ScopeUser, quotabus.ScopeDepartment)
+}
@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/ardanlabs/conf/v3"
 	"github.com/himynamej/todo/api/tooling/admin/commands"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/todobus/s3"
 	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/types/role"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/google/uuid"
 )
@@ -20,6 +24,7 @@ var build = "develop"
 type config struct {
 	conf.Version
 	Args conf.Args
+	Org  string `conf:"help:organization gentoken issues the token for, the first one the user joined when empty"`
 	DB   struct {
 		User         string `conf:"default:postgres"`
 		Password     string `conf:"default:postgres,mask"`
@@ -33,6 +38,16 @@ type config struct {
 		KeysFolder string `conf:"default:zarf/keys/"`
 		DefaultKID string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
 	}
+	S3 struct {
+		Bucket   string
+		Region   string `conf:"default:us-east-1"`
+		Endpoint string
+	}
+	Quota struct {
+		User       int64 `conf:"default:1073741824"`
+		Admin      int64 `conf:"default:10737418240"`
+		Department int64 `conf:"default:53687091200"`
+	}
 }
 
 func main() {
@@ -121,11 +136,38 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 			return fmt.Errorf("getting users: %w", err)
 		}
 
+	case "storage":
+		limits := quotabus.Limits{
+			Roles: map[role.Role]int64{
+				role.User:  cfg.Quota.User,
+				role.Admin: cfg.Quota.Admin,
+			},
+			Department: cfg.Quota.Department,
+		}
+		if err := commands.Storage(log, dbConfig, limits, args.Num(1), args.Num(2), args.Num(3)); err != nil {
+			return fmt.Errorf("storage usage: %w", err)
+		}
+
+	case "orphans":
+		s3Config := s3.Config{
+			Bucket:   cfg.S3.Bucket,
+			Region:   cfg.S3.Region,
+			Endpoint: cfg.S3.Endpoint,
+		}
+		if err := commands.Orphans(log, dbConfig, s3Config, args.Num(1), args.Num(2)); err != nil {
+			return fmt.Errorf("collecting orphans: %w", err)
+		}
+
 	case "genkey":
 		if err := commands.GenKey(); err != nil {
 			return fmt.Errorf("key generation: %w", err)
 		}
 
+	case "rotatekey":
+		if err := commands.RotateKey(cfg.Auth.KeysFolder, args.Num(1), args.Num(2)); err != nil {
+			return fmt.Errorf("rotating key: %w", err)
+		}
+
 	case "gentoken":
 		userID, err := uuid.Parse(args.Num(1))
 		if err != nil {
@@ -135,7 +177,19 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		if kid == "" {
 			kid = cfg.Auth.DefaultKID
 		}
-		if err := commands.GenToken(log, dbConfig, cfg.Auth.KeysFolder, userID, kid); err != nil {
+		var ttl time.Duration
+		if v := args.Num(3); v != "" {
+			if ttl, err = time.ParseDuration(v); err != nil {
+				return fmt.Errorf("generating token: %w", err)
+			}
+		}
+		var orgID uuid.UUID
+		if cfg.Org != "" {
+			if orgID, err = uuid.Parse(cfg.Org); err != nil {
+				return fmt.Errorf("generating token: %w", err)
+			}
+		}
+		if err := commands.GenToken(log, dbConfig, cfg.Auth.KeysFolder, userID, orgID, kid, ttl); err != nil {
 			return fmt.Errorf("generating token: %w", err)
 		}
 
@@ -144,8 +198,11 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		fmt.Println("seed:       add data to the database")
 		fmt.Println("useradd:    add a new user to the database")
 		fmt.Println("users:      get a list of users from the database")
+		fmt.Println("storage:    report storage used by a user or department and set its quota")
+		fmt.Println("orphans:    find and delete files in the bucket that nothing refers to")
 		fmt.Println("genkey:     generate a set of private/public key files")
 		fmt.Println("gentoken:   generate a JWT for a user with claims")
+		fmt.Println("rotatekey:  generate a new signing key that takes over from the current one after a delay")
 		fmt.Println("provide a command to get more help.")
 		return commands.ErrHelp
 	}
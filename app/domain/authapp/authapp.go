@@ -5,39 +5,253 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/errs"
 	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/types/role"
 	"github.com/himynamej/todo/foundation/web"
 )
 
 type app struct {
-	auth *auth.Auth
+	auth       *auth.Auth
+	userBus    *userbus.Business
+	orgBus     *orgbus.Business
+	sessionBus *sessionbus.Business
 }
 
-func newApp(ath *auth.Auth) *app {
+func newApp(ath *auth.Auth, userBus *userbus.Business, orgBus *orgbus.Business, sessionBus *sessionbus.Business) *app {
 	return &app{
-		auth: ath,
+		auth:       ath,
+		userBus:    userBus,
+		orgBus:     orgBus,
+		sessionBus: sessionBus,
 	}
 }
 
 func (a *app) token(ctx context.Context, r *http.Request) web.Encoder {
-	kid := web.Param(r, "kid")
-	if kid == "" {
-		return errs.New(errs.InvalidArgument, errs.NewFieldsError("kid", errors.New("missing kid")))
-	}
+	// Tokens are always signed with the active key. The kid in the path is
+	// ignored, it is only there for clients written before keys were
+	// rotated, which keep asking with the kid they were set up with.
 
 	// The BearerBasic middleware function generates the claims.
 	claims := mid.GetClaims(ctx)
 
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	mbr, appErr := a.member(ctx, userID, r.URL.Query().Get("org"))
+	if appErr != nil {
+		return appErr
+	}
+
+	claims.OrgID = mbr.OrgID.String()
+	if mbr.Role == orgbus.RoleAdmin {
+		claims.Roles = append(claims.Roles, auth.RoleOrgAdmin)
+	}
+
+	return a.issue(ctx, claims, mbr, uuid.Nil)
+}
+
+func (a *app) refresh(ctx context.Context, r *http.Request) web.Encoder {
+	var app Refresh
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rt, err := a.sessionBus.Redeem(ctx, app.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, sessionbus.ErrInvalidToken), errors.Is(err, sessionbus.ErrTokenExpired), errors.Is(err, sessionbus.ErrTokenReused):
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "redeem: %s", err)
+	}
+
+	// The user and their membership are looked up again so a refresh can't
+	// outlive a disabled account or a changed role.
+
+	usr, err := a.userBus.QueryByID(ctx, rt.UserID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", rt.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return errs.Newf(errs.Unauthenticated, "user disabled")
+	}
+
+	mbr, appErr := a.member(ctx, usr.ID, rt.OrgID.String())
+	if appErr != nil {
+		return appErr
+	}
+
+	now := time.Now().UTC()
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   usr.ID.String(),
+			Issuer:    a.auth.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.auth.AccessTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Roles: role.ParseToString(usr.Roles),
+		OrgID: mbr.OrgID.String(),
+	}
+
+	if mbr.Role == orgbus.RoleAdmin {
+		claims.Roles = append(claims.Roles, auth.RoleOrgAdmin)
+	}
+
+	return a.issue(ctx, claims, mbr, rt.FamilyID)
+}
+
+func (a *app) logout(ctx context.Context, r *http.Request) web.Encoder {
+	var app Logout
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	claims := mid.GetClaims(ctx)
+
+	access := sessionbus.Revocation{
+		AccessID: claims.ID,
+	}
+
+	if claims.ExpiresAt != nil {
+		access.DateExpires = claims.ExpiresAt.Time
+	}
+
+	if err := a.sessionBus.Logout(ctx, userID, access, app.RefreshToken); err != nil {
+		if errors.Is(err, sessionbus.ErrInvalidToken) {
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("refreshToken", err))
+		}
+		return errs.Newf(errs.Internal, "logout: userID[%s]: %s", userID, err)
+	}
+
+	return nil
+}
+
+// revoke ends every session of a member of the organization. The route only
+// lets the admins of the organization through.
+func (a *app) revoke(ctx context.Context, r *http.Request) web.Encoder {
+	orgID, err := mid.GetOrgID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	target, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("user_id", err))
+	}
+
+	if _, err := a.orgBus.QueryMember(ctx, orgID, target); err != nil {
+		if errors.Is(err, orgbus.ErrMemberNotFound) {
+			return errs.Newf(errs.NotFound, "user[%s] is not a member of org[%s]", target, orgID)
+		}
+		return errs.Newf(errs.Internal, "querymember: orgID[%s] userID[%s]: %s", orgID, target, err)
+	}
+
+	if err := a.sessionBus.RevokeUser(ctx, target); err != nil {
+		return errs.Newf(errs.Internal, "revokeuser: userID[%s]: %s", target, err)
+	}
+
+	return nil
+}
+
+func (a *app) jwks(ctx context.Context, r *http.Request) web.Encoder {
+	set, err := a.auth.JWKS()
+	if err != nil {
+		return errs.Newf(errs.Internal, "jwks: %s", err)
+	}
+
+	return toAppJWKS(set)
+}
+
+// issue signs the access token with the active key and hands it out with a
+// refresh token in the family, starting a new family when none is given.
+func (a *app) issue(ctx context.Context, claims auth.Claims, mbr orgbus.Member, familyID uuid.UUID) web.Encoder {
+	claims.ID = uuid.NewString()
+
+	kid, err := a.auth.ActiveKID()
+	if err != nil {
+		return errs.Newf(errs.Internal, "activekid: %s", err)
+	}
+
 	tkn, err := a.auth.GenerateToken(kid, claims)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
-	return token{Token: tkn}
+	nrt := sessionbus.NewRefreshToken{
+		FamilyID:      familyID,
+		UserID:        mbr.UserID,
+		OrgID:         mbr.OrgID,
+		AccessID:      claims.ID,
+		AccessExpires: claims.ExpiresAt.Time,
+	}
+
+	refresh, _, err := a.sessionBus.Issue(ctx, nrt)
+	if err != nil {
+		return errs.Newf(errs.Internal, "issue: userID[%s]: %s", mbr.UserID, err)
+	}
+
+	resp := token{
+		Token:        tkn,
+		RefreshToken: refresh,
+		ExpiresAt:    claims.ExpiresAt.Time.Format(time.RFC3339),
+	}
+
+	return resp
+}
+
+// member finds the membership of the user in the organization the token is
+// asked for. With none asked for, the token is for the organization the user
+// joined first.
+func (a *app) member(ctx context.Context, userID uuid.UUID, org string) (orgbus.Member, *errs.Error) {
+	if org == "" {
+		orgs, err := a.orgBus.QueryByUserID(ctx, userID)
+		if err != nil {
+			return orgbus.Member{}, errs.New(errs.Internal, err)
+		}
+
+		if len(orgs) == 0 {
+			return orgbus.Member{}, errs.Newf(errs.FailedPrecondition, "user does not belong to an organization")
+		}
+
+		org = orgs[0].ID.String()
+	}
+
+	orgID, err := uuid.Parse(org)
+	if err != nil {
+		return orgbus.Member{}, errs.New(errs.InvalidArgument, errs.NewFieldsError("org", err))
+	}
+
+	mbr, err := a.orgBus.QueryMember(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, orgbus.ErrMemberNotFound) {
+			return orgbus.Member{}, errs.Newf(errs.PermissionDenied, "user is not a member of org[%s]", orgID)
+		}
+		return orgbus.Member{}, errs.Newf(errs.Internal, "querymember: orgID[%s]: %s", orgID, err)
+	}
+
+	return mbr, nil
 }
 
 func (a *app) authenticate(ctx context.Context, r *http.Request) web.Encoder {
@@ -1,9 +1,16 @@
 package authapp
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/errs"
+)
 
 type token struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresAt    string `json:"expiresAt"`
 }
 
 // Encode implements the encoder interface.
@@ -11,3 +18,52 @@ func (t token) Encode() ([]byte, string, error) {
 	data, err := json.Marshal(t)
 	return data, "application/json", err
 }
+
+// Refresh defines the data needed to exchange a refresh token for new tokens.
+type Refresh struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *Refresh) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Refresh) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// Logout defines the data needed to sign out. The session of the refresh
+// token is ended too when one is given.
+type Logout struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Decode implements the decoder interface.
+func (app *Logout) Decode(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, app)
+}
+
+// jwks is the set of public keys published for verifying tokens.
+type jwks struct {
+	auth.JWKSet
+}
+
+// Encode implements the encoder interface.
+func (j jwks) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(j.JWKSet)
+	return data, "application/json", err
+}
+
+func toAppJWKS(set auth.JWKSet) jwks {
+	return jwks{JWKSet: set}
+}
@@ -5,14 +5,18 @@ import (
 
 	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/sessionbus"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/foundation/web"
 )
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
-	UserBus *userbus.Business
-	Auth    *auth.Auth
+	UserBus    *userbus.Business
+	OrgBus     *orgbus.Business
+	SessionBus *sessionbus.Business
+	Auth       *auth.Auth
 }
 
 // Routes adds specific routes for this group.
@@ -21,10 +25,16 @@ func Routes(app *web.App, cfg Config) {
 
 	bearer := mid.Bearer(cfg.Auth)
 	basic := mid.Basic(cfg.Auth, cfg.UserBus)
+	ruleOrgAdmin := mid.AuthorizeLocal(cfg.Auth, auth.RuleOrgAdmin)
 
-	api := newApp(cfg.Auth)
+	api := newApp(cfg.Auth, cfg.UserBus, cfg.OrgBus, cfg.SessionBus)
 
+	app.HandlerFunc(http.MethodGet, "", "/.well-known/jwks.json", api.jwks)
+	app.HandlerFunc(http.MethodGet, version, "/auth/token", api.token, basic)
 	app.HandlerFunc(http.MethodGet, version, "/auth/token/{kid}", api.token, basic)
+	app.HandlerFunc(http.MethodPost, version, "/auth/token/refresh", api.refresh)
+	app.HandlerFunc(http.MethodPost, version, "/auth/logout", api.logout, bearer)
+	app.HandlerFunc(http.MethodPost, version, "/auth/revoke/{user_id}", api.revoke, bearer, ruleOrgAdmin)
 	app.HandlerFunc(http.MethodGet, version, "/auth/authenticate", api.authenticate, bearer)
 	app.HandlerFunc(http.MethodPost, version, "/auth/authorize", api.authorize)
 }
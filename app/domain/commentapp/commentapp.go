@@ -0,0 +1,142 @@
+// Package commentapp maintains the app layer api for the comment domain.
+package commentapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/app/sdk/query"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/markdown"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	commentBus *commentbus.Business
+}
+
+func newApp(commentBus *commentbus.Business) *app {
+	return &app{
+		commentBus: commentBus,
+	}
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewComment
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	todoID, err := uuid.Parse(web.Param(r, "item_id"))
+	if err != nil {
+		return errs.NewFieldsError("item_id", err)
+	}
+
+	body, err := markdown.Parse(app.Body)
+	if err != nil {
+		return errs.NewFieldsError("body", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	nc := commentbus.NewComment{
+		TodoID: todoID,
+		UserID: userID,
+		Body:   body,
+	}
+
+	cmt, err := a.commentBus.Create(ctx, nc)
+	if err != nil {
+		if errors.Is(err, commentbus.ErrTodoNotFound) {
+			return errs.New(errs.NotFound, commentbus.ErrTodoNotFound)
+		}
+		return errs.Newf(errs.Internal, "create: cmt[%+v]: %s", nc, err)
+	}
+
+	return toAppComment(cmt)
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateComment
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	uc, err := toBusUpdateComment(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cmt, err := mid.GetComment(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "comment missing in context: %s", err)
+	}
+
+	updCmt, err := a.commentBus.Update(ctx, cmt, uc)
+	if err != nil {
+		return errs.Newf(errs.Internal, "update: commentID[%s]: %s", cmt.ID, err)
+	}
+
+	return toAppComment(updCmt)
+}
+
+func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
+	cmt, err := mid.GetComment(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "comment missing in context: %s", err)
+	}
+
+	if err := a.commentBus.Delete(ctx, cmt); err != nil {
+		return errs.Newf(errs.Internal, "delete: commentID[%s]: %s", cmt.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	todoID, err := uuid.Parse(web.Param(r, "item_id"))
+	if err != nil {
+		return errs.NewFieldsError("item_id", err)
+	}
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldsError("page", err)
+	}
+
+	filter, err := parseFilter(qp, todoID)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, commentbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldsError("order", err)
+	}
+
+	cmts, err := a.commentBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.commentBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppComments(cmts), total, page)
+}
@@ -0,0 +1,57 @@
+package commentapp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/commentbus"
+)
+
+func parseQueryParams(r *http.Request) (queryParams, error) {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		UserID:           values.Get("user_id"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+
+	return filter, nil
+}
+
+func parseFilter(qp queryParams, todoID uuid.UUID) (commentbus.QueryFilter, error) {
+	filter := commentbus.QueryFilter{
+		TodoID: &todoID,
+	}
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		if err != nil {
+			return commentbus.QueryFilter{}, errs.NewFieldsError("user_id", err)
+		}
+		filter.UserID = &id
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		if err != nil {
+			return commentbus.QueryFilter{}, errs.NewFieldsError("start_created_date", err)
+		}
+		filter.StartCreatedDate = &t
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		if err != nil {
+			return commentbus.QueryFilter{}, errs.NewFieldsError("end_created_date", err)
+		}
+		filter.EndCreatedDate = &t
+	}
+
+	return filter, nil
+}
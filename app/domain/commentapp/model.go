@@ -0,0 +1,138 @@
+package commentapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/types/markdown"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	UserID           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+// =============================================================================
+
+// Comment represents information about a comment on a todo item.
+type Comment struct {
+	ID          string   `json:"id"`
+	TodoID      string   `json:"todoId"`
+	UserID      string   `json:"userId"`
+	Body        string   `json:"body"`
+	Mentions    []string `json:"mentions"`
+	Edited      bool     `json:"edited"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Comment) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppComment(bus commentbus.Comment) Comment {
+	mentions := make([]string, len(bus.Mentions))
+	for i, userID := range bus.Mentions {
+		mentions[i] = userID.String()
+	}
+
+	return Comment{
+		ID:          bus.ID.String(),
+		TodoID:      bus.TodoID.String(),
+		UserID:      bus.UserID.String(),
+		Body:        bus.Body.String(),
+		Mentions:    mentions,
+		Edited:      bus.Edited,
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+		DateUpdated: bus.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppComments(cmts []commentbus.Comment) []Comment {
+	app := make([]Comment, len(cmts))
+	for i, cmt := range cmts {
+		app[i] = toAppComment(cmt)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewComment defines the data needed to add a new comment.
+type NewComment struct {
+	Body string `json:"body" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app NewComment) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *NewComment) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewComment) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// UpdateComment defines the data needed to update a comment.
+type UpdateComment struct {
+	Body *string `json:"body" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app UpdateComment) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateComment) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateComment) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateComment(app UpdateComment) (commentbus.UpdateComment, error) {
+	var body *markdown.Markdown
+	if app.Body != nil {
+		md, err := markdown.Parse(*app.Body)
+		if err != nil {
+			return commentbus.UpdateComment{}, fmt.Errorf("parse: %w", err)
+		}
+		body = &md
+	}
+
+	bus := commentbus.UpdateComment{
+		Body: body,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,11 @@
+package commentapp
+
+import (
+	"github.com/himynamej/todo/business/domain/commentbus"
+)
+
+var orderByFields = map[string]string{
+	"comment_id":   commentbus.OrderByID,
+	"user_id":      commentbus.OrderByUserID,
+	"date_created": commentbus.OrderByDateCreated,
+}
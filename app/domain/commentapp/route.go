@@ -0,0 +1,37 @@
+package commentapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	CommentBus *commentbus.Business
+	TodoBus    *todobus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAuthorizeTodo := mid.AuthorizeTodoAssignee(cfg.AuthClient, cfg.TodoBus, auth.RuleAdminOrSubject)
+	ruleAuthorizeComment := mid.AuthorizeComment(cfg.AuthClient, cfg.CommentBus, auth.RuleAdminOrSubject)
+
+	api := newApp(cfg.CommentBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/todo/{item_id}/comments", api.query, authen, ruleAuthorizeTodo)
+	app.HandlerFunc(http.MethodPost, version, "/todo/{item_id}/comments", api.create, authen, ruleAuthorizeTodo)
+	app.HandlerFunc(http.MethodPut, version, "/comments/{comment_id}", api.update, authen, ruleAuthorizeComment)
+	app.HandlerFunc(http.MethodDelete, version, "/comments/{comment_id}", api.delete, authen, ruleAuthorizeComment)
+}
@@ -0,0 +1,233 @@
+package orgapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+// Org represents information about an individual organization.
+type Org struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Org) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrg(bus orgbus.Org) Org {
+	return Org{
+		ID:          bus.ID.String(),
+		Name:        bus.Name.String(),
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+		DateUpdated: bus.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// Orgs is a collection of organizations.
+type Orgs []Org
+
+// Encode implements the encoder interface.
+func (app Orgs) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrgs(orgs []orgbus.Org) Orgs {
+	app := make(Orgs, len(orgs))
+	for i, org := range orgs {
+		app[i] = toAppOrg(org)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewOrg defines the data needed to add a new organization. The user adding
+// it becomes its first admin.
+type NewOrg struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewOrg) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewOrg) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewOrg(app NewOrg, ownerID uuid.UUID) (orgbus.NewOrg, error) {
+	nme, err := name.Parse(app.Name)
+	if err != nil {
+		return orgbus.NewOrg{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := orgbus.NewOrg{
+		Name:    nme,
+		OwnerID: ownerID,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateOrg defines the data needed to update an organization.
+type UpdateOrg struct {
+	Name *string `json:"name"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateOrg) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateOrg) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateOrg(app UpdateOrg) (orgbus.UpdateOrg, error) {
+	var nme *name.Name
+	if app.Name != nil {
+		nm, err := name.Parse(*app.Name)
+		if err != nil {
+			return orgbus.UpdateOrg{}, fmt.Errorf("parse: %w", err)
+		}
+		nme = &nm
+	}
+
+	bus := orgbus.UpdateOrg{
+		Name: nme,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// Member represents a user that belongs to an organization.
+type Member struct {
+	OrgID       string `json:"orgId"`
+	UserID      string `json:"userId"`
+	Role        string `json:"role"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Member) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMember(bus orgbus.Member) Member {
+	return Member{
+		OrgID:       bus.OrgID.String(),
+		UserID:      bus.UserID.String(),
+		Role:        bus.Role.String(),
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// Members is a collection of members.
+type Members []Member
+
+// Encode implements the encoder interface.
+func (app Members) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMembers(mbrs []orgbus.Member) Members {
+	app := make(Members, len(mbrs))
+	for i, mbr := range mbrs {
+		app[i] = toAppMember(mbr)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewMember defines the data needed to add a user to the organization.
+type NewMember struct {
+	UserID string `json:"userId" validate:"required"`
+	Role   string `json:"role" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewMember) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewMember) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewMember(app NewMember, orgID uuid.UUID) (orgbus.NewMember, error) {
+	userID, err := uuid.Parse(app.UserID)
+	if err != nil {
+		return orgbus.NewMember{}, fmt.Errorf("parse: %w", err)
+	}
+
+	rl, err := orgbus.ParseRole(app.Role)
+	if err != nil {
+		return orgbus.NewMember{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := orgbus.NewMember{
+		OrgID:  orgID,
+		UserID: userID,
+		Role:   rl,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateMember defines the data needed to give a member a new role.
+type UpdateMember struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateMember) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateMember) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+// Package orgapp maintains the app layer api for organizations and their
+// members.
+package orgapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	orgBus  *orgbus.Business
+	userBus *userbus.Business
+}
+
+func newApp(orgBus *orgbus.Business, userBus *userbus.Business) *app {
+	return &app{
+		orgBus:  orgBus,
+		userBus: userBus,
+	}
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewOrg
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	no, err := toBusNewOrg(app, userID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	org, err := a.orgBus.Create(ctx, no)
+	if err != nil {
+		return errs.Newf(errs.Internal, "create: %s", err)
+	}
+
+	return toAppOrg(org)
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateOrg
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	uo, err := toBusUpdateOrg(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	org, err := a.current(ctx)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	updOrg, err := a.orgBus.Update(ctx, org, uo)
+	if err != nil {
+		return errs.Newf(errs.Internal, "update: orgID[%s]: %s", org.ID, err)
+	}
+
+	return toAppOrg(updOrg)
+}
+
+func (a *app) query(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	orgs, err := a.orgBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyuserid: userID[%s]: %s", userID, err)
+	}
+
+	return toAppOrgs(orgs)
+}
+
+func (a *app) queryCurrent(ctx context.Context, _ *http.Request) web.Encoder {
+	org, err := a.current(ctx)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	return toAppOrg(org)
+}
+
+// =============================================================================
+
+func (a *app) queryMembers(ctx context.Context, _ *http.Request) web.Encoder {
+	org, err := a.current(ctx)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	mbrs, err := a.orgBus.QueryMembers(ctx, org.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querymembers: orgID[%s]: %s", org.ID, err)
+	}
+
+	return toAppMembers(mbrs)
+}
+
+func (a *app) addMember(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewMember
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	org, err := a.current(ctx)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	nm, err := toBusNewMember(app, org.ID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if _, err := a.userBus.QueryByID(ctx, nm.UserID); err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("userId", err))
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", nm.UserID, err)
+	}
+
+	mbr, err := a.orgBus.AddMember(ctx, nm)
+	if err != nil {
+		return toAppError("addmember", err)
+	}
+
+	return toAppMember(mbr)
+}
+
+func (a *app) updateMember(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateMember
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rl, err := orgbus.ParseRole(app.Role)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("role", err))
+	}
+
+	mbr, err := a.member(ctx, r)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	updMbr, err := a.orgBus.UpdateMember(ctx, mbr, rl)
+	if err != nil {
+		return toAppError("updatemember", err)
+	}
+
+	return toAppMember(updMbr)
+}
+
+func (a *app) removeMember(ctx context.Context, r *http.Request) web.Encoder {
+	mbr, err := a.member(ctx, r)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	if err := a.orgBus.RemoveMember(ctx, mbr); err != nil {
+		return toAppError("removemember", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// current finds the organization the token of the request is for.
+func (a *app) current(ctx context.Context) (orgbus.Org, error) {
+	orgID, err := mid.GetOrgID(ctx)
+	if err != nil {
+		return orgbus.Org{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	org, err := a.orgBus.QueryByID(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, orgbus.ErrNotFound) {
+			return orgbus.Org{}, errs.New(errs.NotFound, orgbus.ErrNotFound)
+		}
+		return orgbus.Org{}, errs.Newf(errs.Internal, "querybyid: orgID[%s]: %s", orgID, err)
+	}
+
+	return org, nil
+}
+
+// member finds the membership of the user in the path in the organization
+// the token of the request is for.
+func (a *app) member(ctx context.Context, r *http.Request) (orgbus.Member, error) {
+	orgID, err := mid.GetOrgID(ctx)
+	if err != nil {
+		return orgbus.Member{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	userID, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return orgbus.Member{}, errs.New(errs.InvalidArgument, errs.NewFieldsError("user_id", err))
+	}
+
+	mbr, err := a.orgBus.QueryMember(ctx, orgID, userID)
+	if err != nil {
+		return orgbus.Member{}, toAppError("querymember", err).(error)
+	}
+
+	return mbr, nil
+}
+
+func toAppError(op string, err error) web.Encoder {
+	switch {
+	case errors.Is(err, orgbus.ErrMemberNotFound):
+		return errs.New(errs.NotFound, orgbus.ErrMemberNotFound)
+	case errors.Is(err, orgbus.ErrMemberExists):
+		return errs.New(errs.AlreadyExists, orgbus.ErrMemberExists)
+	case errors.Is(err, orgbus.ErrLastAdmin):
+		return errs.New(errs.FailedPrecondition, orgbus.ErrLastAdmin)
+	}
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
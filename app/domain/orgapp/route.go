@@ -0,0 +1,41 @@
+package orgapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	OrgBus     *orgbus.Business
+	UserBus    *userbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group. The organization a request
+// works on is the one its token is for.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleOrgAdmin := mid.Authorize(cfg.AuthClient, auth.RuleOrgAdmin)
+
+	api := newApp(cfg.OrgBus, cfg.UserBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/orgs", api.query, authen)
+	app.HandlerFunc(http.MethodPost, version, "/orgs", api.create, authen)
+	app.HandlerFunc(http.MethodGet, version, "/orgs/current", api.queryCurrent, authen)
+	app.HandlerFunc(http.MethodPut, version, "/orgs/current", api.update, authen, ruleOrgAdmin)
+	app.HandlerFunc(http.MethodGet, version, "/orgs/current/members", api.queryMembers, authen)
+	app.HandlerFunc(http.MethodPost, version, "/orgs/current/members", api.addMember, authen, ruleOrgAdmin)
+	app.HandlerFunc(http.MethodPut, version, "/orgs/current/members/{user_id}", api.updateMember, authen, ruleOrgAdmin)
+	app.HandlerFunc(http.MethodDelete, version, "/orgs/current/members/{user_id}", api.removeMember, authen, ruleOrgAdmin)
+}
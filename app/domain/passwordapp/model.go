@@ -0,0 +1,108 @@
+package passwordapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+)
+
+// Forgot defines the data needed to ask for a reset link.
+type Forgot struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Decode implements the decoder interface.
+func (app *Forgot) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Forgot) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusEmail(app Forgot) (mail.Address, error) {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return mail.Address{}, fmt.Errorf("parse: %w", err)
+	}
+
+	return *addr, nil
+}
+
+// Sent is the response to asking for a reset link. It looks the same whether
+// or not the email has an account.
+type Sent struct {
+	Message string `json:"message"`
+}
+
+// Encode implements the encoder interface.
+func (app Sent) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// Reset defines the data needed to reset a password with a reset link.
+type Reset struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Decode implements the decoder interface.
+func (app *Reset) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Reset) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Change defines the data needed to change a password.
+type Change struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Decode implements the decoder interface.
+func (app *Change) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Change) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Updated is the response to a password being set.
+type Updated struct {
+	UserID string `json:"userId"`
+}
+
+// Encode implements the encoder interface.
+func (app Updated) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
@@ -0,0 +1,98 @@
+// Package passwordapp maintains the app layer api for resetting and changing
+// passwords.
+package passwordapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	passwordBus *passwordbus.Business
+	userBus     *userbus.Business
+}
+
+func newApp(passwordBus *passwordbus.Business, userBus *userbus.Business) *app {
+	return &app{
+		passwordBus: passwordBus,
+		userBus:     userBus,
+	}
+}
+
+func (a *app) forgot(ctx context.Context, r *http.Request) web.Encoder {
+	var app Forgot
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	email, err := toBusEmail(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.passwordBus.Forgot(ctx, email); err != nil {
+		return errs.Newf(errs.Internal, "forgot: %s", err)
+	}
+
+	return Sent{Message: "if the email has an account, a reset link was sent to it"}
+}
+
+func (a *app) reset(ctx context.Context, r *http.Request) web.Encoder {
+	var app Reset
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.passwordBus.Reset(ctx, app.Token, app.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, passwordbus.ErrInvalidToken), errors.Is(err, passwordbus.ErrTokenExpired):
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("token", err))
+		case errors.Is(err, passwordbus.ErrInvalidPassword):
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("password", err))
+		}
+		return errs.Newf(errs.Internal, "reset: %s", err)
+	}
+
+	return Updated{UserID: usr.ID.String()}
+}
+
+func (a *app) change(ctx context.Context, r *http.Request) web.Encoder {
+	var app Change
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	usr, err = a.passwordBus.Change(ctx, usr, app.CurrentPassword, app.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, passwordbus.ErrWrongPassword):
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("currentPassword", err))
+		case errors.Is(err, passwordbus.ErrInvalidPassword):
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("password", err))
+		}
+		return errs.Newf(errs.Internal, "change: %s", err)
+	}
+
+	return Updated{UserID: usr.ID.String()}
+}
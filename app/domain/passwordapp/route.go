@@ -0,0 +1,39 @@
+package passwordapp
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/ratelimit"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log         *logger.Logger
+	PasswordBus *passwordbus.Business
+	UserBus     *userbus.Business
+	AuthClient  *authclient.Client
+	Limiter     *ratelimit.Limiter // Bounds how often a client address can ask for or use reset links
+	Proxies     []netip.Prefix     // Load balancers whose X-Forwarded-For names the client
+}
+
+// Routes adds specific routes for this group. Only changing a password needs
+// a token, the people resetting theirs can't sign in.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	rateLimit := mid.RateLimit(cfg.Limiter, cfg.Proxies)
+
+	api := newApp(cfg.PasswordBus, cfg.UserBus)
+
+	app.HandlerFunc(http.MethodPost, version, "/password/forgot", api.forgot, rateLimit)
+	app.HandlerFunc(http.MethodPost, version, "/password/reset", api.reset, rateLimit)
+	app.HandlerFunc(http.MethodPost, version, "/password/change", api.change, authen)
+}
@@ -0,0 +1,80 @@
+package quotaapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/quotabus"
+)
+
+// Usage represents the storage used by a user or a department.
+type Usage struct {
+	Scope       string `json:"scope"`
+	Owner       string `json:"owner"`
+	Bytes       int64  `json:"bytes"`
+	Quota       int64  `json:"quota"`
+	Custom      bool   `json:"custom"`
+	DateUpdated string `json:"dateUpdated,omitempty"`
+}
+
+// Encode implements the encoder interface.
+func (app Usage) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppUsage(bus quotabus.Usage) Usage {
+	app := Usage{
+		Scope:  bus.Scope,
+		Owner:  bus.Owner,
+		Bytes:  bus.Bytes,
+		Quota:  bus.Quota,
+		Custom: bus.Custom,
+	}
+
+	if !bus.DateUpdated.IsZero() {
+		app.DateUpdated = bus.DateUpdated.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+// Usages is a collection of usage.
+type Usages []Usage
+
+// Encode implements the encoder interface.
+func (app Usages) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppUsages(bus []quotabus.Usage) Usages {
+	app := make(Usages, len(bus))
+	for i, usg := range bus {
+		app[i] = toAppUsage(usg)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// UpdateQuota defines the data needed to change a quota.
+type UpdateQuota struct {
+	Quota *int64 `json:"quota" validate:"required,gte=0"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateQuota) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateQuota) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
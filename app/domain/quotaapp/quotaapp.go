@@ -0,0 +1,124 @@
+// Package quotaapp maintains the app layer api for storage quotas.
+package quotaapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	quotaBus *quotabus.Business
+	userBus  *userbus.Business
+}
+
+func newApp(quotaBus *quotabus.Business, userBus *userbus.Business) *app {
+	return &app{
+		quotaBus: quotaBus,
+		userBus:  userBus,
+	}
+}
+
+// queryMine returns the storage used by the caller and their department.
+func (a *app) queryMine(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	usgs, err := a.quotaBus.QueryByUser(ctx, usr)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyuser: userID[%s]: %s", userID, err)
+	}
+
+	return toAppUsages(usgs)
+}
+
+// queryUser returns the storage used by the user and their department.
+func (a *app) queryUser(ctx context.Context, r *http.Request) web.Encoder {
+	usr, err := mid.GetUser(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	usgs, err := a.quotaBus.QueryByUser(ctx, usr)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyuser: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppUsages(usgs)
+}
+
+// updateUser sets the quota of the user.
+func (a *app) updateUser(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateQuota
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := mid.GetUser(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	usg, err := a.quotaBus.SetUserQuota(ctx, usr, *app.Quota)
+	if err != nil {
+		return toAppError("setuserquota", err)
+	}
+
+	return toAppUsage(usg)
+}
+
+// queryDepartment returns the storage used by the department.
+func (a *app) queryDepartment(ctx context.Context, r *http.Request) web.Encoder {
+	department, err := name.Parse(web.Param(r, "department"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("department", err))
+	}
+
+	usg, err := a.quotaBus.QueryByDepartment(ctx, department)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybydepartment: department[%s]: %s", department, err)
+	}
+
+	return toAppUsage(usg)
+}
+
+// updateDepartment sets the quota of the department.
+func (a *app) updateDepartment(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateQuota
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	department, err := name.Parse(web.Param(r, "department"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("department", err))
+	}
+
+	usg, err := a.quotaBus.SetDepartmentQuota(ctx, department, *app.Quota)
+	if err != nil {
+		return toAppError("setdepartmentquota", err)
+	}
+
+	return toAppUsage(usg)
+}
+
+func toAppError(op string, err error) web.Encoder {
+	if errors.Is(err, quotabus.ErrInvalidQuota) {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("quota", err))
+	}
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
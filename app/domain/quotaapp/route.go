@@ -0,0 +1,39 @@
+package quotaapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	QuotaBus   *quotabus.Business
+	UserBus    *userbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+	ruleAuthorizeUser := mid.AuthorizeUser(cfg.AuthClient, cfg.UserBus, auth.RuleAdminOrSubject)
+	ruleAuthorizeAdmin := mid.AuthorizeUser(cfg.AuthClient, cfg.UserBus, auth.RuleAdminOnly)
+
+	api := newApp(cfg.QuotaBus, cfg.UserBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/storage", api.queryMine, authen)
+	app.HandlerFunc(http.MethodGet, version, "/storage/users/{user_id}", api.queryUser, authen, ruleAuthorizeUser)
+	app.HandlerFunc(http.MethodPut, version, "/storage/users/{user_id}", api.updateUser, authen, ruleAuthorizeAdmin)
+	app.HandlerFunc(http.MethodGet, version, "/storage/departments/{department}", api.queryDepartment, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodPut, version, "/storage/departments/{department}", api.updateDepartment, authen, ruleAdmin)
+}
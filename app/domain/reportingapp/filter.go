@@ -0,0 +1,85 @@
+package reportingapp
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+// defaultRange is how far back a report looks when no start date is given.
+const defaultRange = 30 * 24 * time.Hour
+
+func parseQueryParams(r *http.Request) (queryParams, error) {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		UserID:    values.Get("user_id"),
+		StartDate: values.Get("start_date"),
+		EndDate:   values.Get("end_date"),
+		Interval:  values.Get("interval"),
+		GroupBy:   values.Get("group_by"),
+	}
+
+	return filter, nil
+}
+
+// parseFilter builds the report filter. Admins see every todo item and may
+// narrow the report to one user, everyone else only sees the items they own
+// or are assigned.
+func parseFilter(ctx context.Context, qp queryParams) (reportingbus.QueryFilter, error) {
+	var filter reportingbus.QueryFilter
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return reportingbus.QueryFilter{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	switch {
+	case !slices.Contains(mid.GetClaims(ctx).Roles, role.Admin.String()):
+		filter.UserID = &userID
+
+	case qp.UserID != "":
+		id, err := uuid.Parse(qp.UserID)
+		if err != nil {
+			return reportingbus.QueryFilter{}, errs.NewFieldsError("user_id", err)
+		}
+		filter.UserID = &id
+	}
+
+	end := time.Now()
+	if qp.EndDate != "" {
+		end, err = parseDate(qp.EndDate)
+		if err != nil {
+			return reportingbus.QueryFilter{}, errs.NewFieldsError("end_date", err)
+		}
+	}
+	filter.EndDate = &end
+
+	start := end.Add(-defaultRange)
+	if qp.StartDate != "" {
+		start, err = parseDate(qp.StartDate)
+		if err != nil {
+			return reportingbus.QueryFilter{}, errs.NewFieldsError("start_date", err)
+		}
+	}
+	filter.StartDate = &start
+
+	return filter, nil
+}
+
+// parseDate accepts a calendar date, read as midnight UTC, or an RFC3339
+// timestamp.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
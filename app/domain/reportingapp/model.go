@@ -0,0 +1,97 @@
+package reportingapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/reportingbus"
+)
+
+type queryParams struct {
+	UserID    string
+	StartDate string
+	EndDate   string
+	Interval  string
+	GroupBy   string
+}
+
+// =============================================================================
+
+// Throughput represents the todo items created and completed in a period.
+type Throughput struct {
+	Period    string `json:"period"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+// Throughputs is a collection of throughput periods.
+type Throughputs []Throughput
+
+// Encode implements the encoder interface.
+func (app Throughputs) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppThroughputs(bus []reportingbus.Throughput) Throughputs {
+	app := make(Throughputs, len(bus))
+	for i, tp := range bus {
+		app[i] = Throughput{
+			Period:    tp.Period.Format(time.DateOnly),
+			Created:   tp.Created,
+			Completed: tp.Completed,
+		}
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// CompletionTime represents how long todo items took to complete.
+type CompletionTime struct {
+	Completed     int     `json:"completed"`
+	MedianSeconds float64 `json:"medianSeconds"`
+}
+
+// Encode implements the encoder interface.
+func (app CompletionTime) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppCompletionTime(bus reportingbus.CompletionTime) CompletionTime {
+	return CompletionTime{
+		Completed:     bus.Completed,
+		MedianSeconds: bus.Median.Seconds(),
+	}
+}
+
+// =============================================================================
+
+// Bucket represents a count of todo items sharing a key.
+type Bucket struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Buckets is a collection of buckets.
+type Buckets []Bucket
+
+// Encode implements the encoder interface.
+func (app Buckets) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppBuckets(bus []reportingbus.Bucket) Buckets {
+	app := make(Buckets, len(bus))
+	for i, bkt := range bus {
+		app[i] = Bucket{
+			Key:   bkt.Key,
+			Count: bkt.Count,
+		}
+	}
+
+	return app
+}
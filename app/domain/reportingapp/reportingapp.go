@@ -0,0 +1,125 @@
+// Package reportingapp maintains the app layer api for the reporting domain.
+package reportingapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	reportingBus *reportingbus.Business
+}
+
+func newApp(reportingBus *reportingbus.Business) *app {
+	return &app{
+		reportingBus: reportingBus,
+	}
+}
+
+func (a *app) throughput(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(ctx, qp)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	interval := reportingbus.IntervalDay
+	if qp.Interval != "" {
+		interval, err = reportingbus.ParseInterval(qp.Interval)
+		if err != nil {
+			return errs.NewFieldsError("interval", err)
+		}
+	}
+
+	tps, err := a.reportingBus.Throughput(ctx, filter, interval)
+	if err != nil {
+		return toAppError("throughput", err)
+	}
+
+	return toAppThroughputs(tps)
+}
+
+func (a *app) completionTime(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(ctx, qp)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	ct, err := a.reportingBus.CompletionTime(ctx, filter)
+	if err != nil {
+		return toAppError("completiontime", err)
+	}
+
+	return toAppCompletionTime(ct)
+}
+
+func (a *app) overdue(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(ctx, qp)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	// An overdue item is due before now, so the range only needs a start.
+	filter.EndDate = nil
+
+	groupBy := reportingbus.GroupByList
+	if qp.GroupBy != "" {
+		groupBy, err = reportingbus.ParseGroupBy(qp.GroupBy)
+		if err != nil {
+			return errs.NewFieldsError("group_by", err)
+		}
+	}
+
+	bkts, err := a.reportingBus.OverdueBacklog(ctx, filter, groupBy)
+	if err != nil {
+		return toAppError("overduebacklog", err)
+	}
+
+	return toAppBuckets(bkts)
+}
+
+func (a *app) labels(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(ctx, qp)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	bkts, err := a.reportingBus.LabelDistribution(ctx, filter)
+	if err != nil {
+		return toAppError("labeldistribution", err)
+	}
+
+	return toAppBuckets(bkts)
+}
+
+func toAppError(op string, err error) web.Encoder {
+	if errors.Is(err, reportingbus.ErrInvalidRange) {
+		return errs.NewFieldsError("start_date", reportingbus.ErrInvalidRange)
+	}
+
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
@@ -0,0 +1,32 @@
+package reportingapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log          *logger.Logger
+	ReportingBus *reportingbus.Business
+	AuthClient   *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+
+	api := newApp(cfg.ReportingBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/reports/throughput", api.throughput, authen)
+	app.HandlerFunc(http.MethodGet, version, "/reports/completion-time", api.completionTime, authen)
+	app.HandlerFunc(http.MethodGet, version, "/reports/overdue", api.overdue, authen)
+	app.HandlerFunc(http.MethodGet, version, "/reports/labels", api.labels, authen)
+}
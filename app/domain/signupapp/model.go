@@ -0,0 +1,89 @@
+package signupapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+// Accepted represents a signup that was taken. It reads the same whether or
+// not the email already had an account.
+type Accepted struct {
+	Email string `json:"email"`
+}
+
+// Encode implements the encoder interface.
+func (app Accepted) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Verified represents the account a verified signup enabled.
+type Verified struct {
+	UserID  string `json:"userId"`
+	Email   string `json:"email"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Encode implements the encoder interface.
+func (app Verified) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppVerified(bus userbus.User) Verified {
+	return Verified{
+		UserID:  bus.ID.String(),
+		Email:   bus.Email.Address,
+		Enabled: bus.Enabled,
+	}
+}
+
+// =============================================================================
+
+// NewSignup defines the data needed to sign up.
+type NewSignup struct {
+	Name            string `json:"name" validate:"required"`
+	Email           string `json:"email" validate:"required,email"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewSignup) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewSignup) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewSignup(app NewSignup) (signupbus.NewSignup, error) {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return signupbus.NewSignup{}, fmt.Errorf("parse: %w", err)
+	}
+
+	nme, err := name.Parse(app.Name)
+	if err != nil {
+		return signupbus.NewSignup{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := signupbus.NewSignup{
+		Name:     nme,
+		Email:    *addr,
+		Password: app.Password,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,33 @@
+package signupapp
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/ratelimit"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log       *logger.Logger
+	SignupBus *signupbus.Business
+	Limiter   *ratelimit.Limiter // Bounds how often a client address can sign up
+	Proxies   []netip.Prefix     // Load balancers whose X-Forwarded-For names the client
+}
+
+// Routes adds specific routes for this group. Neither route needs a token,
+// since the people using them have no account yet.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	rateLimit := mid.RateLimit(cfg.Limiter, cfg.Proxies)
+
+	api := newApp(cfg.SignupBus)
+
+	app.HandlerFunc(http.MethodPost, version, "/signup", api.signup, rateLimit)
+	app.HandlerFunc(http.MethodGet, version, "/signup/verify", api.verify)
+}
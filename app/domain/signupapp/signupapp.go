@@ -0,0 +1,63 @@
+// Package signupapp maintains the app layer api for self-service signup.
+package signupapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	signupBus *signupbus.Business
+}
+
+func newApp(signupBus *signupbus.Business) *app {
+	return &app{
+		signupBus: signupBus,
+	}
+}
+
+func (a *app) signup(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewSignup
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ns, err := toBusNewSignup(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.signupBus.Signup(ctx, ns); err != nil {
+		if errors.Is(err, signupbus.ErrDomainNotAllowed) {
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("email", signupbus.ErrDomainNotAllowed))
+		}
+		return errs.Newf(errs.Internal, "signup: %s", err)
+	}
+
+	return Accepted{Email: ns.Email.Address}
+}
+
+func (a *app) verify(ctx context.Context, r *http.Request) web.Encoder {
+	tkn := r.URL.Query().Get("token")
+	if tkn == "" {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("token", errors.New("missing token")))
+	}
+
+	usr, err := a.signupBus.Verify(ctx, tkn)
+	if err != nil {
+		switch {
+		case errors.Is(err, signupbus.ErrInvalidToken), errors.Is(err, signupbus.ErrTokenExpired):
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("token", err))
+		case errors.Is(err, signupbus.ErrAlreadyVerified):
+			return errs.New(errs.FailedPrecondition, signupbus.ErrAlreadyVerified)
+		}
+		return errs.Newf(errs.Internal, "verify: %s", err)
+	}
+
+	return toAppVerified(usr)
+}
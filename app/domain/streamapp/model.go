@@ -0,0 +1,37 @@
+package streamapp
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/streambus"
+)
+
+// Event represents a change made to a todo item. Data holds the item as it
+// was after the change, or the assignment for assigned events.
+type Event struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	TodoID      string          `json:"todoId"`
+	List        string          `json:"list,omitempty"`
+	Data        json.RawMessage `json:"data"`
+	DateCreated string          `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Event) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppEvent(bus streambus.Event) Event {
+	return Event{
+		ID:          strconv.FormatInt(bus.ID, 10),
+		Type:        bus.Type,
+		TodoID:      bus.TodoID.String(),
+		List:        bus.List,
+		Data:        bus.Data,
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+	}
+}
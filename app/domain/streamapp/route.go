@@ -0,0 +1,34 @@
+package streamapp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	StreamBus  *streambus.Business
+	AuthClient *authclient.Client
+	Heartbeat  time.Duration   // Default: 15s
+	Shutdown   <-chan struct{} // Closed when the server starts shutting down
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleOrgAdmin := mid.Authorize(cfg.AuthClient, auth.RuleOrgAdmin)
+
+	api := newApp(cfg.Log, cfg.StreamBus, cfg.Heartbeat, cfg.Shutdown)
+	app.StreamHandlerFunc(http.MethodGet, version, "/todo/stream", api.stream, authen)
+	app.StreamHandlerFunc(http.MethodGet, version, "/todo/stream/org", api.streamOrg, authen, ruleOrgAdmin)
+}
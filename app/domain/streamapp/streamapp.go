@@ -0,0 +1,218 @@
+// Package streamapp maintains the app layer api for following todo changes
+// as they happen.
+package streamapp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// backlog is the most events a resuming client is sent to catch up. A client
+// further behind is told to reset instead.
+const backlog = 500
+
+// eventReset tells the client it missed events that can no longer be sent,
+// so it has to fetch the items again.
+const eventReset = "reset"
+
+type app struct {
+	log       *logger.Logger
+	streamBus *streambus.Business
+	heartbeat time.Duration
+	shutdown  <-chan struct{}
+}
+
+func newApp(log *logger.Logger, streamBus *streambus.Business, heartbeat time.Duration, shutdown <-chan struct{}) *app {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+
+	return &app{
+		log:       log,
+		streamBus: streamBus,
+		heartbeat: heartbeat,
+		shutdown:  shutdown,
+	}
+}
+
+// stream sends the changes made to the items the caller owns or is assigned
+// as server-sent events. A client that sends the id of the last event it
+// received, in the Last-Event-ID header or the lastEventId parameter, is
+// first sent what it missed.
+func (a *app) stream(ctx context.Context, r *http.Request, stream *web.Stream) web.Encoder {
+	filter, err := parseFilter(ctx, r)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+	filter.UserID = &userID
+
+	return a.follow(ctx, r, stream, filter)
+}
+
+// streamOrg works like stream for the changes made to every item in the
+// organization, which only its admins may follow.
+func (a *app) streamOrg(ctx context.Context, r *http.Request, stream *web.Stream) web.Encoder {
+	filter, err := parseFilter(ctx, r)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	return a.follow(ctx, r, stream, filter)
+}
+
+// follow sends the events that match the filter until the client goes away
+// or the server shuts down.
+func (a *app) follow(ctx context.Context, r *http.Request, stream *web.Stream, filter streambus.Filter) web.Encoder {
+	lastID, resuming, err := parseLastEventID(r)
+	if err != nil {
+		return errs.NewFieldsError("lastEventId", err)
+	}
+
+	// Subscribe before catching up so nothing recorded in between is missed.
+	// Whatever arrives twice is skipped by id.
+
+	sub, err := a.streamBus.Subscribe(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Unavailable, "subscribe: %s", err)
+	}
+	defer sub.Close()
+
+	if err := stream.Start(); err != nil {
+		return errs.Newf(errs.Internal, "start: %s", err)
+	}
+
+	if resuming {
+		if lastID, err = a.catchUp(ctx, stream, filter, lastID); err != nil {
+			return errs.Newf(errs.Internal, "catchup: %s", err)
+		}
+	}
+
+	ticker := time.NewTicker(a.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		// Streams never finish on their own, so they are ended for the
+		// server to shut down. Clients reconnect to another instance.
+		case <-a.shutdown:
+			return nil
+
+		case <-ticker.C:
+			if err := stream.Heartbeat(); err != nil {
+				return errs.Newf(errs.Internal, "heartbeat: %s", err)
+			}
+
+		case evt, ok := <-sub.Events():
+			if !ok {
+				a.log.Info(ctx, "stream: subscription ended", "lastEventID", lastID)
+				return nil
+			}
+
+			if evt.ID <= lastID {
+				continue
+			}
+
+			if err := send(stream, evt); err != nil {
+				return errs.Newf(errs.Internal, "send: eventID[%d]: %s", evt.ID, err)
+			}
+			lastID = evt.ID
+		}
+	}
+}
+
+// catchUp sends the events recorded after the one the client saw last and
+// returns the id of the last event sent. A client too far behind is told to
+// reset and is only sent new events from then on.
+func (a *app) catchUp(ctx context.Context, stream *web.Stream, filter streambus.Filter, lastID int64) (int64, error) {
+	resumable, err := a.streamBus.Resumable(ctx, lastID)
+	if err != nil {
+		return 0, err
+	}
+
+	var evts []streambus.Event
+	if resumable {
+		if evts, err = a.streamBus.QueryAfter(ctx, filter, lastID, backlog+1); err != nil {
+			return 0, err
+		}
+	}
+
+	if !resumable || len(evts) > backlog {
+		if err := stream.Send("", eventReset, []byte("{}")); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	for _, evt := range evts {
+		if err := send(stream, evt); err != nil {
+			return 0, err
+		}
+		lastID = evt.ID
+	}
+
+	return lastID, nil
+}
+
+func send(stream *web.Stream, evt streambus.Event) error {
+	app := toAppEvent(evt)
+
+	data, _, err := app.Encode()
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(app.ID, app.Type, data)
+}
+
+// =============================================================================
+
+func parseFilter(ctx context.Context, r *http.Request) (streambus.Filter, error) {
+	orgID, err := mid.GetOrgID(ctx)
+	if err != nil {
+		return streambus.Filter{}, err
+	}
+
+	filter := streambus.Filter{
+		OrgID: &orgID,
+	}
+
+	if list := r.URL.Query().Get("list"); list != "" {
+		filter.List = &list
+	}
+
+	return filter, nil
+}
+
+func parseLastEventID(r *http.Request) (int64, bool, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("lastEventId")
+	}
+
+	if v == "" {
+		return 0, false, nil
+	}
+
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
@@ -0,0 +1,340 @@
+package templateapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+)
+
+// Template represents a named set of todo items that are created together.
+// Placeholders lists the assignees that must be given a user whenever the
+// template is used.
+type Template struct {
+	ID           string   `json:"id"`
+	UserID       string   `json:"userId"`
+	Name         string   `json:"name"`
+	Items        []Item   `json:"items"`
+	Placeholders []string `json:"placeholders"`
+	DateCreated  string   `json:"dateCreated"`
+	DateUpdated  string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Template) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppTemplate(bus templatebus.Template) Template {
+	items := make([]Item, len(bus.Items))
+	for i, item := range bus.Items {
+		items[i] = Item{
+			Description: item.Description,
+			DueDays:     item.DueDays,
+			Priority:    item.Priority.String(),
+			List:        item.List.String(),
+			Labels:      label.ParseToString(item.Labels),
+			Checklist:   item.Checklist,
+			Assignee:    item.Assignee,
+		}
+	}
+
+	placeholders := bus.Placeholders()
+	if placeholders == nil {
+		placeholders = []string{}
+	}
+
+	return Template{
+		ID:           bus.ID.String(),
+		UserID:       bus.UserID.String(),
+		Name:         bus.Name,
+		Items:        items,
+		Placeholders: placeholders,
+		DateCreated:  bus.DateCreated.Format(time.RFC3339),
+		DateUpdated:  bus.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// Templates is a collection of templates.
+type Templates []Template
+
+// Encode implements the encoder interface.
+func (app Templates) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppTemplates(tmpls []templatebus.Template) Templates {
+	app := make(Templates, len(tmpls))
+	for i, tmpl := range tmpls {
+		app[i] = toAppTemplate(tmpl)
+	}
+
+	return app
+}
+
+// Item represents one todo item of a template. DueDays is the number of
+// days after the start date the item is due, before it when negative, and
+// the item has no due date without it. Assignee names a placeholder, such as
+// "manager", that is given a user when the template is used.
+type Item struct {
+	Description string   `json:"description" validate:"required"`
+	DueDays     *int     `json:"dueDays,omitempty" validate:"omitempty,min=-3650,max=3650"`
+	Priority    string   `json:"priority,omitempty"`
+	List        string   `json:"list,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Checklist   []string `json:"checklist,omitempty" validate:"omitempty,max=100,dive,required,max=200"`
+	Assignee    string   `json:"assignee,omitempty"`
+}
+
+func toBusItems(app []Item) ([]templatebus.Item, error) {
+	if app == nil {
+		return nil, nil
+	}
+
+	bus := make([]templatebus.Item, len(app))
+	for i, item := range app {
+		var pri priority.Priority
+		if item.Priority != "" {
+			var err error
+			if pri, err = priority.Parse(item.Priority); err != nil {
+				return nil, fmt.Errorf("item[%d]: %w", i, err)
+			}
+		}
+
+		list, err := name.ParseNull(item.List)
+		if err != nil {
+			return nil, fmt.Errorf("item[%d]: %w", i, err)
+		}
+
+		labels, err := label.ParseMany(item.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("item[%d]: %w", i, err)
+		}
+
+		bus[i] = templatebus.Item{
+			Description: item.Description,
+			DueDays:     item.DueDays,
+			Priority:    pri,
+			List:        list,
+			Labels:      labels,
+			Checklist:   item.Checklist,
+			Assignee:    item.Assignee,
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// NewTemplate defines the data needed to add a new template.
+type NewTemplate struct {
+	Name  string `json:"name" validate:"required,max=100"`
+	Items []Item `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// Encode implements the encoder interface.
+func (app NewTemplate) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *NewTemplate) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewTemplate) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// UpdateTemplate defines the data needed to update a template. A set of
+// items replaces all of the items of the template.
+type UpdateTemplate struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Items []Item  `json:"items" validate:"omitempty,min=1,max=200,dive"`
+}
+
+// Encode implements the encoder interface.
+func (app UpdateTemplate) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateTemplate) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateTemplate) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Instantiate defines the data needed to use a template. The start date is
+// a calendar date such as "2024-05-01" and every placeholder of the template
+// is given the id of a user in assignees.
+type Instantiate struct {
+	StartDate string            `json:"startDate" validate:"required"`
+	Assignees map[string]string `json:"assignees" validate:"omitempty,dive,uuid"`
+}
+
+// Encode implements the encoder interface.
+func (app Instantiate) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *Instantiate) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Instantiate) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusInstantiation(app Instantiate, userID uuid.UUID) (templatebus.Instantiation, error) {
+	start, err := time.Parse(time.DateOnly, app.StartDate)
+	if err != nil {
+		return templatebus.Instantiation{}, fmt.Errorf("startDate: expected %s: %q", time.DateOnly, app.StartDate)
+	}
+
+	assignees := make(map[string]uuid.UUID, len(app.Assignees))
+	for placeholder, id := range app.Assignees {
+		assigneeID, err := uuid.Parse(id)
+		if err != nil {
+			return templatebus.Instantiation{}, fmt.Errorf("assignees: %s: %w", placeholder, err)
+		}
+		assignees[placeholder] = assigneeID
+	}
+
+	inst := templatebus.Instantiation{
+		UserID:    userID,
+		Start:     start,
+		Assignees: assignees,
+	}
+
+	return inst, nil
+}
+
+// =============================================================================
+
+// TodoItem represents a todo item created from a template, or one a preview
+// shows would be created, in which case it has no id or status yet.
+type TodoItem struct {
+	ID          string   `json:"id,omitempty"`
+	UserID      string   `json:"userId"`
+	AssigneeID  string   `json:"assigneeId,omitempty"`
+	Description string   `json:"description"`
+	DueDate     string   `json:"dueDate,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Priority    string   `json:"priority"`
+	List        string   `json:"list,omitempty"`
+	Labels      []string `json:"labels"`
+	Checklist   []string `json:"checklist"`
+}
+
+// TodoItems is a collection of todo items.
+type TodoItems []TodoItem
+
+// Encode implements the encoder interface.
+func (app TodoItems) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppPreview(nts []todobus.NewTodoItem) TodoItems {
+	app := make(TodoItems, len(nts))
+	for i, nt := range nts {
+		pri := nt.Priority
+		if pri.IsZero() {
+			pri = priority.Default
+		}
+
+		app[i] = TodoItem{
+			UserID:      nt.UserID.String(),
+			AssigneeID:  uuidString(nt.AssigneeID),
+			Description: nt.Description,
+			DueDate:     dateString(nt.DueDate),
+			Priority:    pri.String(),
+			List:        nt.List.String(),
+			Labels:      label.ParseToString(nt.Labels),
+			Checklist:   checklistTexts(nt.Checklist),
+		}
+	}
+
+	return app
+}
+
+func toAppTodoItems(items []todobus.TodoItem) TodoItems {
+	app := make(TodoItems, len(items))
+	for i, item := range items {
+		app[i] = TodoItem{
+			ID:          item.ID.String(),
+			UserID:      item.UserID.String(),
+			AssigneeID:  uuidString(item.AssigneeID),
+			Description: item.Description,
+			DueDate:     dateString(item.DueDate),
+			Status:      item.Status.String(),
+			Priority:    item.Priority.String(),
+			List:        item.List.String(),
+			Labels:      label.ParseToString(item.Labels),
+			Checklist:   checklistTexts(item.Checklist),
+		}
+	}
+
+	return app
+}
+
+func checklistTexts(checklist []todobus.ChecklistItem) []string {
+	texts := make([]string, len(checklist))
+	for i, ci := range checklist {
+		texts[i] = ci.Text
+	}
+
+	return texts
+}
+
+// dateString returns the calendar date of an all-day due date, which is all
+// a template creates, or an empty string when there is none.
+func dateString(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
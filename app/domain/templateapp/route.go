@@ -0,0 +1,40 @@
+package templateapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log            *logger.Logger
+	TemplateBus    *templatebus.Business
+	IdempotencyBus *idempotencybus.Business
+	AuthClient     *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAuthorizeTemplate := mid.AuthorizeTemplate(cfg.AuthClient, cfg.TemplateBus, auth.RuleAdminOrSubject)
+	idempotent := mid.Idempotent(cfg.Log, cfg.IdempotencyBus, mid.IdempotencyMaxBody)
+
+	api := newApp(cfg.TemplateBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/templates", api.query, authen)
+	app.HandlerFunc(http.MethodPost, version, "/templates", api.create, authen)
+	app.HandlerFunc(http.MethodGet, version, "/templates/{template_id}", api.queryByID, authen, ruleAuthorizeTemplate)
+	app.HandlerFunc(http.MethodPut, version, "/templates/{template_id}", api.update, authen, ruleAuthorizeTemplate)
+	app.HandlerFunc(http.MethodDelete, version, "/templates/{template_id}", api.delete, authen, ruleAuthorizeTemplate)
+	app.HandlerFunc(http.MethodPost, version, "/templates/{template_id}/preview", api.preview, authen, ruleAuthorizeTemplate)
+	app.HandlerFunc(http.MethodPost, version, "/templates/{template_id}/instantiate", api.instantiate, authen, ruleAuthorizeTemplate, idempotent)
+}
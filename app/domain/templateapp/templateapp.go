@@ -0,0 +1,193 @@
+// Package templateapp maintains the app layer api for todo templates.
+package templateapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	templateBus *templatebus.Business
+}
+
+func newApp(templateBus *templatebus.Business) *app {
+	return &app{
+		templateBus: templateBus,
+	}
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewTemplate
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	items, err := toBusItems(app.Items)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("items", err))
+	}
+
+	nt := templatebus.NewTemplate{
+		UserID: userID,
+		Name:   app.Name,
+		Items:  items,
+	}
+
+	tmpl, err := a.templateBus.Create(ctx, nt)
+	if err != nil {
+		return toAppError("create", err)
+	}
+
+	return toAppTemplate(tmpl)
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateTemplate
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tmpl, err := mid.GetTemplate(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "template missing in context: %s", err)
+	}
+
+	items, err := toBusItems(app.Items)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("items", err))
+	}
+
+	ut := templatebus.UpdateTemplate{
+		Name:  app.Name,
+		Items: items,
+	}
+
+	updTmpl, err := a.templateBus.Update(ctx, tmpl, ut)
+	if err != nil {
+		return toAppError(fmt.Sprintf("update: templateID[%s]", tmpl.ID), err)
+	}
+
+	return toAppTemplate(updTmpl)
+}
+
+func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
+	tmpl, err := mid.GetTemplate(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "template missing in context: %s", err)
+	}
+
+	if err := a.templateBus.Delete(ctx, tmpl); err != nil {
+		return errs.Newf(errs.Internal, "delete: templateID[%s]: %s", tmpl.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) query(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	tmpls, err := a.templateBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyuserid: userID[%s]: %s", userID, err)
+	}
+
+	return toAppTemplates(tmpls)
+}
+
+func (a *app) queryByID(ctx context.Context, _ *http.Request) web.Encoder {
+	tmpl, err := mid.GetTemplate(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "template missing in context: %s", err)
+	}
+
+	return toAppTemplate(tmpl)
+}
+
+func (a *app) preview(ctx context.Context, r *http.Request) web.Encoder {
+	tmpl, inst, err := a.instantiation(ctx, r)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	nts, err := a.templateBus.Preview(ctx, tmpl, inst)
+	if err != nil {
+		return toAppError("preview", err)
+	}
+
+	return toAppPreview(nts)
+}
+
+func (a *app) instantiate(ctx context.Context, r *http.Request) web.Encoder {
+	tmpl, inst, err := a.instantiation(ctx, r)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	items, err := a.templateBus.Instantiate(ctx, tmpl, inst)
+	if err != nil {
+		return toAppError("instantiate", err)
+	}
+
+	return toAppTodoItems(items)
+}
+
+// instantiation reads how the template in the context is to be used. The
+// items it creates belong to the caller.
+func (a *app) instantiation(ctx context.Context, r *http.Request) (templatebus.Template, templatebus.Instantiation, error) {
+	var app Instantiate
+	if err := web.Decode(r, &app); err != nil {
+		return templatebus.Template{}, templatebus.Instantiation{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	tmpl, err := mid.GetTemplate(ctx)
+	if err != nil {
+		return templatebus.Template{}, templatebus.Instantiation{}, errs.Newf(errs.Internal, "template missing in context: %s", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return templatebus.Template{}, templatebus.Instantiation{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	inst, err := toBusInstantiation(app, userID)
+	if err != nil {
+		return templatebus.Template{}, templatebus.Instantiation{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	return tmpl, inst, nil
+}
+
+func toAppError(op string, err error) web.Encoder {
+	switch {
+	case errors.Is(err, templatebus.ErrNoItems),
+		errors.Is(err, templatebus.ErrTooManyItems),
+		errors.Is(err, templatebus.ErrInvalidPlaceholder):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("items", err))
+	case errors.Is(err, templatebus.ErrMissingAssignee),
+		errors.Is(err, templatebus.ErrUnknownPlaceholder),
+		errors.Is(err, todobus.ErrAssigneeNotFound),
+		errors.Is(err, todobus.ErrAssigneeDisabled),
+		errors.Is(err, todobus.ErrAssigneeNotMember):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("assignees", err))
+	case errors.Is(err, templatebus.ErrMissingStartDate):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("startDate", err))
+	}
+
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
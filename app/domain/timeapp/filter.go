@@ -0,0 +1,78 @@
+package timeapp
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+// defaultRange is how far back a report looks when no start date is given.
+const defaultRange = 30 * 24 * time.Hour
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	return queryParams{
+		UserID:    values.Get("user_id"),
+		StartDate: values.Get("start_date"),
+		EndDate:   values.Get("end_date"),
+		GroupBy:   values.Get("group_by"),
+	}
+}
+
+// parseFilter builds the report filter. Admins see the time of every user
+// and may narrow the report to one of them, everyone else only sees the time
+// they logged themselves.
+func parseFilter(ctx context.Context, qp queryParams) (timebus.ReportFilter, error) {
+	var filter timebus.ReportFilter
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return timebus.ReportFilter{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	switch {
+	case !slices.Contains(mid.GetClaims(ctx).Roles, role.Admin.String()):
+		filter.UserID = &userID
+
+	case qp.UserID != "":
+		id, err := uuid.Parse(qp.UserID)
+		if err != nil {
+			return timebus.ReportFilter{}, errs.NewFieldsError("user_id", err)
+		}
+		filter.UserID = &id
+	}
+
+	filter.EndDate = time.Now()
+	if qp.EndDate != "" {
+		if filter.EndDate, err = parseDate(qp.EndDate); err != nil {
+			return timebus.ReportFilter{}, errs.NewFieldsError("end_date", err)
+		}
+	}
+
+	filter.StartDate = filter.EndDate.Add(-defaultRange)
+	if qp.StartDate != "" {
+		if filter.StartDate, err = parseDate(qp.StartDate); err != nil {
+			return timebus.ReportFilter{}, errs.NewFieldsError("start_date", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseDate accepts a calendar date, read as midnight UTC, or an RFC3339
+// timestamp.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
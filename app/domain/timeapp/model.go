@@ -0,0 +1,265 @@
+package timeapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/types/money"
+)
+
+type queryParams struct {
+	UserID    string
+	StartDate string
+	EndDate   string
+	GroupBy   string
+}
+
+// =============================================================================
+
+// Entry represents time a user spent on a todo item. A running timer has no
+// end yet and counts its minutes up to now. The amount is what the entry
+// bills at its hourly rate.
+type Entry struct {
+	ID          string   `json:"id"`
+	TodoID      string   `json:"todoId"`
+	UserID      string   `json:"userId"`
+	Start       string   `json:"start"`
+	End         string   `json:"end,omitempty"`
+	Running     bool     `json:"running"`
+	Note        string   `json:"note,omitempty"`
+	HourlyRate  *float64 `json:"hourlyRate,omitempty"`
+	Minutes     int      `json:"minutes"`
+	Amount      float64  `json:"amount"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Entry) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppEntry(bus timebus.Entry) Entry {
+	app := Entry{
+		ID:          bus.ID.String(),
+		TodoID:      bus.TodoID.String(),
+		UserID:      bus.UserID.String(),
+		Start:       bus.Start.Format(time.RFC3339),
+		Running:     bus.Running(),
+		Note:        bus.Note,
+		Minutes:     bus.Minutes().Value(),
+		Amount:      bus.Amount().Value(),
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+		DateUpdated: bus.DateUpdated.Format(time.RFC3339),
+	}
+
+	if !bus.Running() {
+		app.End = bus.End.Format(time.RFC3339)
+	}
+
+	if bus.Rate != nil {
+		rate := bus.Rate.Value()
+		app.HourlyRate = &rate
+	}
+
+	return app
+}
+
+// Entries is a collection of time entries.
+type Entries []Entry
+
+// Encode implements the encoder interface.
+func (app Entries) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppEntries(bus []timebus.Entry) Entries {
+	app := make(Entries, len(bus))
+	for i, e := range bus {
+		app[i] = toAppEntry(e)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewTimer defines the data needed to start a timer on a todo item.
+type NewTimer struct {
+	Note       string   `json:"note" validate:"max=1000"`
+	HourlyRate *float64 `json:"hourlyRate"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewTimer) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewTimer) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// NewEntry defines the data needed to log time spent on a todo item. The
+// start and end are RFC3339 timestamps.
+type NewEntry struct {
+	Start      string   `json:"start" validate:"required"`
+	End        string   `json:"end" validate:"required"`
+	Note       string   `json:"note" validate:"max=1000"`
+	HourlyRate *float64 `json:"hourlyRate"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewEntry) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewEntry) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewEntry(app NewEntry) (timebus.NewEntry, error) {
+	start, err := time.Parse(time.RFC3339, app.Start)
+	if err != nil {
+		return timebus.NewEntry{}, fmt.Errorf("parse: start: %w", err)
+	}
+
+	end, err := time.Parse(time.RFC3339, app.End)
+	if err != nil {
+		return timebus.NewEntry{}, fmt.Errorf("parse: end: %w", err)
+	}
+
+	rate, err := toBusRate(app.HourlyRate)
+	if err != nil {
+		return timebus.NewEntry{}, fmt.Errorf("parse: hourlyRate: %w", err)
+	}
+
+	bus := timebus.NewEntry{
+		Start: start,
+		End:   end,
+		Note:  app.Note,
+		Rate:  rate,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateEntry defines the data needed to update a time entry. Giving a
+// running timer an end stops it.
+type UpdateEntry struct {
+	Start      *string  `json:"start"`
+	End        *string  `json:"end"`
+	Note       *string  `json:"note" validate:"omitempty,max=1000"`
+	HourlyRate *float64 `json:"hourlyRate"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateEntry) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateEntry) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateEntry(app UpdateEntry) (timebus.UpdateEntry, error) {
+	var start *time.Time
+	if app.Start != nil {
+		t, err := time.Parse(time.RFC3339, *app.Start)
+		if err != nil {
+			return timebus.UpdateEntry{}, fmt.Errorf("parse: start: %w", err)
+		}
+		start = &t
+	}
+
+	var end *time.Time
+	if app.End != nil {
+		t, err := time.Parse(time.RFC3339, *app.End)
+		if err != nil {
+			return timebus.UpdateEntry{}, fmt.Errorf("parse: end: %w", err)
+		}
+		end = &t
+	}
+
+	rate, err := toBusRate(app.HourlyRate)
+	if err != nil {
+		return timebus.UpdateEntry{}, fmt.Errorf("parse: hourlyRate: %w", err)
+	}
+
+	bus := timebus.UpdateEntry{
+		Start: start,
+		End:   end,
+		Note:  app.Note,
+		Rate:  rate,
+	}
+
+	return bus, nil
+}
+
+func toBusRate(rate *float64) (*money.Money, error) {
+	if rate == nil {
+		return nil, nil
+	}
+
+	m, err := money.Parse(*rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// =============================================================================
+
+// ReportRow represents the time logged and the amount billed for the entries
+// sharing a key.
+type ReportRow struct {
+	Key     string  `json:"key"`
+	Minutes int     `json:"minutes"`
+	Amount  float64 `json:"amount"`
+}
+
+// Report is a collection of report rows.
+type Report []ReportRow
+
+// Encode implements the encoder interface.
+func (app Report) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppReport(bus []timebus.ReportRow) Report {
+	app := make(Report, len(bus))
+	for i, row := range bus {
+		app[i] = ReportRow{
+			Key:     row.Key,
+			Minutes: row.Minutes.Value(),
+			Amount:  row.Amount.Value(),
+		}
+	}
+
+	return app
+}
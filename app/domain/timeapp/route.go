@@ -0,0 +1,42 @@
+package timeapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	TimeBus    *timebus.Business
+	TodoBus    *todobus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAuthorizeAssignee := mid.AuthorizeTodoAssignee(cfg.AuthClient, cfg.TodoBus, auth.RuleAdminOrSubject)
+	ruleAuthorizeEntry := mid.AuthorizeTimeEntry(cfg.AuthClient, cfg.TimeBus, auth.RuleAdminOrSubject)
+
+	api := newApp(cfg.TimeBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/timer", api.queryTimer, authen)
+	app.HandlerFunc(http.MethodPost, version, "/timer/stop", api.stopTimer, authen)
+	app.HandlerFunc(http.MethodPost, version, "/todo/{item_id}/timer", api.startTimer, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodGet, version, "/todo/{item_id}/time", api.queryByTodo, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodPost, version, "/todo/{item_id}/time", api.create, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodGet, version, "/time/report", api.report, authen)
+	app.HandlerFunc(http.MethodGet, version, "/time/{entry_id}", api.queryByID, authen, ruleAuthorizeEntry)
+	app.HandlerFunc(http.MethodPut, version, "/time/{entry_id}", api.update, authen, ruleAuthorizeEntry)
+	app.HandlerFunc(http.MethodDelete, version, "/time/{entry_id}", api.delete, authen, ruleAuthorizeEntry)
+}
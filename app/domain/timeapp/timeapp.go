@@ -0,0 +1,227 @@
+// Package timeapp maintains the app layer api for time tracking.
+package timeapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	timeBus *timebus.Business
+}
+
+func newApp(timeBus *timebus.Business) *app {
+	return &app{
+		timeBus: timeBus,
+	}
+}
+
+func (a *app) startTimer(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewTimer
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo item missing in context: %s", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	rate, err := toBusRate(app.HourlyRate)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("hourlyRate", err))
+	}
+
+	nt := timebus.NewTimer{
+		TodoID: item.ID,
+		UserID: userID,
+		Note:   app.Note,
+		Rate:   rate,
+	}
+
+	e, err := a.timeBus.StartTimer(ctx, nt)
+	if err != nil {
+		return toAppError("starttimer", err)
+	}
+
+	return toAppEntry(e)
+}
+
+func (a *app) stopTimer(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	e, err := a.timeBus.StopTimer(ctx, userID)
+	if err != nil {
+		return toAppError(fmt.Sprintf("stoptimer: userID[%s]", userID), err)
+	}
+
+	return toAppEntry(e)
+}
+
+func (a *app) queryTimer(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	e, err := a.timeBus.QueryRunning(ctx, userID)
+	if err != nil {
+		return toAppError(fmt.Sprintf("queryrunning: userID[%s]", userID), err)
+	}
+
+	return toAppEntry(e)
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewEntry
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo item missing in context: %s", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	ne, err := toBusNewEntry(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+	ne.TodoID = item.ID
+	ne.UserID = userID
+
+	e, err := a.timeBus.Create(ctx, ne)
+	if err != nil {
+		return toAppError("create", err)
+	}
+
+	return toAppEntry(e)
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateEntry
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	e, err := mid.GetTimeEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "time entry missing in context: %s", err)
+	}
+
+	ue, err := toBusUpdateEntry(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	updEntry, err := a.timeBus.Update(ctx, e, ue)
+	if err != nil {
+		return toAppError(fmt.Sprintf("update: entryID[%s]", e.ID), err)
+	}
+
+	return toAppEntry(updEntry)
+}
+
+func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
+	e, err := mid.GetTimeEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "time entry missing in context: %s", err)
+	}
+
+	if err := a.timeBus.Delete(ctx, e); err != nil {
+		return errs.Newf(errs.Internal, "delete: entryID[%s]: %s", e.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) queryByID(ctx context.Context, _ *http.Request) web.Encoder {
+	e, err := mid.GetTimeEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "time entry missing in context: %s", err)
+	}
+
+	return toAppEntry(e)
+}
+
+func (a *app) queryByTodo(ctx context.Context, _ *http.Request) web.Encoder {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo item missing in context: %s", err)
+	}
+
+	es, err := a.timeBus.QueryByTodoID(ctx, item.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybytodoid: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppEntries(es)
+}
+
+func (a *app) report(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	filter, err := parseFilter(ctx, qp)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	groupBy := timebus.GroupByList
+	if qp.GroupBy != "" {
+		groupBy, err = timebus.ParseGroupBy(qp.GroupBy)
+		if err != nil {
+			return errs.NewFieldsError("group_by", err)
+		}
+	}
+
+	rows, err := a.timeBus.Report(ctx, filter, groupBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, timebus.ErrInvalidRange),
+			errors.Is(err, timebus.ErrReportTooLong),
+			errors.Is(err, timebus.ErrReportTooLarge):
+			return errs.NewFieldsError("start_date", err)
+		}
+		return errs.Newf(errs.Internal, "report: %s", err)
+	}
+
+	return toAppReport(rows)
+}
+
+func toAppError(op string, err error) web.Encoder {
+	switch {
+	case errors.Is(err, timebus.ErrTimerRunning):
+		return errs.New(errs.FailedPrecondition, timebus.ErrTimerRunning)
+	case errors.Is(err, timebus.ErrNoTimer):
+		return errs.New(errs.NotFound, timebus.ErrNoTimer)
+	case errors.Is(err, timebus.ErrInvalidRange),
+		errors.Is(err, timebus.ErrTooLong):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("end", err))
+	case errors.Is(err, timebus.ErrInFuture):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, timebus.ErrInvalidRate):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("hourlyRate", err))
+	}
+
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
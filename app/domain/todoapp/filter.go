@@ -1,12 +1,67 @@
 package todoapp
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/status"
+)
 
 func parseQueryParams(r *http.Request) (queryParams, error) {
 	values := r.URL.Query()
 
 	filter := queryParams{
-		ID: values.Get("user_id"),
+		Page:    values.Get("page"),
+		Rows:    values.Get("rows"),
+		Cursor:  values.Get("cursor"),
+		OrderBy: values.Get("orderBy"),
+		ID:      values.Get("user_id"),
+		Status:  values.Get("status"),
+		View:    values.Get("view"),
+		List:    values.Get("list"),
+		Label:   values.Get("label"),
+	}
+
+	return filter, nil
+}
+
+func parseFilter(qp queryParams, now time.Time, loc *time.Location) (todobus.QueryFilter, error) {
+	var filter todobus.QueryFilter
+
+	if qp.View != "" {
+		view, err := todobus.ParseView(qp.View)
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("view", err)
+		}
+		view.Apply(&filter, now, loc)
+	}
+
+	if qp.Status != "" {
+		sts, err := status.Parse(qp.Status)
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("status", err)
+		}
+		filter.Status = &sts
+	}
+
+	if qp.List != "" {
+		list, err := name.Parse(qp.List)
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("list", err)
+		}
+		filter.List = &list
+	}
+
+	if qp.Label != "" {
+		l, err := label.Parse(qp.Label)
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("label", err)
+		}
+		filter.Label = &l
 	}
 
 	return filter, nil
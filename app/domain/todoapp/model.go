@@ -2,19 +2,39 @@ package todoapp
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/himynamej/todo/app/sdk/errs"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/recurrence"
+	"github.com/himynamej/todo/business/types/status"
 )
 
 type queryParams struct {
-	ID string
+	Page    string
+	Rows    string
+	Cursor  string
+	OrderBy string
+	ID      string
+	Status  string
+	View    string
+	List    string
+	Label   string
 }
 
 // FileUploadResponse represents the response returned when a file is uploaded.
 type FileUploadResponse struct {
-	FileID string `json:"fileId"`
+	FileID       string `json:"fileId"`
+	AttachmentID string `json:"attachmentId"`
+	Checksum     string `json:"checksum"` // Hex encoded SHA-256 of the contents
 }
 
 // Encode implements the encoder interface for FileUploadResponse.
@@ -28,6 +48,7 @@ type FileResponse struct {
 	FileID      string `json:"fileId"`
 	FileName    string `json:"fileName"`
 	ContentType string `json:"contentType"`
+	Checksum    string `json:"checksum,omitempty"` // Hex encoded SHA-256 of the data
 	Data        []byte `json:"data"`
 }
 
@@ -37,12 +58,38 @@ func (fileResp FileResponse) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
+// Rendition represents a downscaled image returned as is rather than
+// wrapped in JSON, so clients can use the response directly as an image.
+type Rendition struct {
+	ContentType string
+	Data        []byte
+}
+
+// Encode implements the encoder interface for Rendition.
+func (rnd Rendition) Encode() ([]byte, string, error) {
+	return rnd.Data, rnd.ContentType, nil
+}
+
 // TodoItem represents the structure for a Todo item in the application layer.
 type TodoItem struct {
-	ID          string `json:"id"`
-	Description string `json:"description"`
-	DueDate     string `json:"dueDate"`
-	FileID      string `json:"fileId"`
+	ID            string          `json:"id"`
+	UserID        string          `json:"userId"`
+	AssigneeID    string          `json:"assigneeId,omitempty"`
+	Description   string          `json:"description"`
+	DueDate       string          `json:"dueDate,omitempty"`
+	AllDay        bool            `json:"allDay"`
+	FileID        string          `json:"fileId"`
+	Status        string          `json:"status"`
+	Priority      string          `json:"priority"`
+	Recurrence    string          `json:"recurrence,omitempty"`
+	List          string          `json:"list,omitempty"`
+	Labels        []string        `json:"labels"`
+	Checklist     []ChecklistItem `json:"checklist"`
+	Blocked       bool            `json:"blocked"`
+	Rank          string          `json:"rank,omitempty"`
+	DateCreated   string          `json:"dateCreated"`
+	DateUpdated   string          `json:"dateUpdated"`
+	DateCompleted string          `json:"dateCompleted,omitempty"`
 }
 
 // Encode implements the encoder interface for a TodoItem.
@@ -51,11 +98,18 @@ func (app TodoItem) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
-// NewTodoItem defines the data needed to create a new TodoItem.
+// NewTodoItem defines the data needed to create a new TodoItem. Items get
+// the lowest priority when none is given.
 type NewTodoItem struct {
-	Description string `json:"description" validate:"required"`
-	DueDate     string `json:"dueDate" validate:"required"`
-	FileID      string `json:"fileId"`
+	Description string          `json:"description" validate:"required"`
+	DueDate     string          `json:"dueDate"`
+	FileID      string          `json:"fileId"`
+	AssigneeID  string          `json:"assigneeId" validate:"omitempty,uuid"`
+	Priority    string          `json:"priority"`
+	Recurrence  string          `json:"recurrence"`
+	List        string          `json:"list"`
+	Labels      []string        `json:"labels"`
+	Checklist   []ChecklistItem `json:"checklist" validate:"omitempty,max=100,dive"`
 }
 
 // Encode implements the encoder interface.
@@ -78,11 +132,824 @@ func (app NewTodoItem) Validate() error {
 	return nil
 }
 
+// toBusNewTodoItem converts the new item, leaving the owner to the caller.
+// Errors are returned as app errors naming the field at fault.
+func toBusNewTodoItem(app NewTodoItem) (todobus.NewTodoItem, error) {
+	dueDate, allDay, err := parseDueDate(app.DueDate)
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.NewFieldsError("dueDate", err)
+	}
+
+	var assigneeID uuid.UUID
+	if app.AssigneeID != "" {
+		assigneeID, err = uuid.Parse(app.AssigneeID)
+		if err != nil {
+			return todobus.NewTodoItem{}, errs.NewFieldsError("assigneeId", err)
+		}
+	}
+
+	list, err := name.ParseNull(app.List)
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.NewFieldsError("list", err)
+	}
+
+	labels, err := label.ParseMany(app.Labels)
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.NewFieldsError("labels", err)
+	}
+
+	var pri priority.Priority
+	if app.Priority != "" {
+		if pri, err = priority.Parse(app.Priority); err != nil {
+			return todobus.NewTodoItem{}, errs.New(errs.InvalidArgument, errs.NewFieldsError("priority", err))
+		}
+	}
+
+	rule, err := recurrence.Parse(app.Recurrence)
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.New(errs.InvalidArgument, errs.NewFieldsError("recurrence", err))
+	}
+
+	nt := todobus.NewTodoItem{
+		AssigneeID:  assigneeID,
+		Description: app.Description,
+		DueDate:     dueDate,
+		AllDay:      allDay,
+		Priority:    pri,
+		Recurrence:  rule,
+		List:        list,
+		Labels:      labels,
+		Checklist:   toBusChecklist(app.Checklist),
+		FileID:      app.FileID,
+	}
+
+	return nt, nil
+}
+
 func toAppTodoItem(bus todobus.TodoItem) TodoItem {
+	var assigneeID string
+	if bus.AssigneeID != uuid.Nil {
+		assigneeID = bus.AssigneeID.String()
+	}
+
+	var dateCompleted string
+	if !bus.DateCompleted.IsZero() {
+		dateCompleted = bus.DateCompleted.Format(time.RFC3339)
+	}
+
 	return TodoItem{
-		ID:          bus.ID.String(),
+		ID:            bus.ID.String(),
+		UserID:        bus.UserID.String(),
+		AssigneeID:    assigneeID,
+		Description:   bus.Description,
+		DueDate:       formatDueDate(bus.DueDate, bus.AllDay),
+		AllDay:        bus.AllDay,
+		FileID:        bus.FileID,
+		Status:        bus.Status.String(),
+		Priority:      bus.Priority.String(),
+		Recurrence:    bus.Recurrence.String(),
+		List:          bus.List.String(),
+		Labels:        label.ParseToString(bus.Labels),
+		Checklist:     toAppChecklist(bus.Checklist),
+		Blocked:       bus.Blocked,
+		Rank:          bus.Rank.String(),
+		DateCreated:   bus.DateCreated.Format(time.RFC3339),
+		DateUpdated:   bus.DateUpdated.Format(time.RFC3339),
+		DateCompleted: dateCompleted,
+	}
+}
+
+func toAppTodoItems(items []todobus.TodoItem) []TodoItem {
+	app := make([]TodoItem, len(items))
+	for i, item := range items {
+		app[i] = toAppTodoItem(item)
+	}
+
+	return app
+}
+
+// ChecklistItem represents one step of a TodoItem.
+type ChecklistItem struct {
+	Text string `json:"text" validate:"required,max=200"`
+	Done bool   `json:"done"`
+}
+
+func toAppChecklist(checklist []todobus.ChecklistItem) []ChecklistItem {
+	app := make([]ChecklistItem, len(checklist))
+	for i, ci := range checklist {
+		app[i] = ChecklistItem(ci)
+	}
+
+	return app
+}
+
+func toBusChecklist(app []ChecklistItem) []todobus.ChecklistItem {
+	if app == nil {
+		return nil
+	}
+
+	bus := make([]todobus.ChecklistItem, len(app))
+	for i, ci := range app {
+		bus[i] = todobus.ChecklistItem(ci)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+// UpdateTodoItem defines the data needed to update a TodoItem. An empty due
+// date removes the due date, and an empty recurrence stops the item from
+// repeating. A checklist replaces the whole checklist of the item.
+type UpdateTodoItem struct {
+	Description *string         `json:"description"`
+	DueDate     *string         `json:"dueDate"`
+	Priority    *string         `json:"priority"`
+	Recurrence  *string         `json:"recurrence"`
+	List        *string         `json:"list"`
+	Labels      []string        `json:"labels"`
+	Checklist   []ChecklistItem `json:"checklist" validate:"omitempty,max=100,dive"`
+}
+
+// Encode implements the encoder interface.
+func (app UpdateTodoItem) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateTodoItem) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateTodoItem) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateTodoItem(app UpdateTodoItem) (todobus.UpdateTodoItem, error) {
+	var dueDate *time.Time
+	var allDay *bool
+	if app.DueDate != nil {
+		t, ad, err := parseDueDate(*app.DueDate)
+		if err != nil {
+			return todobus.UpdateTodoItem{}, fmt.Errorf("parse: %w", err)
+		}
+		dueDate = &t
+		allDay = &ad
+	}
+
+	var pri *priority.Priority
+	if app.Priority != nil {
+		p, err := priority.Parse(*app.Priority)
+		if err != nil {
+			return todobus.UpdateTodoItem{}, fmt.Errorf("parse: %w", err)
+		}
+		pri = &p
+	}
+
+	var rule *recurrence.Rule
+	if app.Recurrence != nil {
+		r, err := recurrence.Parse(*app.Recurrence)
+		if err != nil {
+			return todobus.UpdateTodoItem{}, fmt.Errorf("parse: %w", err)
+		}
+		rule = &r
+	}
+
+	var list *name.Null
+	if app.List != nil {
+		l, err := name.ParseNull(*app.List)
+		if err != nil {
+			return todobus.UpdateTodoItem{}, fmt.Errorf("parse: %w", err)
+		}
+		list = &l
+	}
+
+	// A non-nil empty slice clears the labels.
+	var labels []label.Label
+	if app.Labels != nil {
+		var err error
+		labels, err = label.ParseMany(app.Labels)
+		if err != nil {
+			return todobus.UpdateTodoItem{}, fmt.Errorf("parse: %w", err)
+		}
+		if labels == nil {
+			labels = []label.Label{}
+		}
+	}
+
+	bus := todobus.UpdateTodoItem{
+		Description: app.Description,
+		DueDate:     dueDate,
+		AllDay:      allDay,
+		Priority:    pri,
+		Recurrence:  rule,
+		List:        list,
+		Labels:      labels,
+		Checklist:   toBusChecklist(app.Checklist),
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// QuickAdd defines a line of text that describes a new TodoItem, such as
+// "call mom tomorrow 6pm #family !p2".
+type QuickAdd struct {
+	Text string `json:"text" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app QuickAdd) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *QuickAdd) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app QuickAdd) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// QuickAddPreview represents the TodoItem a quick-add text describes,
+// before it is created.
+type QuickAddPreview struct {
+	Description string   `json:"description"`
+	DueDate     string   `json:"dueDate,omitempty"`
+	AllDay      bool     `json:"allDay"`
+	Priority    string   `json:"priority"`
+	Recurrence  string   `json:"recurrence,omitempty"`
+	Labels      []string `json:"labels"`
+}
+
+// Encode implements the encoder interface.
+func (app QuickAddPreview) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppQuickAddPreview(bus todobus.NewTodoItem) QuickAddPreview {
+	return QuickAddPreview{
 		Description: bus.Description,
-		DueDate:     bus.DueDate.Format(time.RFC3339),
-		FileID:      bus.FileID,
+		DueDate:     formatDueDate(bus.DueDate, bus.AllDay),
+		AllDay:      bus.AllDay,
+		Priority:    bus.Priority.String(),
+		Recurrence:  bus.Recurrence.String(),
+		Labels:      label.ParseToString(bus.Labels),
+	}
+}
+
+// =============================================================================
+
+// UpdateStatus defines the data needed to change the status of a TodoItem.
+type UpdateStatus struct {
+	Status string `json:"status" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app UpdateStatus) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateStatus) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateStatus) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// UpdateAssignee defines the data needed to assign a TodoItem. An empty
+// assignee id removes the current assignee.
+type UpdateAssignee struct {
+	AssigneeID string `json:"assigneeId" validate:"omitempty,uuid"`
+}
+
+// Encode implements the encoder interface.
+func (app UpdateAssignee) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateAssignee) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateAssignee) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// MoveTodoItem defines where a TodoItem is dropped on the board. The item
+// goes into the column of the status, or stays in its own without one, right
+// after the item beforeId and right before the item afterId. Without either
+// neighbour it goes to the end of the column.
+type MoveTodoItem struct {
+	Status   string `json:"status"`
+	BeforeID string `json:"beforeId" validate:"omitempty,uuid"`
+	AfterID  string `json:"afterId" validate:"omitempty,uuid"`
+}
+
+// Encode implements the encoder interface.
+func (app MoveTodoItem) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *MoveTodoItem) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app MoveTodoItem) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusMove(app MoveTodoItem) (todobus.Move, error) {
+	var mv todobus.Move
+
+	if app.Status != "" {
+		sts, err := status.Parse(app.Status)
+		if err != nil {
+			return todobus.Move{}, errs.NewFieldsError("status", err)
+		}
+		mv.Status = &sts
+	}
+
+	if app.BeforeID != "" {
+		id, err := uuid.Parse(app.BeforeID)
+		if err != nil {
+			return todobus.Move{}, errs.NewFieldsError("beforeId", err)
+		}
+		mv.BeforeID = &id
+	}
+
+	if app.AfterID != "" {
+		id, err := uuid.Parse(app.AfterID)
+		if err != nil {
+			return todobus.Move{}, errs.NewFieldsError("afterId", err)
+		}
+		mv.AfterID = &id
+	}
+
+	return mv, nil
+}
+
+// BoardColumn represents the TodoItems of one status in the order they are
+// shown on the board.
+type BoardColumn struct {
+	Status string     `json:"status"`
+	Items  []TodoItem `json:"items"`
+}
+
+// Board represents the TodoItems of a user arranged in a column per status.
+type Board struct {
+	Columns []BoardColumn `json:"columns"`
+}
+
+// Encode implements the encoder interface.
+func (app Board) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppBoard(bus todobus.Board) Board {
+	app := Board{
+		Columns: make([]BoardColumn, len(bus.Columns)),
+	}
+
+	for i, col := range bus.Columns {
+		app.Columns[i] = BoardColumn{
+			Status: col.Status.String(),
+			Items:  toAppTodoItems(col.Items),
+		}
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// History represents a recorded change to a TodoItem.
+type History struct {
+	ID          string `json:"id"`
+	TodoID      string `json:"todoId"`
+	ActorID     string `json:"actorId,omitempty"`
+	Field       string `json:"field"`
+	OldValue    string `json:"oldValue"`
+	NewValue    string `json:"newValue"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Histories is a collection of history entries.
+type Histories []History
+
+// Encode implements the encoder interface.
+func (app Histories) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppHistories(hsts []todobus.History) Histories {
+	app := make(Histories, len(hsts))
+	for i, hst := range hsts {
+		var actorID string
+		if hst.ActorID != uuid.Nil {
+			actorID = hst.ActorID.String()
+		}
+
+		app[i] = History{
+			ID:          hst.ID.String(),
+			TodoID:      hst.TodoID.String(),
+			ActorID:     actorID,
+			Field:       hst.Field,
+			OldValue:    hst.OldValue,
+			NewValue:    hst.NewValue,
+			DateCreated: hst.DateCreated.Format(time.RFC3339),
+		}
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// Dependency represents a TodoItem that is blocked by another one until the
+// other one is done.
+type Dependency struct {
+	TodoID      string `json:"todoId"`
+	BlockerID   string `json:"blockerId"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Dependency) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppDependency(dep todobus.Dependency) Dependency {
+	return Dependency{
+		TodoID:      dep.ItemID.String(),
+		BlockerID:   dep.BlockerID.String(),
+		DateCreated: dep.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// Dependencies is a collection of dependencies.
+type Dependencies []Dependency
+
+// Encode implements the encoder interface.
+func (app Dependencies) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppDependencies(deps []todobus.Dependency) Dependencies {
+	app := make(Dependencies, len(deps))
+	for i, dep := range deps {
+		app[i] = toAppDependency(dep)
+	}
+
+	return app
+}
+
+// GraphNode represents a TodoItem in a dependency graph.
+type GraphNode struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Blocked     bool   `json:"blocked"`
+}
+
+// Graph represents the dependencies between a set of TodoItems. BlockedBy
+// maps the id of every item to the ids of the items it is blocked by.
+type Graph struct {
+	Items     []GraphNode         `json:"items"`
+	BlockedBy map[string][]string `json:"blockedBy"`
+}
+
+// Encode implements the encoder interface.
+func (app Graph) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppGraph(bus todobus.Graph) Graph {
+	app := Graph{
+		Items:     make([]GraphNode, len(bus.Items)),
+		BlockedBy: make(map[string][]string, len(bus.Items)),
+	}
+
+	for i, item := range bus.Items {
+		app.Items[i] = GraphNode{
+			ID:          item.ID.String(),
+			Description: item.Description,
+			Status:      item.Status.String(),
+			Blocked:     item.Blocked,
+		}
+		app.BlockedBy[item.ID.String()] = []string{}
+	}
+
+	for _, dep := range bus.Dependencies {
+		id := dep.ItemID.String()
+		app.BlockedBy[id] = append(app.BlockedBy[id], dep.BlockerID.String())
+	}
+
+	return app
+}
+
+// GraphDOT represents a dependency graph in the Graphviz DOT language. Each
+// edge points from a blocker to the item waiting on it, so the graph reads
+// in the order the work gets done.
+type GraphDOT struct {
+	Graph todobus.Graph
+}
+
+// Encode implements the encoder interface.
+func (app GraphDOT) Encode() ([]byte, string, error) {
+	var b strings.Builder
+
+	b.WriteString("digraph todo {\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	for _, item := range app.Graph.Items {
+		var style string
+		switch {
+		case item.Status == status.Done:
+			style = ", style=dashed"
+		case item.Blocked:
+			style = ", color=red"
+		}
+
+		text := fmt.Sprintf("%s\n%s", item.Description, item.Status)
+		fmt.Fprintf(&b, "\t%s [label=%s%s];\n", dotQuote(item.ID.String()), dotQuote(text), style)
+	}
+
+	for _, dep := range app.Graph.Dependencies {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", dotQuote(dep.BlockerID.String()), dotQuote(dep.ItemID.String()))
+	}
+
+	b.WriteString("}\n")
+
+	return []byte(b.String()), "text/vnd.graphviz", nil
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+
+// dotQuote writes a DOT identifier that holds any text, line breaks
+// included.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// =============================================================================
+
+// Sync defines the changes a client made while it was offline, along with
+// the token returned by its last sync. An empty token fetches every item.
+type Sync struct {
+	Token     string         `json:"token"`
+	Mutations []SyncMutation `json:"mutations" validate:"omitempty,max=100,dive"`
+}
+
+// Encode implements the encoder interface.
+func (app Sync) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *Sync) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app Sync) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// SyncMutation defines one change made on a client. Creates carry the id
+// the client gave the item and the item itself. Updates carry the changes,
+// and both updates and deletes carry the version of the item they were
+// made to.
+type SyncMutation struct {
+	Type    string       `json:"type" validate:"required,oneof=create update delete"`
+	ID      string       `json:"id" validate:"required,uuid"`
+	Version int64        `json:"version"`
+	Item    *NewTodoItem `json:"item"`
+	Changes *SyncChanges `json:"changes"`
+}
+
+// SyncChanges defines the fields an update changes. Assignees of an item
+// may only change its status.
+type SyncChanges struct {
+	UpdateTodoItem
+	Status *string `json:"status"`
+}
+
+func toBusSync(app Sync) (int64, []todobus.Mutation, error) {
+	var token int64
+	if app.Token != "" {
+		var err error
+		if token, err = strconv.ParseInt(app.Token, 10, 64); err != nil || token < 0 {
+			return 0, nil, errs.NewFieldsError("token", todobus.ErrInvalidSyncToken)
+		}
+	}
+
+	muts := make([]todobus.Mutation, len(app.Mutations))
+	for i, am := range app.Mutations {
+		mut, err := toBusMutation(am)
+		if err != nil {
+			return 0, nil, errs.NewFieldsError(fmt.Sprintf("mutations[%d]", i), err)
+		}
+		muts[i] = mut
+	}
+
+	return token, muts, nil
+}
+
+func toBusMutation(app SyncMutation) (todobus.Mutation, error) {
+	typ, err := todobus.ParseMutationType(app.Type)
+	if err != nil {
+		return todobus.Mutation{}, err
+	}
+
+	itemID, err := uuid.Parse(app.ID)
+	if err != nil {
+		return todobus.Mutation{}, fmt.Errorf("parse id: %w", err)
+	}
+
+	mut := todobus.Mutation{
+		Type:    typ,
+		ItemID:  itemID,
+		Version: app.Version,
+	}
+
+	switch typ {
+	case todobus.MutationCreate:
+		if app.Item == nil {
+			return todobus.Mutation{}, errors.New("item is required")
+		}
+
+		if mut.Create, err = toBusNewTodoItem(*app.Item); err != nil {
+			return todobus.Mutation{}, fmt.Errorf("item: %w", err)
+		}
+
+	case todobus.MutationUpdate:
+		if app.Changes == nil {
+			return todobus.Mutation{}, errors.New("changes are required")
+		}
+
+		if mut.Update, err = toBusUpdateTodoItem(app.Changes.UpdateTodoItem); err != nil {
+			return todobus.Mutation{}, fmt.Errorf("changes: %w", err)
+		}
+
+		if app.Changes.Status != nil {
+			sts, err := status.Parse(*app.Changes.Status)
+			if err != nil {
+				return todobus.Mutation{}, fmt.Errorf("changes: parse: %w", err)
+			}
+			mut.Update.Status = &sts
+		}
+	}
+
+	return mut, nil
+}
+
+// SyncResult represents what became of a mutation. Error is left out when
+// the mutation was applied, or had been already.
+type SyncResult struct {
+	ID    string      `json:"id"`
+	Error *errs.Error `json:"error,omitempty"`
+}
+
+// Change represents a todo item as it is after a change, with the version
+// to send when changing it.
+type Change struct {
+	TodoItem
+	Version int64 `json:"version"`
+}
+
+// Tombstone represents a todo item the user can no longer see and a client
+// should drop.
+type Tombstone struct {
+	ID          string `json:"id"`
+	Version     int64  `json:"version"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// SyncResponse represents the outcome of a sync. The token is sent with the
+// next sync. When more is set there are changes left to fetch, and the
+// client should sync again straight away.
+type SyncResponse struct {
+	Results    []SyncResult `json:"results"`
+	Changes    []Change     `json:"changes"`
+	Tombstones []Tombstone  `json:"tombstones"`
+	Token      string       `json:"token"`
+	More       bool         `json:"more"`
+}
+
+// Encode implements the encoder interface.
+func (app SyncResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppSync(bus todobus.Sync, results []SyncResult) SyncResponse {
+	changes := make([]Change, len(bus.Changes))
+	for i, chg := range bus.Changes {
+		changes[i] = Change{
+			TodoItem: toAppTodoItem(chg.Item),
+			Version:  chg.Version,
+		}
+	}
+
+	tombs := make([]Tombstone, len(bus.Tombstones))
+	for i, tomb := range bus.Tombstones {
+		tombs[i] = Tombstone{
+			ID:          tomb.ItemID.String(),
+			Version:     tomb.Version,
+			DateCreated: tomb.DateCreated.Format(time.RFC3339),
+		}
+	}
+
+	return SyncResponse{
+		Results:    results,
+		Changes:    changes,
+		Tombstones: tombs,
+		Token:      strconv.FormatInt(bus.Token, 10),
+		More:       bus.More,
+	}
+}
+
+// =============================================================================
+
+// parseDueDate accepts either a calendar date such as "2024-05-01", which
+// makes an all-day item, or an RFC3339 timestamp for a timed item. An empty
+// value means no due date.
+func parseDueDate(value string) (time.Time, bool, error) {
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected %s or RFC3339: %q", time.DateOnly, value)
+	}
+
+	return t, false, nil
+}
+
+func formatDueDate(t time.Time, allDay bool) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case allDay:
+		return t.Format(time.DateOnly)
+	default:
+		return t.Format(time.RFC3339)
 	}
 }
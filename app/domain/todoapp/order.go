@@ -0,0 +1,14 @@
+package todoapp
+
+import (
+	"github.com/himynamej/todo/business/domain/todobus"
+)
+
+var orderByFields = map[string]string{
+	"item_id":      todobus.OrderByID,
+	"description":  todobus.OrderByDescription,
+	"due_date":     todobus.OrderByDueDate,
+	"status":       todobus.OrderByStatus,
+	"date_created": todobus.OrderByDateCreated,
+	"rank":         todobus.OrderByRank,
+}
@@ -3,18 +3,24 @@ package todoapp
 import (
 	"net/http"
 
+	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/himynamej/todo/foundation/web"
 )
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
-	Log        *logger.Logger
-	TodoBus    *todobus.Business
-	AuthClient *authclient.Client
+	Log            *logger.Logger
+	TodoBus        *todobus.Business
+	UserBus        *userbus.Business
+	IdempotencyBus *idempotencybus.Business
+	AuthClient     *authclient.Client
+	CursorKey      []byte // Signs the cursors handed out with each page
 }
 
 // Routes adds specific routes for this group.
@@ -23,9 +29,36 @@ func Routes(app *web.App, cfg Config) {
 
 	authen := mid.Authenticate(cfg.AuthClient)
 	//	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+	ruleAuthorizeOwner := mid.AuthorizeTodo(cfg.AuthClient, cfg.TodoBus, auth.RuleAdminOrSubject)
+	ruleAuthorizeAssignee := mid.AuthorizeTodoAssignee(cfg.AuthClient, cfg.TodoBus, auth.RuleAdminOrSubject)
+	ruleAuthorizeBlocker := mid.AuthorizeBlocker(cfg.AuthClient, cfg.TodoBus, auth.RuleOrgAdminOrSubject)
+	ruleAuthorizeAttachment := mid.AuthorizeAttachment(cfg.AuthClient, cfg.TodoBus, auth.RuleOrgAdminOrSubject)
+	ruleAuthorizeAttachmentFile := mid.AuthorizeAttachmentFile(cfg.AuthClient, cfg.TodoBus, auth.RuleOrgAdminOrSubject)
+	ruleAuthorizeFile := mid.AuthorizeFile(cfg.AuthClient, cfg.TodoBus, auth.RuleOrgAdminOrSubject)
+	idempotent := mid.Idempotent(cfg.Log, cfg.IdempotencyBus, mid.IdempotencyMaxBody)
+	idempotentUpload := mid.Idempotent(cfg.Log, cfg.IdempotencyBus, maxUploadSize)
 
-	api := newApp(cfg.TodoBus)
-	app.HandlerFunc(http.MethodPost, version, "/todo", api.CreateTodoItem, authen)
-	app.HandlerFunc(http.MethodPost, version, "/upload", api.UploadFile, authen)
-	app.HandlerFunc(http.MethodGet, version, "/download/{file_id}", api.DownloadFile, authen)
+	api := newApp(cfg.Log, cfg.TodoBus, cfg.UserBus, cfg.CursorKey)
+	app.HandlerFunc(http.MethodGet, version, "/todo", api.QueryTodoItems, authen)
+	app.HandlerFunc(http.MethodGet, version, "/todo/assigned", api.QueryAssigned, authen)
+	app.HandlerFunc(http.MethodGet, version, "/todo/{item_id}", api.QueryTodoItemByID, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodGet, version, "/todo/graph", api.QueryGraph, authen)
+	app.HandlerFunc(http.MethodGet, version, "/todo/board", api.QueryBoard, authen)
+	app.HandlerFunc(http.MethodGet, version, "/todo/{item_id}/history", api.QueryHistory, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodGet, version, "/todo/{item_id}/blockers", api.QueryBlockers, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodPost, version, "/todo/{item_id}/blockers/{blocker_id}", api.AddBlocker, authen, ruleAuthorizeOwner, ruleAuthorizeBlocker)
+	app.HandlerFunc(http.MethodDelete, version, "/todo/{item_id}/blockers/{blocker_id}", api.RemoveBlocker, authen, ruleAuthorizeOwner)
+	app.HandlerFunc(http.MethodPost, version, "/todo", api.CreateTodoItem, authen, idempotent)
+	app.HandlerFunc(http.MethodPost, version, "/todo/quick", api.QuickAddTodoItem, authen, idempotent)
+	app.HandlerFunc(http.MethodPost, version, "/todo/sync", api.Sync, authen)
+	app.HandlerFunc(http.MethodPost, version, "/todo/quick/preview", api.PreviewQuickAdd, authen)
+	app.HandlerFunc(http.MethodPut, version, "/todo/{item_id}", api.UpdateTodoItem, authen, ruleAuthorizeOwner)
+	app.HandlerFunc(http.MethodPut, version, "/todo/{item_id}/status", api.UpdateStatus, authen, ruleAuthorizeAssignee)
+	app.HandlerFunc(http.MethodPut, version, "/todo/{item_id}/assignee", api.AssignTodoItem, authen, ruleAuthorizeOwner)
+	app.HandlerFunc(http.MethodPut, version, "/todo/{item_id}/position", api.MoveTodoItem, authen, ruleAuthorizeOwner)
+	app.HandlerFunc(http.MethodDelete, version, "/todo/{item_id}", api.DeleteTodoItem, authen, ruleAuthorizeOwner)
+	app.HandlerFunc(http.MethodPost, version, "/upload", api.UploadFile, authen, idempotentUpload)
+	app.HandlerFunc(http.MethodGet, version, "/download/{file_id...}", api.DownloadFile, authen, ruleAuthorizeFile)
+	app.HandlerFunc(http.MethodDelete, version, "/attachments/{attachment_id}", api.DeleteAttachment, authen, ruleAuthorizeAttachment)
+	app.HandlerFunc(http.MethodGet, version, "/attachments/{attachment_id}/renditions/{size}", api.QueryRendition, authen, ruleAuthorizeAttachmentFile)
 }
@@ -3,23 +3,49 @@ package todoapp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/app/sdk/query"
+	"github.com/himynamej/todo/business/domain/quotabus"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/status"
+	"github.com/himynamej/todo/foundation/logger"
 	"github.com/himynamej/todo/foundation/web"
 )
 
+// maxUploadSize bounds how much of an upload request is read. It sits above
+// every per role limit so the business layer decides what is acceptable.
+const maxUploadSize = 64 << 20 // 64 MB
+
 type app struct {
-	todoBus *todobus.Business
+	log       *logger.Logger
+	todoBus   *todobus.Business
+	userBus   *userbus.Business
+	cursorKey []byte
 }
 
-func newApp(todoBus *todobus.Business) *app {
+func newApp(log *logger.Logger, todoBus *todobus.Business, userBus *userbus.Business, cursorKey []byte) *app {
 	return &app{
-		todoBus: todoBus,
+		log:       log,
+		todoBus:   todoBus,
+		userBus:   userBus,
+		cursorKey: cursorKey,
 	}
 }
 
@@ -29,88 +55,672 @@ func (a *app) CreateTodoItem(ctx context.Context, r *http.Request) web.Encoder {
 	if err := web.Decode(r, &app); err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
-	// Use RFC3339 layout to parse the DueDate string
-	parsedTime, err := time.Parse(time.RFC3339, app.DueDate)
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	nt, err := toBusNewTodoItem(app)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+	nt.UserID = userID
+
+	return a.create(ctx, nt)
+}
+
+// QuickAddTodoItem handles creating a new TodoItem from a line of text,
+// read in the caller's time zone.
+func (a *app) QuickAddTodoItem(ctx context.Context, r *http.Request) web.Encoder {
+	nt, err := a.parseQuickAdd(ctx, r)
 	if err != nil {
-		return errs.New(errs.Internal, fmt.Errorf("invalid date format: %w", err))
+		return err.(web.Encoder)
 	}
 
-	// Create the TodoItem using the business layer
-	item, err := a.todoBus.Create(ctx, app.Description, parsedTime, nil, app.FileID)
+	return a.create(ctx, nt)
+}
+
+// PreviewQuickAdd handles returning the TodoItem a line of text describes
+// without creating it, so clients can show what will be added.
+func (a *app) PreviewQuickAdd(ctx context.Context, r *http.Request) web.Encoder {
+	nt, err := a.parseQuickAdd(ctx, r)
 	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	return toAppQuickAddPreview(nt)
+}
+
+func (a *app) parseQuickAdd(ctx context.Context, r *http.Request) (todobus.NewTodoItem, error) {
+	var app QuickAdd
+	if err := web.Decode(r, &app); err != nil {
+		return todobus.NewTodoItem{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	nt, err := todobus.ParseQuickAdd(app.Text, time.Now(), usr.TimeZone.Location())
+	if err != nil {
+		return todobus.NewTodoItem{}, errs.New(errs.InvalidArgument, errs.NewFieldsError("text", err))
+	}
+	nt.UserID = userID
+
+	return nt, nil
+}
+
+func (a *app) create(ctx context.Context, nt todobus.NewTodoItem) web.Encoder {
+	item, err := a.todoBus.Create(ctx, nt)
+	if err != nil {
+		if errors.Is(err, todobus.ErrAssigneeNotFound) || errors.Is(err, todobus.ErrAssigneeDisabled) || errors.Is(err, todobus.ErrAssigneeNotMember) {
+			return errs.NewFieldsError("assigneeId", err)
+		}
+		if errors.Is(err, todobus.ErrBlobNotFound) || errors.Is(err, todobus.ErrFileNotOwned) {
+			return errs.NewFieldsError("fileId", err)
+		}
+		if ee := quotabus.GetExceeded(err); ee != nil {
+			return errs.New(errs.ResourceExhausted, ee)
+		}
 		return errs.New(errs.Internal, err)
 	}
 
 	return toAppTodoItem(item)
 }
 
-// UploadFile handles file uploads and stores them in an S3 bucket.
-func (a *app) UploadFile(ctx context.Context, r *http.Request) web.Encoder {
-	// Set a maximum size limit for file uploads (e.g., 10 MB)
-	const maxFileSize = 10 << 20 // 10 MB
-	r.Body = http.MaxBytesReader(nil, r.Body, maxFileSize)
+// UpdateTodoItem handles changing the description or due date of a TodoItem.
+func (a *app) UpdateTodoItem(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateTodoItem
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
 
-	// Extract the file name from a query parameter or custom header (adjust as necessary)
-	fileName := r.URL.Query().Get("filename")
-	if fileName == "" {
-		return errs.New(errs.InvalidArgument, fmt.Errorf("missing file name"))
+	ut, err := toBusUpdateTodoItem(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
 	}
 
-	// Read the file data directly from the request body
-	fileData, err := io.ReadAll(r.Body)
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	updItem, err := a.todoBus.Update(ctx, item, ut)
+	if err != nil {
+		return errs.Newf(errs.Internal, "update: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppTodoItem(updItem)
+}
+
+// UpdateStatus handles moving a TodoItem to a new status. Owners and
+// assignees are both allowed to do this.
+func (a *app) UpdateStatus(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateStatus
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	sts, err := status.Parse(app.Status)
+	if err != nil {
+		return errs.NewFieldsError("status", err)
+	}
+
+	item, err := mid.GetTodo(ctx)
 	if err != nil {
-		return errs.New(errs.Internal, fmt.Errorf("error reading file data: %w", err))
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
 	}
 
-	// Optional validation: Limit file size to a smaller maximum (e.g., 5 MB)
-	if len(fileData) > 5<<20 { // 5 MB limit
-		return errs.New(errs.InvalidArgument, fmt.Errorf("file size exceeds the maximum allowed limit of 5 MB"))
+	updItem, err := a.todoBus.Update(ctx, item, todobus.UpdateTodoItem{Status: &sts})
+	if err != nil {
+		return errs.Newf(errs.Internal, "updatestatus: itemID[%s]: %s", item.ID, err)
 	}
 
-	// Validate the file type by inspecting the initial bytes
-	allowedFileTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"text/plain": true,
+	return toAppTodoItem(updItem)
+}
+
+// AssignTodoItem handles handing a TodoItem to another user.
+func (a *app) AssignTodoItem(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateAssignee
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
 	}
-	fileType := http.DetectContentType(fileData[:512])
-	if !allowedFileTypes[fileType] {
-		return errs.New(errs.InvalidArgument, fmt.Errorf("unsupported file type: %s", fileType))
+
+	var assigneeID uuid.UUID
+	if app.AssigneeID != "" {
+		var err error
+		assigneeID, err = uuid.Parse(app.AssigneeID)
+		if err != nil {
+			return errs.NewFieldsError("assigneeId", err)
+		}
 	}
 
-	// Upload the file to S3 using the business layer
-	fileID, err := a.todoBus.UploadFile(ctx, fileName, fileData)
+	actorID, err := mid.GetUserID(ctx)
 	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	updItem, err := a.todoBus.Assign(ctx, item, assigneeID, actorID)
+	if err != nil {
+		if errors.Is(err, todobus.ErrAssigneeNotFound) || errors.Is(err, todobus.ErrAssigneeDisabled) || errors.Is(err, todobus.ErrAssigneeNotMember) {
+			return errs.NewFieldsError("assigneeId", err)
+		}
+		return errs.Newf(errs.Internal, "assign: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppTodoItem(updItem)
+}
+
+// DeleteTodoItem handles removing a TodoItem.
+func (a *app) DeleteTodoItem(ctx context.Context, _ *http.Request) web.Encoder {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	if err := a.todoBus.Delete(ctx, item); err != nil {
+		return errs.Newf(errs.Internal, "delete: itemID[%s]: %s", item.ID, err)
+	}
+
+	return nil
+}
+
+// Sync handles applying the changes a client made while offline and
+// returning the changes made elsewhere since its last sync. Mutations that
+// fail are reported one by one and don't fail the sync.
+func (a *app) Sync(ctx context.Context, r *http.Request) web.Encoder {
+	var app Sync
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	token, muts, err := toBusSync(app)
+	if err != nil {
+		return err.(web.Encoder)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	sync, err := a.todoBus.Sync(ctx, userID, token, muts)
+	if err != nil {
+		switch {
+		case errors.Is(err, todobus.ErrInvalidSyncToken):
+			return errs.NewFieldsError("token", todobus.ErrInvalidSyncToken)
+		case errors.Is(err, todobus.ErrTooManyMutations):
+			return errs.NewFieldsError("mutations", err)
+		case errors.Is(err, todobus.ErrSyncBusy):
+			return errs.New(errs.Unavailable, todobus.ErrSyncBusy)
+		}
+		return errs.Newf(errs.Internal, "sync: userID[%s]: %s", userID, err)
+	}
+
+	results := make([]SyncResult, len(sync.Results))
+	for i, res := range sync.Results {
+		results[i] = SyncResult{
+			ID:    res.ItemID.String(),
+			Error: a.toSyncError(ctx, res),
+		}
+	}
+
+	return toAppSync(sync, results)
+}
+
+// toSyncError converts the error of a mutation for the client. Errors the
+// client can't act on are logged and reported as internal.
+func (a *app) toSyncError(ctx context.Context, res todobus.MutationResult) *errs.Error {
+	switch err := res.Err; {
+	case err == nil:
+		return nil
+	case errors.Is(err, todobus.ErrSyncConflict):
+		return errs.New(errs.Aborted, todobus.ErrSyncConflict)
+	case errors.Is(err, todobus.ErrNotFound):
+		return errs.New(errs.NotFound, todobus.ErrNotFound)
+	case errors.Is(err, todobus.ErrNotPermitted):
+		return errs.New(errs.PermissionDenied, todobus.ErrNotPermitted)
+	case errors.Is(err, todobus.ErrAssigneeNotFound),
+		errors.Is(err, todobus.ErrAssigneeDisabled),
+		errors.Is(err, todobus.ErrAssigneeNotMember),
+		errors.Is(err, todobus.ErrBlobNotFound),
+		errors.Is(err, todobus.ErrFileNotOwned):
+		return errs.New(errs.InvalidArgument, err)
+	default:
+		if ee := quotabus.GetExceeded(err); ee != nil {
+			return errs.New(errs.ResourceExhausted, ee)
+		}
+
+		a.log.Error(ctx, "sync: mutation", "itemID", res.ItemID, "err", err)
+		return errs.Newf(errs.Internal, "Internal Server Error")
+	}
+}
+
+// QueryTodoItemByID handles returning a single TodoItem.
+func (a *app) QueryTodoItemByID(ctx context.Context, _ *http.Request) web.Encoder {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	return toAppTodoItem(item)
+}
+
+// QueryTodoItems handles returning the TodoItems owned by the caller.
+func (a *app) QueryTodoItems(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	return a.query(ctx, r, userID, func(filter *todobus.QueryFilter) {
+		filter.UserID = &userID
+	})
+}
+
+// QueryAssigned handles returning the TodoItems assigned to the caller.
+func (a *app) QueryAssigned(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	return a.query(ctx, r, userID, func(filter *todobus.QueryFilter) {
+		filter.AssigneeID = &userID
+	})
+}
+
+// QueryHistory handles returning the recorded changes for a TodoItem.
+func (a *app) QueryHistory(ctx context.Context, _ *http.Request) web.Encoder {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	hsts, err := a.todoBus.QueryHistory(ctx, item.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryhistory: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppHistories(hsts)
+}
+
+// QueryBoard handles returning the TodoItems the caller owns, optionally in
+// a single list, arranged in the columns of a board.
+func (a *app) QueryBoard(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	filter := todobus.QueryFilter{
+		UserID: &userID,
+	}
+
+	if qp.List != "" {
+		list, err := name.Parse(qp.List)
+		if err != nil {
+			return errs.NewFieldsError("list", err)
+		}
+		filter.List = &list
+	}
+
+	board, err := a.todoBus.QueryBoard(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryboard: %s", err)
+	}
+
+	return toAppBoard(board)
+}
+
+// MoveTodoItem handles dropping a TodoItem at a new place on the board.
+func (a *app) MoveTodoItem(ctx context.Context, r *http.Request) web.Encoder {
+	var app MoveTodoItem
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	mv, err := toBusMove(app)
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	updItem, err := a.todoBus.Move(ctx, item, mv)
+	if err != nil {
+		switch {
+		case errors.Is(err, todobus.ErrNeighbourNotFound):
+			return errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, todobus.ErrInvalidNeighbour):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "move: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppTodoItem(updItem)
+}
+
+// QueryBlockers handles returning the dependencies of a TodoItem on the
+// items it is blocked by.
+func (a *app) QueryBlockers(ctx context.Context, _ *http.Request) web.Encoder {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	deps, err := a.todoBus.QueryBlockers(ctx, item.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryblockers: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppDependencies(deps)
+}
+
+// AddBlocker handles blocking a TodoItem by another one. The caller must be
+// able to see the blocker as well, which the route checks.
+func (a *app) AddBlocker(ctx context.Context, r *http.Request) web.Encoder {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	blocker, err := mid.GetBlocker(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "blocker missing in context: %s", err)
+	}
+
+	dep, err := a.todoBus.AddBlocker(ctx, item, blocker)
+	if err != nil {
+		switch {
+		case errors.Is(err, todobus.ErrSelfDependency), errors.Is(err, todobus.ErrDependencyCycle):
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError("blocker_id", err))
+		case errors.Is(err, todobus.ErrDependencyExists):
+			return errs.New(errs.AlreadyExists, err)
+		}
+		return errs.Newf(errs.Internal, "addblocker: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toAppDependency(dep)
+}
+
+// RemoveBlocker handles removing the dependency of a TodoItem on another
+// one.
+func (a *app) RemoveBlocker(ctx context.Context, r *http.Request) web.Encoder {
+	blockerID, err := uuid.Parse(web.Param(r, "blocker_id"))
+	if err != nil {
+		return errs.NewFieldsError("blocker_id", err)
+	}
+
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	if err := a.todoBus.RemoveBlocker(ctx, item, blockerID); err != nil {
+		if errors.Is(err, todobus.ErrDependencyNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "removeblocker: itemID[%s]: %s", item.ID, err)
+	}
+
+	return nil
+}
+
+// QueryGraph handles returning the dependencies between the TodoItems the
+// caller owns, optionally in a single list, as JSON or, with format=dot, in
+// the Graphviz DOT language.
+func (a *app) QueryGraph(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dot" {
+		return errs.NewFieldsError("format", fmt.Errorf("format must be json or dot"))
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	filter := todobus.QueryFilter{
+		UserID: &userID,
+	}
+
+	if qp.List != "" {
+		list, err := name.Parse(qp.List)
+		if err != nil {
+			return errs.NewFieldsError("list", err)
+		}
+		filter.List = &list
+	}
+
+	graph, err := a.todoBus.QueryGraph(ctx, filter)
+	if err != nil {
+		if errors.Is(err, todobus.ErrGraphTooLarge) {
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "querygraph: %s", err)
+	}
+
+	if format == "dot" {
+		return GraphDOT{Graph: graph}
+	}
+
+	return toAppGraph(graph)
+}
+
+// query runs a paged query for the caller, by page number or by the cursor
+// handed out with the previous page. Smart views are evaluated in the
+// caller's time zone.
+func (a *app) query(ctx context.Context, r *http.Request, userID uuid.UUID, scope func(filter *todobus.QueryFilter)) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, todobus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldsError("order", err)
+	}
+
+	pg, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldsError("page", err)
+	}
+
+	if qp.Cursor != "" {
+		pg, err = page.ParseCursor(a.cursorKey, qp.Cursor, qp.Rows, orderBy)
+		if err != nil {
+			return errs.NewFieldsError("cursor", err)
+		}
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	filter, err := parseFilter(qp, time.Now(), usr.TimeZone.Location())
+	if err != nil {
+		return err.(errs.FieldErrors)
+	}
+	scope(&filter)
+
+	items, err := a.todoBus.Query(ctx, filter, orderBy, pg)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.todoBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	prev, next := page.Adjacent(a.cursorKey, pg, items, func(item todobus.TodoItem) page.Cursor {
+		return todobus.Cursor(item, orderBy)
+	})
+
+	return query.NewResult(toAppTodoItems(items), total, pg).WithCursors(prev, next)
+}
+
+// UploadFile handles file uploads and stores them in an S3 bucket. The
+// business layer decides what is acceptable, this only bounds how much of
+// the request is read.
+func (a *app) UploadFile(ctx context.Context, r *http.Request) web.Encoder {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxUploadSize)
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	// The organization role only grants access within the organization, the
+	// upload limits go by the roles of the user.
+	claimRoles := slices.DeleteFunc(slices.Clone(mid.GetClaims(ctx).Roles), func(r string) bool {
+		return r == auth.RoleOrgAdmin
+	})
+
+	roles, err := role.ParseMany(claimRoles)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	fileData, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError(upload.FieldFile, fmt.Errorf("file size exceeds the maximum request size of %d bytes", maxErr.Limit)))
+		}
+		return errs.New(errs.InvalidArgument, fmt.Errorf("error reading file data: %w", err))
+	}
+
+	file := upload.File{
+		Name:  r.URL.Query().Get("filename"),
+		Data:  fileData,
+		Roles: roles,
+	}
+
+	att, err := a.todoBus.UploadFile(ctx, userID, file)
+	if err != nil {
+		if rej := upload.GetRejection(err); rej != nil {
+			return errs.New(errs.InvalidArgument, errs.NewFieldsError(rej.Field, rej))
+		}
+		if ee := quotabus.GetExceeded(err); ee != nil {
+			return errs.New(errs.ResourceExhausted, ee)
+		}
 		return errs.New(errs.Internal, fmt.Errorf("error uploading file: %w", err))
 	}
 
 	// Return the file ID in the response using the FileUploadResponse model
 	return FileUploadResponse{
-		FileID: fileID,
+		FileID:       att.FileID,
+		AttachmentID: att.ID.String(),
+		Checksum:     att.Checksum,
 	}
 }
 
-// DownloadFile handles downloading a file from S3.
-func (a *app) DownloadFile(ctx context.Context, r *http.Request) web.Encoder {
-	// Extract the file ID from URL parameters
-	qp, err := parseQueryParams(r)
+// DeleteAttachment removes an attachment. Only the user who uploaded it or an
+// admin of the organization may do so, which the route checks.
+func (a *app) DeleteAttachment(ctx context.Context, r *http.Request) web.Encoder {
+	att, err := mid.GetAttachment(ctx)
 	if err != nil {
-		return errs.New(errs.InvalidArgument, err)
+		return errs.Newf(errs.Internal, "attachment missing in context: %s", err)
+	}
+
+	if err := a.todoBus.DeleteAttachment(ctx, att); err != nil {
+		return errs.Newf(errs.Internal, "deleteattachment: attachmentID[%s]: %s", att.ID, err)
+	}
+
+	return nil
+}
+
+// QueryRendition returns a downscaled rendition of an image attachment.
+// Renditions are rendered in the background, so one may not exist yet
+// straight after the upload. Only the uploader, an admin of the organization
+// or a user who can see a todo item holding the same contents may read them.
+func (a *app) QueryRendition(ctx context.Context, r *http.Request) web.Encoder {
+	size, err := strconv.Atoi(web.Param(r, "size"))
+	if err != nil || !slices.Contains(todobus.RenditionSizes, size) {
+		return errs.NewFieldsError("size", fmt.Errorf("size must be one of %v", todobus.RenditionSizes))
+	}
+
+	att, err := mid.GetAttachment(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "attachment missing in context: %s", err)
+	}
+
+	rnd, data, err := a.todoBus.QueryRendition(ctx, att, size)
+	if err != nil {
+		if errors.Is(err, todobus.ErrRenditionNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "queryrendition: attachmentID[%s]: %s", att.ID, err)
+	}
+
+	if w := web.GetWriter(ctx); w != nil {
+		w.Header().Set("Cache-Control", "private, max-age=86400, immutable")
+	}
+
+	return Rendition{
+		ContentType: rnd.ContentType,
+		Data:        data,
+	}
+}
+
+// DownloadFile handles downloading a file from S3. Contents are shared by
+// every attachment holding them, so the caller must be able to see one of
+// them rather than just know the file id.
+func (a *app) DownloadFile(ctx context.Context, r *http.Request) web.Encoder {
+	fileID := web.Param(r, "file_id")
+
+	// Files stored before contents were deduplicated have no blob, so they
+	// are served without a checksum.
+	blob, err := a.todoBus.QueryBlobByFileID(ctx, fileID)
+	if err != nil && !errors.Is(err, todobus.ErrBlobNotFound) {
+		return errs.Newf(errs.Internal, "queryblobbyfileid: fileID[%s]: %s", fileID, err)
 	}
 
 	// Retrieve the file data from S3 using the business layer
-	fileData, err := a.todoBus.GetFile(ctx, qp.ID)
+	fileData, err := a.todoBus.GetFile(ctx, fileID)
 	if err != nil {
 		return errs.New(errs.Internal, fmt.Errorf("error retrieving file: %w", err))
 	}
 
-	// Set a default file name for the downloaded file
-	fileName := "downloaded-file"
+	contentType := blob.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
 
 	// Create a response to download the file
 	return FileResponse{
-		FileName:    fileName,
-		ContentType: "application/octet-stream",
+		FileID:      fileID,
+		FileName:    "downloaded-file",
+		ContentType: contentType,
+		Checksum:    blob.Checksum,
 		Data:        fileData,
 	}
 }
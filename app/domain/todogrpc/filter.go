@@ -0,0 +1,57 @@
+package todogrpc
+
+import (
+	"time"
+
+	"github.com/himynamej/todo/app/domain/todogrpc/todopb"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+var orderByFields = map[string]string{
+	"item_id":      todobus.OrderByID,
+	"description":  todobus.OrderByDescription,
+	"due_date":     todobus.OrderByDueDate,
+	"status":       todobus.OrderByStatus,
+	"date_created": todobus.OrderByDateCreated,
+}
+
+func parseFilter(req *todopb.ListTodosRequest, now time.Time, loc *time.Location) (todobus.QueryFilter, error) {
+	var filter todobus.QueryFilter
+
+	if req.GetView() != "" {
+		view, err := todobus.ParseView(req.GetView())
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("view", err)
+		}
+		view.Apply(&filter, now, loc)
+	}
+
+	if req.GetStatus() != todopb.Status_STATUS_UNSPECIFIED {
+		sts, err := toBusStatus(req.GetStatus())
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("status", err)
+		}
+		filter.Status = &sts
+	}
+
+	if req.GetList() != "" {
+		list, err := name.Parse(req.GetList())
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("list", err)
+		}
+		filter.List = &list
+	}
+
+	if req.GetLabel() != "" {
+		l, err := label.Parse(req.GetLabel())
+		if err != nil {
+			return todobus.QueryFilter{}, errs.NewFieldsError("label", err)
+		}
+		filter.Label = &l
+	}
+
+	return filter, nil
+}
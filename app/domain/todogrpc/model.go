@@ -0,0 +1,136 @@
+package todogrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/domain/todogrpc/todopb"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var statuses = map[status.Status]todopb.Status{
+	status.Open:       todopb.Status_STATUS_OPEN,
+	status.InProgress: todopb.Status_STATUS_IN_PROGRESS,
+	status.Done:       todopb.Status_STATUS_DONE,
+}
+
+func toPBStatus(sts status.Status) todopb.Status {
+	return statuses[sts]
+}
+
+func toBusStatus(pb todopb.Status) (status.Status, error) {
+	for sts, v := range statuses {
+		if v == pb {
+			return sts, nil
+		}
+	}
+
+	return status.Status{}, fmt.Errorf("invalid status %q", pb)
+}
+
+func toPBTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+// toBusDueDate returns the due date as the business layer keeps it. All day
+// due dates are kept as midnight UTC of their day, as the http api parses
+// a date without a time.
+func toBusDueDate(ts *timestamppb.Timestamp, allDay bool) (time.Time, bool, error) {
+	if ts == nil {
+		return time.Time{}, false, nil
+	}
+
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, false, err
+	}
+
+	t := ts.AsTime()
+	if allDay {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	return t, allDay, nil
+}
+
+func toPBTodo(bus todobus.TodoItem) *todopb.Todo {
+	var assigneeID string
+	if bus.AssigneeID != uuid.Nil {
+		assigneeID = bus.AssigneeID.String()
+	}
+
+	return &todopb.Todo{
+		Id:            bus.ID.String(),
+		UserId:        bus.UserID.String(),
+		AssigneeId:    assigneeID,
+		Description:   bus.Description,
+		DueDate:       toPBTime(bus.DueDate),
+		AllDay:        bus.AllDay,
+		FileId:        bus.FileID,
+		Status:        toPBStatus(bus.Status),
+		Priority:      bus.Priority.String(),
+		Recurrence:    bus.Recurrence.String(),
+		List:          bus.List.String(),
+		Labels:        label.ParseToString(bus.Labels),
+		DateCreated:   toPBTime(bus.DateCreated),
+		DateUpdated:   toPBTime(bus.DateUpdated),
+		DateCompleted: toPBTime(bus.DateCompleted),
+	}
+}
+
+func toPBEvent(bus streambus.Event) (*todopb.TodoEvent, error) {
+	evt := todopb.TodoEvent{
+		Id:          bus.ID,
+		Type:        bus.Type,
+		DateCreated: toPBTime(bus.DateCreated),
+	}
+
+	if bus.PreviousAssigneeID != uuid.Nil {
+		evt.PreviousAssigneeId = bus.PreviousAssigneeID.String()
+	}
+
+	if bus.Type == streambus.TypeAssigned {
+		evt.Todo = &todopb.Todo{
+			Id:     bus.TodoID.String(),
+			UserId: bus.OwnerID.String(),
+			List:   bus.List,
+		}
+		if bus.AssigneeID != uuid.Nil {
+			evt.Todo.AssigneeId = bus.AssigneeID.String()
+		}
+
+		return &evt, nil
+	}
+
+	var parms todobus.ActionItemParms
+	if err := json.Unmarshal(bus.Data, &parms); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	evt.Todo = &todopb.Todo{
+		Id:          parms.TodoID.String(),
+		UserId:      parms.OwnerID.String(),
+		Description: parms.Description,
+		DueDate:     toPBTime(parms.DueDate),
+		AllDay:      parms.AllDay,
+		Status:      toPBStatus(parms.Status),
+		Priority:    parms.Priority.String(),
+		Recurrence:  parms.Recurrence.String(),
+		List:        parms.List,
+		Labels:      parms.Labels,
+	}
+	if parms.AssigneeID != uuid.Nil {
+		evt.Todo.AssigneeId = parms.AssigneeID.String()
+	}
+
+	return &evt, nil
+}
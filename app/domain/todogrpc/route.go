@@ -0,0 +1,41 @@
+package todogrpc
+
+import (
+	"github.com/himynamej/todo/app/domain/todogrpc/todopb"
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/app/sdk/rpc"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/foundation/logger"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	TodoBus    *todobus.Business
+	UserBus    *userbus.Business
+	StreamBus  *streambus.Business
+	AuthClient *authclient.Client
+	Shutdown   <-chan struct{} // Closed when the server starts shutting down
+}
+
+// Routes binds the todo service to the server.
+func Routes(srv *rpc.Server, cfg Config) {
+	authen := mid.Authenticate(cfg.AuthClient)
+	itemID := rpc.Param("item_id", func(req interface{ GetId() string }) string { return req.GetId() })
+	ruleAuthorizeOwner := mid.AuthorizeTodo(cfg.AuthClient, cfg.TodoBus, auth.RuleAdminOrSubject)
+	ruleAuthorizeAssignee := mid.AuthorizeTodoAssignee(cfg.AuthClient, cfg.TodoBus, auth.RuleAdminOrSubject)
+
+	api := newServer(cfg.Log, cfg.TodoBus, cfg.UserBus, cfg.StreamBus, cfg.Shutdown)
+	todopb.RegisterTodoServiceServer(srv, api)
+
+	srv.Handle(todopb.TodoService_CreateTodo_FullMethodName, authen)
+	srv.Handle(todopb.TodoService_GetTodo_FullMethodName, authen, itemID, ruleAuthorizeAssignee)
+	srv.Handle(todopb.TodoService_UpdateTodo_FullMethodName, authen, itemID, ruleAuthorizeOwner)
+	srv.Handle(todopb.TodoService_DeleteTodo_FullMethodName, authen, itemID, ruleAuthorizeOwner)
+	srv.Handle(todopb.TodoService_ListTodos_FullMethodName, authen)
+	srv.Handle(todopb.TodoService_WatchTodos_FullMethodName, authen)
+}
@@ -0,0 +1,415 @@
+// Package todogrpc maintains the gRPC api for the todo domain, served to
+// internal service-to-service callers next to the http api.
+package todogrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/domain/todogrpc/todopb"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/recurrence"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/foundation/logger"
+)
+
+// listRows is how many items are read at a time for a list call.
+const listRows = 100
+
+// backlog is the most events a resuming caller is sent to catch up. A caller
+// further behind is told to reset instead.
+const backlog = 500
+
+// eventReset tells the caller it missed events that can no longer be sent,
+// so it has to list the items again.
+const eventReset = "reset"
+
+type server struct {
+	todopb.UnimplementedTodoServiceServer
+	log       *logger.Logger
+	todoBus   *todobus.Business
+	userBus   *userbus.Business
+	streamBus *streambus.Business
+	shutdown  <-chan struct{}
+}
+
+func newServer(log *logger.Logger, todoBus *todobus.Business, userBus *userbus.Business, streamBus *streambus.Business, shutdown <-chan struct{}) *server {
+	return &server{
+		log:       log,
+		todoBus:   todoBus,
+		userBus:   userBus,
+		streamBus: streamBus,
+		shutdown:  shutdown,
+	}
+}
+
+// CreateTodo handles creating a new TodoItem.
+func (s *server) CreateTodo(ctx context.Context, req *todopb.CreateTodoRequest) (*todopb.Todo, error) {
+	if req.GetDescription() == "" {
+		return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("description", errors.New("description is a required field")))
+	}
+
+	dueDate, allDay, err := toBusDueDate(req.GetDueDate(), req.GetAllDay())
+	if err != nil {
+		return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("dueDate", err))
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return nil, errs.New(errs.Unauthenticated, err)
+	}
+
+	var assigneeID uuid.UUID
+	if req.GetAssigneeId() != "" {
+		assigneeID, err = uuid.Parse(req.GetAssigneeId())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("assigneeId", err))
+		}
+	}
+
+	list, err := name.ParseNull(req.GetList())
+	if err != nil {
+		return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("list", err))
+	}
+
+	labels, err := label.ParseMany(req.GetLabels())
+	if err != nil {
+		return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("labels", err))
+	}
+
+	var pri priority.Priority
+	if req.GetPriority() != "" {
+		if pri, err = priority.Parse(req.GetPriority()); err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("priority", err))
+		}
+	}
+
+	rule, err := recurrence.Parse(req.GetRecurrence())
+	if err != nil {
+		return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("recurrence", err))
+	}
+
+	nt := todobus.NewTodoItem{
+		UserID:      userID,
+		AssigneeID:  assigneeID,
+		Description: req.GetDescription(),
+		DueDate:     dueDate,
+		AllDay:      allDay,
+		Priority:    pri,
+		Recurrence:  rule,
+		List:        list,
+		Labels:      labels,
+		FileID:      req.GetFileId(),
+	}
+
+	item, err := s.todoBus.Create(ctx, nt)
+	if err != nil {
+		if errors.Is(err, todobus.ErrAssigneeNotFound) || errors.Is(err, todobus.ErrAssigneeDisabled) || errors.Is(err, todobus.ErrAssigneeNotMember) {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("assigneeId", err))
+		}
+		if errors.Is(err, todobus.ErrBlobNotFound) || errors.Is(err, todobus.ErrFileNotOwned) {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("fileId", err))
+		}
+		if ee := quotabus.GetExceeded(err); ee != nil {
+			return nil, errs.New(errs.ResourceExhausted, ee)
+		}
+		return nil, errs.New(errs.Internal, err)
+	}
+
+	return toPBTodo(item), nil
+}
+
+// GetTodo handles returning a single TodoItem.
+func (s *server) GetTodo(ctx context.Context, _ *todopb.GetTodoRequest) (*todopb.Todo, error) {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	return toPBTodo(item), nil
+}
+
+// UpdateTodo handles changing the fields of a TodoItem that are set.
+func (s *server) UpdateTodo(ctx context.Context, req *todopb.UpdateTodoRequest) (*todopb.Todo, error) {
+	var ut todobus.UpdateTodoItem
+
+	if req.Description != nil {
+		ut.Description = req.Description
+	}
+
+	switch {
+	case req.GetClearDueDate():
+		var zero time.Time
+		var allDay bool
+		ut.DueDate = &zero
+		ut.AllDay = &allDay
+
+	case req.GetDueDate() != nil:
+		dueDate, allDay, err := toBusDueDate(req.GetDueDate(), req.GetAllDay())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("dueDate", err))
+		}
+		ut.DueDate = &dueDate
+		ut.AllDay = &allDay
+	}
+
+	if req.Status != nil {
+		sts, err := toBusStatus(req.GetStatus())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("status", err))
+		}
+		ut.Status = &sts
+	}
+
+	if req.Priority != nil {
+		pri, err := priority.Parse(req.GetPriority())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("priority", err))
+		}
+		ut.Priority = &pri
+	}
+
+	if req.Recurrence != nil {
+		rule, err := recurrence.Parse(req.GetRecurrence())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("recurrence", err))
+		}
+		ut.Recurrence = &rule
+	}
+
+	if req.List != nil {
+		list, err := name.ParseNull(req.GetList())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("list", err))
+		}
+		ut.List = &list
+	}
+
+	// A non-nil empty slice clears the labels.
+	if req.GetSetLabels() {
+		labels, err := label.ParseMany(req.GetLabels())
+		if err != nil {
+			return nil, errs.New(errs.InvalidArgument, errs.NewFieldsError("labels", err))
+		}
+		if labels == nil {
+			labels = []label.Label{}
+		}
+		ut.Labels = labels
+	}
+
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	updItem, err := s.todoBus.Update(ctx, item, ut)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "update: itemID[%s]: %s", item.ID, err)
+	}
+
+	return toPBTodo(updItem), nil
+}
+
+// DeleteTodo handles removing a TodoItem.
+func (s *server) DeleteTodo(ctx context.Context, _ *todopb.DeleteTodoRequest) (*todopb.DeleteTodoResponse, error) {
+	item, err := mid.GetTodo(ctx)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "todo missing in context: %s", err)
+	}
+
+	if err := s.todoBus.Delete(ctx, item); err != nil {
+		return nil, errs.Newf(errs.Internal, "delete: itemID[%s]: %s", item.ID, err)
+	}
+
+	return &todopb.DeleteTodoResponse{}, nil
+}
+
+// ListTodos handles sending every TodoItem the caller owns, or is assigned,
+// that passes the filter. The items are read a page at a time, each page
+// picking up after the last item sent. Smart views are evaluated in the
+// caller's time zone.
+func (s *server) ListTodos(req *todopb.ListTodosRequest, stream todopb.TodoService_ListTodosServer) error {
+	ctx := stream.Context()
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := s.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	filter, err := parseFilter(req, time.Now(), usr.TimeZone.Location())
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	switch {
+	case req.GetAssigned():
+		filter.AssigneeID = &userID
+	default:
+		filter.UserID = &userID
+	}
+
+	orderBy, err := order.Parse(orderByFields, req.GetOrderBy(), todobus.DefaultOrderBy)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("orderBy", err))
+	}
+
+	pg, err := page.Parse("1", strconv.Itoa(listRows))
+	if err != nil {
+		return errs.Newf(errs.Internal, "page: %s", err)
+	}
+
+	for {
+		items, err := s.todoBus.Query(ctx, filter, orderBy, pg)
+		if err != nil {
+			return errs.Newf(errs.Internal, "query: %s", err)
+		}
+
+		for _, item := range items {
+			if err := stream.Send(toPBTodo(item)); err != nil {
+				return errs.Newf(errs.Internal, "send: itemID[%s]: %s", item.ID, err)
+			}
+		}
+
+		if len(items) < listRows {
+			return nil
+		}
+
+		if pg, err = page.FromCursor(todobus.Cursor(items[len(items)-1], orderBy), listRows); err != nil {
+			return errs.Newf(errs.Internal, "page: %s", err)
+		}
+	}
+}
+
+// WatchTodos handles sending the changes made to the items the caller owns
+// or is assigned, or to every item for admins. A caller that sets the id of
+// the last event it received is first sent what it missed.
+func (s *server) WatchTodos(req *todopb.WatchTodosRequest, stream todopb.TodoService_WatchTodosServer) error {
+	ctx := stream.Context()
+
+	orgID, err := mid.GetOrgID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	filter := streambus.Filter{
+		OrgID: &orgID,
+	}
+
+	if !slices.Contains(mid.GetClaims(ctx).Roles, role.Admin.String()) {
+		userID, err := mid.GetUserID(ctx)
+		if err != nil {
+			return errs.New(errs.Unauthenticated, err)
+		}
+		filter.UserID = &userID
+	}
+
+	if list := req.GetList(); list != "" {
+		filter.List = &list
+	}
+
+	// Subscribe before catching up so nothing recorded in between is missed.
+	// Whatever arrives twice is skipped by id.
+
+	sub, err := s.streamBus.Subscribe(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Unavailable, "subscribe: %s", err)
+	}
+	defer sub.Close()
+
+	lastID := req.GetLastEventId()
+
+	if lastID > 0 {
+		if lastID, err = s.catchUp(ctx, stream, filter, lastID); err != nil {
+			return errs.Newf(errs.Internal, "catchup: %s", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		// Watches never finish on their own, so they are ended for the
+		// server to shut down. Callers watch again on another instance.
+		case <-s.shutdown:
+			return nil
+
+		case evt, ok := <-sub.Events():
+			if !ok {
+				s.log.Info(ctx, "watch: subscription ended", "lastEventID", lastID)
+				return errs.Newf(errs.Unavailable, "subscription ended, watch again from event %d", lastID)
+			}
+
+			if evt.ID <= lastID {
+				continue
+			}
+
+			if err := send(stream, evt); err != nil {
+				return errs.Newf(errs.Internal, "send: eventID[%d]: %s", evt.ID, err)
+			}
+			lastID = evt.ID
+		}
+	}
+}
+
+// catchUp sends the events recorded after the one the caller saw last and
+// returns the id of the last event sent. A caller too far behind is told to
+// reset and is only sent new events from then on.
+func (s *server) catchUp(ctx context.Context, stream todopb.TodoService_WatchTodosServer, filter streambus.Filter, lastID int64) (int64, error) {
+	resumable, err := s.streamBus.Resumable(ctx, lastID)
+	if err != nil {
+		return 0, err
+	}
+
+	var evts []streambus.Event
+	if resumable {
+		if evts, err = s.streamBus.QueryAfter(ctx, filter, lastID, backlog+1); err != nil {
+			return 0, err
+		}
+	}
+
+	if !resumable || len(evts) > backlog {
+		if err := stream.Send(&todopb.TodoEvent{Type: eventReset}); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	for _, evt := range evts {
+		if err := send(stream, evt); err != nil {
+			return 0, err
+		}
+		lastID = evt.ID
+	}
+
+	return lastID, nil
+}
+
+func send(stream todopb.TodoService_WatchTodosServer, evt streambus.Event) error {
+	pb, err := toPBEvent(evt)
+	if err != nil {
+		return fmt.Errorf("eventID[%d]: %w", evt.ID, err)
+	}
+
+	return stream.Send(pb)
+}
@@ -17,6 +17,7 @@ func parseQueryParams(r *http.Request) (queryParams, error) {
 	filter := queryParams{
 		Page:             values.Get("page"),
 		Rows:             values.Get("row"),
+		Cursor:           values.Get("cursor"),
 		OrderBy:          values.Get("orderBy"),
 		ID:               values.Get("user_id"),
 		Name:             values.Get("name"),
@@ -10,11 +10,13 @@ import (
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/types/name"
 	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/timezone"
 )
 
 type queryParams struct {
 	Page             string
 	Rows             string
+	Cursor           string
 	OrderBy          string
 	ID               string
 	Name             string
@@ -33,6 +35,7 @@ type User struct {
 	Roles        []string `json:"roles"`
 	PasswordHash []byte   `json:"-"`
 	Department   string   `json:"department"`
+	TimeZone     string   `json:"timeZone"`
 	Enabled      bool     `json:"enabled"`
 	DateCreated  string   `json:"dateCreated"`
 	DateUpdated  string   `json:"dateUpdated"`
@@ -52,6 +55,7 @@ func toAppUser(bus userbus.User) User {
 		Roles:        role.ParseToString(bus.Roles),
 		PasswordHash: bus.PasswordHash,
 		Department:   bus.Department.String(),
+		TimeZone:     bus.TimeZone.String(),
 		Enabled:      bus.Enabled,
 		DateCreated:  bus.DateCreated.Format(time.RFC3339),
 		DateUpdated:  bus.DateUpdated.Format(time.RFC3339),
@@ -75,6 +79,7 @@ type NewUser struct {
 	Email           string   `json:"email" validate:"required,email"`
 	Roles           []string `json:"roles" validate:"required"`
 	Department      string   `json:"department"`
+	TimeZone        string   `json:"timeZone"`
 	Password        string   `json:"password" validate:"required"`
 	PasswordConfirm string   `json:"passwordConfirm" validate:"eqfield=Password"`
 }
@@ -114,11 +119,20 @@ func toBusNewUser(app NewUser) (userbus.NewUser, error) {
 		return userbus.NewUser{}, fmt.Errorf("parse: %w", err)
 	}
 
+	tz := timezone.UTC
+	if app.TimeZone != "" {
+		tz, err = timezone.Parse(app.TimeZone)
+		if err != nil {
+			return userbus.NewUser{}, fmt.Errorf("parse: %w", err)
+		}
+	}
+
 	bus := userbus.NewUser{
 		Name:       nme,
 		Email:      *addr,
 		Roles:      roles,
 		Department: department,
+		TimeZone:   tz,
 		Password:   app.Password,
 	}
 
@@ -170,6 +184,7 @@ type UpdateUser struct {
 	Name            *string `json:"name"`
 	Email           *string `json:"email" validate:"omitempty,email"`
 	Department      *string `json:"department"`
+	TimeZone        *string `json:"timeZone"`
 	Password        *string `json:"password"`
 	PasswordConfirm *string `json:"passwordConfirm" validate:"omitempty,eqfield=Password"`
 	Enabled         *bool   `json:"enabled"`
@@ -217,10 +232,20 @@ func toBusUpdateUser(app UpdateUser) (userbus.UpdateUser, error) {
 		department = &dep
 	}
 
+	var tz *timezone.TimeZone
+	if app.TimeZone != nil {
+		t, err := timezone.Parse(*app.TimeZone)
+		if err != nil {
+			return userbus.UpdateUser{}, fmt.Errorf("parse: %w", err)
+		}
+		tz = &t
+	}
+
 	bus := userbus.UpdateUser{
 		Name:       nme,
 		Email:      addr,
 		Department: department,
+		TimeZone:   tz,
 		Password:   app.Password,
 		Enabled:    app.Enabled,
 	}
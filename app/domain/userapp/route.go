@@ -6,6 +6,7 @@ import (
 	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/himynamej/todo/foundation/web"
@@ -13,9 +14,11 @@ import (
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
-	Log        *logger.Logger
-	UserBus    *userbus.Business
-	AuthClient *authclient.Client
+	Log            *logger.Logger
+	UserBus        *userbus.Business
+	IdempotencyBus *idempotencybus.Business
+	AuthClient     *authclient.Client
+	CursorKey      []byte // Signs the cursors handed out with each page
 }
 
 // Routes adds specific routes for this group.
@@ -26,12 +29,13 @@ func Routes(app *web.App, cfg Config) {
 	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
 	ruleAuthorizeUser := mid.AuthorizeUser(cfg.AuthClient, cfg.UserBus, auth.RuleAdminOrSubject)
 	ruleAuthorizeAdmin := mid.AuthorizeUser(cfg.AuthClient, cfg.UserBus, auth.RuleAdminOnly)
+	idempotent := mid.Idempotent(cfg.Log, cfg.IdempotencyBus, mid.IdempotencyMaxBody)
 
-	api := newApp(cfg.UserBus)
+	api := newApp(cfg.UserBus, cfg.CursorKey)
 
 	app.HandlerFunc(http.MethodGet, version, "/users", api.query, authen, ruleAdmin)
 	app.HandlerFunc(http.MethodGet, version, "/users/{user_id}", api.queryByID, authen, ruleAuthorizeUser)
-	app.HandlerFunc(http.MethodPost, version, "/users", api.create, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodPost, version, "/users", api.create, authen, ruleAdmin, idempotent)
 	app.HandlerFunc(http.MethodPut, version, "/users/role/{user_id}", api.updateRole, authen, ruleAuthorizeAdmin)
 	app.HandlerFunc(http.MethodPut, version, "/users/{user_id}", api.update, authen, ruleAuthorizeUser)
 	app.HandlerFunc(http.MethodDelete, version, "/users/{user_id}", api.delete, authen, ruleAuthorizeUser)
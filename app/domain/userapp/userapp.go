@@ -16,12 +16,14 @@ import (
 )
 
 type app struct {
-	userBus *userbus.Business
+	userBus   *userbus.Business
+	cursorKey []byte
 }
 
-func newApp(userBus *userbus.Business) *app {
+func newApp(userBus *userbus.Business, cursorKey []byte) *app {
 	return &app{
-		userBus: userBus,
+		userBus:   userBus,
+		cursorKey: cursorKey,
 	}
 }
 
@@ -114,22 +116,29 @@ func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	page, err := page.Parse(qp.Page, qp.Rows)
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, userbus.DefaultOrderBy)
 	if err != nil {
-		return errs.NewFieldsError("page", err)
+		return errs.NewFieldsError("order", err)
 	}
 
-	filter, err := parseFilter(qp)
+	pg, err := page.Parse(qp.Page, qp.Rows)
 	if err != nil {
-		return err.(errs.FieldErrors)
+		return errs.NewFieldsError("page", err)
 	}
 
-	orderBy, err := order.Parse(orderByFields, qp.OrderBy, userbus.DefaultOrderBy)
+	if qp.Cursor != "" {
+		pg, err = page.ParseCursor(a.cursorKey, qp.Cursor, qp.Rows, orderBy)
+		if err != nil {
+			return errs.NewFieldsError("cursor", err)
+		}
+	}
+
+	filter, err := parseFilter(qp)
 	if err != nil {
-		return errs.NewFieldsError("order", err)
+		return err.(errs.FieldErrors)
 	}
 
-	usrs, err := a.userBus.Query(ctx, filter, orderBy, page)
+	usrs, err := a.userBus.Query(ctx, filter, orderBy, pg)
 	if err != nil {
 		return errs.Newf(errs.Internal, "query: %s", err)
 	}
@@ -139,7 +148,11 @@ func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "count: %s", err)
 	}
 
-	return query.NewResult(toAppUsers(usrs), total, page)
+	prev, next := page.Adjacent(a.cursorKey, pg, usrs, func(usr userbus.User) page.Cursor {
+		return userbus.Cursor(usr, orderBy)
+	})
+
+	return query.NewResult(toAppUsers(usrs), total, pg).WithCursors(prev, next)
 }
 
 func (a *app) queryByID(ctx context.Context, _ *http.Request) web.Encoder {
@@ -0,0 +1,196 @@
+package webhookapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+)
+
+// Subscription represents a webhook subscription. The secret is only
+// returned when the subscription is created or the secret is changed.
+type Subscription struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"userId"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	AllUsers    bool     `json:"allUsers"`
+	Status      string   `json:"status"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Subscription) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppSubscription(bus webhookbus.Subscription) Subscription {
+	return Subscription{
+		ID:          bus.ID.String(),
+		UserID:      bus.UserID.String(),
+		URL:         bus.URL,
+		Events:      bus.Events,
+		AllUsers:    bus.AllUsers,
+		Status:      bus.Status,
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+		DateUpdated: bus.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// Subscriptions is a collection of subscriptions.
+type Subscriptions []Subscription
+
+// Encode implements the encoder interface.
+func (app Subscriptions) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppSubscriptions(subs []webhookbus.Subscription) Subscriptions {
+	app := make(Subscriptions, len(subs))
+	for i, sub := range subs {
+		app[i] = toAppSubscription(sub)
+	}
+
+	return app
+}
+
+// Events lists the events a subscription can ask for.
+type Events []string
+
+// Encode implements the encoder interface.
+func (app Events) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// NewSubscription defines the data needed to add a new subscription. A
+// secret is generated when none is provided, and only admins may subscribe
+// to the events of all users.
+type NewSubscription struct {
+	URL      string   `json:"url" validate:"required,url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events" validate:"required,min=1"`
+	AllUsers bool     `json:"allUsers"`
+}
+
+// Encode implements the encoder interface.
+func (app NewSubscription) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *NewSubscription) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewSubscription) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// UpdateSubscription defines the data needed to update a subscription.
+// Setting active to true starts a failed or paused subscription again.
+type UpdateSubscription struct {
+	URL    *string  `json:"url" validate:"omitempty,url"`
+	Secret *string  `json:"secret" validate:"omitempty,min=1"`
+	Events []string `json:"events" validate:"omitempty,min=1"`
+	Active *bool    `json:"active"`
+}
+
+// Encode implements the encoder interface.
+func (app UpdateSubscription) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateSubscription) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateSubscription) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateSubscription(app UpdateSubscription) webhookbus.UpdateSubscription {
+	return webhookbus.UpdateSubscription{
+		URL:    app.URL,
+		Secret: app.Secret,
+		Events: app.Events,
+		Active: app.Active,
+	}
+}
+
+// =============================================================================
+
+// Delivery represents one event sent, or being sent, to a subscription.
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	EventID        string          `json:"eventId"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseCode   int             `json:"responseCode,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	NextAttempt    string          `json:"nextAttempt,omitempty"`
+	DateCreated    string          `json:"dateCreated"`
+	DateUpdated    string          `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Delivery) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppDelivery(bus webhookbus.Delivery) Delivery {
+	app := Delivery{
+		ID:             bus.ID.String(),
+		SubscriptionID: bus.SubscriptionID.String(),
+		EventID:        bus.EventID.String(),
+		Event:          bus.Event,
+		Payload:        bus.Payload,
+		Status:         bus.Status,
+		Attempts:       bus.Attempts,
+		ResponseCode:   bus.ResponseCode,
+		Error:          bus.Error,
+		DateCreated:    bus.DateCreated.Format(time.RFC3339),
+		DateUpdated:    bus.DateUpdated.Format(time.RFC3339),
+	}
+
+	if bus.Status == webhookbus.DeliveryPending {
+		app.NextAttempt = bus.NextAttempt.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+func toAppDeliveries(dlvs []webhookbus.Delivery) []Delivery {
+	app := make([]Delivery, len(dlvs))
+	for i, dlv := range dlvs {
+		app[i] = toAppDelivery(dlv)
+	}
+
+	return app
+}
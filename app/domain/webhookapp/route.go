@@ -0,0 +1,38 @@
+package webhookapp
+
+import (
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/app/sdk/authclient"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	WebhookBus *webhookbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAuthorizeWebhook := mid.AuthorizeWebhook(cfg.AuthClient, cfg.WebhookBus, auth.RuleAdminOrSubject)
+
+	api := newApp(cfg.WebhookBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/webhooks/events", api.queryEvents, authen)
+	app.HandlerFunc(http.MethodGet, version, "/webhooks", api.query, authen)
+	app.HandlerFunc(http.MethodPost, version, "/webhooks", api.create, authen)
+	app.HandlerFunc(http.MethodGet, version, "/webhooks/{subscription_id}", api.queryByID, authen, ruleAuthorizeWebhook)
+	app.HandlerFunc(http.MethodPut, version, "/webhooks/{subscription_id}", api.update, authen, ruleAuthorizeWebhook)
+	app.HandlerFunc(http.MethodDelete, version, "/webhooks/{subscription_id}", api.delete, authen, ruleAuthorizeWebhook)
+	app.HandlerFunc(http.MethodGet, version, "/webhooks/{subscription_id}/deliveries", api.queryDeliveries, authen, ruleAuthorizeWebhook)
+	app.HandlerFunc(http.MethodPost, version, "/webhooks/{subscription_id}/deliveries/{delivery_id}/redeliver", api.redeliver, authen, ruleAuthorizeWebhook)
+}
@@ -0,0 +1,192 @@
+// Package webhookapp maintains the app layer api for webhook subscriptions.
+package webhookapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/app/sdk/query"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+type app struct {
+	webhookBus *webhookbus.Business
+}
+
+func newApp(webhookBus *webhookbus.Business) *app {
+	return &app{
+		webhookBus: webhookBus,
+	}
+}
+
+func (a *app) queryEvents(ctx context.Context, _ *http.Request) web.Encoder {
+	return Events(webhookbus.Events)
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewSubscription
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	if app.AllUsers && !slices.Contains(mid.GetClaims(ctx).Roles, role.Admin.String()) {
+		return errs.New(errs.PermissionDenied, errors.New("only admins may subscribe to the events of all users"))
+	}
+
+	ns := webhookbus.NewSubscription{
+		UserID:   userID,
+		URL:      app.URL,
+		Secret:   app.Secret,
+		Events:   app.Events,
+		AllUsers: app.AllUsers,
+	}
+
+	sub, err := a.webhookBus.Create(ctx, ns)
+	if err != nil {
+		return toAppError("create", err)
+	}
+
+	resp := toAppSubscription(sub)
+	resp.Secret = sub.Secret
+
+	return resp
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateSubscription
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	sub, err := mid.GetSubscription(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "webhook subscription missing in context: %s", err)
+	}
+
+	updSub, err := a.webhookBus.Update(ctx, sub, toBusUpdateSubscription(app))
+	if err != nil {
+		return toAppError(fmt.Sprintf("update: subscriptionID[%s]", sub.ID), err)
+	}
+
+	resp := toAppSubscription(updSub)
+	if app.Secret != nil {
+		resp.Secret = updSub.Secret
+	}
+
+	return resp
+}
+
+func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
+	sub, err := mid.GetSubscription(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "webhook subscription missing in context: %s", err)
+	}
+
+	if err := a.webhookBus.Delete(ctx, sub); err != nil {
+		return errs.Newf(errs.Internal, "delete: subscriptionID[%s]: %s", sub.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) query(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	subs, err := a.webhookBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyuserid: userID[%s]: %s", userID, err)
+	}
+
+	return toAppSubscriptions(subs)
+}
+
+func (a *app) queryByID(ctx context.Context, _ *http.Request) web.Encoder {
+	sub, err := mid.GetSubscription(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "webhook subscription missing in context: %s", err)
+	}
+
+	return toAppSubscription(sub)
+}
+
+func (a *app) queryDeliveries(ctx context.Context, r *http.Request) web.Encoder {
+	sub, err := mid.GetSubscription(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "webhook subscription missing in context: %s", err)
+	}
+
+	values := r.URL.Query()
+
+	page, err := page.Parse(values.Get("page"), values.Get("rows"))
+	if err != nil {
+		return errs.NewFieldsError("page", err)
+	}
+
+	dlvs, err := a.webhookBus.QueryDeliveries(ctx, sub.ID, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querydeliveries: %s", err)
+	}
+
+	total, err := a.webhookBus.CountDeliveries(ctx, sub.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "countdeliveries: %s", err)
+	}
+
+	return query.NewResult(toAppDeliveries(dlvs), total, page)
+}
+
+func (a *app) redeliver(ctx context.Context, r *http.Request) web.Encoder {
+	sub, err := mid.GetSubscription(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "webhook subscription missing in context: %s", err)
+	}
+
+	deliveryID, err := uuid.Parse(web.Param(r, "delivery_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("delivery_id", err))
+	}
+
+	dlv, err := a.webhookBus.QueryDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return toAppError("querydeliverybyid", err)
+	}
+
+	redlv, err := a.webhookBus.Redeliver(ctx, sub, dlv)
+	if err != nil {
+		return toAppError("redeliver", err)
+	}
+
+	return toAppDelivery(redlv)
+}
+
+func toAppError(op string, err error) web.Encoder {
+	switch {
+	case errors.Is(err, webhookbus.ErrInvalidURL),
+		errors.Is(err, webhookbus.ErrPrivateAddress):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("url", err))
+	case errors.Is(err, webhookbus.ErrUnknownEvent):
+		return errs.New(errs.InvalidArgument, errs.NewFieldsError("events", err))
+	case errors.Is(err, webhookbus.ErrDeliveryNotFound):
+		return errs.New(errs.NotFound, webhookbus.ErrDeliveryNotFound)
+	case errors.Is(err, webhookbus.ErrInactive):
+		return errs.New(errs.FailedPrecondition, webhookbus.ErrInactive)
+	}
+	return errs.Newf(errs.Internal, "%s: %s", op, err)
+}
@@ -12,10 +12,12 @@ import (
 	"time"
 
 	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/business/domain/orgbus"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/sdk/dbtest"
 	"github.com/himynamej/todo/business/types/role"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 // Test contains functions for executing an api test.
@@ -85,12 +87,14 @@ func Token(userBus *userbus.Business, ath *auth.Auth, email string) string {
 
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   dbUsr.ID.String(),
 			Issuer:    ath.Issuer(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 		Roles: role.ParseToString(dbUsr.Roles),
+		OrgID: orgbus.DefaultID.String(),
 	}
 
 	token, err := ath.GenerateToken(kid, claims)
@@ -13,6 +13,16 @@ func (ks *KeyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+// PublicKeys implements the auth interface.
+func (ks *KeyStore) PublicKeys() map[string]string {
+	return map[string]string{kid: publicKeyPEM}
+}
+
+// ActiveKID implements the auth interface.
+func (ks *KeyStore) ActiveKID() (string, error) {
+	return kid, nil
+}
+
 const (
 	kid = "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3Hd9k9NQ"
 
@@ -1,6 +1,7 @@
 package apitest
 
 import (
+	"context"
 	"net/http/httptest"
 	"testing"
 
@@ -10,8 +11,11 @@ import (
 	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/mux"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessiondb"
 	"github.com/himynamej/todo/business/domain/todobus/mocks"
 	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/upload"
 )
 
 // New initialized the system to run a test.
@@ -23,10 +27,13 @@ func New(t *testing.T, testName string) *Test {
 
 	// -------------------------------------------------------------------------
 
+	sessionBus := sessionbus.NewBusiness(db.Log, nil, sessiondb.NewStore(db.Log, db.DB), sessionbus.Config{})
+
 	auth, err := auth.New(auth.Config{
-		Log:       db.Log,
-		DB:        db.DB,
-		KeyLookup: &KeyStore{},
+		Log:        db.Log,
+		DB:         db.DB,
+		KeyLookup:  &KeyStore{},
+		SessionBus: sessionBus,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -39,7 +46,13 @@ func New(t *testing.T, testName string) *Test {
 	mockSQSClient := mocks.NewMockSQSClient(ctrl)
 	mockS3Client.EXPECT().
 		Upload(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return("mock-file-id", nil).AnyTimes() // Adjust as necessary
+		DoAndReturn(func(_ context.Context, fileName string, _ []byte) (string, error) {
+			return fileName, nil
+		}).AnyTimes() // Like S3, the file ID is the key.
+
+	mockS3Client.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
 
 	mockSQSClient.EXPECT().
 		SendMessage(gomock.Any(), gomock.Any()).
@@ -49,7 +62,8 @@ func New(t *testing.T, testName string) *Test {
 		Log: db.Log,
 		DB:  db.DB,
 		AuthConfig: mux.AuthConfig{
-			Auth: auth,
+			Auth:       auth,
+			SessionBus: sessionBus,
 		},
 	}, authbuild.Routes()))
 
@@ -60,8 +74,10 @@ func New(t *testing.T, testName string) *Test {
 	mux := mux.WebAPI(mux.Config{
 		Log:       db.Log,
 		DB:        db.DB,
+		ReportDB:  db.DB,
 		S3Client:  mockS3Client,
 		SQSClient: mockSQSClient,
+		Uploader:  upload.TestNewPipeline(),
 		SalesConfig: mux.SalesConfig{
 			AuthClient: authClient,
 		},
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/himynamej/todo/business/domain/sessionbus"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/domain/userbus/stores/usercache"
 	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
@@ -23,10 +24,15 @@ import (
 // ErrForbidden is returned when a auth issue is identified.
 var ErrForbidden = errors.New("attempted action is not allowed")
 
+// DefaultAccessTTL is how long an access token works when the config does
+// not say. They are short lived since a refresh token gets a new one.
+const DefaultAccessTTL = 15 * time.Minute
+
 // Claims represents the authorization claims transmitted via a JWT.
 type Claims struct {
 	jwt.RegisteredClaims
 	Roles []string `json:"roles"`
+	OrgID string   `json:"org_id,omitempty"`
 }
 
 // KeyLookup declares a method set of behavior for looking up
@@ -35,25 +41,31 @@ type Claims struct {
 type KeyLookup interface {
 	PrivateKey(kid string) (key string, err error)
 	PublicKey(kid string) (key string, err error)
+	PublicKeys() map[string]string
+	ActiveKID() (string, error)
 }
 
 // Config represents information required to initialize auth.
 type Config struct {
-	Log       *logger.Logger
-	DB        *sqlx.DB
-	KeyLookup KeyLookup
-	Issuer    string
+	Log        *logger.Logger
+	DB         *sqlx.DB
+	KeyLookup  KeyLookup
+	Issuer     string
+	AccessTTL  time.Duration        // How long the access tokens handed out work
+	SessionBus *sessionbus.Business // Revoked tokens are not checked when nil
 }
 
 // Auth is used to authenticate clients. It can generate a token for a
 // set of user claims and recreate the claims by parsing the token.
 type Auth struct {
-	log       *logger.Logger
-	keyLookup KeyLookup
-	userBus   *userbus.Business
-	method    jwt.SigningMethod
-	parser    *jwt.Parser
-	issuer    string
+	log        *logger.Logger
+	keyLookup  KeyLookup
+	userBus    *userbus.Business
+	sessionBus *sessionbus.Business
+	method     jwt.SigningMethod
+	parser     *jwt.Parser
+	issuer     string
+	accessTTL  time.Duration
 }
 
 // New creates an Auth to support authentication/authorization.
@@ -66,13 +78,20 @@ func New(cfg Config) (*Auth, error) {
 		userBus = userbus.NewBusiness(cfg.Log, nil, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), 10*time.Minute))
 	}
 
+	accessTTL := cfg.AccessTTL
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTTL
+	}
+
 	a := Auth{
-		log:       cfg.Log,
-		keyLookup: cfg.KeyLookup,
-		userBus:   userBus,
-		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
-		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
-		issuer:    cfg.Issuer,
+		log:        cfg.Log,
+		keyLookup:  cfg.KeyLookup,
+		userBus:    userBus,
+		sessionBus: cfg.SessionBus,
+		method:     jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
+		parser:     jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
+		issuer:     cfg.Issuer,
+		accessTTL:  accessTTL,
 	}
 
 	return &a, nil
@@ -83,8 +102,23 @@ func (a *Auth) Issuer() string {
 	return a.issuer
 }
 
+// AccessTTL provides how long the access tokens handed out work.
+func (a *Auth) AccessTTL() time.Duration {
+	return a.accessTTL
+}
+
+// ActiveKID provides the kid of the key new tokens are signed with.
+func (a *Auth) ActiveKID() (string, error) {
+	return a.keyLookup.ActiveKID()
+}
+
 // GenerateToken generates a signed JWT token string representing the user Claims.
+// Claims without a jti are given one so the token can be revoked.
 func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
+
 	token := jwt.NewWithClaims(a.method, claims)
 	token.Header["kid"] = kid
 
@@ -152,6 +186,10 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		return Claims{}, fmt.Errorf("user not enabled : %w", err)
 	}
 
+	if err := a.isRevoked(ctx, claims); err != nil {
+		return Claims{}, fmt.Errorf("token revoked : %w", err)
+	}
+
 	return claims, nil
 }
 
@@ -225,3 +263,27 @@ func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
 
 	return nil
 }
+
+// isRevoked checks the token is not on the denylist. Tokens without a jti
+// can't be revoked, so they are not accepted at all. If no session business
+// was provided, this check is skipped.
+func (a *Auth) isRevoked(ctx context.Context, claims Claims) error {
+	if a.sessionBus == nil {
+		return nil
+	}
+
+	if claims.ID == "" {
+		return errors.New("jti missing from token")
+	}
+
+	revoked, err := a.sessionBus.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return fmt.Errorf("query revoked: %w", err)
+	}
+
+	if revoked {
+		return errors.New("token was revoked")
+	}
+
+	return nil
+}
@@ -33,6 +33,9 @@ func Test_Auth(t *testing.T) {
 	t.Run("test4", test4(ath))
 	t.Run("test5", test5(ath))
 	t.Run("test6", test6(ath))
+	t.Run("test7", test7(ath))
+	t.Run("test8", test8(ath))
+	t.Run("test9", test9(ath))
 }
 
 func test1(ath *auth.Auth) func(t *testing.T) {
@@ -255,6 +258,137 @@ func test6(ath *auth.Auth) func(t *testing.T) {
 	return f
 }
 
+func test7(ath *auth.Auth) func(t *testing.T) {
+	f := func(t *testing.T) {
+		claims := auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    ath.Issuer(),
+				Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			},
+			Roles: []string{role.User.String(), auth.RoleOrgAdmin},
+			OrgID: "00000000-0000-0000-0000-000000000001",
+		}
+
+		token, err := ath.GenerateToken(kid, claims)
+		if err != nil {
+			t.Fatalf("Should be able to generate a JWT : %s", err)
+		}
+
+		parsedClaims, err := ath.Authenticate(context.Background(), "Bearer "+token)
+		if err != nil {
+			t.Fatalf("Should be able to authenticate the claims : %s", err)
+		}
+
+		if parsedClaims.OrgID != claims.OrgID {
+			t.Errorf("Should get back the org of the claims : got %q, exp %q", parsedClaims.OrgID, claims.OrgID)
+		}
+
+		userID := uuid.MustParse(claims.Subject)
+
+		err = ath.Authorize(context.Background(), parsedClaims, userID, auth.RuleOrgAdmin)
+		if err != nil {
+			t.Errorf("Should be able to authorize the RuleOrgAdmin claim with RoleOrgAdmin : %s", err)
+		}
+
+		err = ath.Authorize(context.Background(), parsedClaims, userID, auth.RuleAdminOnly)
+		if err == nil {
+			t.Error("Should NOT be able to authorize the RuleAdminOnly claim with RoleOrgAdmin")
+		}
+
+		claims.Roles = []string{role.User.String()}
+
+		err = ath.Authorize(context.Background(), claims, userID, auth.RuleOrgAdmin)
+		if err == nil {
+			t.Error("Should NOT be able to authorize the RuleOrgAdmin claim with Roles.User only")
+		}
+
+		claims.Roles = []string{role.Admin.String()}
+
+		err = ath.Authorize(context.Background(), claims, userID, auth.RuleOrgAdmin)
+		if err == nil {
+			t.Error("Should NOT be able to authorize the RuleOrgAdmin claim with Roles.Admin only")
+		}
+
+		claims.Roles = []string{role.User.String()}
+
+		err = ath.Authorize(context.Background(), claims, userID, auth.RuleOrgAdminOrSubject)
+		if err != nil {
+			t.Errorf("Should be able to authorize the RuleOrgAdminOrSubject claim as the subject : %s", err)
+		}
+
+		err = ath.Authorize(context.Background(), claims, uuid.New(), auth.RuleOrgAdminOrSubject)
+		if err == nil {
+			t.Error("Should NOT be able to authorize the RuleOrgAdminOrSubject claim for another user")
+		}
+
+		claims.Roles = []string{role.User.String(), auth.RoleOrgAdmin}
+
+		err = ath.Authorize(context.Background(), claims, uuid.New(), auth.RuleOrgAdminOrSubject)
+		if err != nil {
+			t.Errorf("Should be able to authorize the RuleOrgAdminOrSubject claim for another user with RoleOrgAdmin : %s", err)
+		}
+	}
+
+	return f
+}
+
+func test8(ath *auth.Auth) func(t *testing.T) {
+	f := func(t *testing.T) {
+		claims := auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    ath.Issuer(),
+				Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+				ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(ath.AccessTTL())),
+				IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			},
+			Roles: []string{role.User.String()},
+		}
+
+		if ath.AccessTTL() != auth.DefaultAccessTTL {
+			t.Errorf("Should default the access token lifetime : got %s, exp %s", ath.AccessTTL(), auth.DefaultAccessTTL)
+		}
+
+		token, err := ath.GenerateToken(kid, claims)
+		if err != nil {
+			t.Fatalf("Should be able to generate a JWT : %s", err)
+		}
+
+		parsedClaims, err := ath.Authenticate(context.Background(), "Bearer "+token)
+		if err != nil {
+			t.Fatalf("Should be able to authenticate the claims : %s", err)
+		}
+
+		if _, err := uuid.Parse(parsedClaims.ID); err != nil {
+			t.Errorf("Should give the token a jti so it can be revoked : %q : %s", parsedClaims.ID, err)
+		}
+	}
+
+	return f
+}
+
+func test9(ath *auth.Auth) func(t *testing.T) {
+	f := func(t *testing.T) {
+		set, err := ath.JWKS()
+		if err != nil {
+			t.Fatalf("Should be able to build the JWKS : %s", err)
+		}
+
+		if len(set.Keys) != 1 {
+			t.Fatalf("Should publish the public key : got %d keys", len(set.Keys))
+		}
+
+		jwk := set.Keys[0]
+
+		if jwk.Kid != kid || jwk.Kty != "RSA" || jwk.Alg != "RS256" || jwk.E != "AQAB" {
+			t.Errorf("Should describe the RS256 key : got %+v", jwk)
+		}
+	}
+
+	return f
+}
+
 // =============================================================================
 
 func newUnit(t *testing.T) *logger.Logger {
@@ -284,6 +418,14 @@ func (ks *keyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+func (ks *keyStore) PublicKeys() map[string]string {
+	return map[string]string{kid: publicKeyPEM}
+}
+
+func (ks *keyStore) ActiveKID() (string, error) {
+	return kid, nil
+}
+
 const (
 	kid = "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3Hd9k9NQ"
 
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWK represents an RSA public key as a JSON Web Key (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSet represents the public keys tokens may be signed with.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that verify tokens, so other services can
+// check tokens without asking this one. Keys that are pending are included
+// before they sign anything, and keys that are retiring until they are
+// retired.
+func (a *Auth) JWKS() (JWKSet, error) {
+	pems := a.keyLookup.PublicKeys()
+
+	kids := make([]string, 0, len(pems))
+	for kid := range pems {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKSet{
+		Keys: make([]JWK, 0, len(kids)),
+	}
+
+	for _, kid := range kids {
+		pub, err := jwt.ParseRSAPublicKeyFromPEM([]byte(pems[kid]))
+		if err != nil {
+			return JWKSet{}, fmt.Errorf("parsing public pem: kid[%s]: %w", kid, err)
+		}
+
+		jwk := JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: a.method.Alg(),
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
@@ -6,6 +6,8 @@ role_user := "USER"
 
 role_admin := "ADMIN"
 
+role_org_admin := "ORG_ADMIN"
+
 role_all := {role_admin, role_user}
 
 default rule_any := false
@@ -44,3 +46,24 @@ rule_admin_or_subject if {
 	count(input_user) > 0
 	input.UserID == input.Subject
 }
+
+default rule_org_admin := false
+
+rule_org_admin if {
+	claim_roles := {role | some role in input.Roles}
+	input_admin := {role_org_admin} & claim_roles
+	count(input_admin) > 0
+}
+
+default rule_org_admin_or_subject := false
+
+rule_org_admin_or_subject if {
+	claim_roles := {role | some role in input.Roles}
+	input_admin := {role_org_admin} & claim_roles
+	count(input_admin) > 0
+} else if {
+	claim_roles := {role | some role in input.Roles}
+	input_user := {role_user} & claim_roles
+	count(input_user) > 0
+	input.UserID == input.Subject
+}
@@ -6,13 +6,19 @@ import (
 
 // These are the current set of rules we have for auth.
 const (
-	RuleAuthenticate   = "auth"
-	RuleAny            = "rule_any"
-	RuleAdminOnly      = "rule_admin_only"
-	RuleUserOnly       = "rule_user_only"
-	RuleAdminOrSubject = "rule_admin_or_subject"
+	RuleAuthenticate      = "auth"
+	RuleAny               = "rule_any"
+	RuleAdminOnly         = "rule_admin_only"
+	RuleUserOnly          = "rule_user_only"
+	RuleAdminOrSubject    = "rule_admin_or_subject"
+	RuleOrgAdmin          = "rule_org_admin"
+	RuleOrgAdminOrSubject = "rule_org_admin_or_subject"
 )
 
+// RoleOrgAdmin is the role claimed by a user who administers the
+// organization the token is for.
+const RoleOrgAdmin = "ORG_ADMIN"
+
 // Package name of our rego code.
 const (
 	opaPackage string = "ardan.rego"
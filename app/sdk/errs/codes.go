@@ -2,6 +2,8 @@ package errs
 
 import (
 	"net/http"
+
+	"google.golang.org/grpc/codes"
 )
 
 var (
@@ -116,6 +118,11 @@ var (
 	// system has been broken. If you see one of these errors,
 	// something is very broken. The error message is not sent to the client.
 	InternalOnlyLog = ErrCode{value: 19}
+
+	// Unprocessable indicates the request was well formed but conflicts with
+	// what the server already knows about it, such as an idempotency key
+	// being reused with a different request.
+	Unprocessable = ErrCode{value: 20}
 )
 
 var codeNumbers = map[string]ErrCode{
@@ -139,6 +146,7 @@ var codeNumbers = map[string]ErrCode{
 	"unauthenticated":     Unauthenticated,
 	"too_many_requests":   TooManyRequests,
 	"internal_only_log":   InternalOnlyLog,
+	"unprocessable":       Unprocessable,
 }
 
 var codeNames = map[ErrCode]string{
@@ -162,6 +170,7 @@ var codeNames = map[ErrCode]string{
 	Unauthenticated:    "unauthenticated",
 	TooManyRequests:    "too_many_requests",
 	InternalOnlyLog:    "internal_only_log",
+	Unprocessable:      "unprocessable",
 }
 
 var httpStatus = map[ErrCode]int{
@@ -185,4 +194,29 @@ var httpStatus = map[ErrCode]int{
 	Unauthenticated:    http.StatusUnauthorized,
 	TooManyRequests:    http.StatusTooManyRequests,
 	InternalOnlyLog:    http.StatusInternalServerError,
+	Unprocessable:      http.StatusUnprocessableEntity,
+}
+
+var grpcCodes = map[ErrCode]codes.Code{
+	OK:                 codes.OK,
+	NoContent:          codes.OK,
+	Canceled:           codes.Canceled,
+	Unknown:            codes.Unknown,
+	InvalidArgument:    codes.InvalidArgument,
+	DeadlineExceeded:   codes.DeadlineExceeded,
+	NotFound:           codes.NotFound,
+	AlreadyExists:      codes.AlreadyExists,
+	PermissionDenied:   codes.PermissionDenied,
+	ResourceExhausted:  codes.ResourceExhausted,
+	FailedPrecondition: codes.FailedPrecondition,
+	Aborted:            codes.Aborted,
+	OutOfRange:         codes.OutOfRange,
+	Unimplemented:      codes.Unimplemented,
+	Internal:           codes.Internal,
+	Unavailable:        codes.Unavailable,
+	DataLoss:           codes.DataLoss,
+	Unauthenticated:    codes.Unauthenticated,
+	TooManyRequests:    codes.ResourceExhausted,
+	InternalOnlyLog:    codes.Internal,
+	Unprocessable:      codes.FailedPrecondition,
 }
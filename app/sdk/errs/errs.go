@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+
+	"google.golang.org/grpc/status"
 )
 
 // ErrCode represents an error code in the system.
@@ -109,6 +111,12 @@ func (e *Error) HTTPStatus() int {
 	return httpStatus[e.Code]
 }
 
+// GRPCStatus implements the interface the grpc package looks for so it can
+// send the correct status code to the client.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(grpcCodes[e.Code], e.Message)
+}
+
 // Equal provides support for the go-cmp package and testing.
 func (e *Error) Equal(e2 *Error) bool {
 	return e.Code == e2.Code && e.Message == e2.Message
@@ -21,10 +21,18 @@ import (
 func Authenticate(client *authclient.Client) web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
-			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+			// Only the call to the auth service is bound by the timeout, the
+			// handler gets the request context so streams are not cut short.
+			actx, cancel := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel()
 
-			resp, err := client.Authenticate(ctx, r.Header.Get("authorization"))
+			resp, err := client.Authenticate(actx, r.Header.Get("authorization"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			ctx, err = setOrgID(ctx, resp.Claims)
 			if err != nil {
 				return errs.New(errs.Unauthenticated, err)
 			}
@@ -59,6 +67,11 @@ func Bearer(ath *auth.Auth) web.MidFunc {
 				return errs.Newf(errs.Unauthenticated, "parsing subject: %s", err)
 			}
 
+			ctx, err = setOrgID(ctx, claims)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
 			ctx = setUserID(ctx, subjectID)
 			ctx = setClaims(ctx, claims)
 
@@ -94,7 +107,7 @@ func Basic(ath *auth.Auth, userBus *userbus.Business) web.MidFunc {
 				RegisteredClaims: jwt.RegisteredClaims{
 					Subject:   usr.ID.String(),
 					Issuer:    ath.Issuer(),
-					ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(8760 * time.Hour)),
+					ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(ath.AccessTTL())),
 					IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 				},
 				Roles: role.ParseToString(usr.Roles),
@@ -7,9 +7,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/domain/todobus"
 	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/domain/webhookbus"
 	"github.com/himynamej/todo/foundation/web"
 )
 
@@ -31,10 +37,33 @@ func Authorize(client *authclient.Client, rule string) web.MidFunc {
 				Rule:   rule,
 			}
 
-			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			actx, cancel := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel()
 
-			if err := client.Authorize(ctx, auth); err != nil {
+			if err := client.Authorize(actx, auth); err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeLocal validates authorization against the local auth package, for
+// the service that holds the rules.
+func AuthorizeLocal(ath *auth.Auth, rule string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			if err := ath.Authorize(ctx, GetClaims(ctx), userID, rule); err != nil {
 				return errs.New(errs.Unauthenticated, err)
 			}
 
@@ -78,7 +107,264 @@ func AuthorizeUser(client *authclient.Client, userBus *userbus.Business, rule st
 				ctx = setUser(ctx, usr)
 			}
 
-			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			actx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			auth := authclient.Authorize{
+				Claims: GetClaims(ctx),
+				UserID: userID,
+				Rule:   rule,
+			}
+
+			if err := client.Authorize(actx, auth); err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeComment executes the specified role and extracts the specified
+// comment from the DB if a comment id is specified in the call. Depending on
+// the rule specified, the userid from the claims may be compared with the
+// specified author of the comment.
+func AuthorizeComment(client *authclient.Client, commentBus *commentbus.Business, rule string) web.MidFunc {
+	lookup := func(ctx context.Context, commentID uuid.UUID) (context.Context, uuid.UUID, error) {
+		cmt, err := commentBus.QueryByID(ctx, commentID)
+		if err != nil {
+			return ctx, uuid.Nil, err
+		}
+
+		return setComment(ctx, cmt), cmt.UserID, nil
+	}
+
+	return authorizeLookup(client, rule, "comment_id", commentbus.ErrNotFound, lookup)
+}
+
+// AuthorizeWebhook executes the specified role and extracts the specified
+// webhook subscription from the DB if a subscription id is specified in the
+// call. Depending on the rule specified, the userid from the claims may be
+// compared with the owner of the subscription.
+func AuthorizeWebhook(client *authclient.Client, webhookBus *webhookbus.Business, rule string) web.MidFunc {
+	lookup := func(ctx context.Context, subscriptionID uuid.UUID) (context.Context, uuid.UUID, error) {
+		sub, err := webhookBus.QueryByID(ctx, subscriptionID)
+		if err != nil {
+			return ctx, uuid.Nil, err
+		}
+
+		return setSubscription(ctx, sub), sub.UserID, nil
+	}
+
+	return authorizeLookup(client, rule, "subscription_id", webhookbus.ErrNotFound, lookup)
+}
+
+// AuthorizeTemplate executes the specified role and extracts the specified
+// template from the DB if a template id is specified in the call. Depending
+// on the rule specified, the userid from the claims may be compared with the
+// owner of the template.
+func AuthorizeTemplate(client *authclient.Client, templateBus *templatebus.Business, rule string) web.MidFunc {
+	lookup := func(ctx context.Context, templateID uuid.UUID) (context.Context, uuid.UUID, error) {
+		tmpl, err := templateBus.QueryByID(ctx, templateID)
+		if err != nil {
+			return ctx, uuid.Nil, err
+		}
+
+		return setTemplate(ctx, tmpl), tmpl.UserID, nil
+	}
+
+	return authorizeLookup(client, rule, "template_id", templatebus.ErrNotFound, lookup)
+}
+
+// AuthorizeTimeEntry executes the specified role and extracts the specified
+// time entry from the DB if an entry id is specified in the call. Depending
+// on the rule specified, the userid from the claims may be compared with the
+// user who logged the entry.
+func AuthorizeTimeEntry(client *authclient.Client, timeBus *timebus.Business, rule string) web.MidFunc {
+	lookup := func(ctx context.Context, entryID uuid.UUID) (context.Context, uuid.UUID, error) {
+		e, err := timeBus.QueryByID(ctx, entryID)
+		if err != nil {
+			return ctx, uuid.Nil, err
+		}
+
+		return setTimeEntry(ctx, e), e.UserID, nil
+	}
+
+	return authorizeLookup(client, rule, "entry_id", timebus.ErrNotFound, lookup)
+}
+
+// AuthorizeTodo executes the specified role and extracts the specified todo
+// item from the DB if an item id is specified in the call. Depending on the
+// rule specified, the userid from the claims may be compared with the owner
+// of the todo item.
+func AuthorizeTodo(client *authclient.Client, todoBus *todobus.Business, rule string) web.MidFunc {
+	return authorizeTodo(client, todoBus, rule, "item_id", false, setTodo)
+}
+
+// AuthorizeTodoAssignee works like AuthorizeTodo but also accepts the
+// assignee of the todo item in place of the owner.
+func AuthorizeTodoAssignee(client *authclient.Client, todoBus *todobus.Business, rule string) web.MidFunc {
+	return authorizeTodo(client, todoBus, rule, "item_id", true, setTodo)
+}
+
+// AuthorizeBlocker works like AuthorizeTodoAssignee for the todo item named
+// by the blocker id, so an item can only be blocked by one the caller may see.
+func AuthorizeBlocker(client *authclient.Client, todoBus *todobus.Business, rule string) web.MidFunc {
+	return authorizeTodo(client, todoBus, rule, "blocker_id", true, setBlocker)
+}
+
+func authorizeTodo(client *authclient.Client, todoBus *todobus.Business, rule string, param string, allowAssignee bool, set func(context.Context, todobus.TodoItem) context.Context) web.MidFunc {
+	lookup := func(ctx context.Context, itemID uuid.UUID) (context.Context, uuid.UUID, error) {
+		item, err := todoBus.QueryByID(ctx, itemID)
+		if err != nil {
+			return ctx, uuid.Nil, err
+		}
+
+		userID := item.UserID
+
+		// The rule compares a single user id with the subject of the
+		// claims, so the assignee is only offered when it is the caller.
+		if allowAssignee && item.AssigneeID != uuid.Nil && GetClaims(ctx).Subject == item.AssigneeID.String() {
+			userID = item.AssigneeID
+		}
+
+		return set(ctx, item), userID, nil
+	}
+
+	return authorizeLookup(client, rule, param, todobus.ErrNotFound, lookup)
+}
+
+// AuthorizeAttachment executes the specified role and extracts the specified
+// attachment from the DB if an attachment id is specified in the call.
+// Depending on the rule specified, the userid from the claims may be compared
+// with the user who uploaded the attachment.
+func AuthorizeAttachment(client *authclient.Client, todoBus *todobus.Business, rule string) web.MidFunc {
+	return authorizeAttachment(client, todoBus, rule, false)
+}
+
+// AuthorizeAttachmentFile works like AuthorizeAttachment but also accepts a
+// caller who can see the contents of the attachment in place of the uploader.
+func AuthorizeAttachmentFile(client *authclient.Client, todoBus *todobus.Business, rule string) web.MidFunc {
+	return authorizeAttachment(client, todoBus, rule, true)
+}
+
+func authorizeAttachment(client *authclient.Client, todoBus *todobus.Business, rule string, allowFile bool) web.MidFunc {
+	lookup := func(ctx context.Context, attachmentID uuid.UUID) (context.Context, uuid.UUID, error) {
+		att, err := todoBus.QueryAttachmentByID(ctx, attachmentID)
+		if err != nil {
+			return ctx, uuid.Nil, err
+		}
+
+		userID := att.UserID
+
+		if allowFile {
+			callerID, err := fileUser(ctx, todoBus, att.FileID)
+			if err != nil {
+				return ctx, uuid.Nil, err
+			}
+
+			if callerID != uuid.Nil {
+				userID = callerID
+			}
+		}
+
+		return setAttachment(ctx, att), userID, nil
+	}
+
+	return authorizeLookup(client, rule, "attachment_id", todobus.ErrAttachmentNotFound, lookup)
+}
+
+// AuthorizeFile executes the specified role for the file named in the call.
+// Depending on the rule specified, the userid from the claims may be compared
+// with the caller, which is only offered when the file is shared with them.
+func AuthorizeFile(client *authclient.Client, todoBus *todobus.Business, rule string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			fileID := web.Param(r, "file_id")
+
+			userID, err := fileUser(ctx, todoBus, fileID)
+			if err != nil {
+				return errs.Newf(errs.Internal, "canaccessfile: fileID[%s]: %s", fileID, err)
+			}
+
+			actx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			auth := authclient.Authorize{
+				Claims: GetClaims(ctx),
+				UserID: userID,
+				Rule:   rule,
+			}
+
+			if err := client.Authorize(actx, auth); err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// fileUser returns the caller when they may read the contents of the file,
+// because they uploaded it or it belongs to a todo item they can see.
+func fileUser(ctx context.Context, todoBus *todobus.Business, fileID string) (uuid.UUID, error) {
+	callerID, err := GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	access, err := todoBus.CanAccessFile(ctx, callerID, fileID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if !access {
+		return uuid.Nil, nil
+	}
+
+	return callerID, nil
+}
+
+// lookupFunc finds the value named by the id in the path, adds it to the
+// context and returns the user the rule compares with the claims.
+type lookupFunc func(ctx context.Context, id uuid.UUID) (context.Context, uuid.UUID, error)
+
+// authorizeLookup executes the specified role after looking up the value
+// named by the param, if the call has one. A value that can't be found fails
+// the call the way one the caller may not see does.
+func authorizeLookup(client *authclient.Client, rule string, param string, errNotFound error, lookup lookupFunc) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			id := web.Param(r, param)
+
+			var userID uuid.UUID
+
+			if id != "" {
+				parsedID, err := uuid.Parse(id)
+				if err != nil {
+					return errs.New(errs.Unauthenticated, ErrInvalidID)
+				}
+
+				ctx, userID, err = lookup(ctx, parsedID)
+				if err != nil {
+					switch {
+					case errors.Is(err, errNotFound):
+						return errs.New(errs.Unauthenticated, err)
+					default:
+						return errs.Newf(errs.Internal, "querybyid: %s[%s]: %s", param, parsedID, err)
+					}
+				}
+			}
+
+			actx, cancel := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel()
 
 			auth := authclient.Authorize{
@@ -87,7 +373,7 @@ func AuthorizeUser(client *authclient.Client, userBus *userbus.Business, rule st
 				Rule:   rule,
 			}
 
-			if err := client.Authorize(ctx, auth); err != nil {
+			if err := client.Authorize(actx, auth); err != nil {
 				return errs.New(errs.Unauthenticated, err)
 			}
 
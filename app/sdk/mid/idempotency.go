@@ -0,0 +1,180 @@
+package mid
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+const (
+	idempotencyHeader       = "Idempotency-Key"
+	idempotencyReplayHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLen    = 255
+)
+
+// IdempotencyMaxBody is the largest payload Idempotent reads for routes that
+// take a JSON document.
+const IdempotencyMaxBody = 1 << 20 // 1 MB
+
+// Idempotent honors the Idempotency-Key header so a retried request returns
+// the response of the first attempt instead of running the handler again.
+// Requests without the header pass straight through. The payload is read to
+// fingerprint the request, so no more than maxBody bytes of it are accepted.
+// It must run after authentication since keys are scoped to the calling user.
+func Idempotent(log *logger.Logger, idempotencyBus *idempotencybus.Business, maxBody int64) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			key := r.Header.Get(idempotencyHeader)
+			if key == "" {
+				return next(ctx, r)
+			}
+
+			if len(key) > idempotencyKeyMaxLen {
+				return errs.Newf(errs.InvalidArgument, "%s must not be longer than %d characters", idempotencyHeader, idempotencyKeyMaxLen)
+			}
+
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBody))
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					return errs.Newf(errs.InvalidArgument, "payload exceeds the maximum size of %d bytes", maxErr.Limit)
+				}
+				return errs.Newf(errs.InvalidArgument, "unable to read payload: %s", err)
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			nr := idempotencybus.NewRecord{
+				Key:         key,
+				UserID:      userID,
+				RequestHash: requestHash(r, body),
+			}
+
+			rec, err := idempotencyBus.Begin(ctx, nr)
+			if err != nil {
+				switch {
+				case errors.Is(err, idempotencybus.ErrMismatch):
+					return errs.New(errs.Unprocessable, err)
+				case errors.Is(err, idempotencybus.ErrInFlight):
+					return errs.New(errs.Aborted, err)
+				}
+				return errs.Newf(errs.Internal, "begin: key[%s]: %s", key, err)
+			}
+
+			if rec.Completed() {
+				if w := web.GetWriter(ctx); w != nil {
+					w.Header().Set(idempotencyReplayHeader, "true")
+				}
+				return storedResponse{rec: rec}
+			}
+
+			resp := next(ctx, r)
+
+			stored, ok := toStoredResponse(resp)
+			if !ok {
+				if err := idempotencyBus.Release(ctx, rec); err != nil {
+					log.Error(ctx, "idempotency: release", "key", key, "ERROR", err)
+				}
+				return resp
+			}
+
+			if _, err := idempotencyBus.Complete(ctx, rec, stored); err != nil {
+				log.Error(ctx, "idempotency: complete", "key", key, "ERROR", err)
+			}
+
+			return resp
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// requestHash fingerprints the parts of a request a retry must repeat,
+// including the query string some handlers take their input from. The
+// multipart boundary is stripped since clients generate a new one for every
+// attempt.
+func requestHash(r *http.Request, body []byte) string {
+	if mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType == "multipart/form-data" {
+		if boundary := params["boundary"]; boundary != "" {
+			body = bytes.ReplaceAll(body, []byte(boundary), nil)
+		}
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RawQuery))
+	h.Write([]byte{0})
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// toStoredResponse captures a successful handler response. Errors and
+// responses the handler wrote itself are not stored so the client is free
+// to retry them.
+func toStoredResponse(resp web.Encoder) (idempotencybus.Response, bool) {
+	if isError(resp) != nil {
+		return idempotencybus.Response{}, false
+	}
+
+	if resp == nil {
+		return idempotencybus.Response{StatusCode: http.StatusNoContent}, true
+	}
+
+	if _, ok := resp.(web.NoResponse); ok {
+		return idempotencybus.Response{}, false
+	}
+
+	data, contentType, err := resp.Encode()
+	if err != nil {
+		return idempotencybus.Response{}, false
+	}
+
+	statusCode := http.StatusOK
+	if v, ok := resp.(interface{ HTTPStatus() int }); ok {
+		statusCode = v.HTTPStatus()
+	}
+
+	stored := idempotencybus.Response{
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        data,
+	}
+
+	return stored, true
+}
+
+// storedResponse replays a response saved under an idempotency key.
+type storedResponse struct {
+	rec idempotencybus.Record
+}
+
+// Encode implements the encoder interface.
+func (sr storedResponse) Encode() ([]byte, string, error) {
+	return sr.rec.Body, sr.rec.ContentType, nil
+}
+
+// HTTPStatus implements the web package httpStatus interface so the
+// original status code is replayed.
+func (sr storedResponse) HTTPStatus() int {
+	return sr.rec.StatusCode
+}
@@ -4,10 +4,17 @@ package mid
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/himynamej/todo/app/sdk/auth"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/domain/todobus"
 	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
 )
 
 // Encoder defines behavior that can encode a data model and provide
@@ -39,6 +46,13 @@ const (
 	productKey
 	homeKey
 	trKey
+	commentKey
+	todoKey
+	webhookKey
+	templateKey
+	timeEntryKey
+	blockerKey
+	attachmentKey
 )
 
 func setClaims(ctx context.Context, claims auth.Claims) context.Context {
@@ -58,6 +72,32 @@ func setUserID(ctx context.Context, userID uuid.UUID) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
 
+// setOrgID scopes database access to the organization the claims are for.
+// Tokens are only issued for an organization the user is a member of, so one
+// without an organization is not accepted.
+func setOrgID(ctx context.Context, claims auth.Claims) (context.Context, error) {
+	if claims.OrgID == "" {
+		return ctx, errors.New("org missing from token")
+	}
+
+	orgID, err := uuid.Parse(claims.OrgID)
+	if err != nil {
+		return ctx, fmt.Errorf("parsing org: %w", err)
+	}
+
+	return sqldb.WithOrgID(ctx, orgID), nil
+}
+
+// GetOrgID returns the organization the request is scoped to.
+func GetOrgID(ctx context.Context) (uuid.UUID, error) {
+	orgID, ok := sqldb.GetOrgID(ctx)
+	if !ok {
+		return uuid.UUID{}, errors.New("org id not found in context")
+	}
+
+	return orgID, nil
+}
+
 // GetUserID returns the user id from the context.
 func GetUserID(ctx context.Context) (uuid.UUID, error) {
 	v, ok := ctx.Value(userIDKey).(uuid.UUID)
@@ -81,3 +121,101 @@ func GetUser(ctx context.Context) (userbus.User, error) {
 
 	return v, nil
 }
+
+func setComment(ctx context.Context, cmt commentbus.Comment) context.Context {
+	return context.WithValue(ctx, commentKey, cmt)
+}
+
+// GetComment returns the comment from the context.
+func GetComment(ctx context.Context) (commentbus.Comment, error) {
+	v, ok := ctx.Value(commentKey).(commentbus.Comment)
+	if !ok {
+		return commentbus.Comment{}, errors.New("comment not found in context")
+	}
+
+	return v, nil
+}
+
+func setTodo(ctx context.Context, item todobus.TodoItem) context.Context {
+	return context.WithValue(ctx, todoKey, item)
+}
+
+// GetTodo returns the todo item from the context.
+func GetTodo(ctx context.Context) (todobus.TodoItem, error) {
+	v, ok := ctx.Value(todoKey).(todobus.TodoItem)
+	if !ok {
+		return todobus.TodoItem{}, errors.New("todo item not found in context")
+	}
+
+	return v, nil
+}
+
+func setSubscription(ctx context.Context, sub webhookbus.Subscription) context.Context {
+	return context.WithValue(ctx, webhookKey, sub)
+}
+
+// GetSubscription returns the webhook subscription from the context.
+func GetSubscription(ctx context.Context) (webhookbus.Subscription, error) {
+	v, ok := ctx.Value(webhookKey).(webhookbus.Subscription)
+	if !ok {
+		return webhookbus.Subscription{}, errors.New("webhook subscription not found in context")
+	}
+
+	return v, nil
+}
+
+func setTemplate(ctx context.Context, tmpl templatebus.Template) context.Context {
+	return context.WithValue(ctx, templateKey, tmpl)
+}
+
+// GetTemplate returns the template from the context.
+func GetTemplate(ctx context.Context) (templatebus.Template, error) {
+	v, ok := ctx.Value(templateKey).(templatebus.Template)
+	if !ok {
+		return templatebus.Template{}, errors.New("template not found in context")
+	}
+
+	return v, nil
+}
+
+func setTimeEntry(ctx context.Context, e timebus.Entry) context.Context {
+	return context.WithValue(ctx, timeEntryKey, e)
+}
+
+// GetTimeEntry returns the time entry from the context.
+func GetTimeEntry(ctx context.Context) (timebus.Entry, error) {
+	v, ok := ctx.Value(timeEntryKey).(timebus.Entry)
+	if !ok {
+		return timebus.Entry{}, errors.New("time entry not found in context")
+	}
+
+	return v, nil
+}
+
+func setBlocker(ctx context.Context, item todobus.TodoItem) context.Context {
+	return context.WithValue(ctx, blockerKey, item)
+}
+
+// GetBlocker returns the todo item named as the blocker from the context.
+func GetBlocker(ctx context.Context) (todobus.TodoItem, error) {
+	v, ok := ctx.Value(blockerKey).(todobus.TodoItem)
+	if !ok {
+		return todobus.TodoItem{}, errors.New("blocker not found in context")
+	}
+
+	return v, nil
+}
+
+func setAttachment(ctx context.Context, att todobus.Attachment) context.Context {
+	return context.WithValue(ctx, attachmentKey, att)
+}
+
+// GetAttachment returns the attachment from the context.
+func GetAttachment(ctx context.Context) (todobus.Attachment, error) {
+	v, ok := ctx.Value(attachmentKey).(todobus.Attachment)
+	if !ok {
+		return todobus.Attachment{}, errors.New("attachment not found in context")
+	}
+
+	return v, nil
+}
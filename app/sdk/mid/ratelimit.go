@@ -0,0 +1,87 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/foundation/ratelimit"
+	"github.com/himynamej/todo/foundation/web"
+)
+
+// RateLimit rejects requests from a client address once it has made more
+// than the limiter allows. Requests that come through one of the trusted
+// proxies are counted against the address the proxies say they came from.
+func RateLimit(limiter *ratelimit.Limiter, proxies []netip.Prefix) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if !limiter.Allow(clientAddr(r, proxies)) {
+				return errs.New(errs.TooManyRequests, errors.New("too many requests, try again later"))
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// clientAddr returns the address of the client that made the request. When
+// the request comes from a trusted proxy, X-Forwarded-For is read from the
+// right, since each proxy appends the address it was called from, and the
+// first address that is not a trusted proxy is the client. Anything left of
+// it was sent by the client and can't be believed.
+func clientAddr(r *http.Request, proxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !trusted(host, proxies) {
+		return host
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+
+		if !trusted(hop, proxies) {
+			return hop
+		}
+
+		host = hop
+	}
+
+	return host
+}
+
+// trusted reports whether the address belongs to one of the proxies.
+func trusted(host string, proxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	for _, p := range proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
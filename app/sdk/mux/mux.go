@@ -6,13 +6,26 @@ import (
 	"context"
 	"embed"
 	"net/http"
+	"net/netip"
+	"time"
 
 	"github.com/himynamej/todo/app/sdk/auth"
 	"github.com/himynamej/todo/app/sdk/authclient"
 	"github.com/himynamej/todo/app/sdk/mid"
+	"github.com/himynamej/todo/app/sdk/rpc"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/domain/streambus"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/sdk/mailer"
+	"github.com/himynamej/todo/business/sdk/upload"
 	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/ratelimit"
 	"github.com/himynamej/todo/foundation/web"
+	"github.com/himynamej/todo/foundation/worker"
 	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -57,17 +70,34 @@ type SalesConfig struct {
 
 // AuthConfig contains auth service specific config.
 type AuthConfig struct {
-	Auth *auth.Auth
+	Auth       *auth.Auth
+	SessionBus *sessionbus.Business // Shared with auth so revocations are seen at once
 }
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
-	Build     string
-	Log       *logger.Logger
-	DB        *sqlx.DB
-	Tracer    trace.Tracer
-	S3Client  todobus.S3Client  // S3 client for interactions with S3
-	SQSClient todobus.SQSClient // SQS client for interactions with SQS
+	Build         string
+	Log           *logger.Logger
+	DB            *sqlx.DB
+	ReportDB      *sqlx.DB // Read only connection for reporting, usually a replica
+	Tracer        trace.Tracer
+	S3Client      todobus.S3Client   // S3 client for interactions with S3
+	SQSClient     todobus.SQSClient  // SQS client for interactions with SQS
+	Uploader      *upload.Pipeline   // Validators every uploaded file must pass
+	Worker        *worker.Worker     // Runs background jobs such as rendering thumbnails
+	Quota         quotabus.Limits    // Storage quotas used when an admin has not set one
+	Webhook       webhookbus.Config  // How webhook deliveries are sent and retried
+	Stream        streambus.Config   // How todo changes reach the clients following them
+	Heartbeat     time.Duration      // How often idle streams are kept alive
+	Shutdown      <-chan struct{}    // Closed when the server starts shutting down
+	RPC           *rpc.Server        // Where the gRPC services are bound, none when nil
+	CursorKey     []byte             // Signs the paging cursors handed to clients
+	Mailer        mailer.Mailer      // Sends the email of the service
+	Signup        signupbus.Config   // How signups are verified and who may sign up
+	Password      passwordbus.Config // How password reset links are sent
+	SignupLimit   *ratelimit.Limiter // Bounds how often a client address can sign up
+	PasswordLimit *ratelimit.Limiter // Bounds how often a client address can ask for or use reset links
+	Proxies       []netip.Prefix     // Load balancers whose X-Forwarded-For names the client
 	SalesConfig
 	AuthConfig
 }
@@ -78,6 +108,20 @@ type RouteAdder interface {
 	Add(app *web.App, cfg Config)
 }
 
+// RPCAPI constructs a gRPC server with the same middleware as the web api.
+// The services are bound to it by the RouteAdder when it is set in the
+// config given to WebAPI.
+func RPCAPI(cfg Config) *rpc.Server {
+	return rpc.NewServer(
+		cfg.Tracer,
+		mid.Otel(cfg.Tracer),
+		mid.Logger(cfg.Log),
+		mid.Errors(cfg.Log),
+		mid.Metrics(),
+		mid.Panics(),
+	)
+}
+
 // WebAPI constructs a http.Handler with all application routes bound.
 func WebAPI(cfg Config, routeAdder RouteAdder, options ...func(opts *Options)) http.Handler {
 	logger := func(ctx context.Context, msg string, args ...any) {
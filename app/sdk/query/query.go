@@ -9,22 +9,38 @@ import (
 
 // Result is the data model used when returning a query result.
 type Result[T any] struct {
-	Items       []T `json:"items"`
-	Total       int `json:"total"`
-	Page        int `json:"page"`
-	RowsPerPage int `json:"rowsPerPage"`
+	Items       []T    `json:"items"`
+	Total       int    `json:"total"`
+	Page        int    `json:"page,omitempty"`
+	RowsPerPage int    `json:"rowsPerPage"`
+	Prev        string `json:"prev,omitempty"`
+	Next        string `json:"next,omitempty"`
 }
 
-// NewResult constructs a result value to return query results.
+// NewResult constructs a result value to return query results. Pages read
+// from a cursor have no number.
 func NewResult[T any](items []T, total int, page page.Page) Result[T] {
+	var number int
+	if _, isCursor := page.Cursor(); !isCursor {
+		number = page.Number()
+	}
+
 	return Result[T]{
 		Items:       items,
 		Total:       total,
-		Page:        page.Number(),
+		Page:        number,
 		RowsPerPage: page.RowsPerPage(),
 	}
 }
 
+// WithCursors adds the cursors clients send to read the pages before and
+// after the result.
+func (r Result[T]) WithCursors(prev string, next string) Result[T] {
+	r.Prev = prev
+	r.Next = next
+	return r
+}
+
 // Encode implements the encoder interface.
 func (r Result[T]) Encode() ([]byte, string, error) {
 	data, err := json.Marshal(r)
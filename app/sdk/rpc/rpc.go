@@ -0,0 +1,222 @@
+// Package rpc provides support for serving gRPC services through the same
+// middleware as the web api, so both apis log, trace, count, authenticate
+// and authorize calls the same way.
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// Server serves gRPC services. Every call runs through the middleware given
+// to the server and then the middleware set for its method. Methods without
+// middleware set through Handle are refused, so a service can't be served
+// without the checks its routes need. The middleware sees a call as a POST
+// request to the full method name, with the incoming metadata as the
+// headers.
+type Server struct {
+	tracer trace.Tracer
+	srv    *grpc.Server
+	mw     []web.MidFunc
+	routes map[string][]web.MidFunc
+}
+
+// NewServer constructs a server for use.
+func NewServer(tracer trace.Tracer, mw ...web.MidFunc) *Server {
+	s := Server{
+		tracer: tracer,
+		mw:     mw,
+		routes: make(map[string][]web.MidFunc),
+	}
+
+	s.srv = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unary),
+		grpc.ChainStreamInterceptor(s.stream),
+	)
+
+	return &s
+}
+
+// EnableReflection serves the reflection service through the specified
+// middleware, so tools such as grpcurl can find the services without the
+// proto files. It must be called before the server is started.
+func (s *Server) EnableReflection(mw ...web.MidFunc) {
+	reflection.Register(s.srv)
+
+	s.Handle(grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName, mw...)
+	s.Handle(grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfo_FullMethodName, mw...)
+}
+
+// RegisterService implements the grpc.ServiceRegistrar interface so the
+// generated register functions can bind services to the server.
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	s.srv.RegisterService(desc, impl)
+}
+
+// Handle sets the middleware that runs for the specified method, after the
+// middleware of the server. It must be called before the server is started.
+func (s *Server) Handle(fullMethod string, mw ...web.MidFunc) {
+	s.routes[fullMethod] = mw
+}
+
+// Serve accepts connections on the listener until the server is stopped.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// GracefulStop stops accepting connections and waits for the calls in
+// flight to finish.
+func (s *Server) GracefulStop() {
+	s.srv.GracefulStop()
+}
+
+// Stop closes every connection, canceling the calls in flight.
+func (s *Server) Stop() {
+	s.srv.Stop()
+}
+
+// =============================================================================
+
+func (s *Server) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var resp any
+
+	h := func(ctx context.Context, r *http.Request) web.Encoder {
+		v, err := handler(ctx, req)
+		if err != nil {
+			return errs.NewError(err)
+		}
+
+		resp = v
+		return nil
+	}
+
+	ctx = setRequest(ctx, req)
+
+	if err := s.call(ctx, info.FullMethod, h); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *Server) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	h := func(ctx context.Context, r *http.Request) web.Encoder {
+		if err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx}); err != nil {
+			return errs.NewError(err)
+		}
+
+		return nil
+	}
+
+	return s.call(ss.Context(), info.FullMethod, h)
+}
+
+// call runs the handler through the middleware as a request would be. The
+// span is started here, as otelhttp does for the web api, continuing the
+// trace of the caller when the metadata carries one.
+func (s *Server) call(ctx context.Context, fullMethod string, handler web.HandlerFunc) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	header := make(http.Header, len(md))
+	for k, v := range md {
+		header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+
+	ctx, span := s.tracer.Start(ctx, fullMethod, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, fullMethod, nil)
+	if err != nil {
+		return errs.Newf(errs.Internal, "request: %s", err)
+	}
+	r.Header = header
+
+	if p, ok := peer.FromContext(ctx); ok {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	mw, exists := s.routes[fullMethod]
+	if !exists {
+		handler = func(ctx context.Context, r *http.Request) web.Encoder {
+			return errs.Newf(errs.PermissionDenied, "method[%s] has no route", fullMethod)
+		}
+	}
+
+	handler = wrapMiddleware(mw, handler)
+	handler = wrapMiddleware(s.mw, handler)
+
+	if err, ok := handler(ctx, r).(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+func wrapMiddleware(mw []web.MidFunc, handler web.HandlerFunc) web.HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] != nil {
+			handler = mw[i](handler)
+		}
+	}
+
+	return handler
+}
+
+// serverStream hands the context built by the middleware to the handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+// =============================================================================
+
+// Param sets a path value from the request message of a unary call, so the
+// middleware written for the web api finds it as it would in the url. It
+// does nothing for requests of another type and for streaming calls, whose
+// request is only read by the handler.
+func Param[T any](name string, fn func(req T) string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if req, ok := getRequest(ctx).(T); ok {
+				r.SetPathValue(name, fn(req))
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+type ctxKey int
+
+const requestKey ctxKey = 1
+
+func setRequest(ctx context.Context, req any) context.Context {
+	return context.WithValue(ctx, requestKey, req)
+}
+
+func getRequest(ctx context.Context) any {
+	return ctx.Value(requestKey)
+}
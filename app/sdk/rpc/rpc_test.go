@@ -0,0 +1,184 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/himynamej/todo/app/sdk/errs"
+	"github.com/himynamej/todo/app/sdk/rpc"
+	"github.com/himynamej/todo/foundation/web"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+type ctxKey int
+
+const callerKey ctxKey = 1
+
+// authen plays the part of the authentication middleware, reading the token
+// from the metadata and handing the caller to the handler.
+func authen(next web.HandlerFunc) web.HandlerFunc {
+	return func(ctx context.Context, r *http.Request) web.Encoder {
+		if r.Header.Get("authorization") != "Bearer ok" {
+			return errs.Newf(errs.Unauthenticated, "no token")
+		}
+
+		return next(context.WithValue(ctx, callerKey, "bill"), r)
+	}
+}
+
+// private plays the part of the authorization middleware, reading the
+// path value set from the request.
+func private(next web.HandlerFunc) web.HandlerFunc {
+	return func(ctx context.Context, r *http.Request) web.Encoder {
+		if web.Param(r, "service") == "private" {
+			return errs.Newf(errs.PermissionDenied, "private service")
+		}
+
+		return next(ctx, r)
+	}
+}
+
+type health struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (health) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if req.GetService() == "missing" {
+		return nil, errs.Newf(errs.NotFound, "unknown service")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (health) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	if stream.Context().Value(callerKey) != "bill" {
+		return errs.Newf(errs.Internal, "caller missing in context")
+	}
+
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+func Test_Server(t *testing.T) {
+	srv := rpc.NewServer(noop.NewTracerProvider().Tracer(""), authen)
+	grpc_health_v1.RegisterHealthServer(srv, health{})
+
+	service := rpc.Param("service", func(req interface{ GetService() string }) string { return req.GetService() })
+	srv.Handle(grpc_health_v1.Health_Check_FullMethodName, service, private)
+	srv.Handle(grpc_health_v1.Health_Watch_FullMethodName)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Should be able to listen : %s", err)
+	}
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Should be able to connect : %s", err)
+	}
+	defer conn.Close()
+
+	client := grpc_health_v1.NewHealthClient(conn)
+	authed := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer ok")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		service string
+		code    codes.Code
+	}{
+		{name: "unauthenticated", ctx: context.Background(), service: "public", code: codes.Unauthenticated},
+		{name: "denied", ctx: authed, service: "private", code: codes.PermissionDenied},
+		{name: "notfound", ctx: authed, service: "missing", code: codes.NotFound},
+		{name: "ok", ctx: authed, service: "public", code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		_, err := client.Check(tt.ctx, &grpc_health_v1.HealthCheckRequest{Service: tt.service})
+		if got := status.Code(err); got != tt.code {
+			t.Errorf("%s: got code %s, exp %s : %v", tt.name, got, tt.code, err)
+		}
+	}
+
+	stream, err := client.Watch(authed, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Should be able to watch : %s", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Should receive the status : %s", err)
+	}
+
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("Got status %s, exp SERVING", resp.GetStatus())
+	}
+}
+
+func Test_ServerUnrouted(t *testing.T) {
+	srv := rpc.NewServer(noop.NewTracerProvider().Tracer(""))
+	grpc_health_v1.RegisterHealthServer(srv, health{})
+	srv.EnableReflection(authen)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Should be able to listen : %s", err)
+	}
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Should be able to connect : %s", err)
+	}
+	defer conn.Close()
+
+	// Check was registered with the server but never given a route.
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Errorf("unrouted: got code %s, exp %s : %v", got, codes.PermissionDenied, err)
+	}
+
+	reflect := grpc_reflection_v1.NewServerReflectionClient(conn)
+	authed := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer ok")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{name: "reflection-unauthenticated", ctx: context.Background(), code: codes.Unauthenticated},
+		{name: "reflection-ok", ctx: authed, code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		stream, err := reflect.ServerReflectionInfo(tt.ctx)
+		if err != nil {
+			t.Fatalf("%s: Should be able to open the stream : %s", tt.name, err)
+		}
+
+		req := grpc_reflection_v1.ServerReflectionRequest{
+			MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+		}
+		if err := stream.Send(&req); err != nil {
+			t.Fatalf("%s: Should be able to send : %s", tt.name, err)
+		}
+
+		_, err = stream.Recv()
+		if got := status.Code(err); got != tt.code {
+			t.Errorf("%s: got code %s, exp %s : %v", tt.name, got, tt.code, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package auditbus
+
+import "fmt"
+
+// The set of actions recorded in the audit log.
+var (
+	ActionPasswordResetRequested = newAction("password_reset_requested")
+	ActionPasswordReset          = newAction("password_reset")
+	ActionPasswordChanged        = newAction("password_changed")
+)
+
+// Set of known actions.
+var actions = make(map[string]Action)
+
+// Action represents something done to the credentials of a user.
+type Action struct {
+	value string
+}
+
+func newAction(action string) Action {
+	a := Action{action}
+	actions[action] = a
+	return a
+}
+
+// ParseAction parses the string value and returns an action if one exists.
+func ParseAction(value string) (Action, error) {
+	a, exists := actions[value]
+	if !exists {
+		return Action{}, fmt.Errorf("invalid action %q", value)
+	}
+
+	return a, nil
+}
+
+// String returns the name of the action.
+func (a Action) String() string {
+	return a.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (a Action) Equal(a2 Action) bool {
+	return a.value == a2.value
+}
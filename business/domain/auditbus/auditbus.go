@@ -0,0 +1,84 @@
+// Package auditbus provides business access to the auth audit log, which
+// records every change made to how a user signs in.
+package auditbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, evt Event) error
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Event, error)
+}
+
+// Business manages the set of APIs for audit log access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an audit log business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Record adds an event to the audit log.
+func (b *Business) Record(ctx context.Context, ne NewEvent) (Event, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.record")
+	defer span.End()
+
+	evt := Event{
+		ID:          uuid.New(),
+		UserID:      ne.UserID,
+		Action:      ne.Action,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.Create(ctx, evt); err != nil {
+		return Event{}, fmt.Errorf("create: %w", err)
+	}
+
+	return evt, nil
+}
+
+// QueryByUserID finds the events of the specified user, newest first.
+func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Event, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.querybyuserid")
+	defer span.End()
+
+	evts, err := b.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return evts, nil
+}
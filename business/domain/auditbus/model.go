@@ -0,0 +1,21 @@
+package auditbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event represents an entry in the auth audit log.
+type Event struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Action      Action
+	DateCreated time.Time
+}
+
+// NewEvent is what we require to record an event.
+type NewEvent struct {
+	UserID uuid.UUID
+	Action Action
+}
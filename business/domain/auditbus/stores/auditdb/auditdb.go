@@ -0,0 +1,84 @@
+// Package auditdb contains auth audit log related CRUD functionality.
+package auditdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/auditbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for audit log database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (auditbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new event into the database.
+func (s *Store) Create(ctx context.Context, evt auditbus.Event) error {
+	const q = `
+	INSERT INTO auth_audit
+		(event_id, user_id, action, date_created)
+	VALUES
+		(:event_id, :user_id, :action, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBEvent(evt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByUserID gets the events of the specified user, newest first.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]auditbus.Event, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		event_id, user_id, action, date_created
+	FROM
+		auth_audit
+	WHERE
+		user_id = :user_id
+	ORDER BY
+		date_created DESC, event_id`
+
+	var dbEvts []event
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEvts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEvents(dbEvts)
+}
@@ -0,0 +1,54 @@
+package auditdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/auditbus"
+)
+
+type event struct {
+	ID          uuid.UUID `db:"event_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Action      string    `db:"action"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBEvent(bus auditbus.Event) event {
+	return event{
+		ID:          bus.ID,
+		UserID:      bus.UserID,
+		Action:      bus.Action.String(),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusEvent(db event) (auditbus.Event, error) {
+	action, err := auditbus.ParseAction(db.Action)
+	if err != nil {
+		return auditbus.Event{}, fmt.Errorf("parse action: %w", err)
+	}
+
+	bus := auditbus.Event{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		Action:      action,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusEvents(dbs []event) ([]auditbus.Event, error) {
+	bus := make([]auditbus.Event, len(dbs))
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusEvent(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,221 @@
+// Package commentbus provides business access to comment domain.
+package commentbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound     = errors.New("comment not found")
+	ErrTodoNotFound = errors.New("todo item not found")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, cmt Comment) error
+	Update(ctx context.Context, cmt Comment) error
+	Delete(ctx context.Context, cmt Comment) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Comment, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, commentID uuid.UUID) (Comment, error)
+}
+
+// Business manages the set of APIs for comment access.
+type Business struct {
+	log      *logger.Logger
+	storer   Storer
+	delegate *delegate.Delegate
+	userBus  *userbus.Business
+	todoBus  *todobus.Business
+}
+
+// NewBusiness constructs a comment business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, userBus *userbus.Business, todoBus *todobus.Business, storer Storer) *Business {
+	return &Business{
+		log:      log,
+		storer:   storer,
+		delegate: delegate,
+		userBus:  userBus,
+		todoBus:  todoBus,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		storer:   storer,
+		delegate: b.delegate,
+		userBus:  b.userBus,
+		todoBus:  b.todoBus,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new comment to a todo item and notifies mentioned users.
+func (b *Business) Create(ctx context.Context, nc NewComment) (Comment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.commentbus.create")
+	defer span.End()
+
+	item, err := b.todoBus.QueryByID(ctx, nc.TodoID)
+	if err != nil {
+		if errors.Is(err, todobus.ErrNotFound) {
+			return Comment{}, fmt.Errorf("todo: itemID[%s]: %w", nc.TodoID, ErrTodoNotFound)
+		}
+		return Comment{}, fmt.Errorf("todo: itemID[%s]: %w", nc.TodoID, err)
+	}
+
+	mentions, err := b.resolveMentions(ctx, item.OrgID, nc.Body.String())
+	if err != nil {
+		return Comment{}, fmt.Errorf("resolvementions: %w", err)
+	}
+
+	now := time.Now()
+
+	cmt := Comment{
+		ID:          uuid.New(),
+		TodoID:      nc.TodoID,
+		UserID:      nc.UserID,
+		Body:        nc.Body,
+		Mentions:    mentions,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, cmt); err != nil {
+		return Comment{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.notify(ctx, cmt, mentions)
+
+	return cmt, nil
+}
+
+// Update modifies the body of a comment and marks it as edited. Only users
+// who were not already mentioned are notified.
+func (b *Business) Update(ctx context.Context, cmt Comment, uc UpdateComment) (Comment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.commentbus.update")
+	defer span.End()
+
+	if uc.Body == nil || uc.Body.Equal(cmt.Body) {
+		return cmt, nil
+	}
+
+	item, err := b.todoBus.QueryByID(ctx, cmt.TodoID)
+	if err != nil {
+		return Comment{}, fmt.Errorf("todo: itemID[%s]: %w", cmt.TodoID, err)
+	}
+
+	mentions, err := b.resolveMentions(ctx, item.OrgID, uc.Body.String())
+	if err != nil {
+		return Comment{}, fmt.Errorf("resolvementions: %w", err)
+	}
+
+	prior := make(map[uuid.UUID]bool, len(cmt.Mentions))
+	for _, userID := range cmt.Mentions {
+		prior[userID] = true
+	}
+
+	var added []uuid.UUID
+	for _, userID := range mentions {
+		if !prior[userID] {
+			added = append(added, userID)
+		}
+	}
+
+	cmt.Body = *uc.Body
+	cmt.Mentions = mentions
+	cmt.Edited = true
+	cmt.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, cmt); err != nil {
+		return Comment{}, fmt.Errorf("update: %w", err)
+	}
+
+	b.notify(ctx, cmt, added)
+
+	return cmt, nil
+}
+
+// Delete removes the specified comment.
+func (b *Business) Delete(ctx context.Context, cmt Comment) error {
+	ctx, span := otel.AddSpan(ctx, "business.commentbus.delete")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, cmt); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing comments.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Comment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.commentbus.query")
+	defer span.End()
+
+	cmts, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return cmts, nil
+}
+
+// Count returns the total number of comments.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.commentbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the comment by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, commentID uuid.UUID) (Comment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.commentbus.querybyid")
+	defer span.End()
+
+	cmt, err := b.storer.QueryByID(ctx, commentID)
+	if err != nil {
+		return Comment{}, fmt.Errorf("query: commentID[%s]: %w", commentID, err)
+	}
+
+	return cmt, nil
+}
+
+// notify lets other domains know a user was mentioned so a notification can
+// be sent. Authors mentioning themselves are skipped.
+func (b *Business) notify(ctx context.Context, cmt Comment, userIDs []uuid.UUID) {
+	for _, userID := range userIDs {
+		if userID == cmt.UserID {
+			continue
+		}
+
+		if err := b.delegate.Call(ctx, ActionMentionedData(cmt, userID)); err != nil {
+			b.log.Error(ctx, "commentbus: notify", "action", ActionMentioned, "commentID", cmt.ID, "userID", userID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package commentbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/markdown"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Comment(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Comment")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+}
+
+// =============================================================================
+
+type seedData struct {
+	unitest.SeedData
+	Comments []commentbus.Comment
+}
+
+func insertSeedData(busDomain dbtest.BusDomain) (seedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 3, role.User, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	todos, err := todobus.TestSeedTodoItems(ctx, 1, usrs[0].ID, busDomain.Todo)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding todo items : %w", err)
+	}
+
+	// Only the second user can be mentioned on the item, the third is in
+	// another organization.
+
+	nm := orgbus.NewMember{
+		OrgID:  todos[0].OrgID,
+		UserID: usrs[1].ID,
+		Role:   orgbus.RoleMember,
+	}
+
+	if _, err := busDomain.Org.AddMember(ctx, nm); err != nil {
+		return seedData{}, fmt.Errorf("seeding member : %w", err)
+	}
+
+	cmts, err := commentbus.TestSeedComments(ctx, 2, todos[0].ID, usrs[0].ID, busDomain.Comment)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding comments : %w", err)
+	}
+
+	sd := seedData{
+		SeedData: unitest.SeedData{
+			Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}, {User: usrs[2]}},
+			Todos: todos,
+		},
+		Comments: cmts,
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "all",
+			ExpResp: sd.Comments,
+			ExcFunc: func(ctx context.Context) any {
+				filter := commentbus.QueryFilter{
+					TodoID: &sd.Todos[0].ID,
+				}
+
+				resp, err := busDomain.Comment.Query(ctx, filter, commentbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]commentbus.Comment)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]commentbus.Comment)
+
+				for i := range gotResp {
+					if gotResp[i].DateCreated.Format(time.RFC3339) == expResp[i].DateCreated.Format(time.RFC3339) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+
+					if gotResp[i].DateUpdated.Format(time.RFC3339) == expResp[i].DateUpdated.Format(time.RFC3339) {
+						expResp[i].DateUpdated = gotResp[i].DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "byid",
+			ExpResp: sd.Comments[0],
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Comment.QueryByID(ctx, sd.Comments[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(commentbus.Comment)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(commentbus.Comment)
+
+				if gotResp.DateCreated.Format(time.RFC3339) == expResp.DateCreated.Format(time.RFC3339) {
+					expResp.DateCreated = gotResp.DateCreated
+				}
+
+				if gotResp.DateUpdated.Format(time.RFC3339) == expResp.DateUpdated.Format(time.RFC3339) {
+					expResp.DateUpdated = gotResp.DateUpdated
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func create(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	mentioned := sd.Users[1]
+	outsider := sd.Users[2]
+
+	var events []delegate.Data
+	busDomain.Delegate.Register(commentbus.DomainName, commentbus.ActionMentioned, func(ctx context.Context, data delegate.Data) error {
+		events = append(events, data)
+		return nil
+	})
+
+	table := []unitest.Table{
+		{
+			Name: "mention",
+			ExpResp: commentbus.Comment{
+				TodoID:   sd.Todos[0].ID,
+				UserID:   sd.Users[0].ID,
+				Body:     markdown.MustParse(fmt.Sprintf("ping @%s, @%s and @%s", mentioned.Email.Address, outsider.Email.Address, "nobody@example.com")),
+				Mentions: []uuid.UUID{mentioned.ID},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nc := commentbus.NewComment{
+					TodoID: sd.Todos[0].ID,
+					UserID: sd.Users[0].ID,
+					Body:   markdown.MustParse(fmt.Sprintf("ping @%s, @%s and @%s", mentioned.Email.Address, outsider.Email.Address, "nobody@example.com")),
+				}
+
+				resp, err := busDomain.Comment.Create(ctx, nc)
+				if err != nil {
+					return err
+				}
+
+				if len(events) != 1 {
+					return fmt.Errorf("expected 1 mention event, got %d", len(events))
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(commentbus.Comment)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.(commentbus.Comment)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "todo-not-found",
+			ExpResp: commentbus.ErrTodoNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				nc := commentbus.NewComment{
+					TodoID: uuid.New(),
+					UserID: sd.Users[0].ID,
+					Body:   markdown.MustParse("orphan"),
+				}
+
+				_, err := busDomain.Comment.Create(ctx, nc)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("expected %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func update(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: commentbus.Comment{
+				ID:          sd.Comments[0].ID,
+				TodoID:      sd.Comments[0].TodoID,
+				UserID:      sd.Comments[0].UserID,
+				Body:        markdown.MustParse("edited body"),
+				Edited:      true,
+				DateCreated: sd.Comments[0].DateCreated,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				body := markdown.MustParse("edited body")
+
+				resp, err := busDomain.Comment.Update(ctx, sd.Comments[0], commentbus.UpdateComment{Body: &body})
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(commentbus.Comment)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(commentbus.Comment)
+
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func delete(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "comment",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Comment.Delete(ctx, sd.Comments[1]); err != nil {
+					return err
+				}
+
+				return nil
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != nil {
+					return "error occurred"
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
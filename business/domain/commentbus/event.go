@@ -0,0 +1,56 @@
+package commentbus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/delegate"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "comment"
+
+// Set of delegate actions.
+const (
+	ActionMentioned = "mentioned"
+)
+
+// ActionMentionedParms represents the parameters for the mentioned action.
+type ActionMentionedParms struct {
+	CommentID uuid.UUID
+	TodoID    uuid.UUID
+	AuthorID  uuid.UUID
+	UserID    uuid.UUID
+}
+
+// String returns a string representation of the action parameters.
+func (am *ActionMentionedParms) String() string {
+	return fmt.Sprintf("&EventParamsMentioned{CommentID:%v, TodoID:%v, AuthorID:%v, UserID:%v}", am.CommentID, am.TodoID, am.AuthorID, am.UserID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (am *ActionMentionedParms) Marshal() ([]byte, error) {
+	return json.Marshal(am)
+}
+
+// ActionMentionedData constructs the data for the mentioned action.
+func ActionMentionedData(cmt Comment, userID uuid.UUID) delegate.Data {
+	params := ActionMentionedParms{
+		CommentID: cmt.ID,
+		TodoID:    cmt.TodoID,
+		AuthorID:  cmt.UserID,
+		UserID:    userID,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionMentioned,
+		RawParams: rawParams,
+	}
+}
@@ -0,0 +1,129 @@
+package commentbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+// ParseMentions returns the distinct @mentions found in the text, in the
+// order they first appear, without the leading @. A mention must start at
+// the beginning of the text or follow a character that is not a letter or
+// digit, so plain email addresses inside a sentence are not treated as
+// mentions. Both "@Name" and "@user@example.com" forms are returned.
+func ParseMentions(text string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' {
+			continue
+		}
+
+		if i > 0 && (unicode.IsLetter(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			continue
+		}
+
+		j := i + 1
+		for j < len(runes) && isMentionRune(runes[j]) {
+			j++
+		}
+
+		token := strings.TrimRight(string(runes[i+1:j]), ".-'@")
+		i = j - 1
+
+		if token == "" || seen[token] {
+			continue
+		}
+
+		seen[token] = true
+		mentions = append(mentions, token)
+	}
+
+	return mentions
+}
+
+func isMentionRune(r rune) bool {
+	switch {
+	case unicode.IsLetter(r), unicode.IsDigit(r):
+		return true
+	}
+
+	return strings.ContainsRune("._%+-@'", r)
+}
+
+// resolveMentions looks up the users referenced by the mentions in the text
+// among the members of the organization. A mention containing an @ is looked
+// up by email, anything else by name. Mentions that do not resolve to a
+// member are left as plain text, since a comment can legitimately contain an
+// @ that is not meant to notify anyone, and users of other organizations must
+// not be found through it.
+func (b *Business) resolveMentions(ctx context.Context, orgID uuid.UUID, text string) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+
+	for _, mention := range ParseMentions(text) {
+		usr, err := b.resolveMention(ctx, orgID, mention)
+		if err != nil {
+			if errors.Is(err, userbus.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("mention[%s]: %w", mention, err)
+		}
+
+		if seen[usr.ID] {
+			continue
+		}
+
+		seen[usr.ID] = true
+		userIDs = append(userIDs, usr.ID)
+	}
+
+	return userIDs, nil
+}
+
+func (b *Business) resolveMention(ctx context.Context, orgID uuid.UUID, mention string) (userbus.User, error) {
+	filter := userbus.QueryFilter{
+		OrgID: &orgID,
+	}
+
+	switch {
+	case strings.Contains(mention, "@"):
+		addr, err := mail.ParseAddress(mention)
+		if err != nil {
+			return userbus.User{}, userbus.ErrNotFound
+		}
+		filter.Email = addr
+
+	default:
+		nme, err := name.Parse(mention)
+		if err != nil {
+			return userbus.User{}, userbus.ErrNotFound
+		}
+		filter.Name = &nme
+	}
+
+	// The name filter is a partial match so the exact name is
+	// selected from the candidates.
+	usrs, err := b.userBus.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "100"))
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	for _, usr := range usrs {
+		if filter.Email != nil || strings.EqualFold(usr.Name.String(), filter.Name.String()) {
+			return usr, nil
+		}
+	}
+
+	return userbus.User{}, userbus.ErrNotFound
+}
@@ -0,0 +1,34 @@
+package commentbus_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/commentbus"
+)
+
+func Test_ParseMentions(t *testing.T) {
+	table := []struct {
+		name string
+		text string
+		exp  []string
+	}{
+		{"none", "nothing to see here", nil},
+		{"name", "hey @Bill can you look", []string{"Bill"}},
+		{"email", "cc @bill@example.com.", []string{"bill@example.com"}},
+		{"inline-email", "send to bill@example.com", nil},
+		{"duplicates", "@Ann @Ann and @Bob", []string{"Ann", "Bob"}},
+		{"punctuation", "(@Ann), @Bob!", []string{"Ann", "Bob"}},
+		{"bare-at", "meet @ noon", nil},
+		{"line-start", "@Ann\n@Bob", []string{"Ann", "Bob"}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got := commentbus.ParseMentions(tt.text)
+			if diff := cmp.Diff(got, tt.exp); diff != "" {
+				t.Fatalf("Should get the expected mentions:\n%s", diff)
+			}
+		})
+	}
+}
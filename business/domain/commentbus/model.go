@@ -0,0 +1,32 @@
+package commentbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/markdown"
+)
+
+// Comment represents a comment left by a user on a todo item.
+type Comment struct {
+	ID          uuid.UUID
+	TodoID      uuid.UUID
+	UserID      uuid.UUID
+	Body        markdown.Markdown
+	Mentions    []uuid.UUID
+	Edited      bool
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewComment contains information needed to create a new comment.
+type NewComment struct {
+	TodoID uuid.UUID
+	UserID uuid.UUID
+	Body   markdown.Markdown
+}
+
+// UpdateComment contains information needed to update a comment.
+type UpdateComment struct {
+	Body *markdown.Markdown
+}
@@ -0,0 +1,13 @@
+package commentbus
+
+import "github.com/himynamej/todo/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "comment_id"
+	OrderByUserID      = "user_id"
+	OrderByDateCreated = "date_created"
+)
@@ -0,0 +1,193 @@
+// Package commentdb contains comment related CRUD functionality.
+package commentdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for comment database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (commentbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new comment into the database.
+func (s *Store) Create(ctx context.Context, cmt commentbus.Comment) error {
+	const q = `
+	INSERT INTO comments
+		(comment_id, item_id, user_id, body, mentions, edited, date_created, date_updated)
+	VALUES
+		(:comment_id, :item_id, :user_id, :body, :mentions, :edited, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBComment(cmt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a comment document in the database.
+func (s *Store) Update(ctx context.Context, cmt commentbus.Comment) error {
+	const q = `
+	UPDATE
+		comments
+	SET
+		"body" = :body,
+		"mentions" = :mentions,
+		"edited" = :edited,
+		"date_updated" = :date_updated
+	WHERE
+		comment_id = :comment_id AND ` + orgScope
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, scopedComment(ctx, cmt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a comment from the database.
+func (s *Store) Delete(ctx context.Context, cmt commentbus.Comment) error {
+	const q = `
+	DELETE FROM
+		comments
+	WHERE
+		comment_id = :comment_id AND ` + orgScope
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, scopedComment(ctx, cmt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing comments from the database.
+func (s *Store) Query(ctx context.Context, filter commentbus.QueryFilter, orderBy order.By, page page.Page) ([]commentbus.Comment, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		comment_id, item_id, user_id, body, mentions, edited, date_created, date_updated
+	FROM
+		comments`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(ctx, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbCmts []comment
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbCmts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusComments(dbCmts)
+}
+
+// Count returns the total number of comments in the DB.
+func (s *Store) Count(ctx context.Context, filter commentbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		comments`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified comment from the database.
+func (s *Store) QueryByID(ctx context.Context, commentID uuid.UUID) (commentbus.Comment, error) {
+	data := struct {
+		ID    string        `db:"comment_id"`
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		ID:    commentID.String(),
+		OrgID: sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		comment_id, item_id, user_id, body, mentions, edited, date_created, date_updated
+	FROM
+		comments
+	WHERE
+		comment_id = :comment_id AND ` + orgScope
+
+	var dbCmt comment
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbCmt); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return commentbus.Comment{}, fmt.Errorf("db: %w", commentbus.ErrNotFound)
+		}
+		return commentbus.Comment{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusComment(dbCmt)
+}
+
+// scopedComment returns the comment along with the organization the context
+// scopes access to, for the queries that use orgScope.
+func scopedComment(ctx context.Context, cmt commentbus.Comment) any {
+	return struct {
+		comment
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		comment: toDBComment(cmt),
+		OrgID:   sqldb.OrgArg(ctx),
+	}
+}
@@ -0,0 +1,53 @@
+package commentdb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+)
+
+// orgScope limits a query to the comments on the items of the organization
+// in :org_id. Every comment passes when it is null, which is how work that
+// spans organizations reads.
+const orgScope = `item_id IN (
+		SELECT item_id FROM todo_items
+		WHERE CAST(:org_id AS UUID) IS NULL OR org_id = CAST(:org_id AS UUID)
+	)`
+
+func applyFilter(ctx context.Context, filter commentbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	data["org_id"] = sqldb.OrgArg(ctx)
+	wc := []string{orgScope}
+
+	if filter.ID != nil {
+		data["comment_id"] = *filter.ID
+		wc = append(wc, "comment_id = :comment_id")
+	}
+
+	if filter.TodoID != nil {
+		data["item_id"] = *filter.TodoID
+		wc = append(wc, "item_id = :item_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
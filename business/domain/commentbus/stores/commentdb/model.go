@@ -0,0 +1,83 @@
+package commentdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/sdk/sqldb/dbarray"
+	"github.com/himynamej/todo/business/types/markdown"
+)
+
+type comment struct {
+	ID          uuid.UUID      `db:"comment_id"`
+	TodoID      uuid.UUID      `db:"item_id"`
+	UserID      uuid.UUID      `db:"user_id"`
+	Body        string         `db:"body"`
+	Mentions    dbarray.String `db:"mentions"`
+	Edited      bool           `db:"edited"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBComment(bus commentbus.Comment) comment {
+	mentions := make(dbarray.String, len(bus.Mentions))
+	for i, userID := range bus.Mentions {
+		mentions[i] = userID.String()
+	}
+
+	return comment{
+		ID:          bus.ID,
+		TodoID:      bus.TodoID,
+		UserID:      bus.UserID,
+		Body:        bus.Body.String(),
+		Mentions:    mentions,
+		Edited:      bus.Edited,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusComment(db comment) (commentbus.Comment, error) {
+	body, err := markdown.Parse(db.Body)
+	if err != nil {
+		return commentbus.Comment{}, fmt.Errorf("parse body: %w", err)
+	}
+
+	var mentions []uuid.UUID
+	for _, id := range db.Mentions {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return commentbus.Comment{}, fmt.Errorf("parse mention: %w", err)
+		}
+		mentions = append(mentions, userID)
+	}
+
+	bus := commentbus.Comment{
+		ID:          db.ID,
+		TodoID:      db.TodoID,
+		UserID:      db.UserID,
+		Body:        body,
+		Mentions:    mentions,
+		Edited:      db.Edited,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusComments(dbs []comment) ([]commentbus.Comment, error) {
+	bus := make([]commentbus.Comment, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusComment(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,23 @@
+package commentdb
+
+import (
+	"fmt"
+
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	commentbus.OrderByID:          "comment_id",
+	commentbus.OrderByUserID:      "user_id",
+	commentbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
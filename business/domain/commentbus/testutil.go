@@ -0,0 +1,47 @@
+package commentbus
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/markdown"
+)
+
+// TestNewComments is a helper method for testing.
+func TestNewComments(n int, todoID uuid.UUID, userID uuid.UUID) []NewComment {
+	newCmts := make([]NewComment, n)
+
+	idx := rand.Intn(10000)
+	for i := 0; i < n; i++ {
+		idx++
+
+		nc := NewComment{
+			TodoID: todoID,
+			UserID: userID,
+			Body:   markdown.MustParse(fmt.Sprintf("Comment **%d**", idx)),
+		}
+
+		newCmts[i] = nc
+	}
+
+	return newCmts
+}
+
+// TestSeedComments is a helper method for testing.
+func TestSeedComments(ctx context.Context, n int, todoID uuid.UUID, userID uuid.UUID, api *Business) ([]Comment, error) {
+	newCmts := TestNewComments(n, todoID, userID)
+
+	cmts := make([]Comment, len(newCmts))
+	for i, nc := range newCmts {
+		cmt, err := api.Create(ctx, nc)
+		if err != nil {
+			return nil, fmt.Errorf("seeding comment: idx: %d : %w", i, err)
+		}
+
+		cmts[i] = cmt
+	}
+
+	return cmts, nil
+}
@@ -0,0 +1,202 @@
+// Package idempotencybus provides business access to idempotency keys so
+// retried requests can be answered with the response of the first attempt.
+package idempotencybus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound  = errors.New("idempotency key not found")
+	ErrDuplicate = errors.New("idempotency key already exists")
+	ErrMismatch  = errors.New("idempotency key was used with a different request")
+	ErrInFlight  = errors.New("a request with this idempotency key is still in progress")
+)
+
+const (
+	// TTL is how long a key and its response are kept.
+	TTL = 24 * time.Hour
+
+	// LockTimeout is how long a key may stay reserved without a response
+	// before a retry is allowed to take it over.
+	LockTimeout = time.Minute
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, rec Record) error
+	Update(ctx context.Context, rec Record) error
+	Delete(ctx context.Context, rec Record) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+	QueryByKey(ctx context.Context, userID uuid.UUID, key string) (Record, error)
+}
+
+// Business manages the set of APIs for idempotency key access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an idempotency business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Begin reserves the key for the request. If the key was already used for the
+// same request and a response was stored, that record is returned and the
+// caller should replay it. A key reused for a different request returns
+// ErrMismatch, and a key whose first request is still running returns
+// ErrInFlight.
+func (b *Business) Begin(ctx context.Context, nr NewRecord) (Record, error) {
+	ctx, span := otel.AddSpan(ctx, "business.idempotencybus.begin")
+	defer span.End()
+
+	now := time.Now()
+
+	rec := Record{
+		Key:         nr.Key,
+		UserID:      nr.UserID,
+		RequestHash: nr.RequestHash,
+		DateCreated: now,
+		DateExpires: now.Add(TTL),
+	}
+
+	err := b.storer.Create(ctx, rec)
+	if err == nil {
+		return rec, nil
+	}
+
+	if !errors.Is(err, ErrDuplicate) {
+		return Record{}, fmt.Errorf("create: %w", err)
+	}
+
+	existing, err := b.storer.QueryByKey(ctx, nr.UserID, nr.Key)
+	if err != nil {
+		return Record{}, fmt.Errorf("querybykey: key[%s]: %w", nr.Key, err)
+	}
+
+	// Expired keys are only purged now and then, until then they are taken
+	// over as if they were gone.
+
+	if existing.Expired(now) {
+		return b.takeOver(ctx, existing, rec)
+	}
+
+	if existing.RequestHash != nr.RequestHash {
+		return Record{}, ErrMismatch
+	}
+
+	if existing.Completed() {
+		return existing, nil
+	}
+
+	if now.Sub(existing.DateCreated) < LockTimeout {
+		return Record{}, ErrInFlight
+	}
+
+	// The first request never stored a response, most likely because the
+	// process died while handling it, so this retry takes the key over.
+
+	return b.takeOver(ctx, existing, rec)
+}
+
+// Purge removes every key that expired before now. It runs in the
+// background so reserving a key never waits on it.
+func (b *Business) Purge(ctx context.Context, now time.Time) error {
+	ctx, span := otel.AddSpan(ctx, "business.idempotencybus.purge")
+	defer span.End()
+
+	if err := b.storer.DeleteExpired(ctx, now); err != nil {
+		return fmt.Errorf("deleteexpired: %w", err)
+	}
+
+	return nil
+}
+
+// takeOver replaces the existing record of the key with the new one.
+func (b *Business) takeOver(ctx context.Context, existing Record, rec Record) (Record, error) {
+	if err := b.storer.Delete(ctx, existing); err != nil {
+		return Record{}, fmt.Errorf("delete: %w", err)
+	}
+
+	if err := b.storer.Create(ctx, rec); err != nil {
+		if errors.Is(err, ErrDuplicate) {
+			return Record{}, ErrInFlight
+		}
+		return Record{}, fmt.Errorf("create: %w", err)
+	}
+
+	return rec, nil
+}
+
+// Complete stores the response for a reserved key.
+func (b *Business) Complete(ctx context.Context, rec Record, resp Response) (Record, error) {
+	ctx, span := otel.AddSpan(ctx, "business.idempotencybus.complete")
+	defer span.End()
+
+	rec.StatusCode = resp.StatusCode
+	rec.ContentType = resp.ContentType
+	rec.Body = resp.Body
+
+	if err := b.storer.Update(ctx, rec); err != nil {
+		return Record{}, fmt.Errorf("update: %w", err)
+	}
+
+	return rec, nil
+}
+
+// Release removes a reserved key so the request can be retried, used when
+// the request failed and there is no response worth replaying.
+func (b *Business) Release(ctx context.Context, rec Record) error {
+	ctx, span := otel.AddSpan(ctx, "business.idempotencybus.release")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, rec); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByKey finds the record for the specified user and key.
+func (b *Business) QueryByKey(ctx context.Context, userID uuid.UUID, key string) (Record, error) {
+	ctx, span := otel.AddSpan(ctx, "business.idempotencybus.querybykey")
+	defer span.End()
+
+	rec, err := b.storer.QueryByKey(ctx, userID, key)
+	if err != nil {
+		return Record{}, fmt.Errorf("query: userID[%s] key[%s]: %w", userID, key, err)
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,196 @@
+package idempotencybus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Idempotency(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Idempotency")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, begin(db.BusDomain, sd), "begin")
+	unitest.Run(t, release(db.BusDomain, sd), "release")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func begin(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	nr := idempotencybus.NewRecord{
+		Key:         "begin-key",
+		UserID:      sd.Users[0].ID,
+		RequestHash: "hash-1",
+	}
+
+	resp := idempotencybus.Response{
+		StatusCode:  http.StatusCreated,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":"1"}`),
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "inflight",
+			ExpResp: idempotencybus.ErrInFlight,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.Idempotency.Begin(ctx, nr); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Idempotency.Begin(ctx, nr)
+				return err
+			},
+			CmpFunc: cmpErr,
+		},
+		{
+			Name:    "replay",
+			ExpResp: resp,
+			ExcFunc: func(ctx context.Context) any {
+				rec, err := busDomain.Idempotency.QueryByKey(ctx, nr.UserID, nr.Key)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Idempotency.Complete(ctx, rec, resp); err != nil {
+					return err
+				}
+
+				rec, err = busDomain.Idempotency.Begin(ctx, nr)
+				if err != nil {
+					return err
+				}
+
+				if !rec.Completed() {
+					return errors.New("expected a completed record")
+				}
+
+				return idempotencybus.Response{
+					StatusCode:  rec.StatusCode,
+					ContentType: rec.ContentType,
+					Body:        rec.Body,
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "mismatch",
+			ExpResp: idempotencybus.ErrMismatch,
+			ExcFunc: func(ctx context.Context) any {
+				other := nr
+				other.RequestHash = "hash-2"
+
+				_, err := busDomain.Idempotency.Begin(ctx, other)
+				return err
+			},
+			CmpFunc: cmpErr,
+		},
+		{
+			Name:    "otheruser",
+			ExpResp: false,
+			ExcFunc: func(ctx context.Context) any {
+				other := nr
+				other.UserID = sd.Users[1].ID
+				other.RequestHash = "hash-2"
+
+				rec, err := busDomain.Idempotency.Begin(ctx, other)
+				if err != nil {
+					return err
+				}
+
+				return rec.Completed()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func release(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	nr := idempotencybus.NewRecord{
+		Key:         "release-key",
+		UserID:      sd.Users[0].ID,
+		RequestHash: "hash-1",
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "retry",
+			ExpResp: false,
+			ExcFunc: func(ctx context.Context) any {
+				rec, err := busDomain.Idempotency.Begin(ctx, nr)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Idempotency.Release(ctx, rec); err != nil {
+					return err
+				}
+
+				rec, err = busDomain.Idempotency.Begin(ctx, nr)
+				if err != nil {
+					return err
+				}
+
+				return rec.Completed()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func cmpErr(got any, exp any) string {
+	gotErr, ok := got.(error)
+	if !ok {
+		return fmt.Sprintf("expected an error, got %v", got)
+	}
+
+	if !errors.Is(gotErr, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", gotErr, exp)
+	}
+
+	return ""
+}
@@ -0,0 +1,44 @@
+package idempotencybus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record represents a request made under an idempotency key. A record with a
+// zero StatusCode is still being processed.
+type Record struct {
+	Key         string
+	UserID      uuid.UUID
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	DateCreated time.Time
+	DateExpires time.Time
+}
+
+// Completed reports whether a response has been stored for the record.
+func (r Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Expired reports whether the key can be used again as of now.
+func (r Record) Expired(now time.Time) bool {
+	return !now.Before(r.DateExpires)
+}
+
+// NewRecord is what we require from clients when reserving a key.
+type NewRecord struct {
+	Key         string
+	UserID      uuid.UUID
+	RequestHash string
+}
+
+// Response is the response to store against a reserved key.
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
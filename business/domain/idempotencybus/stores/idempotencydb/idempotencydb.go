@@ -0,0 +1,147 @@
+// Package idempotencydb contains idempotency key related CRUD functionality.
+package idempotencydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for idempotency key database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (idempotencybus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create reserves a key in the database.
+func (s *Store) Create(ctx context.Context, rec idempotencybus.Record) error {
+	const q = `
+	INSERT INTO idempotency_keys
+		(idempotency_key, user_id, request_hash, status_code, content_type, body, date_created, date_expires)
+	VALUES
+		(:idempotency_key, :user_id, :request_hash, :status_code, :content_type, :body, :date_created, :date_expires)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRecord(rec)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", idempotencybus.ErrDuplicate)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update stores the response for a key.
+func (s *Store) Update(ctx context.Context, rec idempotencybus.Record) error {
+	const q = `
+	UPDATE
+		idempotency_keys
+	SET
+		"status_code" = :status_code,
+		"content_type" = :content_type,
+		"body" = :body
+	WHERE
+		user_id = :user_id AND idempotency_key = :idempotency_key`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRecord(rec)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a key from the database.
+func (s *Store) Delete(ctx context.Context, rec idempotencybus.Record) error {
+	const q = `
+	DELETE FROM
+		idempotency_keys
+	WHERE
+		user_id = :user_id AND idempotency_key = :idempotency_key`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRecord(rec)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes every key that expired before the specified time.
+func (s *Store) DeleteExpired(ctx context.Context, now time.Time) error {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now.UTC(),
+	}
+
+	const q = `
+	DELETE FROM
+		idempotency_keys
+	WHERE
+		date_expires < :now`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByKey gets the specified key for the user from the database.
+func (s *Store) QueryByKey(ctx context.Context, userID uuid.UUID, key string) (idempotencybus.Record, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+		Key    string `db:"idempotency_key"`
+	}{
+		UserID: userID.String(),
+		Key:    key,
+	}
+
+	const q = `
+	SELECT
+		idempotency_key, user_id, request_hash, status_code, content_type, body, date_created, date_expires
+	FROM
+		idempotency_keys
+	WHERE
+		user_id = :user_id AND idempotency_key = :idempotency_key`
+
+	var dbRec record
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRec); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return idempotencybus.Record{}, fmt.Errorf("db: %w", idempotencybus.ErrNotFound)
+		}
+		return idempotencybus.Record{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRecord(dbRec), nil
+}
@@ -0,0 +1,45 @@
+package idempotencydb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+)
+
+type record struct {
+	Key         string    `db:"idempotency_key"`
+	UserID      uuid.UUID `db:"user_id"`
+	RequestHash string    `db:"request_hash"`
+	StatusCode  int       `db:"status_code"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"body"`
+	DateCreated time.Time `db:"date_created"`
+	DateExpires time.Time `db:"date_expires"`
+}
+
+func toDBRecord(bus idempotencybus.Record) record {
+	return record{
+		Key:         bus.Key,
+		UserID:      bus.UserID,
+		RequestHash: bus.RequestHash,
+		StatusCode:  bus.StatusCode,
+		ContentType: bus.ContentType,
+		Body:        bus.Body,
+		DateCreated: bus.DateCreated.UTC(),
+		DateExpires: bus.DateExpires.UTC(),
+	}
+}
+
+func toBusRecord(db record) idempotencybus.Record {
+	return idempotencybus.Record{
+		Key:         db.Key,
+		UserID:      db.UserID,
+		RequestHash: db.RequestHash,
+		StatusCode:  db.StatusCode,
+		ContentType: db.ContentType,
+		Body:        db.Body,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateExpires: db.DateExpires.In(time.Local),
+	}
+}
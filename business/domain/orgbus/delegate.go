@@ -0,0 +1,47 @@
+package orgbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(userbus.DomainName, userbus.ActionCreated, b.actionUserCreated)
+	}
+}
+
+// actionUserCreated is executed by the user domain indirectly when a user is
+// created. Every new user gets an organization of their own to admin, so they
+// have somewhere to keep their todo items until they are invited to others.
+func (b *Business) actionUserCreated(ctx context.Context, data delegate.Data) error {
+	var params userbus.ActionUserParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	b.log.Info(ctx, "action-usercreated", "user_id", params.UserID)
+
+	orgName, err := name.Parse(params.Name)
+	if err != nil {
+		return fmt.Errorf("parse name: %w", err)
+	}
+
+	no := NewOrg{
+		Name:    orgName,
+		OwnerID: params.UserID,
+	}
+
+	if _, err := b.Create(ctx, no); err != nil {
+		return fmt.Errorf("create: userID[%s]: %w", params.UserID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package orgbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+// Org represents an organization, such as a customer company, whose members
+// share todo items that no one outside it can see.
+type Org struct {
+	ID          uuid.UUID
+	Name        name.Name
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewOrg is what we require from clients when adding an organization. The
+// owner becomes its first admin.
+type NewOrg struct {
+	Name    name.Name
+	OwnerID uuid.UUID
+}
+
+// UpdateOrg contains information needed to update an organization.
+type UpdateOrg struct {
+	Name *name.Name
+}
+
+// Member represents a user that belongs to an organization.
+type Member struct {
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	Role        Role
+	DateCreated time.Time
+}
+
+// NewMember is what we require from clients when adding a user to an
+// organization.
+type NewMember struct {
+	OrgID  uuid.UUID
+	UserID uuid.UUID
+	Role   Role
+}
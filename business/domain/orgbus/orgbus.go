@@ -0,0 +1,340 @@
+// Package orgbus provides business access to organizations and their
+// members.
+package orgbus
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound       = errors.New("organization not found")
+	ErrMemberNotFound = errors.New("member not found")
+	ErrMemberExists   = errors.New("user is already a member")
+	ErrLastAdmin      = errors.New("organization must keep an admin")
+)
+
+// DefaultID identifies the organization users and todo items belong to when
+// no other is given, which is where everything from before organizations
+// went.
+var DefaultID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, org Org) error
+	Update(ctx context.Context, org Org) error
+	Delete(ctx context.Context, org Org) error
+	QueryByID(ctx context.Context, orgID uuid.UUID) (Org, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Org, error)
+	LockOrg(ctx context.Context, orgID uuid.UUID) error
+	CreateMember(ctx context.Context, mbr Member) error
+	UpdateMember(ctx context.Context, mbr Member) error
+	DeleteMember(ctx context.Context, mbr Member) error
+	QueryMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (Member, error)
+	QueryMembers(ctx context.Context, orgID uuid.UUID) ([]Member, error)
+	CountAdmins(ctx context.Context, orgID uuid.UUID) (int, error)
+}
+
+// Business manages the set of APIs for organization access.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+	beginner sqldb.Beginner
+}
+
+// NewBusiness constructs an organization business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, beginner sqldb.Beginner) *Business {
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		beginner: beginner,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		delegate: b.delegate,
+		storer:   storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new organization to the system, with its owner as the
+// first admin.
+func (b *Business) Create(ctx context.Context, no NewOrg) (Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.create")
+	defer span.End()
+
+	now := time.Now()
+
+	org := Org{
+		ID:          uuid.New(),
+		Name:        no.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	mbr := Member{
+		OrgID:       org.ID,
+		UserID:      no.OwnerID,
+		Role:        RoleAdmin,
+		DateCreated: now,
+	}
+
+	err := b.withTran(ctx, func(bus *Business) error {
+		if err := bus.storer.Create(ctx, org); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		if err := bus.storer.CreateMember(ctx, mbr); err != nil {
+			return fmt.Errorf("createmember: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Org{}, err
+	}
+
+	return org, nil
+}
+
+// Update modifies information about an organization.
+func (b *Business) Update(ctx context.Context, org Org, uo UpdateOrg) (Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.update")
+	defer span.End()
+
+	if uo.Name != nil {
+		org.Name = *uo.Name
+	}
+
+	org.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, org); err != nil {
+		return Org{}, fmt.Errorf("update: %w", err)
+	}
+
+	return org, nil
+}
+
+// Delete removes the specified organization, along with everything its
+// members keep in it.
+func (b *Business) Delete(ctx context.Context, org Org) error {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.delete")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, org); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the organization by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, orgID uuid.UUID) (Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.querybyid")
+	defer span.End()
+
+	org, err := b.storer.QueryByID(ctx, orgID)
+	if err != nil {
+		return Org{}, fmt.Errorf("query: orgID[%s]: %w", orgID, err)
+	}
+
+	return org, nil
+}
+
+// QueryByUserID finds the organizations the specified user belongs to, in
+// the order they joined them.
+func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.querybyuserid")
+	defer span.End()
+
+	orgs, err := b.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return orgs, nil
+}
+
+// =============================================================================
+
+// AddMember adds a user to an organization.
+func (b *Business) AddMember(ctx context.Context, nm NewMember) (Member, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.addmember")
+	defer span.End()
+
+	mbr := Member{
+		OrgID:       nm.OrgID,
+		UserID:      nm.UserID,
+		Role:        nm.Role,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.CreateMember(ctx, mbr); err != nil {
+		return Member{}, fmt.Errorf("createmember: %w", err)
+	}
+
+	return mbr, nil
+}
+
+// UpdateMember gives a member a new role. The last admin of an
+// organization can't be made a plain member.
+func (b *Business) UpdateMember(ctx context.Context, mbr Member, role Role) (Member, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.updatemember")
+	defer span.End()
+
+	err := b.withTran(ctx, func(bus *Business) error {
+		if role != RoleAdmin {
+			if err := bus.checkAdminLeft(ctx, mbr); err != nil {
+				return err
+			}
+		}
+
+		mbr.Role = role
+
+		if err := bus.storer.UpdateMember(ctx, mbr); err != nil {
+			return fmt.Errorf("updatemember: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Member{}, err
+	}
+
+	return mbr, nil
+}
+
+// RemoveMember takes a user out of an organization. The last admin of an
+// organization can't be removed.
+func (b *Business) RemoveMember(ctx context.Context, mbr Member) error {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.removemember")
+	defer span.End()
+
+	return b.withTran(ctx, func(bus *Business) error {
+		if err := bus.checkAdminLeft(ctx, mbr); err != nil {
+			return err
+		}
+
+		if err := bus.storer.DeleteMember(ctx, mbr); err != nil {
+			return fmt.Errorf("deletemember: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// QueryMember finds the membership of a user in an organization.
+func (b *Business) QueryMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (Member, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.querymember")
+	defer span.End()
+
+	mbr, err := b.storer.QueryMember(ctx, orgID, userID)
+	if err != nil {
+		return Member{}, fmt.Errorf("query: orgID[%s] userID[%s]: %w", orgID, userID, err)
+	}
+
+	return mbr, nil
+}
+
+// QueryMembers finds the members of an organization.
+func (b *Business) QueryMembers(ctx context.Context, orgID uuid.UUID) ([]Member, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.querymembers")
+	defer span.End()
+
+	mbrs, err := b.storer.QueryMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("query: orgID[%s]: %w", orgID, err)
+	}
+
+	return mbrs, nil
+}
+
+// =============================================================================
+
+// checkAdminLeft makes sure the organization still has an admin once the
+// member stops being one. The organization stays locked until the
+// transaction ends, so two admins can't step down at the same time.
+func (b *Business) checkAdminLeft(ctx context.Context, mbr Member) error {
+	if mbr.Role != RoleAdmin {
+		return nil
+	}
+
+	if err := b.storer.LockOrg(ctx, mbr.OrgID); err != nil {
+		return fmt.Errorf("lockorg: orgID[%s]: %w", mbr.OrgID, err)
+	}
+
+	admins, err := b.storer.CountAdmins(ctx, mbr.OrgID)
+	if err != nil {
+		return fmt.Errorf("countadmins: orgID[%s]: %w", mbr.OrgID, err)
+	}
+
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+
+	return nil
+}
+
+// withTran runs fn against a business value bound to a new transaction,
+// committing when fn succeeds. A business value already bound to a
+// transaction runs fn directly.
+func (b *Business) withTran(ctx context.Context, fn func(bus *Business) error) error {
+	if b.beginner == nil {
+		return fn(b)
+	}
+
+	tx, err := b.beginner.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Error(ctx, "orgbus: rollback", "ERROR", err)
+		}
+	}()
+
+	bus, err := b.NewWithTx(tx)
+	if err != nil {
+		return fmt.Errorf("newwithtx: %w", err)
+	}
+
+	if err := fn(bus); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,361 @@
+package orgbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/jmoiron/sqlx"
+)
+
+func Test_Org(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Org")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, members(db.BusDomain, sd), "members")
+	unitest.Run(t, isolation(db.BusDomain, db.DB, sd), "isolation")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// personalOrg returns the organization the user was given when created.
+func personalOrg(ctx context.Context, busDomain dbtest.BusDomain, usr unitest.User) (orgbus.Org, error) {
+	orgs, err := busDomain.Org.QueryByUserID(ctx, usr.ID)
+	if err != nil {
+		return orgbus.Org{}, err
+	}
+
+	if len(orgs) != 1 {
+		return orgbus.Org{}, fmt.Errorf("got %d orgs, exp 1", len(orgs))
+	}
+
+	return orgs[0], nil
+}
+
+// =============================================================================
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "personal",
+			ExpResp: []any{sd.Users[0].Name.String(), orgbus.RoleAdmin.String()},
+			ExcFunc: func(ctx context.Context) any {
+				org, err := personalOrg(ctx, busDomain, sd.Users[0])
+				if err != nil {
+					return err
+				}
+
+				mbr, err := busDomain.Org.QueryMember(ctx, org.ID, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return []any{org.Name.String(), mbr.Role.String()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "owner",
+			ExpResp: orgbus.RoleAdmin.String(),
+			ExcFunc: func(ctx context.Context) any {
+				no := orgbus.NewOrg{
+					Name:    name.MustParse("Acme"),
+					OwnerID: sd.Users[1].ID,
+				}
+
+				org, err := busDomain.Org.Create(ctx, no)
+				if err != nil {
+					return err
+				}
+
+				mbr, err := busDomain.Org.QueryMember(ctx, org.ID, sd.Users[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return mbr.Role.String()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func members(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "exists",
+			ExpResp: orgbus.ErrMemberExists,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := personalOrg(ctx, busDomain, sd.Users[0])
+				if err != nil {
+					return err
+				}
+
+				nm := orgbus.NewMember{
+					OrgID:  org.ID,
+					UserID: sd.Users[1].ID,
+					Role:   orgbus.RoleMember,
+				}
+
+				if _, err := busDomain.Org.AddMember(ctx, nm); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Org.AddMember(ctx, nm)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "last-admin",
+			ExpResp: orgbus.ErrLastAdmin,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := personalOrg(ctx, busDomain, sd.Users[0])
+				if err != nil {
+					return err
+				}
+
+				mbr, err := busDomain.Org.QueryMember(ctx, org.ID, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Org.UpdateMember(ctx, mbr, orgbus.RoleMember); !errors.Is(err, orgbus.ErrLastAdmin) {
+					return fmt.Errorf("demoting the last admin: got %v", err)
+				}
+
+				return busDomain.Org.RemoveMember(ctx, mbr)
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "hand-over",
+			ExpResp: orgbus.ErrMemberNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := personalOrg(ctx, busDomain, sd.Users[0])
+				if err != nil {
+					return err
+				}
+
+				other, err := busDomain.Org.QueryMember(ctx, org.ID, sd.Users[1].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Org.UpdateMember(ctx, other, orgbus.RoleAdmin); err != nil {
+					return err
+				}
+
+				mbr, err := busDomain.Org.QueryMember(ctx, org.ID, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Org.RemoveMember(ctx, mbr); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Org.QueryMember(ctx, org.ID, sd.Users[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func isolation(busDomain dbtest.BusDomain, db *sqlx.DB, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "other-org",
+			ExpResp: todobus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: name.MustParse("Initech"), OwnerID: sd.Users[1].ID})
+				if err != nil {
+					return err
+				}
+
+				other, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: name.MustParse("Hooli"), OwnerID: sd.Users[1].ID})
+				if err != nil {
+					return err
+				}
+
+				orgCtx := sqldb.WithOrgID(ctx, org.ID)
+				otherCtx := sqldb.WithOrgID(ctx, other.ID)
+
+				nt := todobus.NewTodoItem{
+					UserID:      sd.Users[1].ID,
+					Description: "Quarterly report",
+				}
+
+				item, err := busDomain.Todo.Create(orgCtx, nt)
+				if err != nil {
+					return err
+				}
+
+				if item.OrgID != org.ID {
+					return fmt.Errorf("got org %s, exp %s", item.OrgID, org.ID)
+				}
+
+				filter := todobus.QueryFilter{UserID: &sd.Users[1].ID}
+
+				n, err := busDomain.Todo.Count(otherCtx, filter)
+				if err != nil {
+					return err
+				}
+
+				if n != 0 {
+					return fmt.Errorf("got %d items in the other org, exp 0", n)
+				}
+
+				_, err = busDomain.Todo.QueryByID(otherCtx, item.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "row-security",
+			ExpResp: []int{1, 0, 0},
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: name.MustParse("Globex"), OwnerID: sd.Users[0].ID})
+				if err != nil {
+					return err
+				}
+
+				other, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: name.MustParse("Umbrella"), OwnerID: sd.Users[0].ID})
+				if err != nil {
+					return err
+				}
+
+				nt := todobus.NewTodoItem{
+					UserID:      sd.Users[0].ID,
+					Description: "Annual budget",
+				}
+
+				item, err := busDomain.Todo.Create(sqldb.WithOrgID(ctx, org.ID), nt)
+				if err != nil {
+					return err
+				}
+
+				// The queries below leave out the organization on purpose, so
+				// only the policies stand between the role and the other org.
+
+				var counts []int
+				for _, orgID := range []string{org.ID.String(), other.ID.String(), ""} {
+					n, err := countAsAppRole(ctx, db, orgID, item.ID)
+					if err != nil {
+						return err
+					}
+					counts = append(counts, n)
+				}
+
+				return counts
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// countAsAppRole counts the todo items with the specified id the way the
+// requests see them, connected with the role the service uses and with the
+// specified organization set.
+func countAsAppRole(ctx context.Context, db *sqlx.DB, orgID string, itemID uuid.UUID) (int, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var attrs struct {
+		Super     bool `db:"rolsuper"`
+		BypassRLS bool `db:"rolbypassrls"`
+	}
+	if err := tx.GetContext(ctx, &attrs, "SELECT rolsuper, rolbypassrls FROM pg_roles WHERE rolname = 'sales_api'"); err != nil {
+		return 0, fmt.Errorf("role: %w", err)
+	}
+
+	if attrs.Super || attrs.BypassRLS {
+		return 0, fmt.Errorf("role sales_api is a superuser[%t] or bypasses rls[%t]", attrs.Super, attrs.BypassRLS)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL ROLE sales_api"); err != nil {
+		return 0, fmt.Errorf("set role: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('app.org_id', $1, true)", orgID); err != nil {
+		return 0, fmt.Errorf("set org: %w", err)
+	}
+
+	var n int
+	if err := tx.GetContext(ctx, &n, "SELECT count(*) FROM todo_items WHERE item_id = $1", itemID); err != nil {
+		return 0, fmt.Errorf("count: %w", err)
+	}
+
+	return n, nil
+}
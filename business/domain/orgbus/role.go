@@ -0,0 +1,44 @@
+package orgbus
+
+import "fmt"
+
+// The set of roles a member can have in an organization.
+var (
+	RoleAdmin  = newRole("ADMIN")
+	RoleMember = newRole("MEMBER")
+)
+
+// Set of known roles.
+var roles = make(map[string]Role)
+
+// Role represents the role of a member in an organization. Admins manage
+// the organization and its members.
+type Role struct {
+	value string
+}
+
+func newRole(role string) Role {
+	r := Role{role}
+	roles[role] = r
+	return r
+}
+
+// ParseRole parses the string value and returns a role if one exists.
+func ParseRole(value string) (Role, error) {
+	r, exists := roles[value]
+	if !exists {
+		return Role{}, fmt.Errorf("invalid role %q", value)
+	}
+
+	return r, nil
+}
+
+// String returns the name of the role.
+func (r Role) String() string {
+	return r.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Role) Equal(r2 Role) bool {
+	return r.value == r2.value
+}
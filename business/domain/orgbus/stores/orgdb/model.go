@@ -0,0 +1,102 @@
+package orgdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+type org struct {
+	ID          uuid.UUID `db:"org_id"`
+	Name        string    `db:"name"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBOrg(bus orgbus.Org) org {
+	return org{
+		ID:          bus.ID,
+		Name:        bus.Name.String(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusOrg(db org) (orgbus.Org, error) {
+	nme, err := name.Parse(db.Name)
+	if err != nil {
+		return orgbus.Org{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	bus := orgbus.Org{
+		ID:          db.ID,
+		Name:        nme,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusOrgs(dbs []org) ([]orgbus.Org, error) {
+	bus := make([]orgbus.Org, len(dbs))
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusOrg(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type member struct {
+	OrgID       uuid.UUID `db:"org_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Role        string    `db:"role"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBMember(bus orgbus.Member) member {
+	return member{
+		OrgID:       bus.OrgID,
+		UserID:      bus.UserID,
+		Role:        bus.Role.String(),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusMember(db member) (orgbus.Member, error) {
+	role, err := orgbus.ParseRole(db.Role)
+	if err != nil {
+		return orgbus.Member{}, fmt.Errorf("parse role: %w", err)
+	}
+
+	bus := orgbus.Member{
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		Role:        role,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusMembers(dbs []member) ([]orgbus.Member, error) {
+	bus := make([]orgbus.Member, len(dbs))
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusMember(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,315 @@
+// Package orgdb contains organization related CRUD functionality.
+package orgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for organization database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (orgbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new organization into the database.
+func (s *Store) Create(ctx context.Context, org orgbus.Org) error {
+	const q = `
+	INSERT INTO organizations
+		(org_id, name, date_created, date_updated)
+	VALUES
+		(:org_id, :name, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBOrg(org)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an organization document in the database.
+func (s *Store) Update(ctx context.Context, org orgbus.Org) error {
+	const q = `
+	UPDATE
+		organizations
+	SET
+		"name" = :name,
+		"date_updated" = :date_updated
+	WHERE
+		org_id = :org_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBOrg(org)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an organization, its members and their todo items from
+// the database.
+func (s *Store) Delete(ctx context.Context, org orgbus.Org) error {
+	const q = `
+	DELETE FROM
+		organizations
+	WHERE
+		org_id = :org_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBOrg(org)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified organization from the database.
+func (s *Store) QueryByID(ctx context.Context, orgID uuid.UUID) (orgbus.Org, error) {
+	data := struct {
+		ID string `db:"org_id"`
+	}{
+		ID: orgID.String(),
+	}
+
+	const q = `
+	SELECT
+		org_id, name, date_created, date_updated
+	FROM
+		organizations
+	WHERE
+		org_id = :org_id`
+
+	var dbOrg org
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbOrg); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orgbus.Org{}, fmt.Errorf("db: %w", orgbus.ErrNotFound)
+		}
+		return orgbus.Org{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusOrg(dbOrg)
+}
+
+// QueryByUserID gets the organizations the specified user belongs to.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]orgbus.Org, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		o.org_id, o.name, o.date_created, o.date_updated
+	FROM
+		organizations o
+	JOIN
+		org_members m ON m.org_id = o.org_id
+	WHERE
+		m.user_id = :user_id
+	ORDER BY
+		m.date_created, o.org_id`
+
+	var dbOrgs []org
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbOrgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusOrgs(dbOrgs)
+}
+
+// LockOrg holds the lock that serializes changes to the members of the
+// organization until the transaction ends.
+func (s *Store) LockOrg(ctx context.Context, orgID uuid.UUID) error {
+	data := struct {
+		ID string `db:"org_id"`
+	}{
+		ID: orgID.String(),
+	}
+
+	const q = `
+	SELECT
+		org_id
+	FROM
+		organizations
+	WHERE
+		org_id = :org_id
+	FOR UPDATE`
+
+	var dest struct {
+		ID string `db:"org_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", orgbus.ErrNotFound)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// CreateMember inserts a new member into the database.
+func (s *Store) CreateMember(ctx context.Context, mbr orgbus.Member) error {
+	const q = `
+	INSERT INTO org_members
+		(org_id, user_id, role, date_created)
+	VALUES
+		(:org_id, :user_id, :role, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMember(mbr)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", orgbus.ErrMemberExists)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateMember replaces the role of a member in the database.
+func (s *Store) UpdateMember(ctx context.Context, mbr orgbus.Member) error {
+	const q = `
+	UPDATE
+		org_members
+	SET
+		"role" = :role
+	WHERE
+		org_id = :org_id AND user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMember(mbr)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMember removes a member from the database.
+func (s *Store) DeleteMember(ctx context.Context, mbr orgbus.Member) error {
+	const q = `
+	DELETE FROM
+		org_members
+	WHERE
+		org_id = :org_id AND user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMember(mbr)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMember gets the membership of a user in an organization from the
+// database.
+func (s *Store) QueryMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (orgbus.Member, error) {
+	data := struct {
+		OrgID  string `db:"org_id"`
+		UserID string `db:"user_id"`
+	}{
+		OrgID:  orgID.String(),
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		org_id, user_id, role, date_created
+	FROM
+		org_members
+	WHERE
+		org_id = :org_id AND user_id = :user_id`
+
+	var dbMbr member
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbMbr); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orgbus.Member{}, fmt.Errorf("db: %w", orgbus.ErrMemberNotFound)
+		}
+		return orgbus.Member{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusMember(dbMbr)
+}
+
+// QueryMembers gets the members of an organization from the database.
+func (s *Store) QueryMembers(ctx context.Context, orgID uuid.UUID) ([]orgbus.Member, error) {
+	data := struct {
+		OrgID string `db:"org_id"`
+	}{
+		OrgID: orgID.String(),
+	}
+
+	const q = `
+	SELECT
+		org_id, user_id, role, date_created
+	FROM
+		org_members
+	WHERE
+		org_id = :org_id
+	ORDER BY
+		date_created, user_id`
+
+	var dbMbrs []member
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbMbrs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusMembers(dbMbrs)
+}
+
+// CountAdmins returns the number of admins an organization has.
+func (s *Store) CountAdmins(ctx context.Context, orgID uuid.UUID) (int, error) {
+	data := struct {
+		OrgID string `db:"org_id"`
+		Role  string `db:"role"`
+	}{
+		OrgID: orgID.String(),
+		Role:  orgbus.RoleAdmin.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		org_members
+	WHERE
+		org_id = :org_id AND role = :role`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
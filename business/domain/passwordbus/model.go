@@ -0,0 +1,21 @@
+package passwordbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reset represents an outstanding password reset. Only the hash of the token
+// is kept, the token itself is only ever in the email sent to the user.
+type Reset struct {
+	TokenHash   []byte
+	UserID      uuid.UUID
+	DateExpires time.Time
+	DateCreated time.Time
+}
+
+// Expired reports whether the reset can no longer be used at the given time.
+func (r Reset) Expired(now time.Time) bool {
+	return !now.Before(r.DateExpires)
+}
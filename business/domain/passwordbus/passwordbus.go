@@ -0,0 +1,348 @@
+// Package passwordbus provides business access to password resets and
+// changes made by the users themselves.
+package passwordbus
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/auditbus"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/mailer"
+	"github.com/himynamej/todo/business/sdk/opaque"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for password resets and changes.
+var (
+	ErrNotFound        = errors.New("password reset not found")
+	ErrInvalidToken    = errors.New("invalid reset token")
+	ErrTokenExpired    = errors.New("reset token expired")
+	ErrWrongPassword   = errors.New("current password is wrong")
+	ErrInvalidPassword = errors.New("password must be between 1 and 72 bytes")
+)
+
+// DefaultTTL is how long a reset link works when the config does not say.
+const DefaultTTL = 30 * time.Minute
+
+// Config represents how password resets are sent.
+type Config struct {
+	TTL      time.Duration // How long a reset link works
+	ResetURL string        // Where the links point, the token is added as a query parameter
+}
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, rst Reset) error
+	Consume(ctx context.Context, tokenHash []byte) (Reset, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// Business manages the set of APIs for password access.
+type Business struct {
+	log        *logger.Logger
+	userBus    *userbus.Business
+	auditBus   *auditbus.Business
+	sessionBus *sessionbus.Business
+	mailer     mailer.Mailer
+	storer     Storer
+	cfg        Config
+	beginner   sqldb.Beginner
+}
+
+// NewBusiness constructs a password business API for use. Without a beginner
+// a password is not set in one transaction.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, auditBus *auditbus.Business, sessionBus *sessionbus.Business, mailer mailer.Mailer, storer Storer, cfg Config, beginner sqldb.Beginner) *Business {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+
+	return &Business{
+		log:        log,
+		userBus:    userBus,
+		auditBus:   auditBus,
+		sessionBus: sessionBus,
+		mailer:     mailer,
+		storer:     storer,
+		cfg:        cfg,
+		beginner:   beginner,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	auditBus, err := b.auditBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionBus, err := b.sessionBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:        b.log,
+		userBus:    userBus,
+		auditBus:   auditBus,
+		sessionBus: sessionBus,
+		mailer:     b.mailer,
+		storer:     storer,
+		cfg:        b.cfg,
+	}
+
+	// The new value has no beginner, so work it does joins the transaction
+	// instead of starting another.
+
+	return &bus, nil
+}
+
+// Forgot emails the user with the address a link to reset their password
+// with. Nothing is sent for an address with no enabled account, and no error
+// is returned either, so the caller can't tell which addresses have one.
+func (b *Business) Forgot(ctx context.Context, email mail.Address) error {
+	ctx, span := otel.AddSpan(ctx, "business.passwordbus.forgot")
+	defer span.End()
+
+	usr, err := b.userBus.QueryByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("querybyemail: %w", err)
+	}
+
+	if !usr.Enabled {
+		return nil
+	}
+
+	tkn, hash, err := opaque.New()
+	if err != nil {
+		return fmt.Errorf("newtoken: %w", err)
+	}
+
+	now := time.Now()
+
+	rst := Reset{
+		TokenHash:   hash,
+		UserID:      usr.ID,
+		DateExpires: now.Add(b.cfg.TTL),
+		DateCreated: now,
+	}
+
+	if err := b.storer.Create(ctx, rst); err != nil {
+		return fmt.Errorf("create: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := b.send(ctx, usr, tkn, rst.DateExpires); err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	if err := b.record(ctx, usr.ID, auditbus.ActionPasswordResetRequested); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reset sets the password of the user the token was sent to. A token works
+// once, and every other token sent to the user stops working with it. The
+// token is only used up when the password is set, so a password that can't
+// be set leaves it working.
+func (b *Business) Reset(ctx context.Context, token string, password string) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.passwordbus.reset")
+	defer span.End()
+
+	if err := checkPassword(password); err != nil {
+		return userbus.User{}, err
+	}
+
+	var usr userbus.User
+
+	err := b.withTran(ctx, func(bus *Business) error {
+		rst, err := bus.storer.Consume(ctx, opaque.Hash(token))
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("consume: %w", err)
+		}
+
+		if rst.Expired(time.Now()) {
+			return ErrTokenExpired
+		}
+
+		usr, err = bus.userBus.QueryByID(ctx, rst.UserID)
+		if err != nil {
+			if errors.Is(err, userbus.ErrNotFound) {
+				return ErrInvalidToken
+			}
+			return fmt.Errorf("querybyid: userID[%s]: %w", rst.UserID, err)
+		}
+
+		if !usr.Enabled {
+			return ErrInvalidToken
+		}
+
+		usr, err = bus.set(ctx, usr, password, auditbus.ActionPasswordReset)
+		return err
+	})
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	return usr, nil
+}
+
+// Change sets a new password for the user once the current one is confirmed.
+func (b *Business) Change(ctx context.Context, usr userbus.User, current string, password string) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.passwordbus.change")
+	defer span.End()
+
+	if err := checkPassword(password); err != nil {
+		return userbus.User{}, err
+	}
+
+	if _, err := b.userBus.Authenticate(ctx, usr.Email, current); err != nil {
+		if errors.Is(err, userbus.ErrAuthenticationFailure) {
+			return userbus.User{}, ErrWrongPassword
+		}
+		return userbus.User{}, fmt.Errorf("authenticate: userID[%s]: %w", usr.ID, err)
+	}
+
+	err := b.withTran(ctx, func(bus *Business) error {
+		var err error
+		usr, err = bus.set(ctx, usr, password, auditbus.ActionPasswordChanged)
+		return err
+	})
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+// checkPassword reports whether the password can be hashed. Bcrypt refuses
+// passwords longer than 72 bytes.
+func checkPassword(password string) error {
+	if password == "" || len(password) > 72 {
+		return ErrInvalidPassword
+	}
+
+	return nil
+}
+
+// set replaces the password of the user, drops any reset still outstanding,
+// ends every session signed in with the old password and records what was
+// done. It is called inside withTran, so all of it
+// happens or none of it does.
+func (b *Business) set(ctx context.Context, usr userbus.User, password string, action auditbus.Action) (userbus.User, error) {
+	usr, err := b.userBus.Update(ctx, usr, userbus.UpdateUser{Password: &password})
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("update user: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := b.storer.DeleteByUserID(ctx, usr.ID); err != nil {
+		return userbus.User{}, fmt.Errorf("deletebyuserid: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := b.sessionBus.RevokeUser(ctx, usr.ID); err != nil {
+		return userbus.User{}, fmt.Errorf("revokeuser: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := b.record(ctx, usr.ID, action); err != nil {
+		return userbus.User{}, err
+	}
+
+	return usr, nil
+}
+
+// withTran runs fn with a business whose store calls share one transaction,
+// committing when fn succeeds. Without a beginner, fn runs with b itself.
+func (b *Business) withTran(ctx context.Context, fn func(bus *Business) error) error {
+	if b.beginner == nil {
+		return fn(b)
+	}
+
+	tx, err := b.beginner.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Error(ctx, "passwordbus: rollback", "ERROR", err)
+		}
+	}()
+
+	bus, err := b.NewWithTx(tx)
+	if err != nil {
+		return fmt.Errorf("newwithtx: %w", err)
+	}
+
+	if err := fn(bus); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// record adds the action to the auth audit log.
+func (b *Business) record(ctx context.Context, userID uuid.UUID, action auditbus.Action) error {
+	ne := auditbus.NewEvent{
+		UserID: userID,
+		Action: action,
+	}
+
+	if _, err := b.auditBus.Record(ctx, ne); err != nil {
+		return fmt.Errorf("record: userID[%s] action[%s]: %w", userID, action, err)
+	}
+
+	return nil
+}
+
+// send emails the user the link to reset their password with.
+func (b *Business) send(ctx context.Context, usr userbus.User, tkn string, expires time.Time) error {
+	link := b.cfg.ResetURL + "?token=" + url.QueryEscape(tkn)
+
+	msg := mailer.Message{
+		To:      usr.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to choose a new password:\n\n%s\n\nThe link works once, until %s. If you did not ask to reset your password, you can ignore this email.\n",
+			usr.Name, link, expires.UTC().Format(time.RFC1123)),
+	}
+
+	if err := b.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("mail: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package passwordbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/auditbus"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Password(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Password")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, reset(db.BusDomain, sd), "reset")
+	unitest.Run(t, change(db.BusDomain, sd), "change")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	var sd unitest.SeedData
+	for _, nu := range userbus.TestNewUsers(3, role.User) {
+		nu.Password = password
+
+		usr, err := busDomain.User.Create(ctx, nu)
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+		}
+
+		sd.Users = append(sd.Users, unitest.User{User: usr})
+	}
+
+	return sd, nil
+}
+
+// password is what the seeded users sign in with.
+const password = "gophers"
+
+var tokenRE = regexp.MustCompile(`token=(\S+)`)
+
+// lastToken returns the token of the last reset link sent to the address.
+func lastToken(ctx context.Context, busDomain dbtest.BusDomain, email mail.Address) (string, error) {
+	msgs, err := busDomain.Mail.Query(ctx, email)
+	if err != nil {
+		return "", err
+	}
+
+	if len(msgs) == 0 {
+		return "", fmt.Errorf("no mail sent to %s", email.Address)
+	}
+
+	m := tokenRE.FindStringSubmatch(msgs[len(msgs)-1].Body)
+	if m == nil {
+		return "", fmt.Errorf("no link in %q", msgs[len(msgs)-1].Body)
+	}
+
+	return url.QueryUnescape(m[1])
+}
+
+// actions returns the audit log of the user, oldest first.
+func actions(ctx context.Context, busDomain dbtest.BusDomain, usr userbus.User) ([]string, error) {
+	evts, err := busDomain.Audit.QueryByUserID(ctx, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	acts := make([]string, len(evts))
+	for i, evt := range evts {
+		acts[len(evts)-1-i] = evt.Action.String()
+	}
+
+	return acts, nil
+}
+
+// =============================================================================
+
+func reset(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "reset",
+			ExpResp: []string{auditbus.ActionPasswordResetRequested.String(), auditbus.ActionPasswordResetRequested.String(), auditbus.ActionPasswordReset.String()},
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[0].User
+
+				if err := busDomain.Password.Forgot(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				first, err := lastToken(ctx, busDomain, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Password.Forgot(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				tkn, err := lastToken(ctx, busDomain, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Password.Reset(ctx, tkn, "new-password"); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Authenticate(ctx, usr.Email, "new-password"); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Password.Reset(ctx, tkn, "again"); !errors.Is(err, passwordbus.ErrInvalidToken) {
+					return fmt.Errorf("reusing token: got %v, exp %v", err, passwordbus.ErrInvalidToken)
+				}
+
+				if _, err := busDomain.Password.Reset(ctx, first, "again"); !errors.Is(err, passwordbus.ErrInvalidToken) {
+					return fmt.Errorf("using older token: got %v, exp %v", err, passwordbus.ErrInvalidToken)
+				}
+
+				acts, err := actions(ctx, busDomain, usr)
+				if err != nil {
+					return err
+				}
+
+				return acts
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "bad-password",
+			ExpResp: passwordbus.ErrInvalidPassword,
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[2].User
+
+				if err := busDomain.Password.Forgot(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				tkn, err := lastToken(ctx, busDomain, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				_, resetErr := busDomain.Password.Reset(ctx, tkn, strings.Repeat("x", 73))
+
+				// The token still works after a password that can't be set.
+				if _, err := busDomain.Password.Reset(ctx, tkn, password); err != nil {
+					return fmt.Errorf("using token again: %w", err)
+				}
+
+				return resetErr
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "unknown",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				email := mail.Address{Address: "nobody@example.com"}
+
+				if err := busDomain.Password.Forgot(ctx, email); err != nil {
+					return err
+				}
+
+				msgs, err := busDomain.Mail.Query(ctx, email)
+				if err != nil {
+					return err
+				}
+
+				return len(msgs)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "invalid",
+			ExpResp: passwordbus.ErrInvalidToken,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Password.Reset(ctx, "not-a-token", "new-password")
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func change(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "change",
+			ExpResp: []string{auditbus.ActionPasswordResetRequested.String(), auditbus.ActionPasswordChanged.String()},
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[1].User
+
+				if err := busDomain.Password.Forgot(ctx, usr.Email); err != nil {
+					return err
+				}
+
+				tkn, err := lastToken(ctx, busDomain, usr.Email)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Password.Change(ctx, usr, password, "changed"); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Authenticate(ctx, usr.Email, "changed"); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Password.Reset(ctx, tkn, "again"); !errors.Is(err, passwordbus.ErrInvalidToken) {
+					return fmt.Errorf("using token after change: got %v, exp %v", err, passwordbus.ErrInvalidToken)
+				}
+
+				acts, err := actions(ctx, busDomain, usr)
+				if err != nil {
+					return err
+				}
+
+				return acts
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "wrong",
+			ExpResp: passwordbus.ErrWrongPassword,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Password.Change(ctx, sd.Users[2].User, "not-the-password", "changed")
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
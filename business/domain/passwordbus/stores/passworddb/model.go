@@ -0,0 +1,42 @@
+package passworddb
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+)
+
+type reset struct {
+	TokenHash   string    `db:"token_hash"`
+	UserID      uuid.UUID `db:"user_id"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBReset(bus passwordbus.Reset) reset {
+	return reset{
+		TokenHash:   hex.EncodeToString(bus.TokenHash),
+		UserID:      bus.UserID,
+		DateExpires: bus.DateExpires.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusReset(db reset) (passwordbus.Reset, error) {
+	hash, err := hex.DecodeString(db.TokenHash)
+	if err != nil {
+		return passwordbus.Reset{}, fmt.Errorf("decode token hash: %w", err)
+	}
+
+	bus := passwordbus.Reset{
+		TokenHash:   hash,
+		UserID:      db.UserID,
+		DateExpires: db.DateExpires.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,110 @@
+// Package passworddb contains password reset related CRUD functionality.
+package passworddb
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for password reset database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (passwordbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new password reset into the database.
+func (s *Store) Create(ctx context.Context, rst passwordbus.Reset) error {
+	const q = `
+	INSERT INTO password_resets
+		(token_hash, user_id, date_expires, date_created)
+	VALUES
+		(:token_hash, :user_id, :date_expires, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBReset(rst)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Consume removes the password reset with the token hash from the database
+// and returns it. Deleting and reading in one statement means two requests
+// with the same token can't both get it.
+func (s *Store) Consume(ctx context.Context, tokenHash []byte) (passwordbus.Reset, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+	}
+
+	const q = `
+	DELETE FROM
+		password_resets
+	WHERE
+		token_hash = :token_hash
+	RETURNING
+		token_hash, user_id, date_expires, date_created`
+
+	var dbRst reset
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRst); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return passwordbus.Reset{}, fmt.Errorf("db: %w", passwordbus.ErrNotFound)
+		}
+		return passwordbus.Reset{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusReset(dbRst)
+}
+
+// DeleteByUserID removes every password reset of the user from the database.
+func (s *Store) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		password_resets
+	WHERE
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package quotabus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/business/types/role"
+)
+
+// Set of scopes storage usage is tracked for.
+const (
+	ScopeUser       = "user"
+	ScopeDepartment = "department"
+)
+
+// Usage represents the bytes stored by a user or a department and the quota
+// that applies to them.
+type Usage struct {
+	Scope       string
+	Owner       string // The user id or the department name
+	Bytes       int64
+	Quota       int64 // Zero means there is no limit
+	Custom      bool  // The quota was set by an admin instead of the limits
+	DateUpdated time.Time
+}
+
+// Limits are the quotas used when an admin has not set one. A user gets the
+// largest quota of their roles. A zero quota means there is no limit.
+type Limits struct {
+	Roles      map[role.Role]int64
+	Department int64
+}
+
+// =============================================================================
+
+// ExceededError is returned when storing more bytes would take a user or a
+// department over their quota.
+type ExceededError struct {
+	Usage Usage
+	Size  int64
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s %s has used %d of %d bytes and cannot store %d more", e.Usage.Scope, e.Usage.Owner, e.Usage.Bytes, e.Usage.Quota, e.Size)
+}
+
+// Is reports the error as ErrExceeded.
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrExceeded
+}
+
+// GetExceeded returns a copy of the ExceededError pointer.
+func GetExceeded(err error) *ExceededError {
+	var ee *ExceededError
+	if !errors.As(err, &ee) {
+		return nil
+	}
+	return ee
+}
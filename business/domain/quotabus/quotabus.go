@@ -0,0 +1,242 @@
+// Package quotabus provides business access to the storage used by users and
+// departments and the quotas that limit it.
+package quotabus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for quota operations.
+var (
+	ErrNotFound     = errors.New("usage not found")
+	ErrExceeded     = errors.New("storage quota exceeded")
+	ErrInvalidQuota = errors.New("quota must not be negative")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Charge(ctx context.Context, usg Usage, size int64) (Usage, error)
+	Refund(ctx context.Context, usg Usage, size int64) error
+	SetQuota(ctx context.Context, usg Usage) (Usage, error)
+	QueryUsage(ctx context.Context, scope string, owner string) (Usage, error)
+}
+
+// Business manages the set of APIs for quota access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+	limits Limits
+}
+
+// NewBusiness constructs a quota business API for use.
+func NewBusiness(log *logger.Logger, storer Storer, limits Limits) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+		limits: limits,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+		limits: b.limits,
+	}
+
+	return &bus, nil
+}
+
+// Charge records size more bytes stored by the user and by the user's
+// department. When either would go over quota nothing is recorded and an
+// ExceededError is returned.
+func (b *Business) Charge(ctx context.Context, usr userbus.User, size int64) error {
+	ctx, span := otel.AddSpan(ctx, "business.quotabus.charge")
+	defer span.End()
+
+	usgs := b.owners(usr)
+
+	for i, usg := range usgs {
+		if _, err := b.storer.Charge(ctx, usg, size); err != nil {
+			// Give back what was already charged, so a failed charge leaves
+			// no trace even when it is not part of a transaction.
+			for _, charged := range usgs[:i] {
+				if err := b.storer.Refund(ctx, charged, size); err != nil {
+					b.log.Error(ctx, "quotabus: charge: refund", "scope", charged.Scope, "owner", charged.Owner, "ERROR", err)
+				}
+			}
+
+			if errors.Is(err, ErrExceeded) {
+				return b.exceeded(ctx, usg, size)
+			}
+			return fmt.Errorf("charge: %s[%s]: %w", usg.Scope, usg.Owner, err)
+		}
+	}
+
+	return nil
+}
+
+// Refund records size fewer bytes stored by the user and by the user's
+// department.
+func (b *Business) Refund(ctx context.Context, usr userbus.User, size int64) error {
+	ctx, span := otel.AddSpan(ctx, "business.quotabus.refund")
+	defer span.End()
+
+	for _, usg := range b.owners(usr) {
+		if err := b.storer.Refund(ctx, usg, size); err != nil {
+			return fmt.Errorf("refund: %s[%s]: %w", usg.Scope, usg.Owner, err)
+		}
+	}
+
+	return nil
+}
+
+// QueryByUser returns the usage of the user followed by the usage of the
+// user's department, if the user belongs to one.
+func (b *Business) QueryByUser(ctx context.Context, usr userbus.User) ([]Usage, error) {
+	ctx, span := otel.AddSpan(ctx, "business.quotabus.querybyuser")
+	defer span.End()
+
+	usgs := b.owners(usr)
+
+	for i, usg := range usgs {
+		stored, err := b.queryUsage(ctx, usg)
+		if err != nil {
+			return nil, fmt.Errorf("queryusage: %s[%s]: %w", usg.Scope, usg.Owner, err)
+		}
+		usgs[i] = stored
+	}
+
+	return usgs, nil
+}
+
+// QueryByDepartment returns the usage of the department.
+func (b *Business) QueryByDepartment(ctx context.Context, department name.Name) (Usage, error) {
+	ctx, span := otel.AddSpan(ctx, "business.quotabus.querybydepartment")
+	defer span.End()
+
+	usg, err := b.queryUsage(ctx, b.department(department))
+	if err != nil {
+		return Usage{}, fmt.Errorf("queryusage: department[%s]: %w", department, err)
+	}
+
+	return usg, nil
+}
+
+// SetUserQuota replaces the quota of the user taken from the limits.
+func (b *Business) SetUserQuota(ctx context.Context, usr userbus.User, quota int64) (Usage, error) {
+	ctx, span := otel.AddSpan(ctx, "business.quotabus.setuserquota")
+	defer span.End()
+
+	return b.setQuota(ctx, b.user(usr), quota)
+}
+
+// SetDepartmentQuota replaces the quota of the department taken from the
+// limits.
+func (b *Business) SetDepartmentQuota(ctx context.Context, department name.Name, quota int64) (Usage, error) {
+	ctx, span := otel.AddSpan(ctx, "business.quotabus.setdepartmentquota")
+	defer span.End()
+
+	return b.setQuota(ctx, b.department(department), quota)
+}
+
+// =============================================================================
+
+// owners returns the usage the user is charged against, carrying the quota
+// from the limits.
+func (b *Business) owners(usr userbus.User) []Usage {
+	usgs := []Usage{b.user(usr)}
+
+	if usr.Department.Valid() {
+		usgs = append(usgs, b.department(name.MustParse(usr.Department.String())))
+	}
+
+	return usgs
+}
+
+func (b *Business) user(usr userbus.User) Usage {
+	var quota int64
+	for _, r := range usr.Roles {
+		quota = max(quota, b.limits.Roles[r])
+	}
+
+	return Usage{
+		Scope: ScopeUser,
+		Owner: usr.ID.String(),
+		Quota: quota,
+	}
+}
+
+func (b *Business) department(department name.Name) Usage {
+	return Usage{
+		Scope: ScopeDepartment,
+		Owner: department.String(),
+		Quota: b.limits.Department,
+	}
+}
+
+func (b *Business) queryUsage(ctx context.Context, usg Usage) (Usage, error) {
+	stored, err := b.storer.QueryUsage(ctx, usg.Scope, usg.Owner)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return usg, nil
+		}
+		return Usage{}, err
+	}
+
+	if !stored.Custom {
+		stored.Quota = usg.Quota
+	}
+
+	return stored, nil
+}
+
+func (b *Business) setQuota(ctx context.Context, usg Usage, quota int64) (Usage, error) {
+	if quota < 0 {
+		return Usage{}, ErrInvalidQuota
+	}
+
+	usg.Quota = quota
+	usg.Custom = true
+	usg.DateUpdated = time.Now()
+
+	stored, err := b.storer.SetQuota(ctx, usg)
+	if err != nil {
+		return Usage{}, fmt.Errorf("setquota: %s[%s]: %w", usg.Scope, usg.Owner, err)
+	}
+
+	return stored, nil
+}
+
+// exceeded builds the error for a charge that would go over quota, reporting
+// the usage at the time of the charge.
+func (b *Business) exceeded(ctx context.Context, usg Usage, size int64) error {
+	stored, err := b.queryUsage(ctx, usg)
+	if err != nil {
+		b.log.Error(ctx, "quotabus: exceeded: queryusage", "scope", usg.Scope, "owner", usg.Owner, "ERROR", err)
+		stored = usg
+	}
+
+	return &ExceededError{
+		Usage: stored,
+		Size:  size,
+	}
+}
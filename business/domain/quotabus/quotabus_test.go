@@ -0,0 +1,246 @@
+package quotabus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Quota(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Quota")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, charge(db.BusDomain, sd), "charge")
+	unitest.Run(t, setQuota(db.BusDomain, sd), "setquota")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 3, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	// Two of the users share a department.
+	department := name.MustParseNull("Storage")
+	for i := range usrs[:2] {
+		usrs[i], err = busDomain.User.Update(ctx, usrs[i], userbus.UpdateUser{Department: &department})
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("updating user : %w", err)
+		}
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}, {User: usrs[2]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func charge(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	userQuota := dbtest.TestQuotaLimits.Roles[role.User]
+
+	table := []unitest.Table{
+		{
+			Name: "within",
+			ExpResp: []quotabus.Usage{
+				{Scope: quotabus.ScopeUser, Owner: sd.Users[0].ID.String(), Bytes: 1000, Quota: userQuota},
+				{Scope: quotabus.ScopeDepartment, Owner: "Storage", Bytes: 1000, Quota: dbtest.TestQuotaLimits.Department},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Quota.Charge(ctx, sd.Users[0].User, 1000); err != nil {
+					return err
+				}
+
+				usgs, err := busDomain.Quota.QueryByUser(ctx, sd.Users[0].User)
+				if err != nil {
+					return err
+				}
+
+				return usgs
+			},
+			CmpFunc: cmpUsages,
+		},
+		{
+			Name: "exceeded",
+			ExpResp: quotabus.Usage{
+				Scope: quotabus.ScopeUser,
+				Owner: sd.Users[0].ID.String(),
+				Bytes: 1000,
+				Quota: userQuota,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				err := busDomain.Quota.Charge(ctx, sd.Users[0].User, userQuota)
+
+				ee := quotabus.GetExceeded(err)
+				if ee == nil {
+					return fmt.Errorf("expected the quota to be exceeded, got %v", err)
+				}
+
+				return ee.Usage
+			},
+			CmpFunc: cmpUsage,
+		},
+		{
+			Name: "department",
+			ExpResp: quotabus.Usage{
+				Scope: quotabus.ScopeUser,
+				Owner: sd.Users[1].ID.String(),
+				Bytes: 0,
+				Quota: userQuota,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				department := name.MustParse("Storage")
+				if _, err := busDomain.Quota.SetDepartmentQuota(ctx, department, 1500); err != nil {
+					return err
+				}
+
+				// The user is within their own quota, but the department
+				// is not, so nothing is charged to the user either.
+				err := busDomain.Quota.Charge(ctx, sd.Users[1].User, 1000)
+				if ee := quotabus.GetExceeded(err); ee == nil || ee.Usage.Scope != quotabus.ScopeDepartment {
+					return fmt.Errorf("expected the department quota to be exceeded, got %v", err)
+				}
+
+				usgs, err := busDomain.Quota.QueryByUser(ctx, sd.Users[1].User)
+				if err != nil {
+					return err
+				}
+
+				return usgs[0]
+			},
+			CmpFunc: cmpUsage,
+		},
+		{
+			Name: "refund",
+			ExpResp: quotabus.Usage{
+				Scope: quotabus.ScopeUser,
+				Owner: sd.Users[0].ID.String(),
+				Bytes: 400,
+				Quota: userQuota,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Quota.Refund(ctx, sd.Users[0].User, 600); err != nil {
+					return err
+				}
+
+				usgs, err := busDomain.Quota.QueryByUser(ctx, sd.Users[0].User)
+				if err != nil {
+					return err
+				}
+
+				return usgs[0]
+			},
+			CmpFunc: cmpUsage,
+		},
+	}
+
+	return table
+}
+
+func setQuota(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	userQuota := dbtest.TestQuotaLimits.Roles[role.User]
+
+	table := []unitest.Table{
+		{
+			Name: "raise",
+			ExpResp: quotabus.Usage{
+				Scope:  quotabus.ScopeUser,
+				Owner:  sd.Users[2].ID.String(),
+				Bytes:  userQuota + 1,
+				Quota:  2 * userQuota,
+				Custom: true,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.Quota.SetUserQuota(ctx, sd.Users[2].User, 2*userQuota); err != nil {
+					return err
+				}
+
+				if err := busDomain.Quota.Charge(ctx, sd.Users[2].User, userQuota+1); err != nil {
+					return err
+				}
+
+				usgs, err := busDomain.Quota.QueryByUser(ctx, sd.Users[2].User)
+				if err != nil {
+					return err
+				}
+
+				return usgs[0]
+			},
+			CmpFunc: cmpUsage,
+		},
+		{
+			Name:    "negative",
+			ExpResp: quotabus.ErrInvalidQuota,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Quota.SetUserQuota(ctx, sd.Users[2].User, -1)
+				return err
+			},
+			CmpFunc: cmpErr,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func cmpUsage(got any, exp any) string {
+	gotResp, exists := got.(quotabus.Usage)
+	if !exists {
+		return fmt.Sprintf("error occurred: %v", got)
+	}
+
+	gotResp.DateUpdated = time.Time{}
+
+	return cmp.Diff(gotResp, exp)
+}
+
+func cmpUsages(got any, exp any) string {
+	gotResp, exists := got.([]quotabus.Usage)
+	if !exists {
+		return fmt.Sprintf("error occurred: %v", got)
+	}
+
+	for i := range gotResp {
+		gotResp[i].DateUpdated = time.Time{}
+	}
+
+	return cmp.Diff(gotResp, exp)
+}
+
+func cmpErr(got any, exp any) string {
+	gotErr, ok := got.(error)
+	if !ok {
+		return fmt.Sprintf("expected an error, got %v", got)
+	}
+
+	if !errors.Is(gotErr, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", gotErr, exp)
+	}
+
+	return ""
+}
@@ -0,0 +1,50 @@
+package quotadb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/quotabus"
+)
+
+type usage struct {
+	Scope       string        `db:"scope"`
+	Owner       string        `db:"owner"`
+	Bytes       int64         `db:"bytes"`
+	Quota       sql.NullInt64 `db:"quota"`
+	DateUpdated time.Time     `db:"date_updated"`
+}
+
+func toDBUsage(bus quotabus.Usage) usage {
+	return usage{
+		Scope: bus.Scope,
+		Owner: bus.Owner,
+		Bytes: bus.Bytes,
+		Quota: sql.NullInt64{
+			Int64: bus.Quota,
+			Valid: bus.Custom,
+		},
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusUsage(db usage) quotabus.Usage {
+	return quotabus.Usage{
+		Scope:       db.Scope,
+		Owner:       db.Owner,
+		Bytes:       db.Bytes,
+		Quota:       db.Quota.Int64,
+		Custom:      db.Quota.Valid,
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
+
+// charge carries the bytes to add and the quota to hold them to when no
+// quota was set for the owner.
+type charge struct {
+	Scope       string    `db:"scope"`
+	Owner       string    `db:"owner"`
+	Bytes       int64     `db:"bytes"`
+	Limit       int64     `db:"limit"`
+	DateUpdated time.Time `db:"date_updated"`
+}
@@ -0,0 +1,168 @@
+// Package quotadb contains storage usage and quota related CRUD functionality.
+package quotadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for storage usage database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (quotabus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Charge adds the bytes to the usage of the owner, as long as that keeps the
+// owner within quota. The quota set for the owner wins over the quota on the
+// usage passed in. ErrExceeded is returned when the bytes do not fit.
+func (s *Store) Charge(ctx context.Context, usg quotabus.Usage, size int64) (quotabus.Usage, error) {
+	data := charge{
+		Scope:       usg.Scope,
+		Owner:       usg.Owner,
+		Bytes:       size,
+		Limit:       usg.Quota,
+		DateUpdated: time.Now().UTC(),
+	}
+
+	const qi = `
+	INSERT INTO storage_usage
+		(scope, owner, bytes, date_updated)
+	VALUES
+		(:scope, :owner, 0, :date_updated)
+	ON CONFLICT (scope, owner) DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, qi, data); err != nil {
+		return quotabus.Usage{}, fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	// The row lock taken by the update makes concurrent charges for the same
+	// owner wait for each other, so two uploads cannot both squeeze into the
+	// last bytes of a quota.
+	const q = `
+	UPDATE
+		storage_usage
+	SET
+		bytes = bytes + :bytes,
+		date_updated = :date_updated
+	WHERE
+		scope = :scope AND owner = :owner AND
+		(COALESCE(quota, :limit) = 0 OR bytes + :bytes <= COALESCE(quota, :limit))
+	RETURNING
+		scope, owner, bytes, quota, date_updated`
+
+	var dbUsg usage
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsg); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return quotabus.Usage{}, fmt.Errorf("db: %w", quotabus.ErrExceeded)
+		}
+		return quotabus.Usage{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusUsage(dbUsg), nil
+}
+
+// Refund takes the bytes off the usage of the owner.
+func (s *Store) Refund(ctx context.Context, usg quotabus.Usage, size int64) error {
+	data := charge{
+		Scope:       usg.Scope,
+		Owner:       usg.Owner,
+		Bytes:       size,
+		DateUpdated: time.Now().UTC(),
+	}
+
+	const q = `
+	UPDATE
+		storage_usage
+	SET
+		bytes = GREATEST(bytes - :bytes, 0),
+		date_updated = :date_updated
+	WHERE
+		scope = :scope AND owner = :owner`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// SetQuota records the quota of the owner.
+func (s *Store) SetQuota(ctx context.Context, usg quotabus.Usage) (quotabus.Usage, error) {
+	const q = `
+	INSERT INTO storage_usage
+		(scope, owner, bytes, quota, date_updated)
+	VALUES
+		(:scope, :owner, 0, :quota, :date_updated)
+	ON CONFLICT (scope, owner) DO UPDATE SET
+		quota = EXCLUDED.quota,
+		date_updated = EXCLUDED.date_updated
+	RETURNING
+		scope, owner, bytes, quota, date_updated`
+
+	var dbUsg usage
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBUsage(usg), &dbUsg); err != nil {
+		return quotabus.Usage{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusUsage(dbUsg), nil
+}
+
+// QueryUsage gets the usage of the owner from the database.
+func (s *Store) QueryUsage(ctx context.Context, scope string, owner string) (quotabus.Usage, error) {
+	data := struct {
+		Scope string `db:"scope"`
+		Owner string `db:"owner"`
+	}{
+		Scope: scope,
+		Owner: owner,
+	}
+
+	const q = `
+	SELECT
+		scope, owner, bytes, quota, date_updated
+	FROM
+		storage_usage
+	WHERE
+		scope = :scope AND owner = :owner`
+
+	var dbUsg usage
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsg); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return quotabus.Usage{}, fmt.Errorf("db: %w", quotabus.ErrNotFound)
+		}
+		return quotabus.Usage{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusUsage(dbUsg), nil
+}
@@ -0,0 +1,75 @@
+package reportingbus
+
+import "fmt"
+
+// The set of intervals throughput can be bucketed by.
+var (
+	IntervalDay  = newInterval("day")
+	IntervalWeek = newInterval("week")
+)
+
+// Set of known intervals.
+var intervals = make(map[string]Interval)
+
+// Interval represents the size of the periods in a throughput report.
+type Interval struct {
+	value string
+}
+
+func newInterval(interval string) Interval {
+	i := Interval{interval}
+	intervals[interval] = i
+	return i
+}
+
+// ParseInterval parses the string value and returns an interval if one exists.
+func ParseInterval(value string) (Interval, error) {
+	i, exists := intervals[value]
+	if !exists {
+		return Interval{}, fmt.Errorf("invalid interval %q", value)
+	}
+
+	return i, nil
+}
+
+// String returns the name of the interval.
+func (i Interval) String() string {
+	return i.value
+}
+
+// =============================================================================
+
+// The set of dimensions the overdue backlog can be grouped by.
+var (
+	GroupByList     = newGroupBy("list")
+	GroupByAssignee = newGroupBy("assignee")
+)
+
+// Set of known groupings.
+var groupBys = make(map[string]GroupBy)
+
+// GroupBy represents the dimension the overdue backlog is grouped by.
+type GroupBy struct {
+	value string
+}
+
+func newGroupBy(groupBy string) GroupBy {
+	g := GroupBy{groupBy}
+	groupBys[groupBy] = g
+	return g
+}
+
+// ParseGroupBy parses the string value and returns a grouping if one exists.
+func ParseGroupBy(value string) (GroupBy, error) {
+	g, exists := groupBys[value]
+	if !exists {
+		return GroupBy{}, fmt.Errorf("invalid group by %q", value)
+	}
+
+	return g, nil
+}
+
+// String returns the name of the grouping.
+func (g GroupBy) String() string {
+	return g.value
+}
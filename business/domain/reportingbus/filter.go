@@ -0,0 +1,27 @@
+package reportingbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a report can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+//
+// The date range is inclusive of the start and exclusive of the end. Each
+// report documents which date the range is applied to. UserID limits the
+// report to todo items the user owns or is assigned.
+type QueryFilter struct {
+	UserID    *uuid.UUID
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+func (qf QueryFilter) validate() error {
+	if qf.StartDate != nil && qf.EndDate != nil && !qf.StartDate.Before(*qf.EndDate) {
+		return ErrInvalidRange
+	}
+
+	return nil
+}
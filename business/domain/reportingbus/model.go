@@ -0,0 +1,23 @@
+package reportingbus
+
+import "time"
+
+// Throughput represents the todo items created and completed in a period.
+type Throughput struct {
+	Period    time.Time
+	Created   int
+	Completed int
+}
+
+// CompletionTime represents how long todo items took to complete.
+type CompletionTime struct {
+	Completed int
+	Median    time.Duration
+}
+
+// Bucket represents a count of todo items sharing a key, such as a list
+// name, an assignee or a label. An empty key collects the items without one.
+type Bucket struct {
+	Key   string
+	Count int
+}
@@ -0,0 +1,114 @@
+// Package reportingbus provides business access to productivity and backlog
+// analytics over todo items. Every call is a read and is expected to be
+// served by a reporting replica.
+package reportingbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for reporting operations.
+var (
+	ErrInvalidRange = errors.New("start date must be before end date")
+)
+
+// Storer interface declares the behavior this package needs to read
+// aggregated data.
+type Storer interface {
+	Throughput(ctx context.Context, filter QueryFilter, interval Interval) ([]Throughput, error)
+	CompletionTime(ctx context.Context, filter QueryFilter) (CompletionTime, error)
+	OverdueBacklog(ctx context.Context, filter QueryFilter, groupBy GroupBy, now time.Time) ([]Bucket, error)
+	LabelDistribution(ctx context.Context, filter QueryFilter) ([]Bucket, error)
+}
+
+// Business manages the set of APIs for reporting access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a reporting business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Throughput returns the number of todo items created and completed in each
+// period of the date range.
+func (b *Business) Throughput(ctx context.Context, filter QueryFilter, interval Interval) ([]Throughput, error) {
+	ctx, span := otel.AddSpan(ctx, "business.reportingbus.throughput")
+	defer span.End()
+
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	tps, err := b.storer.Throughput(ctx, filter, interval)
+	if err != nil {
+		return nil, fmt.Errorf("throughput: %w", err)
+	}
+
+	return tps, nil
+}
+
+// CompletionTime returns the median time between creating and completing
+// the todo items completed in the date range.
+func (b *Business) CompletionTime(ctx context.Context, filter QueryFilter) (CompletionTime, error) {
+	ctx, span := otel.AddSpan(ctx, "business.reportingbus.completiontime")
+	defer span.End()
+
+	if err := filter.validate(); err != nil {
+		return CompletionTime{}, err
+	}
+
+	ct, err := b.storer.CompletionTime(ctx, filter)
+	if err != nil {
+		return CompletionTime{}, fmt.Errorf("completiontime: %w", err)
+	}
+
+	return ct, nil
+}
+
+// OverdueBacklog returns the number of open todo items that are past due,
+// grouped by list or assignee. The date range applies to the due date.
+func (b *Business) OverdueBacklog(ctx context.Context, filter QueryFilter, groupBy GroupBy) ([]Bucket, error) {
+	ctx, span := otel.AddSpan(ctx, "business.reportingbus.overduebacklog")
+	defer span.End()
+
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	bkts, err := b.storer.OverdueBacklog(ctx, filter, groupBy, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("overduebacklog: %w", err)
+	}
+
+	return bkts, nil
+}
+
+// LabelDistribution returns the number of todo items created in the date
+// range carrying each label.
+func (b *Business) LabelDistribution(ctx context.Context, filter QueryFilter) ([]Bucket, error) {
+	ctx, span := otel.AddSpan(ctx, "business.reportingbus.labeldistribution")
+	defer span.End()
+
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	bkts, err := b.storer.LabelDistribution(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("labeldistribution: %w", err)
+	}
+
+	return bkts, nil
+}
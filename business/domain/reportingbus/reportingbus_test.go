@@ -0,0 +1,278 @@
+package reportingbus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/status"
+)
+
+func Test_Reporting(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Reporting")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, throughput(db.BusDomain, sd), "throughput")
+	unitest.Run(t, completionTime(db.BusDomain, sd), "completiontime")
+	unitest.Run(t, overdue(db.BusDomain, sd), "overdue")
+	unitest.Run(t, labels(db.BusDomain, sd), "labels")
+	unitest.Run(t, orgs(db.BusDomain, sd), "orgs")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	newItems := []todobus.NewTodoItem{
+		{
+			UserID:      usrs[0].ID,
+			Description: "Overdue at work",
+			DueDate:     time.Now().Add(-48 * time.Hour),
+			List:        name.MustParseNull("Work"),
+			Labels:      []label.Label{label.MustParse("bug"), label.MustParse("urgent")},
+		},
+		{
+			UserID:      usrs[0].ID,
+			Description: "Overdue at home",
+			DueDate:     time.Now().Add(-48 * time.Hour),
+			List:        name.MustParseNull("Home"),
+			Labels:      []label.Label{label.MustParse("bug")},
+		},
+		{
+			UserID:      usrs[0].ID,
+			Description: "Done already",
+			DueDate:     time.Now().Add(-48 * time.Hour),
+			List:        name.MustParseNull("Work"),
+		},
+	}
+
+	items := make([]todobus.TodoItem, len(newItems))
+	for i, nt := range newItems {
+		items[i], err = busDomain.Todo.Create(ctx, nt)
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding todo items : %w", err)
+		}
+	}
+
+	done := status.Done
+	items[2], err = busDomain.Todo.Update(ctx, items[2], todobus.UpdateTodoItem{Status: &done})
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("completing todo item : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}},
+		Todos: items,
+	}
+
+	return sd, nil
+}
+
+func lastDay() reportingbus.QueryFilter {
+	start := time.Now().Add(-24 * time.Hour)
+	end := time.Now().Add(time.Hour)
+
+	return reportingbus.QueryFilter{
+		StartDate: &start,
+		EndDate:   &end,
+	}
+}
+
+// =============================================================================
+
+func throughput(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "day",
+			ExpResp: reportingbus.Throughput{Created: len(sd.Todos), Completed: 1},
+			ExcFunc: func(ctx context.Context) any {
+				tps, err := busDomain.Reporting.Throughput(ctx, lastDay(), reportingbus.IntervalDay)
+				if err != nil {
+					return err
+				}
+
+				var total reportingbus.Throughput
+				for _, tp := range tps {
+					total.Created += tp.Created
+					total.Completed += tp.Completed
+				}
+
+				return total
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "invalid-range",
+			ExpResp: reportingbus.ErrInvalidRange,
+			ExcFunc: func(ctx context.Context) any {
+				filter := lastDay()
+				filter.StartDate, filter.EndDate = filter.EndDate, filter.StartDate
+
+				_, err := busDomain.Reporting.Throughput(ctx, filter, reportingbus.IntervalWeek)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != exp {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func completionTime(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				ct, err := busDomain.Reporting.CompletionTime(ctx, lastDay())
+				if err != nil {
+					return err
+				}
+
+				if ct.Median < 0 || ct.Median > time.Minute {
+					return fmt.Errorf("unexpected median %v", ct.Median)
+				}
+
+				return ct.Completed
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func overdue(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "bylist",
+			ExpResp: []reportingbus.Bucket{
+				{Key: "Home", Count: 1},
+				{Key: "Work", Count: 1},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				bkts, err := busDomain.Reporting.OverdueBacklog(ctx, reportingbus.QueryFilter{UserID: &sd.Users[0].ID}, reportingbus.GroupByList)
+				if err != nil {
+					return err
+				}
+
+				return bkts
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func labels(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: []reportingbus.Bucket{
+				{Key: "bug", Count: 2},
+				{Key: "urgent", Count: 1},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				bkts, err := busDomain.Reporting.LabelDistribution(ctx, lastDay())
+				if err != nil {
+					return err
+				}
+
+				return bkts
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func orgs(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "scoped",
+			ExpResp: []int{len(sd.Todos), 1},
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{
+					Name:    name.MustParse("Other Org"),
+					OwnerID: sd.Users[0].ID,
+				})
+				if err != nil {
+					return err
+				}
+
+				otherCtx := sqldb.WithOrgID(ctx, org.ID)
+
+				nt := todobus.NewTodoItem{
+					UserID:      sd.Users[0].ID,
+					Description: "In the other org",
+				}
+
+				if _, err := busDomain.Todo.Create(otherCtx, nt); err != nil {
+					return err
+				}
+
+				var created []int
+				for _, orgCtx := range []context.Context{sqldb.WithOrgID(ctx, orgbus.DefaultID), otherCtx} {
+					tps, err := busDomain.Reporting.Throughput(orgCtx, lastDay(), reportingbus.IntervalDay)
+					if err != nil {
+						return err
+					}
+
+					var total int
+					for _, tp := range tps {
+						total += tp.Created
+					}
+					created = append(created, total)
+				}
+
+				return created
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
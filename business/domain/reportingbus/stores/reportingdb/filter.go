@@ -0,0 +1,49 @@
+package reportingdb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+)
+
+// orgScope limits a report to the todo items of the organization in :org_id.
+// Every item passes when it is null, which is how work that spans
+// organizations reads. The reporting replica may not enforce the row-level
+// security policies, so every report carries it.
+const orgScope = "(CAST(:org_id AS UUID) IS NULL OR org_id = CAST(:org_id AS UUID))"
+
+var groupByColumns = map[reportingbus.GroupBy]string{
+	reportingbus.GroupByList:     "COALESCE(list, '')",
+	reportingbus.GroupByAssignee: "COALESCE(CAST(assignee_id AS TEXT), '')",
+}
+
+// applyFilter writes the WHERE clause for the filter, applying the date range
+// to the specified column, along with any extra conditions the report needs.
+// Reports only cover the organization the context scopes access to.
+func applyFilter(ctx context.Context, filter reportingbus.QueryFilter, data map[string]any, buf *bytes.Buffer, dateColumn string, extra ...string) {
+	data["org_id"] = sqldb.OrgArg(ctx)
+	wc := append([]string{orgScope}, extra...)
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "(user_id = :user_id OR assignee_id = :user_id)")
+	}
+
+	if filter.StartDate != nil {
+		data["start_date"] = filter.StartDate.UTC()
+		wc = append(wc, dateColumn+" >= :start_date")
+	}
+
+	if filter.EndDate != nil {
+		data["end_date"] = filter.EndDate.UTC()
+		wc = append(wc, dateColumn+" < :end_date")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,55 @@
+package reportingdb
+
+import (
+	"time"
+
+	"github.com/himynamej/todo/business/domain/reportingbus"
+)
+
+type throughput struct {
+	Period    time.Time `db:"period"`
+	Created   int       `db:"created"`
+	Completed int       `db:"completed"`
+}
+
+func toBusThroughputs(dbs []throughput) []reportingbus.Throughput {
+	bus := make([]reportingbus.Throughput, len(dbs))
+	for i, db := range dbs {
+		bus[i] = reportingbus.Throughput{
+			Period:    db.Period.UTC(),
+			Created:   db.Created,
+			Completed: db.Completed,
+		}
+	}
+
+	return bus
+}
+
+type completionTime struct {
+	Completed     int     `db:"completed"`
+	MedianSeconds float64 `db:"median_seconds"`
+}
+
+func toBusCompletionTime(db completionTime) reportingbus.CompletionTime {
+	return reportingbus.CompletionTime{
+		Completed: db.Completed,
+		Median:    time.Duration(db.MedianSeconds * float64(time.Second)).Round(time.Second),
+	}
+}
+
+type bucket struct {
+	Key   string `db:"key"`
+	Count int    `db:"count"`
+}
+
+func toBusBuckets(dbs []bucket) []reportingbus.Bucket {
+	bus := make([]reportingbus.Bucket, len(dbs))
+	for i, db := range dbs {
+		bus[i] = reportingbus.Bucket{
+			Key:   db.Key,
+			Count: db.Count,
+		}
+	}
+
+	return bus
+}
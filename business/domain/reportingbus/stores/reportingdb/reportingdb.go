@@ -0,0 +1,157 @@
+// Package reportingdb contains the aggregate queries behind the reporting
+// domain. The queries only read and are written to run against a replica.
+package reportingdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for reporting database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access. The db should point at the
+// reporting replica when one is available.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Throughput counts the todo items created and completed per period. The
+// date range applies to the creation date for created items and to the
+// completion date for completed items.
+func (s *Store) Throughput(ctx context.Context, filter reportingbus.QueryFilter, interval reportingbus.Interval) ([]reportingbus.Throughput, error) {
+	data := map[string]any{
+		"interval": interval.String(),
+	}
+
+	buf := bytes.NewBufferString(`
+	WITH created AS (
+		SELECT
+			date_trunc(:interval, date_created) AS period, count(1) AS total
+		FROM
+			todo_items`)
+	applyFilter(ctx, filter, data, buf, "date_created")
+	buf.WriteString(`
+		GROUP BY 1
+	), completed AS (
+		SELECT
+			date_trunc(:interval, date_completed) AS period, count(1) AS total
+		FROM
+			todo_items`)
+	applyFilter(ctx, filter, data, buf, "date_completed", "date_completed IS NOT NULL")
+	buf.WriteString(`
+		GROUP BY 1
+	)
+	SELECT
+		COALESCE(c.period, d.period) AS period,
+		COALESCE(c.total, 0) AS created,
+		COALESCE(d.total, 0) AS completed
+	FROM
+		created c
+	FULL OUTER JOIN
+		completed d ON d.period = c.period
+	ORDER BY
+		period`)
+
+	var dbTPs []throughput
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbTPs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusThroughputs(dbTPs), nil
+}
+
+// CompletionTime computes the median time to complete for the todo items
+// completed in the date range.
+func (s *Store) CompletionTime(ctx context.Context, filter reportingbus.QueryFilter) (reportingbus.CompletionTime, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(`
+	SELECT
+		count(1) AS completed,
+		COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (date_completed - date_created))), 0) AS median_seconds
+	FROM
+		todo_items`)
+	applyFilter(ctx, filter, data, buf, "date_completed", "date_completed IS NOT NULL")
+
+	var dbCT completionTime
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbCT); err != nil {
+		return reportingbus.CompletionTime{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusCompletionTime(dbCT), nil
+}
+
+// OverdueBacklog counts the open todo items that are past due as of now.
+// The date range applies to the due date.
+func (s *Store) OverdueBacklog(ctx context.Context, filter reportingbus.QueryFilter, groupBy reportingbus.GroupBy, now time.Time) ([]reportingbus.Bucket, error) {
+	key, exists := groupByColumns[groupBy]
+	if !exists {
+		return nil, fmt.Errorf("group by %q does not exist", groupBy)
+	}
+
+	// All-day items are only overdue once their whole day has passed in UTC,
+	// matching the midnight UTC dates they are stored with.
+	data := map[string]any{
+		"now":   now.UTC(),
+		"today": time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	buf := bytes.NewBufferString(`
+	SELECT
+		` + key + ` AS key, count(1) AS count
+	FROM
+		todo_items`)
+	applyFilter(ctx, filter, data, buf, "due_date",
+		"status <> 'DONE'",
+		"((all_day = FALSE AND due_date < :now) OR (all_day = TRUE AND due_date < :today))",
+	)
+	buf.WriteString(`
+	GROUP BY 1
+	ORDER BY 2 DESC, 1`)
+
+	var dbBkts []bucket
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbBkts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusBuckets(dbBkts), nil
+}
+
+// LabelDistribution counts the todo items created in the date range per
+// label. Items carrying several labels are counted once for each.
+func (s *Store) LabelDistribution(ctx context.Context, filter reportingbus.QueryFilter) ([]reportingbus.Bucket, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(`
+	SELECT
+		l.label AS key, count(1) AS count
+	FROM
+		todo_items
+	CROSS JOIN LATERAL
+		unnest(labels) AS l(label)`)
+	applyFilter(ctx, filter, data, buf, "date_created")
+	buf.WriteString(`
+	GROUP BY 1
+	ORDER BY 2 DESC, 1`)
+
+	var dbBkts []bucket
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbBkts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusBuckets(dbBkts), nil
+}
@@ -0,0 +1,36 @@
+package sessionbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, b.actionUserUpdated)
+	}
+}
+
+// actionUserUpdated is executed by the user domain indirectly when a user is
+// updated. When a user is disabled, every session they have is ended so the
+// tokens already handed out stop working.
+func (b *Business) actionUserUpdated(ctx context.Context, data delegate.Data) error {
+	var params userbus.ActionUpdatedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	b.log.Info(ctx, "action-userupdated", "user_id", params.UserID, "enabled", params.Enabled)
+
+	if params.Enabled == nil || *params.Enabled {
+		return nil
+	}
+
+	return b.RevokeUser(ctx, params.UserID)
+}
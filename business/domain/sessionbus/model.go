@@ -0,0 +1,56 @@
+package sessionbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken represents one refresh token of a session. Each time it is
+// used it is replaced by a new one in the same family, so a family is the
+// chain of refresh tokens handed to one sign in. Only the hash of the token
+// is kept.
+type RefreshToken struct {
+	TokenHash     []byte
+	FamilyID      uuid.UUID
+	UserID        uuid.UUID
+	OrgID         uuid.UUID
+	AccessID      string    // jti of the access token issued with it
+	AccessExpires time.Time // When that access token stops working
+	DateExpires   time.Time
+	DateCreated   time.Time
+	DateUsed      time.Time // Zero until it is exchanged for a new one
+	DateRevoked   time.Time // Zero until its family is revoked
+}
+
+// Used reports whether the token was already exchanged for a new one.
+func (rt RefreshToken) Used() bool {
+	return !rt.DateUsed.IsZero()
+}
+
+// Revoked reports whether the family of the token was revoked.
+func (rt RefreshToken) Revoked() bool {
+	return !rt.DateRevoked.IsZero()
+}
+
+// Expired reports whether the token can no longer be used at the given time.
+func (rt RefreshToken) Expired(now time.Time) bool {
+	return !now.Before(rt.DateExpires)
+}
+
+// NewRefreshToken is what we require to issue a refresh token along with an
+// access token.
+type NewRefreshToken struct {
+	FamilyID      uuid.UUID // Starts a new session when zero
+	UserID        uuid.UUID
+	OrgID         uuid.UUID
+	AccessID      string
+	AccessExpires time.Time
+}
+
+// Revocation represents an access token that must no longer be accepted,
+// kept until the token would have expired anyway.
+type Revocation struct {
+	AccessID    string
+	DateExpires time.Time
+}
@@ -0,0 +1,272 @@
+// Package sessionbus provides business access to the sessions of signed in
+// users: the rotating refresh tokens handed out with access tokens, and the
+// access tokens revoked before they expire.
+package sessionbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/opaque"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for sessions.
+var (
+	ErrNotFound     = errors.New("refresh token not found")
+	ErrInvalidToken = errors.New("invalid refresh token")
+	ErrTokenExpired = errors.New("refresh token expired")
+	ErrTokenReused  = errors.New("refresh token already used, the session was revoked")
+)
+
+// DefaultTTL is how long a refresh token works when the config does not say.
+const DefaultTTL = 30 * 24 * time.Hour
+
+// Config represents how long sessions last.
+type Config struct {
+	TTL time.Duration // How long a refresh token works if it is not used
+}
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, rt RefreshToken) error
+	MarkUsed(ctx context.Context, tokenHash []byte, now time.Time) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, now time.Time) ([]RefreshToken, error)
+	RevokeUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]RefreshToken, error)
+	QueryByHash(ctx context.Context, tokenHash []byte) (RefreshToken, error)
+	CreateRevocation(ctx context.Context, rvk Revocation) error
+	IsRevoked(ctx context.Context, accessID string) (bool, error)
+}
+
+// Business manages the set of APIs for session access.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+	cfg      Config
+}
+
+// NewBusiness constructs a session business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, cfg Config) *Business {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		cfg:      cfg,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		delegate: b.delegate,
+		storer:   storer,
+		cfg:      b.cfg,
+	}
+
+	return &bus, nil
+}
+
+// Issue creates a refresh token to hand out with the access token. The
+// token itself is only returned here, just its hash is kept.
+func (b *Business) Issue(ctx context.Context, nrt NewRefreshToken) (string, RefreshToken, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.issue")
+	defer span.End()
+
+	tkn, hash, err := opaque.New()
+	if err != nil {
+		return "", RefreshToken{}, fmt.Errorf("newtoken: %w", err)
+	}
+
+	familyID := nrt.FamilyID
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+
+	now := time.Now()
+
+	rt := RefreshToken{
+		TokenHash:     hash,
+		FamilyID:      familyID,
+		UserID:        nrt.UserID,
+		OrgID:         nrt.OrgID,
+		AccessID:      nrt.AccessID,
+		AccessExpires: nrt.AccessExpires,
+		DateExpires:   now.Add(b.cfg.TTL),
+		DateCreated:   now,
+	}
+
+	if err := b.storer.Create(ctx, rt); err != nil {
+		return "", RefreshToken{}, fmt.Errorf("create: userID[%s]: %w", nrt.UserID, err)
+	}
+
+	return tkn, rt, nil
+}
+
+// Redeem uses up the refresh token so a new one can be issued in its place.
+// A token that was already used means it was copied, so the whole family is
+// revoked and whoever holds any of its tokens has to sign in again.
+func (b *Business) Redeem(ctx context.Context, token string) (RefreshToken, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.redeem")
+	defer span.End()
+
+	hash := opaque.Hash(token)
+
+	rt, err := b.storer.QueryByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return RefreshToken{}, ErrInvalidToken
+		}
+		return RefreshToken{}, fmt.Errorf("querybyhash: %w", err)
+	}
+
+	if rt.Revoked() {
+		return RefreshToken{}, ErrInvalidToken
+	}
+
+	now := time.Now()
+
+	if rt.Used() {
+		return RefreshToken{}, b.reused(ctx, rt, now)
+	}
+
+	if rt.Expired(now) {
+		return RefreshToken{}, ErrTokenExpired
+	}
+
+	if err := b.storer.MarkUsed(ctx, hash, now); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return RefreshToken{}, b.reused(ctx, rt, now)
+		}
+		return RefreshToken{}, fmt.Errorf("markused: familyID[%s]: %w", rt.FamilyID, err)
+	}
+
+	rt.DateUsed = now
+
+	return rt, nil
+}
+
+// Logout revokes the access token and, when one is given, the session of
+// the refresh token that came with it.
+func (b *Business) Logout(ctx context.Context, userID uuid.UUID, access Revocation, token string) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.logout")
+	defer span.End()
+
+	if err := b.storer.CreateRevocation(ctx, access); err != nil {
+		return fmt.Errorf("createrevocation: accessID[%s]: %w", access.AccessID, err)
+	}
+
+	if token == "" {
+		return nil
+	}
+
+	rt, err := b.storer.QueryByHash(ctx, opaque.Hash(token))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return fmt.Errorf("querybyhash: %w", err)
+	}
+
+	if rt.UserID != userID {
+		return ErrInvalidToken
+	}
+
+	rts, err := b.storer.RevokeFamily(ctx, rt.FamilyID, time.Now())
+	if err != nil {
+		return fmt.Errorf("revokefamily: familyID[%s]: %w", rt.FamilyID, err)
+	}
+
+	return b.revokeAccess(ctx, rts)
+}
+
+// RevokeUser ends every session of the user, along with the access tokens
+// issued to them.
+func (b *Business) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.revokeuser")
+	defer span.End()
+
+	rts, err := b.storer.RevokeUser(ctx, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("revokeuser: userID[%s]: %w", userID, err)
+	}
+
+	return b.revokeAccess(ctx, rts)
+}
+
+// IsRevoked reports whether the access token with the jti was revoked.
+func (b *Business) IsRevoked(ctx context.Context, accessID string) (bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.isrevoked")
+	defer span.End()
+
+	revoked, err := b.storer.IsRevoked(ctx, accessID)
+	if err != nil {
+		return false, fmt.Errorf("isrevoked: accessID[%s]: %w", accessID, err)
+	}
+
+	return revoked, nil
+}
+
+// =============================================================================
+
+// reused revokes the family of a refresh token that was used twice.
+func (b *Business) reused(ctx context.Context, rt RefreshToken, now time.Time) error {
+	b.log.Info(ctx, "sessionbus: refresh token reused", "familyID", rt.FamilyID, "userID", rt.UserID)
+
+	rts, err := b.storer.RevokeFamily(ctx, rt.FamilyID, now)
+	if err != nil {
+		return fmt.Errorf("revokefamily: familyID[%s]: %w", rt.FamilyID, err)
+	}
+
+	if err := b.revokeAccess(ctx, rts); err != nil {
+		return err
+	}
+
+	return ErrTokenReused
+}
+
+// revokeAccess revokes the access tokens issued with the refresh tokens
+// that have not expired yet.
+func (b *Business) revokeAccess(ctx context.Context, rts []RefreshToken) error {
+	now := time.Now()
+
+	for _, rt := range rts {
+		if rt.AccessID == "" || !now.Before(rt.AccessExpires) {
+			continue
+		}
+
+		rvk := Revocation{
+			AccessID:    rt.AccessID,
+			DateExpires: rt.AccessExpires,
+		}
+
+		if err := b.storer.CreateRevocation(ctx, rvk); err != nil {
+			return fmt.Errorf("createrevocation: accessID[%s]: %w", rt.AccessID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package sessionbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Session(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Session")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, rotate(db.BusDomain, sd), "rotate")
+	unitest.Run(t, revoke(db.BusDomain, sd), "revoke")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// newRefreshToken describes the refresh token handed out with a new access
+// token for the user.
+func newRefreshToken(usr userbus.User, familyID uuid.UUID) sessionbus.NewRefreshToken {
+	return sessionbus.NewRefreshToken{
+		FamilyID:      familyID,
+		UserID:        usr.ID,
+		OrgID:         orgbus.DefaultID,
+		AccessID:      uuid.NewString(),
+		AccessExpires: time.Now().Add(15 * time.Minute),
+	}
+}
+
+// =============================================================================
+
+func rotate(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "family",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				first, rt, err := busDomain.Session.Issue(ctx, newRefreshToken(sd.Users[0].User, uuid.Nil))
+				if err != nil {
+					return err
+				}
+
+				used, err := busDomain.Session.Redeem(ctx, first)
+				if err != nil {
+					return err
+				}
+
+				_, next, err := busDomain.Session.Issue(ctx, newRefreshToken(sd.Users[0].User, used.FamilyID))
+				if err != nil {
+					return err
+				}
+
+				return next.FamilyID == rt.FamilyID && used.Used()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reused",
+			ExpResp: []any{sessionbus.ErrTokenReused, sessionbus.ErrInvalidToken, true},
+			ExcFunc: func(ctx context.Context) any {
+				first, _, err := busDomain.Session.Issue(ctx, newRefreshToken(sd.Users[0].User, uuid.Nil))
+				if err != nil {
+					return err
+				}
+
+				used, err := busDomain.Session.Redeem(ctx, first)
+				if err != nil {
+					return err
+				}
+
+				nrt := newRefreshToken(sd.Users[0].User, used.FamilyID)
+
+				next, _, err := busDomain.Session.Issue(ctx, nrt)
+				if err != nil {
+					return err
+				}
+
+				_, reusedErr := busDomain.Session.Redeem(ctx, first)
+				_, nextErr := busDomain.Session.Redeem(ctx, next)
+
+				revoked, err := busDomain.Session.IsRevoked(ctx, nrt.AccessID)
+				if err != nil {
+					return err
+				}
+
+				return []any{reusedErr, nextErr, revoked}
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]any)
+				if !exists {
+					return fmt.Sprintf("got %v", got)
+				}
+
+				expResp := exp.([]any)
+
+				for i := range 2 {
+					if err, _ := gotResp[i].(error); !errors.Is(err, expResp[i].(error)) {
+						return fmt.Sprintf("idx %d: got %v, exp %v", i, gotResp[i], expResp[i])
+					}
+				}
+
+				return cmp.Diff(gotResp[2], expResp[2])
+			},
+		},
+		{
+			Name:    "invalid",
+			ExpResp: sessionbus.ErrInvalidToken,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Session.Redeem(ctx, "not-a-token")
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "logout",
+			ExpResp: []bool{true, true},
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[1].User
+				nrt := newRefreshToken(usr, uuid.Nil)
+
+				tkn, _, err := busDomain.Session.Issue(ctx, nrt)
+				if err != nil {
+					return err
+				}
+
+				access := sessionbus.Revocation{
+					AccessID:    nrt.AccessID,
+					DateExpires: nrt.AccessExpires,
+				}
+
+				if err := busDomain.Session.Logout(ctx, usr.ID, access, tkn); err != nil {
+					return err
+				}
+
+				revoked, err := busDomain.Session.IsRevoked(ctx, nrt.AccessID)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Session.Redeem(ctx, tkn)
+
+				return []bool{revoked, errors.Is(err, sessionbus.ErrInvalidToken)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "user",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[1].User
+				nrt := newRefreshToken(usr, uuid.Nil)
+
+				if _, _, err := busDomain.Session.Issue(ctx, nrt); err != nil {
+					return err
+				}
+
+				if err := busDomain.Session.RevokeUser(ctx, usr.ID); err != nil {
+					return err
+				}
+
+				revoked, err := busDomain.Session.IsRevoked(ctx, nrt.AccessID)
+				if err != nil {
+					return err
+				}
+
+				return revoked
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "disabled",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[0].User
+				nrt := newRefreshToken(usr, uuid.Nil)
+
+				if _, _, err := busDomain.Session.Issue(ctx, nrt); err != nil {
+					return err
+				}
+
+				enabled := false
+				if _, err := busDomain.User.Update(ctx, usr, userbus.UpdateUser{Enabled: &enabled}); err != nil {
+					return err
+				}
+
+				revoked, err := busDomain.Session.IsRevoked(ctx, nrt.AccessID)
+				if err != nil {
+					return err
+				}
+
+				return revoked
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
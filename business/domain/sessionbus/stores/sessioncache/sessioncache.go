@@ -0,0 +1,95 @@
+// Package sessioncache contains session related CRUD functionality with
+// caching of the access token denylist.
+package sessioncache
+
+import (
+	"context"
+	"time"
+
+	"github.com/creativecreature/sturdyc"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+)
+
+// Store manages the set of APIs for session data and caching. Every request
+// checks the denylist, so whether an access token is revoked is cached for
+// the ttl. Revocations made through this store are seen at once, those made
+// by other instances within the ttl.
+type Store struct {
+	log    *logger.Logger
+	storer sessionbus.Storer
+	cache  *sturdyc.Client[bool]
+}
+
+// NewStore constructs the api for data and caching access.
+func NewStore(log *logger.Logger, storer sessionbus.Storer, ttl time.Duration) *Store {
+	const capacity = 10000
+	const numShards = 10
+	const evictionPercentage = 10
+
+	return &Store{
+		log:    log,
+		storer: storer,
+		cache:  sturdyc.New[bool](capacity, numShards, ttl, evictionPercentage),
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (sessionbus.Storer, error) {
+	return s.storer.NewWithTx(tx)
+}
+
+// Create inserts a new refresh token into the database.
+func (s *Store) Create(ctx context.Context, rt sessionbus.RefreshToken) error {
+	return s.storer.Create(ctx, rt)
+}
+
+// MarkUsed records that the refresh token was exchanged.
+func (s *Store) MarkUsed(ctx context.Context, tokenHash []byte, now time.Time) error {
+	return s.storer.MarkUsed(ctx, tokenHash, now)
+}
+
+// RevokeFamily revokes every refresh token of the family and returns them.
+func (s *Store) RevokeFamily(ctx context.Context, familyID uuid.UUID, now time.Time) ([]sessionbus.RefreshToken, error) {
+	return s.storer.RevokeFamily(ctx, familyID, now)
+}
+
+// RevokeUser revokes every refresh token of the user and returns them.
+func (s *Store) RevokeUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]sessionbus.RefreshToken, error) {
+	return s.storer.RevokeUser(ctx, userID, now)
+}
+
+// QueryByHash gets the refresh token with the hash from the database.
+func (s *Store) QueryByHash(ctx context.Context, tokenHash []byte) (sessionbus.RefreshToken, error) {
+	return s.storer.QueryByHash(ctx, tokenHash)
+}
+
+// CreateRevocation adds the access token to the denylist.
+func (s *Store) CreateRevocation(ctx context.Context, rvk sessionbus.Revocation) error {
+	if err := s.storer.CreateRevocation(ctx, rvk); err != nil {
+		return err
+	}
+
+	s.cache.Set(rvk.AccessID, true)
+
+	return nil
+}
+
+// IsRevoked reports whether the access token is on the denylist.
+func (s *Store) IsRevoked(ctx context.Context, accessID string) (bool, error) {
+	if revoked, exists := s.cache.Get(accessID); exists {
+		return revoked, nil
+	}
+
+	revoked, err := s.storer.IsRevoked(ctx, accessID)
+	if err != nil {
+		return false, err
+	}
+
+	s.cache.Set(accessID, revoked)
+
+	return revoked, nil
+}
@@ -0,0 +1,103 @@
+package sessiondb
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+)
+
+type refreshToken struct {
+	TokenHash     string       `db:"token_hash"`
+	FamilyID      uuid.UUID    `db:"family_id"`
+	UserID        uuid.UUID    `db:"user_id"`
+	OrgID         uuid.UUID    `db:"org_id"`
+	AccessID      string       `db:"access_id"`
+	AccessExpires time.Time    `db:"access_expires"`
+	DateExpires   time.Time    `db:"date_expires"`
+	DateCreated   time.Time    `db:"date_created"`
+	DateUsed      sql.NullTime `db:"date_used"`
+	DateRevoked   sql.NullTime `db:"date_revoked"`
+}
+
+func toDBRefreshToken(bus sessionbus.RefreshToken) refreshToken {
+	return refreshToken{
+		TokenHash:     hex.EncodeToString(bus.TokenHash),
+		FamilyID:      bus.FamilyID,
+		UserID:        bus.UserID,
+		OrgID:         bus.OrgID,
+		AccessID:      bus.AccessID,
+		AccessExpires: bus.AccessExpires.UTC(),
+		DateExpires:   bus.DateExpires.UTC(),
+		DateCreated:   bus.DateCreated.UTC(),
+		DateUsed:      toNullTime(bus.DateUsed),
+		DateRevoked:   toNullTime(bus.DateRevoked),
+	}
+}
+
+func toBusRefreshToken(db refreshToken) (sessionbus.RefreshToken, error) {
+	hash, err := hex.DecodeString(db.TokenHash)
+	if err != nil {
+		return sessionbus.RefreshToken{}, fmt.Errorf("decode token hash: %w", err)
+	}
+
+	bus := sessionbus.RefreshToken{
+		TokenHash:     hash,
+		FamilyID:      db.FamilyID,
+		UserID:        db.UserID,
+		OrgID:         db.OrgID,
+		AccessID:      db.AccessID,
+		AccessExpires: db.AccessExpires.In(time.Local),
+		DateExpires:   db.DateExpires.In(time.Local),
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUsed:      fromNullTime(db.DateUsed),
+		DateRevoked:   fromNullTime(db.DateRevoked),
+	}
+
+	return bus, nil
+}
+
+func toBusRefreshTokens(dbs []refreshToken) ([]sessionbus.RefreshToken, error) {
+	bus := make([]sessionbus.RefreshToken, len(dbs))
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusRefreshToken(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+func toNullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{
+		Time:  t.UTC(),
+		Valid: !t.IsZero(),
+	}
+}
+
+func fromNullTime(nt sql.NullTime) time.Time {
+	if !nt.Valid {
+		return time.Time{}
+	}
+
+	return nt.Time.In(time.Local)
+}
+
+// =============================================================================
+
+type revocation struct {
+	AccessID    string    `db:"access_id"`
+	DateExpires time.Time `db:"date_expires"`
+}
+
+func toDBRevocation(bus sessionbus.Revocation) revocation {
+	return revocation{
+		AccessID:    bus.AccessID,
+		DateExpires: bus.DateExpires.UTC(),
+	}
+}
@@ -0,0 +1,226 @@
+// Package sessiondb contains session related CRUD functionality.
+package sessiondb
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for session database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (sessionbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new refresh token into the database.
+func (s *Store) Create(ctx context.Context, rt sessionbus.RefreshToken) error {
+	const q = `
+	INSERT INTO refresh_tokens
+		(token_hash, family_id, user_id, org_id, access_id, access_expires, date_expires, date_created, date_used, date_revoked)
+	VALUES
+		(:token_hash, :family_id, :user_id, :org_id, :access_id, :access_expires, :date_expires, :date_created, :date_used, :date_revoked)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRefreshToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// MarkUsed records that the refresh token was exchanged. Only a token that
+// was not used yet is marked, so of two requests racing with the same token
+// one gets ErrNotFound.
+func (s *Store) MarkUsed(ctx context.Context, tokenHash []byte, now time.Time) error {
+	data := struct {
+		TokenHash string    `db:"token_hash"`
+		DateUsed  time.Time `db:"date_used"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+		DateUsed:  now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_used = :date_used
+	WHERE
+		token_hash = :token_hash AND
+		date_used IS NULL AND
+		date_revoked IS NULL
+	RETURNING
+		token_hash`
+
+	var dest struct {
+		TokenHash string `db:"token_hash"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", sessionbus.ErrNotFound)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every refresh token of the family and returns them.
+func (s *Store) RevokeFamily(ctx context.Context, familyID uuid.UUID, now time.Time) ([]sessionbus.RefreshToken, error) {
+	data := struct {
+		FamilyID    string    `db:"family_id"`
+		DateRevoked time.Time `db:"date_revoked"`
+	}{
+		FamilyID:    familyID.String(),
+		DateRevoked: now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_revoked = :date_revoked
+	WHERE
+		family_id = :family_id AND
+		date_revoked IS NULL
+	RETURNING
+		token_hash, family_id, user_id, org_id, access_id, access_expires, date_expires, date_created, date_used, date_revoked`
+
+	var dbRts []refreshToken
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusRefreshTokens(dbRts)
+}
+
+// RevokeUser revokes every refresh token of the user and returns them.
+func (s *Store) RevokeUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]sessionbus.RefreshToken, error) {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		DateRevoked time.Time `db:"date_revoked"`
+	}{
+		UserID:      userID.String(),
+		DateRevoked: now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_revoked = :date_revoked
+	WHERE
+		user_id = :user_id AND
+		date_revoked IS NULL
+	RETURNING
+		token_hash, family_id, user_id, org_id, access_id, access_expires, date_expires, date_created, date_used, date_revoked`
+
+	var dbRts []refreshToken
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusRefreshTokens(dbRts)
+}
+
+// QueryByHash gets the refresh token with the hash from the database.
+func (s *Store) QueryByHash(ctx context.Context, tokenHash []byte) (sessionbus.RefreshToken, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+	}
+
+	const q = `
+	SELECT
+		token_hash, family_id, user_id, org_id, access_id, access_expires, date_expires, date_created, date_used, date_revoked
+	FROM
+		refresh_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var dbRt refreshToken
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRt); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return sessionbus.RefreshToken{}, fmt.Errorf("db: %w", sessionbus.ErrNotFound)
+		}
+		return sessionbus.RefreshToken{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRefreshToken(dbRt)
+}
+
+// CreateRevocation adds the access token to the denylist. Revoking a token
+// twice is not an error.
+func (s *Store) CreateRevocation(ctx context.Context, rvk sessionbus.Revocation) error {
+	const q = `
+	INSERT INTO revoked_tokens
+		(access_id, date_expires)
+	VALUES
+		(:access_id, :date_expires)
+	ON CONFLICT (access_id) DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRevocation(rvk)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the access token is on the denylist.
+func (s *Store) IsRevoked(ctx context.Context, accessID string) (bool, error) {
+	data := struct {
+		AccessID string `db:"access_id"`
+	}{
+		AccessID: accessID,
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		revoked_tokens
+	WHERE
+		access_id = :access_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return false, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count > 0, nil
+}
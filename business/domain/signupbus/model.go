@@ -0,0 +1,30 @@
+package signupbus
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/name"
+)
+
+// Signup represents a user that signed up themselves, whose account stays
+// disabled until they verify their email.
+type Signup struct {
+	UserID       uuid.UUID
+	Email        mail.Address
+	DateCreated  time.Time
+	DateVerified time.Time // Zero until verified
+}
+
+// Verified reports whether the email of the signup has been verified.
+func (s Signup) Verified() bool {
+	return !s.DateVerified.IsZero()
+}
+
+// NewSignup is what we require from someone signing up.
+type NewSignup struct {
+	Name     name.Name
+	Email    mail.Address
+	Password string
+}
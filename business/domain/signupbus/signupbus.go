@@ -0,0 +1,339 @@
+// Package signupbus provides business access to self-service signup, where
+// people create their own account and verify their email before using it.
+package signupbus
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/mailer"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/timezone"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for signup.
+var (
+	ErrNotFound         = errors.New("signup not found")
+	ErrDomainNotAllowed = errors.New("email domain is not allowed to sign up")
+	ErrInvalidToken     = errors.New("invalid verification token")
+	ErrTokenExpired     = errors.New("verification token expired")
+	ErrAlreadyVerified  = errors.New("email already verified")
+)
+
+// DefaultTTL is how long a verification link works when the config does not
+// say.
+const DefaultTTL = 24 * time.Hour
+
+// Config represents how signups are verified and who may sign up.
+type Config struct {
+	Key            []byte        // Signs the verification links
+	TTL            time.Duration // How long a verification link works
+	VerifyURL      string        // Where the links point, the token is added as a query parameter
+	AllowedDomains []string      // Email domains that may sign up, any when empty
+}
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, sup Signup) error
+	Update(ctx context.Context, sup Signup) error
+	QueryByUserID(ctx context.Context, userID uuid.UUID) (Signup, error)
+}
+
+// Business manages the set of APIs for signup access.
+type Business struct {
+	log      *logger.Logger
+	userBus  *userbus.Business
+	mailer   mailer.Mailer
+	storer   Storer
+	cfg      Config
+	beginner sqldb.Beginner
+}
+
+// NewBusiness constructs a signup business API for use. Without a beginner
+// the account and its signup are not created in one transaction.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, mailer mailer.Mailer, storer Storer, cfg Config, beginner sqldb.Beginner) *Business {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+
+	return &Business{
+		log:      log,
+		userBus:  userBus,
+		mailer:   mailer,
+		storer:   storer,
+		cfg:      cfg,
+		beginner: beginner,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:     b.log,
+		userBus: userBus,
+		mailer:  b.mailer,
+		storer:  storer,
+		cfg:     b.cfg,
+	}
+
+	// The new value has no beginner, so work it does joins the transaction
+	// instead of starting another.
+
+	return &bus, nil
+}
+
+// Signup creates a disabled user and emails them a link to verify their
+// email with. Signing up again before verifying sends a new link and leaves
+// the account as it was first created. The owner of an email that already
+// has an account is told about the attempt instead, and no error is
+// returned either, so the caller can't tell which addresses have one.
+func (b *Business) Signup(ctx context.Context, ns NewSignup) error {
+	ctx, span := otel.AddSpan(ctx, "business.signupbus.signup")
+	defer span.End()
+
+	if !b.allowed(ns.Email) {
+		return ErrDomainNotAllowed
+	}
+
+	usr, err := b.userBus.QueryByEmail(ctx, ns.Email)
+	switch {
+	case err == nil:
+		sup, err := b.storer.QueryByUserID(ctx, usr.ID)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return b.sendExists(ctx, usr)
+
+		case err != nil:
+			return fmt.Errorf("querybyuserid: userID[%s]: %w", usr.ID, err)
+
+		case sup.Verified():
+			return b.sendExists(ctx, usr)
+		}
+
+		if err := b.send(ctx, usr, sup); err != nil {
+			return fmt.Errorf("send: %w", err)
+		}
+
+		return nil
+
+	case !errors.Is(err, userbus.ErrNotFound):
+		return fmt.Errorf("querybyemail: %w", err)
+	}
+
+	nu := userbus.NewUser{
+		Name:     ns.Name,
+		Email:    ns.Email,
+		Roles:    []role.Role{role.User},
+		TimeZone: timezone.UTC,
+		Password: ns.Password,
+		Disabled: true,
+	}
+
+	var sup Signup
+
+	err = b.withTran(ctx, func(bus *Business) error {
+		usr, err = bus.userBus.Create(ctx, nu)
+		if err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+
+		sup = Signup{
+			UserID:      usr.ID,
+			Email:       usr.Email,
+			DateCreated: usr.DateCreated,
+		}
+
+		if err := bus.storer.Create(ctx, sup); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Someone signed up with the same email in the meantime.
+		if errors.Is(err, userbus.ErrUniqueEmail) {
+			return nil
+		}
+		return err
+	}
+
+	if err := b.send(ctx, usr, sup); err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks the token from a verification link and enables the account
+// of the signup it is for.
+func (b *Business) Verify(ctx context.Context, value string) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.signupbus.verify")
+	defer span.End()
+
+	now := time.Now()
+
+	tkn, err := decodeToken(b.cfg.Key, value, now)
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	sup, err := b.storer.QueryByUserID(ctx, tkn.UserID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return userbus.User{}, ErrInvalidToken
+		}
+		return userbus.User{}, fmt.Errorf("querybyuserid: userID[%s]: %w", tkn.UserID, err)
+	}
+
+	if sup.Email.Address != tkn.Email {
+		return userbus.User{}, ErrInvalidToken
+	}
+
+	if sup.Verified() {
+		return userbus.User{}, ErrAlreadyVerified
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, sup.UserID)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("querybyid: userID[%s]: %w", sup.UserID, err)
+	}
+
+	enabled := true
+
+	err = b.withTran(ctx, func(bus *Business) error {
+		usr, err = bus.userBus.Update(ctx, usr, userbus.UpdateUser{Enabled: &enabled})
+		if err != nil {
+			return fmt.Errorf("update user: userID[%s]: %w", usr.ID, err)
+		}
+
+		sup.DateVerified = now
+
+		if err := bus.storer.Update(ctx, sup); err != nil {
+			return fmt.Errorf("update: userID[%s]: %w", sup.UserID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+// allowed reports whether the domain of the email may sign up.
+func (b *Business) allowed(email mail.Address) bool {
+	if len(b.cfg.AllowedDomains) == 0 {
+		return true
+	}
+
+	_, domain, _ := strings.Cut(email.Address, "@")
+
+	return slices.ContainsFunc(b.cfg.AllowedDomains, func(allowed string) bool {
+		return strings.EqualFold(allowed, domain)
+	})
+}
+
+// withTran runs fn with a business whose store calls share one transaction,
+// committing when fn succeeds. Without a beginner, fn runs with b itself.
+func (b *Business) withTran(ctx context.Context, fn func(bus *Business) error) error {
+	if b.beginner == nil {
+		return fn(b)
+	}
+
+	tx, err := b.beginner.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Error(ctx, "signupbus: rollback", "ERROR", err)
+		}
+	}()
+
+	bus, err := b.NewWithTx(tx)
+	if err != nil {
+		return fmt.Errorf("newwithtx: %w", err)
+	}
+
+	if err := fn(bus); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// send emails the user a new link to verify the signup with.
+func (b *Business) send(ctx context.Context, usr userbus.User, sup Signup) error {
+	expires := time.Now().Add(b.cfg.TTL)
+
+	tkn := token{
+		UserID:  sup.UserID,
+		Email:   sup.Email.Address,
+		Expires: expires.Unix(),
+	}
+
+	link := b.cfg.VerifyURL + "?token=" + url.QueryEscape(encodeToken(b.cfg.Key, tkn))
+
+	msg := mailer.Message{
+		To:      usr.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to verify your email and finish signing up:\n\n%s\n\nThe link works until %s. If you did not sign up, you can ignore this email.\n",
+			usr.Name, link, expires.UTC().Format(time.RFC1123)),
+	}
+
+	if err := b.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("mail: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
+
+// sendExists tells the owner of an account that someone tried to sign up
+// with their email.
+func (b *Business) sendExists(ctx context.Context, usr userbus.User) error {
+	msg := mailer.Message{
+		To:      usr.Email,
+		Subject: "You already have an account",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone tried to sign up with your email, but you already have an account. If it was you, sign in or reset your password instead. If it was not, you can ignore this email.\n",
+			usr.Name),
+	}
+
+	if err := b.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("mail: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
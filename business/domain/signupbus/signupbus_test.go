@@ -0,0 +1,248 @@
+package signupbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/domain/signupbus/stores/signupdb"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Signup(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Signup")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, signup(db, sd), "signup")
+	unitest.Run(t, verify(db.BusDomain), "verify")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}},
+	}
+
+	return sd, nil
+}
+
+func newSignup(email string) signupbus.NewSignup {
+	return signupbus.NewSignup{
+		Name:     name.MustParse("Signup Gopher"),
+		Email:    mail.Address{Address: email},
+		Password: "gophers",
+	}
+}
+
+var tokenRE = regexp.MustCompile(`token=(\S+)`)
+
+// lastToken returns the token of the last verification link sent to the
+// address.
+func lastToken(ctx context.Context, busDomain dbtest.BusDomain, email string) (string, error) {
+	msgs, err := busDomain.Mail.Query(ctx, mail.Address{Address: email})
+	if err != nil {
+		return "", err
+	}
+
+	if len(msgs) == 0 {
+		return "", fmt.Errorf("no mail sent to %s", email)
+	}
+
+	m := tokenRE.FindStringSubmatch(msgs[len(msgs)-1].Body)
+	if m == nil {
+		return "", fmt.Errorf("no link in %q", msgs[len(msgs)-1].Body)
+	}
+
+	return url.QueryUnescape(m[1])
+}
+
+// =============================================================================
+
+func signup(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	busDomain := db.BusDomain
+
+	table := []unitest.Table{
+		{
+			Name:    "disabled",
+			ExpResp: false,
+			ExcFunc: func(ctx context.Context) any {
+				const email = "disabled@example.com"
+
+				if err := busDomain.Signup.Signup(ctx, newSignup(email)); err != nil {
+					return err
+				}
+
+				usr, err := busDomain.User.QueryByEmail(ctx, mail.Address{Address: email})
+				if err != nil {
+					return err
+				}
+
+				return usr.Enabled
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "resend",
+			ExpResp: 2,
+			ExcFunc: func(ctx context.Context) any {
+				const email = "resend@example.com"
+
+				for range 2 {
+					if err := busDomain.Signup.Signup(ctx, newSignup(email)); err != nil {
+						return err
+					}
+				}
+
+				msgs, err := busDomain.Mail.Query(ctx, mail.Address{Address: email})
+				if err != nil {
+					return err
+				}
+
+				return len(msgs)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "exists",
+			ExpResp: "You already have an account",
+			ExcFunc: func(ctx context.Context) any {
+				email := sd.Users[0].Email
+
+				if err := busDomain.Signup.Signup(ctx, newSignup(email.Address)); err != nil {
+					return err
+				}
+
+				msgs, err := busDomain.Mail.Query(ctx, email)
+				if err != nil {
+					return err
+				}
+
+				if len(msgs) == 0 {
+					return fmt.Errorf("no mail sent to %s", email.Address)
+				}
+
+				return msgs[len(msgs)-1].Subject
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "domain",
+			ExpResp: signupbus.ErrDomainNotAllowed,
+			ExcFunc: func(ctx context.Context) any {
+				cfg := dbtest.TestSignupConfig
+				cfg.AllowedDomains = []string{"example.com"}
+
+				bus := signupbus.NewBusiness(db.Log, busDomain.User, busDomain.Mail, signupdb.NewStore(db.Log, db.DB), cfg, sqldb.NewBeginner(db.DB))
+
+				if err := bus.Signup(ctx, newSignup("allowed@EXAMPLE.com")); err != nil {
+					return err
+				}
+
+				return bus.Signup(ctx, newSignup("someone@example.org"))
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func verify(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "enabled",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				const email = "verify@example.com"
+
+				if err := busDomain.Signup.Signup(ctx, newSignup(email)); err != nil {
+					return err
+				}
+
+				tkn, err := lastToken(ctx, busDomain, email)
+				if err != nil {
+					return err
+				}
+
+				usr, err := busDomain.Signup.Verify(ctx, tkn)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Signup.Verify(ctx, tkn); !errors.Is(err, signupbus.ErrAlreadyVerified) {
+					return fmt.Errorf("verifying again: got %v, exp %v", err, signupbus.ErrAlreadyVerified)
+				}
+
+				return usr.Enabled
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "tampered",
+			ExpResp: signupbus.ErrInvalidToken,
+			ExcFunc: func(ctx context.Context) any {
+				const email = "tampered@example.com"
+
+				if err := busDomain.Signup.Signup(ctx, newSignup(email)); err != nil {
+					return err
+				}
+
+				tkn, err := lastToken(ctx, busDomain, email)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Signup.Verify(ctx, "e30"+tkn[3:])
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
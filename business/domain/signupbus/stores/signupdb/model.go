@@ -0,0 +1,49 @@
+package signupdb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/signupbus"
+)
+
+type signup struct {
+	UserID       uuid.UUID    `db:"user_id"`
+	Email        string       `db:"email"`
+	DateCreated  time.Time    `db:"date_created"`
+	DateVerified sql.NullTime `db:"date_verified"`
+}
+
+func toDBSignup(bus signupbus.Signup) signup {
+	return signup{
+		UserID:      bus.UserID,
+		Email:       bus.Email.Address,
+		DateCreated: bus.DateCreated.UTC(),
+		DateVerified: sql.NullTime{
+			Time:  bus.DateVerified.UTC(),
+			Valid: bus.Verified(),
+		},
+	}
+}
+
+func toBusSignup(db signup) (signupbus.Signup, error) {
+	addr, err := mail.ParseAddress(db.Email)
+	if err != nil {
+		return signupbus.Signup{}, fmt.Errorf("parse email: %w", err)
+	}
+
+	bus := signupbus.Signup{
+		UserID:      db.UserID,
+		Email:       *addr,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.DateVerified.Valid {
+		bus.DateVerified = db.DateVerified.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
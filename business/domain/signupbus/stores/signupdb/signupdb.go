@@ -0,0 +1,104 @@
+// Package signupdb contains signup related CRUD functionality.
+package signupdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for signup database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (signupbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new signup into the database.
+func (s *Store) Create(ctx context.Context, sup signupbus.Signup) error {
+	const q = `
+	INSERT INTO signups
+		(user_id, email, date_created, date_verified)
+	VALUES
+		(:user_id, :email, :date_created, :date_verified)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSignup(sup)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a signup document in the database.
+func (s *Store) Update(ctx context.Context, sup signupbus.Signup) error {
+	const q = `
+	UPDATE
+		signups
+	SET
+		"email" = :email,
+		"date_verified" = :date_verified
+	WHERE
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSignup(sup)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByUserID gets the signup of the specified user from the database.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) (signupbus.Signup, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		user_id, email, date_created, date_verified
+	FROM
+		signups
+	WHERE
+		user_id = :user_id`
+
+	var dbSup signup
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbSup); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return signupbus.Signup{}, fmt.Errorf("db: %w", signupbus.ErrNotFound)
+		}
+		return signupbus.Signup{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusSignup(dbSup)
+}
@@ -0,0 +1,40 @@
+package signupbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/opaque"
+)
+
+// tokenPurpose keeps values signed with the key for other uses from passing
+// as verification tokens.
+const tokenPurpose = "signup"
+
+// token is what a verification link carries. The email is part of it, so a
+// link stops working when the email of the signup changes.
+type token struct {
+	UserID  uuid.UUID `json:"u"`
+	Email   string    `json:"e"`
+	Expires int64     `json:"x"`
+}
+
+// encodeToken returns the token as an opaque string signed with the key.
+func encodeToken(key []byte, tkn token) string {
+	value, _ := opaque.Sign(key, tokenPurpose, tkn)
+	return value
+}
+
+// decodeToken reads a token encoded with the same key that has not expired.
+func decodeToken(key []byte, value string, now time.Time) (token, error) {
+	var tkn token
+	if err := opaque.Verify(key, tokenPurpose, value, &tkn); err != nil {
+		return token{}, ErrInvalidToken
+	}
+
+	if now.Unix() >= tkn.Expires {
+		return token{}, ErrTokenExpired
+	}
+
+	return tkn, nil
+}
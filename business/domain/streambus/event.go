@@ -0,0 +1,79 @@
+package streambus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate == nil {
+		return
+	}
+
+	for _, action := range []string{todobus.ActionCreated, todobus.ActionUpdated, todobus.ActionCompleted, todobus.ActionDeleted, todobus.ActionUnblocked} {
+		b.delegate.Register(todobus.DomainName, action, b.actionItem)
+	}
+
+	b.delegate.Register(todobus.DomainName, todobus.ActionAssigned, b.actionAssigned)
+}
+
+// actionItem is executed by the todo domain indirectly when an item is
+// created, updated, completed, deleted or unblocked.
+func (b *Business) actionItem(ctx context.Context, data delegate.Data) error {
+	var params todobus.ActionItemParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	evt := Event{
+		Type:        data.Action,
+		TodoID:      params.TodoID,
+		OrgID:       params.OrgID,
+		OwnerID:     params.OwnerID,
+		AssigneeID:  params.AssigneeID,
+		List:        params.List,
+		Data:        data.RawParams,
+		DateCreated: time.Now(),
+	}
+
+	if _, err := b.storer.Create(ctx, evt); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
+
+// actionAssigned is executed by the todo domain indirectly when an item is
+// handed to another user. The previous assignee is told as well so the item
+// can leave their view.
+func (b *Business) actionAssigned(ctx context.Context, data delegate.Data) error {
+	var params todobus.ActionAssignedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	evt := Event{
+		Type:               data.Action,
+		TodoID:             params.TodoID,
+		OrgID:              params.OrgID,
+		OwnerID:            params.OwnerID,
+		AssigneeID:         params.AssigneeID,
+		PreviousAssigneeID: params.PreviousAssigneeID,
+		List:               params.List,
+		Data:               data.RawParams,
+		DateCreated:        time.Now(),
+	}
+
+	if _, err := b.storer.Create(ctx, evt); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
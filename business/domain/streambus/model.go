@@ -0,0 +1,61 @@
+package streambus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/todobus"
+)
+
+// Set of event types. They are named after the todo action they record.
+const (
+	TypeCreated   = todobus.ActionCreated
+	TypeUpdated   = todobus.ActionUpdated
+	TypeCompleted = todobus.ActionCompleted
+	TypeDeleted   = todobus.ActionDeleted
+	TypeAssigned  = todobus.ActionAssigned
+	TypeUnblocked = todobus.ActionUnblocked
+)
+
+// Event represents a change made to a todo item. The events of an
+// organization are numbered in the order they were recorded, so a client that
+// has seen an event has seen every event of the organization before it.
+type Event struct {
+	ID                 int64
+	Type               string
+	TodoID             uuid.UUID
+	OrgID              uuid.UUID
+	OwnerID            uuid.UUID
+	AssigneeID         uuid.UUID
+	PreviousAssigneeID uuid.UUID
+	List               string
+	Data               []byte // Parameters of the todo action, encoded as JSON
+	DateCreated        time.Time
+}
+
+// Filter picks the events a subscriber is shown.
+type Filter struct {
+	OrgID  *uuid.UUID // Items of the organization. Nil for every organization
+	UserID *uuid.UUID // Items the user owns, is assigned or was assigned. Nil for all items
+	List   *string
+}
+
+// Match reports whether the event passes the filter.
+func (f Filter) Match(evt Event) bool {
+	if f.OrgID != nil && evt.OrgID != *f.OrgID {
+		return false
+	}
+
+	if f.UserID != nil {
+		id := *f.UserID
+		if evt.OwnerID != id && evt.AssigneeID != id && evt.PreviousAssigneeID != id {
+			return false
+		}
+	}
+
+	if f.List != nil && evt.List != *f.List {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,38 @@
+package streambus
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_FilterMatch(t *testing.T) {
+	orgID := uuid.New()
+	otherOrgID := uuid.New()
+	userID := uuid.New()
+	list := "work"
+
+	evt := Event{
+		OrgID:   orgID,
+		OwnerID: userID,
+		List:    list,
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		exp    bool
+	}{
+		{name: "all", filter: Filter{}, exp: true},
+		{name: "org", filter: Filter{OrgID: &orgID}, exp: true},
+		{name: "other-org", filter: Filter{OrgID: &otherOrgID}, exp: false},
+		{name: "other-org-owner", filter: Filter{OrgID: &otherOrgID, UserID: &userID}, exp: false},
+		{name: "owner", filter: Filter{OrgID: &orgID, UserID: &userID, List: &list}, exp: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Match(evt); got != tt.exp {
+			t.Errorf("%s: got %t, exp %t", tt.name, got, tt.exp)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package streamdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/streambus"
+)
+
+type event struct {
+	ID                 int64          `db:"event_id"`
+	Type               string         `db:"type"`
+	TodoID             uuid.UUID      `db:"todo_id"`
+	OrgID              uuid.UUID      `db:"org_id"`
+	OwnerID            uuid.NullUUID  `db:"owner_id"`
+	AssigneeID         uuid.NullUUID  `db:"assignee_id"`
+	PreviousAssigneeID uuid.NullUUID  `db:"previous_assignee_id"`
+	List               sql.NullString `db:"list"`
+	Data               []byte         `db:"data"`
+	DateCreated        time.Time      `db:"date_created"`
+}
+
+func toDBEvent(bus streambus.Event) event {
+	return event{
+		ID:                 bus.ID,
+		Type:               bus.Type,
+		TodoID:             bus.TodoID,
+		OrgID:              bus.OrgID,
+		OwnerID:            toNullUUID(bus.OwnerID),
+		AssigneeID:         toNullUUID(bus.AssigneeID),
+		PreviousAssigneeID: toNullUUID(bus.PreviousAssigneeID),
+		List: sql.NullString{
+			String: bus.List,
+			Valid:  bus.List != "",
+		},
+		Data:        bus.Data,
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusEvent(db event) streambus.Event {
+	return streambus.Event{
+		ID:                 db.ID,
+		Type:               db.Type,
+		TodoID:             db.TodoID,
+		OrgID:              db.OrgID,
+		OwnerID:            db.OwnerID.UUID,
+		AssigneeID:         db.AssigneeID.UUID,
+		PreviousAssigneeID: db.PreviousAssigneeID.UUID,
+		List:               db.List.String,
+		Data:               db.Data,
+		DateCreated:        db.DateCreated.In(time.Local),
+	}
+}
+
+func toBusEvents(dbs []event) []streambus.Event {
+	bus := make([]streambus.Event, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusEvent(db)
+	}
+
+	return bus
+}
+
+func toNullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{
+		UUID:  id,
+		Valid: id != uuid.Nil,
+	}
+}
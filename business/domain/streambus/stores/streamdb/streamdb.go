@@ -0,0 +1,239 @@
+// Package streamdb contains todo event related CRUD functionality.
+package streamdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// channel is the notification channel told about every new event.
+const channel = "todo_events"
+
+// Store manages the set of APIs for todo event database access.
+type Store struct {
+	log  *logger.Logger
+	db   sqlx.ExtContext
+	pool *sqlx.DB
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log:  log,
+		db:   db,
+		pool: db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (streambus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log:  s.log,
+		db:   ec,
+		pool: s.pool,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new event into the database and notifies the listeners.
+// The events of an organization are recorded one at a time so their ids are
+// handed out in the order they become visible, which is what lets a client
+// resume after the last id it saw without missing anything. Organizations
+// don't wait on each other, and the event is always recorded in a transaction
+// of its own so the lock is never held for longer than the insert.
+func (s *Store) Create(ctx context.Context, evt streambus.Event) (streambus.Event, error) {
+	const q = `
+	WITH evt AS (
+		INSERT INTO todo_events
+			(type, todo_id, org_id, owner_id, assignee_id, previous_assignee_id, list, data, date_created)
+		SELECT
+			:type, CAST(:todo_id AS UUID), CAST(:org_id AS UUID), CAST(:owner_id AS UUID), CAST(:assignee_id AS UUID),
+			CAST(:previous_assignee_id AS UUID), CAST(:list AS TEXT), CAST(:data AS BYTEA), CAST(:date_created AS TIMESTAMP)
+		FROM
+			(SELECT pg_advisory_xact_lock(hashtext('todo_events'), hashtext(CAST(:org_id AS TEXT)))) AS lock
+		RETURNING
+			event_id
+	)
+	SELECT
+		evt.event_id
+	FROM
+		evt, pg_notify('` + channel + `', CAST(evt.event_id AS TEXT))`
+
+	var dest struct {
+		ID int64 `db:"event_id"`
+	}
+
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.pool, q, toDBEvent(evt), &dest); err != nil {
+		return streambus.Event{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	evt.ID = dest.ID
+
+	return evt, nil
+}
+
+// QueryAfter retrieves up to limit events that pass the filter and were
+// recorded after the specified event, oldest first.
+func (s *Store) QueryAfter(ctx context.Context, filter streambus.Filter, afterID int64, limit int) ([]streambus.Event, error) {
+	data := map[string]any{
+		"after_id": afterID,
+		"limit":    limit,
+	}
+
+	const q = `
+	SELECT
+		event_id, type, todo_id, org_id, owner_id, assignee_id, previous_assignee_id, list, data, date_created
+	FROM
+		todo_events
+	WHERE
+		event_id > :after_id`
+
+	buf := bytes.NewBufferString(q)
+
+	if filter.OrgID != nil {
+		data["org_id"] = *filter.OrgID
+		buf.WriteString(" AND org_id = :org_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		buf.WriteString(" AND (owner_id = :user_id OR assignee_id = :user_id OR previous_assignee_id = :user_id)")
+	}
+
+	if filter.List != nil {
+		data["list"] = *filter.List
+		buf.WriteString(" AND list = :list")
+	}
+
+	buf.WriteString(" ORDER BY event_id LIMIT :limit")
+
+	var dbEvts []event
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbEvts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEvents(dbEvts), nil
+}
+
+// QueryByID finds the event by the specified id.
+func (s *Store) QueryByID(ctx context.Context, eventID int64) (streambus.Event, error) {
+	data := struct {
+		ID int64 `db:"event_id"`
+	}{
+		ID: eventID,
+	}
+
+	const q = `
+	SELECT
+		event_id, type, todo_id, org_id, owner_id, assignee_id, previous_assignee_id, list, data, date_created
+	FROM
+		todo_events
+	WHERE
+		event_id = :event_id`
+
+	var dbEvt event
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbEvt); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return streambus.Event{}, fmt.Errorf("db: %w", streambus.ErrNotFound)
+		}
+		return streambus.Event{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusEvent(dbEvt), nil
+}
+
+// QueryFirstID returns the id of the oldest event, or zero when there are
+// none.
+func (s *Store) QueryFirstID(ctx context.Context) (int64, error) {
+	const q = `
+	SELECT
+		COALESCE(MIN(event_id), 0) AS event_id
+	FROM
+		todo_events`
+
+	var dest struct {
+		ID int64 `db:"event_id"`
+	}
+
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dest); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return dest.ID, nil
+}
+
+// DeleteBefore removes the events recorded before the specified time and
+// returns how many there were. The latest event is always kept so it is
+// still known which events are gone.
+func (s *Store) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	data := struct {
+		Before time.Time `db:"before"`
+	}{
+		Before: before.UTC(),
+	}
+
+	const q = `
+	WITH deleted AS (
+		DELETE FROM
+			todo_events
+		WHERE
+			date_created < :before AND
+			event_id < (SELECT MAX(event_id) FROM todo_events)
+		RETURNING
+			event_id
+	)
+	SELECT
+		COUNT(*) AS count
+	FROM
+		deleted`
+
+	var dest struct {
+		Count int `db:"count"`
+	}
+
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return dest.Count, nil
+}
+
+// Listen calls the function with an id of zero once listening for new events
+// has started, and then with the id of every event recorded, in the order
+// they were committed, until the context is canceled or the function fails.
+func (s *Store) Listen(ctx context.Context, fn func(ctx context.Context, eventID int64) error) error {
+	f := func(ctx context.Context, payload string) error {
+		if payload == "" {
+			return fn(ctx, 0)
+		}
+
+		id, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse: payload[%s]: %w", payload, err)
+		}
+
+		return fn(ctx, id)
+	}
+
+	if err := sqldb.Listen(ctx, s.pool, channel, f); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+// Package streambus provides business access to the changes made to todo
+// items, for clients that follow them as they happen.
+package streambus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// ErrNotFound is returned when an event is not found.
+var ErrNotFound = errors.New("event not found")
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, evt Event) (Event, error)
+	QueryAfter(ctx context.Context, filter Filter, afterID int64, limit int) ([]Event, error)
+	QueryByID(ctx context.Context, eventID int64) (Event, error)
+	QueryFirstID(ctx context.Context) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+	Listen(ctx context.Context, fn func(ctx context.Context, eventID int64) error) error
+}
+
+// Config controls how events reach subscribers. Zero values are replaced
+// with the defaults.
+type Config struct {
+	Buffer int // Events held for a subscriber before it is dropped. Default: 64
+}
+
+// Business manages the set of APIs for following todo changes.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+	cfg      Config
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+	lis  *listener
+}
+
+// NewBusiness constructs a stream business API for use. With a delegate,
+// every change made to a todo item is recorded as an event. Events recorded
+// by any instance of the service reach the subscribers of every instance.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, cfg Config) *Business {
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = 64
+	}
+
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		cfg:      cfg,
+		subs:     make(map[*Subscription]struct{}),
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Subscribe starts delivering the events that pass the filter and are
+// recorded from now on. Events recorded before the call returns are found
+// with QueryAfter, so a client resuming from an earlier event subscribes
+// first and then catches up. Ids only tell the order of the events of one
+// organization, so a client that resumes filters by organization.
+func (b *Business) Subscribe(ctx context.Context, filter Filter) (*Subscription, error) {
+	ctx, span := otel.AddSpan(ctx, "business.streambus.subscribe")
+	defer span.End()
+
+	sub := Subscription{
+		filter: filter,
+		events: make(chan Event, b.cfg.Buffer),
+		bus:    b,
+	}
+
+	b.mu.Lock()
+	b.subs[&sub] = struct{}{}
+	if b.lis == nil {
+		b.lis = b.listen()
+	}
+	lis := b.lis
+	b.mu.Unlock()
+
+	select {
+	case <-lis.ready:
+	case <-ctx.Done():
+		sub.Close()
+		return nil, ctx.Err()
+	}
+
+	if lis.err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("listen: %w", lis.err)
+	}
+
+	return &sub, nil
+}
+
+// QueryAfter retrieves up to limit events that pass the filter and were
+// recorded after the specified event, oldest first.
+func (b *Business) QueryAfter(ctx context.Context, filter Filter, afterID int64, limit int) ([]Event, error) {
+	ctx, span := otel.AddSpan(ctx, "business.streambus.queryafter")
+	defer span.End()
+
+	evts, err := b.storer.QueryAfter(ctx, filter, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("queryafter: afterID[%d]: %w", afterID, err)
+	}
+
+	return evts, nil
+}
+
+// Resumable reports whether every event recorded after the specified one is
+// still held, so a client that saw it can catch up from there.
+func (b *Business) Resumable(ctx context.Context, afterID int64) (bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.streambus.resumable")
+	defer span.End()
+
+	firstID, err := b.storer.QueryFirstID(ctx)
+	if err != nil {
+		return false, fmt.Errorf("queryfirstid: %w", err)
+	}
+
+	if firstID == 0 {
+		return afterID == 0, nil
+	}
+
+	return afterID >= firstID-1, nil
+}
+
+// Purge removes the events recorded before the specified time. Clients that
+// were away for longer can no longer catch up and have to start over. It
+// returns the number of events removed.
+func (b *Business) Purge(ctx context.Context, before time.Time) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.streambus.purge")
+	defer span.End()
+
+	n, err := b.storer.DeleteBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("deletebefore: %w", err)
+	}
+
+	return n, nil
+}
+
+// =============================================================================
+
+// Subscription receives the events that pass its filter as they are recorded.
+type Subscription struct {
+	filter Filter
+	events chan Event
+	bus    *Business
+}
+
+// Events returns the channel the events are delivered on, in the order they
+// were recorded. The channel is closed when the subscriber falls too far
+// behind to be kept up to date, or when the connection to the database is
+// lost. The client then resumes from the last event it received.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the delivery of events.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, exists := s.bus.subs[s]; exists {
+		s.bus.remove(s)
+	}
+}
+
+// =============================================================================
+
+// listener follows the events recorded by every instance of the service for
+// as long as there are subscribers.
+type listener struct {
+	cancel context.CancelFunc
+	ready  chan struct{} // Closed once listening or failed to start
+	err    error         // Why it failed to start
+}
+
+// listen starts a listener. Once listening, every event recorded is handed
+// to the subscribers in the order it was committed. When the listener fails,
+// every subscriber is dropped so clients resume with a new one.
+func (b *Business) listen() *listener {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lis := listener{
+		cancel: cancel,
+		ready:  make(chan struct{}),
+	}
+
+	go func() {
+		var started bool
+
+		fn := func(ctx context.Context, eventID int64) error {
+			if !started {
+				started = true
+				close(lis.ready)
+			}
+
+			if eventID == 0 {
+				return nil
+			}
+
+			evt, err := b.storer.QueryByID(ctx, eventID)
+			if err != nil {
+				if errors.Is(err, ErrNotFound) {
+					return nil
+				}
+				return fmt.Errorf("querybyid: eventID[%d]: %w", eventID, err)
+			}
+
+			b.dispatch([]Event{evt})
+
+			return nil
+		}
+
+		err := b.storer.Listen(ctx, fn)
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if ctx.Err() == nil {
+			b.log.Error(ctx, "streambus: listen", "subscribers", len(b.subs), "ERROR", err)
+		}
+
+		if b.lis == &lis {
+			b.lis = nil
+			for sub := range b.subs {
+				b.remove(sub)
+			}
+		}
+
+		if !started {
+			lis.err = err
+			close(lis.ready)
+		}
+	}()
+
+	return &lis
+}
+
+// dispatch hands the events to every subscriber whose filter they pass. A
+// subscriber with no room left is dropped rather than holding up the rest.
+func (b *Business) dispatch(evts []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+	next:
+		for _, evt := range evts {
+			if !sub.filter.Match(evt) {
+				continue
+			}
+
+			select {
+			case sub.events <- evt:
+			default:
+				b.log.Info(context.Background(), "streambus: subscriber dropped", "eventID", evt.ID, "buffer", b.cfg.Buffer)
+				b.remove(sub)
+				break next
+			}
+		}
+	}
+}
+
+// remove ends the subscription and stops the listener when it was the last
+// one. The caller must hold the lock.
+func (b *Business) remove(sub *Subscription) {
+	delete(b.subs, sub)
+	close(sub.events)
+
+	if len(b.subs) == 0 && b.lis != nil {
+		b.lis.cancel()
+		b.lis = nil
+	}
+}
@@ -0,0 +1,198 @@
+package streambus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/status"
+)
+
+func Test_Stream(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Stream")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, subscribe(db.BusDomain, sd), "subscribe")
+	unitest.Run(t, resume(db.BusDomain, sd), "resume")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func subscribe(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "visible",
+			ExpResp: []string{streambus.TypeCreated, streambus.TypeUpdated, streambus.TypeCompleted},
+			ExcFunc: func(ctx context.Context) any {
+				sub, err := busDomain.Stream.Subscribe(ctx, streambus.Filter{UserID: &sd.Users[0].ID})
+				if err != nil {
+					return err
+				}
+				defer sub.Close()
+
+				// Items of other users are never seen.
+				if _, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[1].ID, busDomain.Todo); err != nil {
+					return err
+				}
+
+				items, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[0].ID, busDomain.Todo)
+				if err != nil {
+					return err
+				}
+
+				done := status.Done
+				if _, err := busDomain.Todo.Update(ctx, items[0], todobus.UpdateTodoItem{Status: &done}); err != nil {
+					return err
+				}
+
+				return receive(sub, 3)
+			},
+			CmpFunc: cmpTypes,
+		},
+	}
+
+	return table
+}
+
+func resume(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "after",
+			ExpResp: []string{streambus.TypeUpdated},
+			ExcFunc: func(ctx context.Context) any {
+				filter := streambus.Filter{UserID: &sd.Users[1].ID}
+
+				evts, err := busDomain.Stream.QueryAfter(ctx, filter, 0, 10)
+				if err != nil {
+					return err
+				}
+
+				if len(evts) != 1 || evts[0].Type != streambus.TypeCreated {
+					return fmt.Errorf("expected the created event only, got %d events", len(evts))
+				}
+
+				item, err := busDomain.Todo.QueryByID(ctx, evts[0].TodoID)
+				if err != nil {
+					return err
+				}
+
+				desc := "Changed while away"
+				if _, err := busDomain.Todo.Update(ctx, item, todobus.UpdateTodoItem{Description: &desc}); err != nil {
+					return err
+				}
+
+				resumable, err := busDomain.Stream.Resumable(ctx, evts[0].ID)
+				if err != nil {
+					return err
+				}
+				if !resumable {
+					return errors.New("expected to be able to resume")
+				}
+
+				evts, err = busDomain.Stream.QueryAfter(ctx, filter, evts[0].ID, 10)
+				if err != nil {
+					return err
+				}
+
+				return types(evts)
+			},
+			CmpFunc: cmpTypes,
+		},
+		{
+			Name:    "purged",
+			ExpResp: []string{},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.Stream.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+					return err
+				}
+
+				resumable, err := busDomain.Stream.Resumable(ctx, 0)
+				if err != nil {
+					return err
+				}
+				if resumable {
+					return errors.New("expected the purged events to be gone")
+				}
+
+				return []string{}
+			},
+			CmpFunc: cmpTypes,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func receive(sub *streambus.Subscription, n int) any {
+	var evts []streambus.Event
+
+	timeout := time.After(5 * time.Second)
+	for len(evts) < n {
+		select {
+		case evt, ok := <-sub.Events():
+			if !ok {
+				return errors.New("subscription ended")
+			}
+			evts = append(evts, evt)
+
+		case <-timeout:
+			return fmt.Errorf("received %d of %d events", len(evts), n)
+		}
+	}
+
+	return types(evts)
+}
+
+func types(evts []streambus.Event) []string {
+	types := make([]string, len(evts))
+	for i, evt := range evts {
+		types[i] = evt.Type
+	}
+
+	return types
+}
+
+func cmpTypes(got any, exp any) string {
+	gotResp, exists := got.([]string)
+	if !exists {
+		return fmt.Sprintf("error occurred: %v", got)
+	}
+
+	return cmp.Diff(gotResp, exp)
+}
@@ -0,0 +1,75 @@
+package templatebus
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+)
+
+// Template represents a named set of todo items that are created together,
+// such as the steps of onboarding an employee or shipping a release.
+type Template struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Items       []Item
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Placeholders returns the distinct assignee placeholders of the template in
+// the order they first appear.
+func (tmpl Template) Placeholders() []string {
+	var placeholders []string
+	for _, item := range tmpl.Items {
+		if item.Assignee != "" && !slices.Contains(placeholders, item.Assignee) {
+			placeholders = append(placeholders, item.Assignee)
+		}
+	}
+
+	return placeholders
+}
+
+// Item represents one todo item of a template. Its due date is a number of
+// days after, or before when negative, the date the template is started on,
+// and it has no due date when DueDays is nil. An assignee is a placeholder
+// such as "manager" that is swapped for a user when the template is used,
+// and an item without one stays with whoever uses the template.
+type Item struct {
+	Description string
+	DueDays     *int
+	Priority    priority.Priority
+	List        name.Null
+	Labels      []label.Label
+	Checklist   []string
+	Assignee    string
+}
+
+// NewTemplate contains information needed to create a new template.
+type NewTemplate struct {
+	UserID uuid.UUID
+	Name   string
+	Items  []Item
+}
+
+// UpdateTemplate contains information needed to update a template. A
+// non-nil set of items replaces all of the items of the template.
+type UpdateTemplate struct {
+	Name  *string
+	Items []Item
+}
+
+// Instantiation contains information needed to create the todo items of a
+// template. The items are owned by the user, due relative to the calendar
+// date of Start, and every placeholder of the template must be given a user
+// in Assignees.
+type Instantiation struct {
+	UserID    uuid.UUID
+	Start     time.Time
+	Assignees map[string]uuid.UUID
+}
@@ -0,0 +1,152 @@
+package templatedb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+)
+
+type template struct {
+	ID          uuid.UUID `db:"template_id"`
+	OrgID       uuid.UUID `db:"org_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Name        string    `db:"name"`
+	Items       items     `db:"items"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+// items represents the items of a template, which are kept as a JSON array.
+type items []item
+
+type item struct {
+	Description string   `json:"description"`
+	DueDays     *int     `json:"dueDays,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	List        string   `json:"list,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Checklist   []string `json:"checklist,omitempty"`
+	Assignee    string   `json:"assignee,omitempty"`
+}
+
+// Value implements the driver.Valuer interface.
+func (i items) Value() (driver.Value, error) {
+	if i == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (i *items) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		*i = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("items: unsupported type %T", src)
+	}
+
+	return json.Unmarshal(data, i)
+}
+
+func toDBTemplate(bus templatebus.Template) template {
+	dbItems := make(items, len(bus.Items))
+	for i, itm := range bus.Items {
+		dbItems[i] = item{
+			Description: itm.Description,
+			DueDays:     itm.DueDays,
+			Priority:    itm.Priority.String(),
+			List:        itm.List.String(),
+			Labels:      label.ParseToString(itm.Labels),
+			Checklist:   itm.Checklist,
+			Assignee:    itm.Assignee,
+		}
+	}
+
+	return template{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		UserID:      bus.UserID,
+		Name:        bus.Name,
+		Items:       dbItems,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusTemplate(db template) (templatebus.Template, error) {
+	busItems := make([]templatebus.Item, len(db.Items))
+	for i, itm := range db.Items {
+		var pri priority.Priority
+		if itm.Priority != "" {
+			var err error
+			if pri, err = priority.Parse(itm.Priority); err != nil {
+				return templatebus.Template{}, fmt.Errorf("parse priority: %w", err)
+			}
+		}
+
+		list, err := name.ParseNull(itm.List)
+		if err != nil {
+			return templatebus.Template{}, fmt.Errorf("parse list: %w", err)
+		}
+
+		labels, err := label.ParseMany(itm.Labels)
+		if err != nil {
+			return templatebus.Template{}, fmt.Errorf("parse labels: %w", err)
+		}
+
+		busItems[i] = templatebus.Item{
+			Description: itm.Description,
+			DueDays:     itm.DueDays,
+			Priority:    pri,
+			List:        list,
+			Labels:      labels,
+			Checklist:   itm.Checklist,
+			Assignee:    itm.Assignee,
+		}
+	}
+
+	tmpl := templatebus.Template{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		Name:        db.Name,
+		Items:       busItems,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return tmpl, nil
+}
+
+func toBusTemplates(dbs []template) ([]templatebus.Template, error) {
+	bus := make([]templatebus.Template, len(dbs))
+	for i, db := range dbs {
+		tmpl, err := toBusTemplate(db)
+		if err != nil {
+			return nil, err
+		}
+		bus[i] = tmpl
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,155 @@
+// Package templatedb contains template related CRUD functionality.
+package templatedb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// orgScope limits a query to the templates of the organization in :org_id.
+// Every template passes when it is null, which is how work that spans
+// organizations reads.
+const orgScope = "(CAST(:org_id AS UUID) IS NULL OR org_id = CAST(:org_id AS UUID))"
+
+// Store manages the set of APIs for template database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (templatebus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new template into the database.
+func (s *Store) Create(ctx context.Context, tmpl templatebus.Template) error {
+	const q = `
+	INSERT INTO templates
+		(template_id, org_id, user_id, name, items, date_created, date_updated)
+	VALUES
+		(:template_id, :org_id, :user_id, :name, :items, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTemplate(tmpl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a template document in the database.
+func (s *Store) Update(ctx context.Context, tmpl templatebus.Template) error {
+	const q = `
+	UPDATE
+		templates
+	SET
+		"name" = :name,
+		"items" = :items,
+		"date_updated" = :date_updated
+	WHERE
+		template_id = :template_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTemplate(tmpl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a template from the database.
+func (s *Store) Delete(ctx context.Context, tmpl templatebus.Template) error {
+	const q = `
+	DELETE FROM
+		templates
+	WHERE
+		template_id = :template_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTemplate(tmpl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified template from the database.
+func (s *Store) QueryByID(ctx context.Context, templateID uuid.UUID) (templatebus.Template, error) {
+	data := struct {
+		ID    string        `db:"template_id"`
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		ID:    templateID.String(),
+		OrgID: sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		template_id, org_id, user_id, name, items, date_created, date_updated
+	FROM
+		templates
+	WHERE
+		template_id = :template_id AND ` + orgScope
+
+	var dbTmpl template
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTmpl); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return templatebus.Template{}, fmt.Errorf("db: %w", templatebus.ErrNotFound)
+		}
+		return templatebus.Template{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusTemplate(dbTmpl)
+}
+
+// QueryByUserID gets the templates of the specified user.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]templatebus.Template, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: userID.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		template_id, org_id, user_id, name, items, date_created, date_updated
+	FROM
+		templates
+	WHERE
+		user_id = :user_id AND ` + orgScope + `
+	ORDER BY
+		name, date_created`
+
+	var dbTmpls []template
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbTmpls); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusTemplates(dbTmpls)
+}
@@ -0,0 +1,278 @@
+// Package templatebus provides business access to todo templates and the
+// creation of their todo items.
+package templatebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound           = errors.New("template not found")
+	ErrNoItems            = errors.New("template has no items")
+	ErrTooManyItems       = errors.New("template has too many items")
+	ErrInvalidPlaceholder = errors.New("invalid assignee placeholder")
+	ErrMissingAssignee    = errors.New("assignee placeholder has no user")
+	ErrUnknownPlaceholder = errors.New("assignee placeholder not used by the template")
+	ErrMissingStartDate   = errors.New("start date is required")
+)
+
+// MaxItems is the largest number of items a template can hold.
+const MaxItems = 200
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, tmpl Template) error
+	Update(ctx context.Context, tmpl Template) error
+	Delete(ctx context.Context, tmpl Template) error
+	QueryByID(ctx context.Context, templateID uuid.UUID) (Template, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Template, error)
+}
+
+// Business manages the set of APIs for template access.
+type Business struct {
+	log     *logger.Logger
+	storer  Storer
+	todoBus *todobus.Business
+}
+
+// NewBusiness constructs a template business API for use.
+func NewBusiness(log *logger.Logger, todoBus *todobus.Business, storer Storer) *Business {
+	return &Business{
+		log:     log,
+		storer:  storer,
+		todoBus: todoBus,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:     b.log,
+		storer:  storer,
+		todoBus: b.todoBus,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new template to the system.
+func (b *Business) Create(ctx context.Context, nt NewTemplate) (Template, error) {
+	ctx, span := otel.AddSpan(ctx, "business.templatebus.create")
+	defer span.End()
+
+	if err := checkItems(nt.Items); err != nil {
+		return Template{}, err
+	}
+
+	now := time.Now()
+
+	tmpl := Template{
+		ID:          uuid.New(),
+		OrgID:       orgOf(ctx),
+		UserID:      nt.UserID,
+		Name:        nt.Name,
+		Items:       nt.Items,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, tmpl); err != nil {
+		return Template{}, fmt.Errorf("create: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Update modifies information about a template.
+func (b *Business) Update(ctx context.Context, tmpl Template, ut UpdateTemplate) (Template, error) {
+	ctx, span := otel.AddSpan(ctx, "business.templatebus.update")
+	defer span.End()
+
+	if ut.Name != nil {
+		tmpl.Name = *ut.Name
+	}
+
+	if ut.Items != nil {
+		if err := checkItems(ut.Items); err != nil {
+			return Template{}, err
+		}
+		tmpl.Items = ut.Items
+	}
+
+	tmpl.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, tmpl); err != nil {
+		return Template{}, fmt.Errorf("update: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Delete removes the specified template. Items created from it are kept.
+func (b *Business) Delete(ctx context.Context, tmpl Template) error {
+	ctx, span := otel.AddSpan(ctx, "business.templatebus.delete")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, tmpl); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the template by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, templateID uuid.UUID) (Template, error) {
+	ctx, span := otel.AddSpan(ctx, "business.templatebus.querybyid")
+	defer span.End()
+
+	tmpl, err := b.storer.QueryByID(ctx, templateID)
+	if err != nil {
+		return Template{}, fmt.Errorf("query: templateID[%s]: %w", templateID, err)
+	}
+
+	return tmpl, nil
+}
+
+// QueryByUserID retrieves the templates of the specified user.
+func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Template, error) {
+	ctx, span := otel.AddSpan(ctx, "business.templatebus.querybyuserid")
+	defer span.End()
+
+	tmpls, err := b.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return tmpls, nil
+}
+
+// Preview returns the todo items the template would create, without
+// creating them.
+func (b *Business) Preview(ctx context.Context, tmpl Template, inst Instantiation) ([]todobus.NewTodoItem, error) {
+	_, span := otel.AddSpan(ctx, "business.templatebus.preview")
+	defer span.End()
+
+	return expand(tmpl, inst)
+}
+
+// Instantiate creates the todo items of the template. The items are created
+// in one transaction, so either all of them exist afterwards or none do.
+func (b *Business) Instantiate(ctx context.Context, tmpl Template, inst Instantiation) ([]todobus.TodoItem, error) {
+	ctx, span := otel.AddSpan(ctx, "business.templatebus.instantiate")
+	defer span.End()
+
+	nts, err := expand(tmpl, inst)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := b.todoBus.CreateBatch(ctx, nts)
+	if err != nil {
+		return nil, fmt.Errorf("createbatch: templateID[%s]: %w", tmpl.ID, err)
+	}
+
+	return items, nil
+}
+
+// =============================================================================
+
+var placeholderRegEx = regexp.MustCompile("^[a-z0-9][a-z0-9_-]{0,29}$")
+
+func checkItems(items []Item) error {
+	if len(items) == 0 {
+		return ErrNoItems
+	}
+
+	if len(items) > MaxItems {
+		return fmt.Errorf("%d items, at most %d: %w", len(items), MaxItems, ErrTooManyItems)
+	}
+
+	for _, item := range items {
+		if item.Assignee != "" && !placeholderRegEx.MatchString(item.Assignee) {
+			return fmt.Errorf("placeholder[%s]: %w", item.Assignee, ErrInvalidPlaceholder)
+		}
+	}
+
+	return nil
+}
+
+// expand turns the items of the template into new todo items for the
+// instantiation. All-day due dates are counted from the calendar date of the
+// start, so the time of day and zone it was given in do not matter.
+func expand(tmpl Template, inst Instantiation) ([]todobus.NewTodoItem, error) {
+	if inst.Start.IsZero() {
+		return nil, ErrMissingStartDate
+	}
+
+	placeholders := tmpl.Placeholders()
+
+	for _, placeholder := range placeholders {
+		if inst.Assignees[placeholder] == uuid.Nil {
+			return nil, fmt.Errorf("placeholder[%s]: %w", placeholder, ErrMissingAssignee)
+		}
+	}
+
+	for placeholder := range inst.Assignees {
+		if !slices.Contains(placeholders, placeholder) {
+			return nil, fmt.Errorf("placeholder[%s]: %w", placeholder, ErrUnknownPlaceholder)
+		}
+	}
+
+	start := time.Date(inst.Start.Year(), inst.Start.Month(), inst.Start.Day(), 0, 0, 0, 0, time.UTC)
+
+	nts := make([]todobus.NewTodoItem, len(tmpl.Items))
+	for i, item := range tmpl.Items {
+		nt := todobus.NewTodoItem{
+			UserID:      inst.UserID,
+			AssigneeID:  inst.Assignees[item.Assignee],
+			Description: item.Description,
+			Priority:    item.Priority,
+			List:        item.List,
+			Labels:      item.Labels,
+		}
+
+		if item.DueDays != nil {
+			nt.DueDate = start.AddDate(0, 0, *item.DueDays)
+			nt.AllDay = true
+		}
+
+		for _, text := range item.Checklist {
+			nt.Checklist = append(nt.Checklist, todobus.ChecklistItem{Text: text})
+		}
+
+		nts[i] = nt
+	}
+
+	return nts, nil
+}
+
+// orgOf returns the organization new templates go into, which is the one
+// access is scoped to or the default one for work that isn't.
+func orgOf(ctx context.Context) uuid.UUID {
+	if orgID, ok := sqldb.GetOrgID(ctx); ok {
+		return orgID
+	}
+
+	return orgbus.DefaultID
+}
@@ -0,0 +1,182 @@
+package templatebus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+)
+
+func Test_Expand(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %s", err)
+	}
+
+	days := func(n int) *int {
+		return &n
+	}
+
+	userID := uuid.New()
+	managerID := uuid.New()
+	buddyID := uuid.New()
+
+	tmpl := Template{
+		Name: "Employee onboarding",
+		Items: []Item{
+			{
+				Description: "Order laptop",
+				DueDays:     days(-3),
+				Priority:    priority.P1,
+				Labels:      []label.Label{label.MustParse("it")},
+			},
+			{
+				Description: "Welcome meeting",
+				DueDays:     days(0),
+				Assignee:    "manager",
+				Checklist:   []string{"Introduce the team", "Walk through the handbook"},
+			},
+			{
+				Description: "First code review",
+				DueDays:     days(14),
+				List:        name.MustParseNull("Engineering"),
+				Assignee:    "buddy",
+			},
+			{
+				Description: "Lunch with the team",
+				Assignee:    "manager",
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		inst Instantiation
+		exp  []todobus.NewTodoItem
+		err  error
+	}{
+		{
+			name: "basic",
+			inst: Instantiation{
+				UserID: userID,
+
+				// Late in the evening in New York is already the next day in
+				// UTC, but the calendar date given is the one that counts.
+				Start:     time.Date(2024, time.January, 31, 22, 0, 0, 0, newYork),
+				Assignees: map[string]uuid.UUID{"manager": managerID, "buddy": buddyID},
+			},
+			exp: []todobus.NewTodoItem{
+				{
+					UserID:      userID,
+					Description: "Order laptop",
+					DueDate:     time.Date(2024, time.January, 28, 0, 0, 0, 0, time.UTC),
+					AllDay:      true,
+					Priority:    priority.P1,
+					Labels:      []label.Label{label.MustParse("it")},
+				},
+				{
+					UserID:      userID,
+					AssigneeID:  managerID,
+					Description: "Welcome meeting",
+					DueDate:     time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC),
+					AllDay:      true,
+					Checklist: []todobus.ChecklistItem{
+						{Text: "Introduce the team"},
+						{Text: "Walk through the handbook"},
+					},
+				},
+				{
+					UserID:      userID,
+					AssigneeID:  buddyID,
+					Description: "First code review",
+					DueDate:     time.Date(2024, time.February, 14, 0, 0, 0, 0, time.UTC),
+					AllDay:      true,
+					List:        name.MustParseNull("Engineering"),
+				},
+				{
+					UserID:      userID,
+					AssigneeID:  managerID,
+					Description: "Lunch with the team",
+				},
+			},
+		},
+		{
+			name: "missing-assignee",
+			inst: Instantiation{
+				UserID:    userID,
+				Start:     time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC),
+				Assignees: map[string]uuid.UUID{"manager": managerID},
+			},
+			err: ErrMissingAssignee,
+		},
+		{
+			name: "unknown-placeholder",
+			inst: Instantiation{
+				UserID:    userID,
+				Start:     time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC),
+				Assignees: map[string]uuid.UUID{"manager": managerID, "buddy": buddyID, "mentor": uuid.New()},
+			},
+			err: ErrUnknownPlaceholder,
+		},
+		{
+			name: "missing-start",
+			inst: Instantiation{
+				UserID:    userID,
+				Assignees: map[string]uuid.UUID{"manager": managerID, "buddy": buddyID},
+			},
+			err: ErrMissingStartDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expand(tmpl, tt.inst)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, exp %v", err, tt.err)
+			}
+
+			if diff := cmp.Diff(got, tt.exp); diff != "" {
+				t.Errorf("got diff:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_CheckItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []Item
+		err   error
+	}{
+		{
+			name:  "valid",
+			items: []Item{{Description: "a", Assignee: "release-manager"}},
+		},
+		{
+			name: "no-items",
+			err:  ErrNoItems,
+		},
+		{
+			name:  "too-many-items",
+			items: make([]Item, MaxItems+1),
+			err:   ErrTooManyItems,
+		},
+		{
+			name:  "invalid-placeholder",
+			items: []Item{{Description: "a", Assignee: "Release Manager"}},
+			err:   ErrInvalidPlaceholder,
+		},
+	}
+
+	for _, tt := range tests {
+		if err := checkItems(tt.items); !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, exp %v", tt.name, err, tt.err)
+		}
+	}
+}
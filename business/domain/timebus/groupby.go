@@ -0,0 +1,39 @@
+package timebus
+
+import "fmt"
+
+// The set of dimensions a time report can be grouped by.
+var (
+	GroupByList  = newGroupBy("list")
+	GroupByLabel = newGroupBy("label")
+	GroupByUser  = newGroupBy("user")
+)
+
+// Set of known groupings.
+var groupBys = make(map[string]GroupBy)
+
+// GroupBy represents the dimension a time report is grouped by.
+type GroupBy struct {
+	value string
+}
+
+func newGroupBy(groupBy string) GroupBy {
+	g := GroupBy{groupBy}
+	groupBys[groupBy] = g
+	return g
+}
+
+// ParseGroupBy parses the string value and returns a grouping if one exists.
+func ParseGroupBy(value string) (GroupBy, error) {
+	g, exists := groupBys[value]
+	if !exists {
+		return GroupBy{}, fmt.Errorf("invalid group by %q", value)
+	}
+
+	return g, nil
+}
+
+// String returns the name of the grouping.
+func (g GroupBy) String() string {
+	return g.value
+}
@@ -0,0 +1,113 @@
+package timebus
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/money"
+	"github.com/himynamej/todo/business/types/quantity"
+)
+
+// Entry represents time a user spent on a todo item. A running timer is an
+// entry without an end. Rate is the hourly rate the time is billed at and
+// the time is not billable when it is nil.
+type Entry struct {
+	ID          uuid.UUID
+	TodoID      uuid.UUID
+	UserID      uuid.UUID
+	Start       time.Time
+	End         time.Time
+	Note        string
+	Rate        *money.Money
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Running reports whether the entry is a timer that has not been stopped.
+func (e Entry) Running() bool {
+	return e.End.IsZero()
+}
+
+// Minutes returns the length of the entry rounded to the nearest minute. A
+// running timer counts up to now, but no further than the longest an entry
+// can be.
+func (e Entry) Minutes() quantity.Quantity {
+	if e.Running() {
+		return toMinutes(min(time.Since(e.Start), MaxEntryDuration))
+	}
+
+	return toMinutes(e.End.Sub(e.Start))
+}
+
+// Amount returns what the entry bills at its rate, rounded to the cent.
+// Entries without a rate bill nothing.
+func (e Entry) Amount() money.Money {
+	if e.Rate == nil {
+		return money.Money{}
+	}
+
+	return toAmount(e.Minutes(), *e.Rate)
+}
+
+// NewTimer contains information needed to start a timer on a todo item.
+type NewTimer struct {
+	TodoID uuid.UUID
+	UserID uuid.UUID
+	Note   string
+	Rate   *money.Money
+}
+
+// NewEntry contains information needed to log time that was spent on a todo
+// item.
+type NewEntry struct {
+	TodoID uuid.UUID
+	UserID uuid.UUID
+	Start  time.Time
+	End    time.Time
+	Note   string
+	Rate   *money.Money
+}
+
+// UpdateEntry contains information needed to update an entry. Giving a
+// running timer an end stops it.
+type UpdateEntry struct {
+	Start *time.Time
+	End   *time.Time
+	Note  *string
+	Rate  *money.Money
+}
+
+// =============================================================================
+
+// ReportFilter holds the fields a time report is filtered on. The date range
+// applies to the start of the entries, inclusive of the start and exclusive
+// of the end. UserID limits the report to the time the user logged.
+type ReportFilter struct {
+	UserID    *uuid.UUID
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// ReportRow represents the time logged and the amount billed for the entries
+// sharing a key, such as a list name, a label or a user. An empty key
+// collects the entries on items without a list.
+type ReportRow struct {
+	Key     string
+	Minutes quantity.Quantity
+	Amount  money.Money
+}
+
+// =============================================================================
+
+func toMinutes(d time.Duration) quantity.Quantity {
+	return quantity.MustParse(int(math.Round(d.Minutes())))
+}
+
+func toAmount(minutes quantity.Quantity, rate money.Money) money.Money {
+	amount := math.Round(float64(minutes.Value())*rate.Value()/60*100) / 100
+
+	// Entries and rates are capped, so the amount of one entry stays well
+	// inside what money can hold.
+	return money.MustParse(amount)
+}
@@ -0,0 +1,110 @@
+package timebus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/business/types/money"
+)
+
+func Test_Amount(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	rate := func(v float64) *money.Money {
+		m := money.MustParse(v)
+		return &m
+	}
+
+	tests := []struct {
+		name    string
+		entry   Entry
+		minutes int
+		amount  float64
+	}{
+		{
+			name:    "no-rate",
+			entry:   Entry{Start: start, End: start.Add(90 * time.Minute)},
+			minutes: 90,
+		},
+		{
+			name:    "hours",
+			entry:   Entry{Start: start, End: start.Add(90 * time.Minute), Rate: rate(120)},
+			minutes: 90,
+			amount:  180,
+		},
+		{
+			name:    "rounded-minutes",
+			entry:   Entry{Start: start, End: start.Add(10*time.Minute + 31*time.Second), Rate: rate(100)},
+			minutes: 11,
+			amount:  18.33,
+		},
+		{
+			name:    "rounded-cents",
+			entry:   Entry{Start: start, End: start.Add(7 * time.Minute), Rate: rate(95.5)},
+			minutes: 7,
+			amount:  11.14,
+		},
+		{
+			name:    "running-capped",
+			entry:   Entry{Start: time.Now().Add(-72 * time.Hour), Rate: rate(60)},
+			minutes: 24 * 60,
+			amount:  1440,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.Minutes().Value(); got != tt.minutes {
+			t.Errorf("%s: got %d minutes, exp %d", tt.name, got, tt.minutes)
+		}
+
+		if got := tt.entry.Amount().Value(); got != tt.amount {
+			t.Errorf("%s: got amount %.2f, exp %.2f", tt.name, got, tt.amount)
+		}
+	}
+}
+
+func Test_CheckRange(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		err   error
+	}{
+		{
+			name:  "valid",
+			start: now.Add(-2 * time.Hour),
+			end:   now.Add(-time.Hour),
+		},
+		{
+			name:  "running",
+			start: now.Add(-2 * time.Hour),
+		},
+		{
+			name:  "reversed",
+			start: now.Add(-time.Hour),
+			end:   now.Add(-2 * time.Hour),
+			err:   ErrInvalidRange,
+		},
+		{
+			name:  "future",
+			start: now.Add(-time.Hour),
+			end:   now.Add(time.Hour),
+			err:   ErrInFuture,
+		},
+		{
+			name:  "too-long",
+			start: now.Add(-MaxEntryDuration - 2*time.Hour),
+			end:   now.Add(-time.Hour),
+			err:   ErrTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		if err := checkRange(tt.start, tt.end); !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, exp %v", tt.name, err, tt.err)
+		}
+	}
+}
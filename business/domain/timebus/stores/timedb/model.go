@@ -0,0 +1,113 @@
+package timedb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/types/money"
+	"github.com/himynamej/todo/business/types/quantity"
+)
+
+type entry struct {
+	ID          uuid.UUID       `db:"entry_id"`
+	TodoID      uuid.UUID       `db:"item_id"`
+	UserID      uuid.UUID       `db:"user_id"`
+	Start       time.Time       `db:"start_time"`
+	End         sql.NullTime    `db:"end_time"`
+	Note        string          `db:"note"`
+	Rate        sql.NullFloat64 `db:"hourly_rate"`
+	DateCreated time.Time       `db:"date_created"`
+	DateUpdated time.Time       `db:"date_updated"`
+}
+
+func toDBEntry(bus timebus.Entry) entry {
+	db := entry{
+		ID:          bus.ID,
+		TodoID:      bus.TodoID,
+		UserID:      bus.UserID,
+		Start:       bus.Start.UTC(),
+		End:         sql.NullTime{Time: bus.End.UTC(), Valid: !bus.Running()},
+		Note:        bus.Note,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+
+	if bus.Rate != nil {
+		db.Rate = sql.NullFloat64{Float64: bus.Rate.Value(), Valid: true}
+	}
+
+	return db
+}
+
+func toBusEntry(db entry) (timebus.Entry, error) {
+	bus := timebus.Entry{
+		ID:          db.ID,
+		TodoID:      db.TodoID,
+		UserID:      db.UserID,
+		Start:       db.Start.In(time.Local),
+		Note:        db.Note,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	if db.End.Valid {
+		bus.End = db.End.Time.In(time.Local)
+	}
+
+	if db.Rate.Valid {
+		rate, err := money.Parse(db.Rate.Float64)
+		if err != nil {
+			return timebus.Entry{}, fmt.Errorf("parse rate: %w", err)
+		}
+		bus.Rate = &rate
+	}
+
+	return bus, nil
+}
+
+func toBusEntries(dbs []entry) ([]timebus.Entry, error) {
+	bus := make([]timebus.Entry, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusEntry(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+type reportRow struct {
+	Key     string  `db:"key"`
+	Minutes int     `db:"minutes"`
+	Amount  float64 `db:"amount"`
+}
+
+func toBusReportRows(dbs []reportRow) ([]timebus.ReportRow, error) {
+	bus := make([]timebus.ReportRow, len(dbs))
+
+	for i, db := range dbs {
+		minutes, err := quantity.Parse(db.Minutes)
+		if err != nil {
+			return nil, fmt.Errorf("key[%s]: %w: %w", db.Key, timebus.ErrReportTooLarge, err)
+		}
+
+		amount, err := money.Parse(db.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("key[%s]: %w: %w", db.Key, timebus.ErrReportTooLarge, err)
+		}
+
+		bus[i] = timebus.ReportRow{
+			Key:     db.Key,
+			Minutes: minutes,
+			Amount:  amount,
+		}
+	}
+
+	return bus, nil
+}
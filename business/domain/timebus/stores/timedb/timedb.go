@@ -0,0 +1,270 @@
+// Package timedb contains time entry related CRUD functionality.
+package timedb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// minutes is the length of a stopped entry rounded to the nearest minute,
+// the way timebus rounds it.
+const minutes = `ROUND(CAST(EXTRACT(EPOCH FROM (e.end_time - e.start_time)) AS NUMERIC) / 60)`
+
+// orgScope limits a query to the entries on the items of the organization in
+// :org_id. Every entry passes when it is null, which is how work that spans
+// organizations reads.
+const orgScope = `item_id IN (
+		SELECT item_id FROM todo_items
+		WHERE CAST(:org_id AS UUID) IS NULL OR org_id = CAST(:org_id AS UUID)
+	)`
+
+var groupByColumns = map[timebus.GroupBy]string{
+	timebus.GroupByList:  "COALESCE(t.list, '')",
+	timebus.GroupByLabel: "l.label",
+	timebus.GroupByUser:  "CAST(e.user_id AS TEXT)",
+}
+
+// Store manages the set of APIs for time entry database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (timebus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new time entry into the database. A second running timer
+// for the same user is rejected by a unique index.
+func (s *Store) Create(ctx context.Context, e timebus.Entry) error {
+	const q = `
+	INSERT INTO time_entries
+		(entry_id, item_id, user_id, start_time, end_time, note, hourly_rate, date_created, date_updated)
+	VALUES
+		(:entry_id, :item_id, :user_id, :start_time, :end_time, :note, :hourly_rate, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBEntry(e)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", timebus.ErrTimerRunning)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a time entry document in the database.
+func (s *Store) Update(ctx context.Context, e timebus.Entry) error {
+	const q = `
+	UPDATE
+		time_entries
+	SET
+		"start_time" = :start_time,
+		"end_time" = :end_time,
+		"note" = :note,
+		"hourly_rate" = :hourly_rate,
+		"date_updated" = :date_updated
+	WHERE
+		entry_id = :entry_id AND ` + orgScope
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, scopedEntry(ctx, e)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a time entry from the database.
+func (s *Store) Delete(ctx context.Context, e timebus.Entry) error {
+	const q = `
+	DELETE FROM
+		time_entries
+	WHERE
+		entry_id = :entry_id AND ` + orgScope
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, scopedEntry(ctx, e)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified time entry from the database.
+func (s *Store) QueryByID(ctx context.Context, entryID uuid.UUID) (timebus.Entry, error) {
+	data := struct {
+		ID    string        `db:"entry_id"`
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		ID:    entryID.String(),
+		OrgID: sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		entry_id, item_id, user_id, start_time, end_time, note, hourly_rate, date_created, date_updated
+	FROM
+		time_entries
+	WHERE
+		entry_id = :entry_id AND ` + orgScope
+
+	var dbEntry entry
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbEntry); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return timebus.Entry{}, fmt.Errorf("db: %w", timebus.ErrNotFound)
+		}
+		return timebus.Entry{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusEntry(dbEntry)
+}
+
+// QueryByTodoID gets the time entries of the specified todo item, latest
+// first.
+func (s *Store) QueryByTodoID(ctx context.Context, todoID uuid.UUID) ([]timebus.Entry, error) {
+	data := struct {
+		TodoID string        `db:"item_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		TodoID: todoID.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		entry_id, item_id, user_id, start_time, end_time, note, hourly_rate, date_created, date_updated
+	FROM
+		time_entries
+	WHERE
+		item_id = :item_id AND ` + orgScope + `
+	ORDER BY
+		start_time DESC, entry_id`
+
+	var dbEntries []entry
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEntries(dbEntries)
+}
+
+// QueryRunning gets the timer the specified user has running.
+func (s *Store) QueryRunning(ctx context.Context, userID uuid.UUID) (timebus.Entry, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: userID.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		entry_id, item_id, user_id, start_time, end_time, note, hourly_rate, date_created, date_updated
+	FROM
+		time_entries
+	WHERE
+		user_id = :user_id AND end_time IS NULL AND ` + orgScope
+
+	var dbEntry entry
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbEntry); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return timebus.Entry{}, fmt.Errorf("db: %w", timebus.ErrNoTimer)
+		}
+		return timebus.Entry{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusEntry(dbEntry)
+}
+
+// Report sums the minutes and amounts of the stopped entries that start in
+// the date range per key. Amounts are rounded to the cent per entry before
+// they are added up, so they match the amounts shown on the entries.
+func (s *Store) Report(ctx context.Context, filter timebus.ReportFilter, groupBy timebus.GroupBy) ([]timebus.ReportRow, error) {
+	key, exists := groupByColumns[groupBy]
+	if !exists {
+		return nil, fmt.Errorf("group by %q does not exist", groupBy)
+	}
+
+	data := map[string]any{
+		"start_date": filter.StartDate.UTC(),
+		"end_date":   filter.EndDate.UTC(),
+		"org_id":     sqldb.OrgArg(ctx),
+	}
+
+	buf := bytes.NewBufferString(`
+	SELECT
+		` + key + ` AS key,
+		CAST(SUM(` + minutes + `) AS BIGINT) AS minutes,
+		CAST(COALESCE(SUM(ROUND(` + minutes + ` * e.hourly_rate / 60, 2)), 0) AS FLOAT8) AS amount
+	FROM
+		time_entries e
+	JOIN
+		todo_items t ON t.item_id = e.item_id`)
+
+	if groupBy == timebus.GroupByLabel {
+		buf.WriteString(`
+	CROSS JOIN LATERAL
+		unnest(t.labels) AS l(label)`)
+	}
+
+	buf.WriteString(`
+	WHERE
+		e.end_time IS NOT NULL AND e.start_time >= :start_date AND e.start_time < :end_date AND
+		(CAST(:org_id AS UUID) IS NULL OR t.org_id = CAST(:org_id AS UUID))`)
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		buf.WriteString(" AND e.user_id = :user_id")
+	}
+
+	buf.WriteString(`
+	GROUP BY 1
+	ORDER BY 2 DESC, 1`)
+
+	var dbRows []reportRow
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbRows); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusReportRows(dbRows)
+}
+
+// scopedEntry returns the entry along with the organization the context
+// scopes access to, for the queries that use orgScope.
+func scopedEntry(ctx context.Context, e timebus.Entry) any {
+	return struct {
+		entry
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		entry: toDBEntry(e),
+		OrgID: sqldb.OrgArg(ctx),
+	}
+}
@@ -0,0 +1,307 @@
+// Package timebus provides business access to the time users track against
+// todo items and the reports billing is done from.
+package timebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/types/money"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound       = errors.New("time entry not found")
+	ErrTimerRunning   = errors.New("a timer is already running")
+	ErrNoTimer        = errors.New("no timer is running")
+	ErrInvalidRange   = errors.New("start must be before end")
+	ErrInFuture       = errors.New("time entry is in the future")
+	ErrTooLong        = errors.New("time entry is too long")
+	ErrInvalidRate    = errors.New("hourly rate is too high")
+	ErrReportTooLong  = errors.New("report date range is too long")
+	ErrReportTooLarge = errors.New("report totals are too large")
+)
+
+// MaxEntryDuration is the longest a single entry can be. A timer left
+// running for longer has to be given its real end by hand.
+const MaxEntryDuration = 24 * time.Hour
+
+// MaxReportRange is the longest date range a report covers.
+const MaxReportRange = 366 * 24 * time.Hour
+
+// MaxRate is the highest hourly rate time can be billed at.
+var MaxRate = money.MustParse(10_000)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, e Entry) error
+	Update(ctx context.Context, e Entry) error
+	Delete(ctx context.Context, e Entry) error
+	QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, error)
+	QueryByTodoID(ctx context.Context, todoID uuid.UUID) ([]Entry, error)
+	QueryRunning(ctx context.Context, userID uuid.UUID) (Entry, error)
+	Report(ctx context.Context, filter ReportFilter, groupBy GroupBy) ([]ReportRow, error)
+}
+
+// Business manages the set of APIs for time entry access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a time business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// StartTimer starts a timer for the user on the todo item. A user has at
+// most one timer running, so it fails while another one is.
+func (b *Business) StartTimer(ctx context.Context, nt NewTimer) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.starttimer")
+	defer span.End()
+
+	if err := checkRate(nt.Rate); err != nil {
+		return Entry{}, err
+	}
+
+	now := time.Now()
+
+	e := Entry{
+		ID:          uuid.New(),
+		TodoID:      nt.TodoID,
+		UserID:      nt.UserID,
+		Start:       now,
+		Note:        nt.Note,
+		Rate:        nt.Rate,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("create: %w", err)
+	}
+
+	return e, nil
+}
+
+// StopTimer stops the timer the user has running.
+func (b *Business) StopTimer(ctx context.Context, userID uuid.UUID) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.stoptimer")
+	defer span.End()
+
+	e, err := b.storer.QueryRunning(ctx, userID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("queryrunning: userID[%s]: %w", userID, err)
+	}
+
+	now := time.Now()
+
+	return b.Update(ctx, e, UpdateEntry{End: &now})
+}
+
+// QueryRunning finds the timer the user has running.
+func (b *Business) QueryRunning(ctx context.Context, userID uuid.UUID) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.queryrunning")
+	defer span.End()
+
+	e, err := b.storer.QueryRunning(ctx, userID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("queryrunning: userID[%s]: %w", userID, err)
+	}
+
+	return e, nil
+}
+
+// Create logs time that was spent on a todo item.
+func (b *Business) Create(ctx context.Context, ne NewEntry) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.create")
+	defer span.End()
+
+	if err := checkRange(ne.Start, ne.End); err != nil {
+		return Entry{}, err
+	}
+
+	if err := checkRate(ne.Rate); err != nil {
+		return Entry{}, err
+	}
+
+	now := time.Now()
+
+	e := Entry{
+		ID:          uuid.New(),
+		TodoID:      ne.TodoID,
+		UserID:      ne.UserID,
+		Start:       ne.Start,
+		End:         ne.End,
+		Note:        ne.Note,
+		Rate:        ne.Rate,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("create: %w", err)
+	}
+
+	return e, nil
+}
+
+// Update modifies information about an entry.
+func (b *Business) Update(ctx context.Context, e Entry, ue UpdateEntry) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.update")
+	defer span.End()
+
+	if ue.Start != nil {
+		e.Start = *ue.Start
+	}
+
+	if ue.End != nil {
+		e.End = *ue.End
+	}
+
+	if ue.Note != nil {
+		e.Note = *ue.Note
+	}
+
+	if ue.Rate != nil {
+		if err := checkRate(ue.Rate); err != nil {
+			return Entry{}, err
+		}
+		e.Rate = ue.Rate
+	}
+
+	if err := checkRange(e.Start, e.End); err != nil {
+		return Entry{}, err
+	}
+
+	e.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("update: %w", err)
+	}
+
+	return e, nil
+}
+
+// Delete removes the specified entry.
+func (b *Business) Delete(ctx context.Context, e Entry) error {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.delete")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, e); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the entry by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.querybyid")
+	defer span.End()
+
+	e, err := b.storer.QueryByID(ctx, entryID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("query: entryID[%s]: %w", entryID, err)
+	}
+
+	return e, nil
+}
+
+// QueryByTodoID retrieves the entries logged on the specified todo item.
+func (b *Business) QueryByTodoID(ctx context.Context, todoID uuid.UUID) ([]Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.querybytodoid")
+	defer span.End()
+
+	es, err := b.storer.QueryByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("query: todoID[%s]: %w", todoID, err)
+	}
+
+	return es, nil
+}
+
+// Report returns the time logged in the date range and the amount it bills,
+// grouped by list, label or user. Running timers are left out until they are
+// stopped. Entries on items with several labels count once for each label,
+// and entries on items without one are left out of the label report.
+func (b *Business) Report(ctx context.Context, filter ReportFilter, groupBy GroupBy) ([]ReportRow, error) {
+	ctx, span := otel.AddSpan(ctx, "business.timebus.report")
+	defer span.End()
+
+	if !filter.StartDate.Before(filter.EndDate) {
+		return nil, ErrInvalidRange
+	}
+
+	if filter.EndDate.Sub(filter.StartDate) > MaxReportRange {
+		return nil, fmt.Errorf("more than %s: %w", MaxReportRange, ErrReportTooLong)
+	}
+
+	rows, err := b.storer.Report(ctx, filter, groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("report: %w", err)
+	}
+
+	return rows, nil
+}
+
+// =============================================================================
+
+// checkRange validates the span of an entry. A running timer only has a
+// start.
+func checkRange(start time.Time, end time.Time) error {
+	now := time.Now()
+
+	if start.After(now) || end.After(now) {
+		return ErrInFuture
+	}
+
+	if end.IsZero() {
+		return nil
+	}
+
+	if !start.Before(end) {
+		return ErrInvalidRange
+	}
+
+	if end.Sub(start) > MaxEntryDuration {
+		return fmt.Errorf("more than %s: %w", MaxEntryDuration, ErrTooLong)
+	}
+
+	return nil
+}
+
+func checkRate(rate *money.Money) error {
+	if rate != nil && rate.Value() > MaxRate.Value() {
+		return fmt.Errorf("more than %s: %w", MaxRate, ErrInvalidRate)
+	}
+
+	return nil
+}
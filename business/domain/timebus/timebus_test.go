@@ -0,0 +1,238 @@
+package timebus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/money"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/quantity"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Time(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Time")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, report(db.BusDomain, sd), "report")
+	unitest.Run(t, timer(db.BusDomain, sd), "timer")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	newItems := []todobus.NewTodoItem{
+		{
+			UserID:      usrs[0].ID,
+			Description: "Client website",
+			List:        name.MustParseNull("Acme"),
+			Labels:      []label.Label{label.MustParse("design"), label.MustParse("frontend")},
+		},
+		{
+			UserID:      usrs[0].ID,
+			Description: "Client API",
+			List:        name.MustParseNull("Globex"),
+			Labels:      []label.Label{label.MustParse("backend")},
+		},
+	}
+
+	items := make([]todobus.TodoItem, len(newItems))
+	for i, nt := range newItems {
+		items[i], err = busDomain.Todo.Create(ctx, nt)
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding todo items : %w", err)
+		}
+	}
+
+	rate := money.MustParse(90)
+	start := time.Now().Add(-5 * time.Hour).Truncate(time.Minute)
+
+	newEntries := []timebus.NewEntry{
+		{
+			TodoID: items[0].ID,
+			UserID: usrs[0].ID,
+			Start:  start,
+			End:    start.Add(time.Hour),
+			Rate:   &rate,
+		},
+		{
+			TodoID: items[1].ID,
+			UserID: usrs[0].ID,
+			Start:  start.Add(time.Hour),
+			End:    start.Add(90 * time.Minute),
+			Rate:   &rate,
+		},
+		{
+			TodoID: items[1].ID,
+			UserID: usrs[1].ID,
+			Start:  start,
+			End:    start.Add(2 * time.Hour),
+		},
+	}
+
+	for _, ne := range newEntries {
+		if _, err := busDomain.Time.Create(ctx, ne); err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding time entries : %w", err)
+		}
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+		Todos: items,
+	}
+
+	return sd, nil
+}
+
+func lastDay() timebus.ReportFilter {
+	return timebus.ReportFilter{
+		StartDate: time.Now().Add(-24 * time.Hour),
+		EndDate:   time.Now(),
+	}
+}
+
+// =============================================================================
+
+func timer(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "one-running",
+			ExpResp: timebus.ErrTimerRunning,
+			ExcFunc: func(ctx context.Context) any {
+				nt := timebus.NewTimer{
+					TodoID: sd.Todos[0].ID,
+					UserID: sd.Users[0].ID,
+				}
+
+				if _, err := busDomain.Time.StartTimer(ctx, nt); err != nil {
+					return err
+				}
+
+				nt.TodoID = sd.Todos[1].ID
+
+				_, err := busDomain.Time.StartTimer(ctx, nt)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "stop",
+			ExpResp: timebus.ErrNoTimer,
+			ExcFunc: func(ctx context.Context) any {
+				e, err := busDomain.Time.StopTimer(ctx, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if e.Running() || e.TodoID != sd.Todos[0].ID {
+					return fmt.Errorf("unexpected entry %+v", e)
+				}
+
+				_, err = busDomain.Time.QueryRunning(ctx, sd.Users[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func report(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "bylist",
+			ExpResp: []timebus.ReportRow{
+				{Key: "Globex", Minutes: quantity.MustParse(150), Amount: money.MustParse(45)},
+				{Key: "Acme", Minutes: quantity.MustParse(60), Amount: money.MustParse(90)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				rows, err := busDomain.Time.Report(ctx, lastDay(), timebus.GroupByList)
+				if err != nil {
+					return err
+				}
+
+				return rows
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "bylabel-user",
+			ExpResp: []timebus.ReportRow{
+				{Key: "design", Minutes: quantity.MustParse(60), Amount: money.MustParse(90)},
+				{Key: "frontend", Minutes: quantity.MustParse(60), Amount: money.MustParse(90)},
+				{Key: "backend", Minutes: quantity.MustParse(30), Amount: money.MustParse(45)},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				filter := lastDay()
+				filter.UserID = &sd.Users[0].ID
+
+				rows, err := busDomain.Time.Report(ctx, filter, timebus.GroupByLabel)
+				if err != nil {
+					return err
+				}
+
+				return rows
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "too-long",
+			ExpResp: timebus.ErrReportTooLong,
+			ExcFunc: func(ctx context.Context) any {
+				filter := lastDay()
+				filter.StartDate = filter.EndDate.Add(-2 * timebus.MaxReportRange)
+
+				_, err := busDomain.Time.Report(ctx, filter, timebus.GroupByUser)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if err, _ := got.(error); !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,175 @@
+package todobus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/thumbnail"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// RenditionSizes are the renditions generated for image attachments, as the
+// longest side in pixels.
+var RenditionSizes = []int{64, 256, 1024}
+
+// renditionTimeout bounds how long rendering an attachment may take,
+// including the wait for a free worker.
+const renditionTimeout = 2 * time.Minute
+
+// QueryAttachmentByID finds the attachment and its renditions by the
+// specified ID.
+func (b *Business) QueryAttachmentByID(ctx context.Context, attachmentID uuid.UUID) (Attachment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.queryattachmentbyid")
+	defer span.End()
+
+	att, err := b.storer.QueryAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("query: attachmentID[%s]: %w", attachmentID, err)
+	}
+
+	return att, nil
+}
+
+// CanAccessFile reports whether the user may read the contents of the file,
+// because they uploaded it or it belongs to a todo item they own or are
+// assigned.
+func (b *Business) CanAccessFile(ctx context.Context, userID uuid.UUID, fileID string) (bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.canaccessfile")
+	defer span.End()
+
+	access, err := b.storer.CanAccessFile(ctx, userID, fileID)
+	if err != nil {
+		return false, fmt.Errorf("canaccessfile: userID[%s] fileID[%s]: %w", userID, fileID, err)
+	}
+
+	return access, nil
+}
+
+// DeleteAttachment removes the attachment. Its contents are deleted once no
+// other attachment or todo item refers to them.
+func (b *Business) DeleteAttachment(ctx context.Context, att Attachment) error {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.deleteattachment")
+	defer span.End()
+
+	var unused Blob
+	err := b.withTran(ctx, func(bus *Business) error {
+		if err := bus.storer.DeleteAttachment(ctx, att); err != nil {
+			return fmt.Errorf("delete: attachmentID[%s]: %w", att.ID, err)
+		}
+
+		if err := bus.refund(ctx, att.UserID, att.Size); err != nil {
+			return fmt.Errorf("refund: %w", err)
+		}
+
+		if att.Checksum == "" {
+			return nil
+		}
+
+		var err error
+		if unused, err = bus.releaseBlob(ctx, att.Checksum); err != nil {
+			return fmt.Errorf("releaseblob: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	b.deleteBlobFiles(ctx, unused)
+
+	return nil
+}
+
+// QueryRendition returns the rendition of the attachment for the size along
+// with its contents.
+func (b *Business) QueryRendition(ctx context.Context, att Attachment, size int) (Rendition, []byte, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.queryrendition")
+	defer span.End()
+
+	for _, rnd := range att.Renditions {
+		if rnd.Size != size {
+			continue
+		}
+
+		data, err := b.s3Client.Download(ctx, rnd.FileID)
+		if err != nil {
+			return Rendition{}, nil, fmt.Errorf("s3 download failed: %w", err)
+		}
+
+		return rnd, data, nil
+	}
+
+	return Rendition{}, nil, fmt.Errorf("attachmentID[%s] size[%d]: %w", att.ID, size, ErrRenditionNotFound)
+}
+
+// =============================================================================
+
+// renditionKey returns where the rendition of the size is stored for the
+// contents stored under key. Renditions follow the blob they were made from,
+// so attachments sharing contents share their renditions too.
+func renditionKey(key string, size int) string {
+	return fmt.Sprintf("%s_%d", key, size)
+}
+
+func hasRenditions(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// startRenditions hands rendering to the worker so the upload can return
+// straight away. Without a worker the renditions are rendered inline.
+func (b *Business) startRenditions(ctx context.Context, att Attachment, key string, data []byte) {
+	job := func(ctx context.Context) {
+		if err := b.createRenditions(ctx, att, key, data); err != nil {
+			b.log.Error(ctx, "todobus: renditions", "attachmentID", att.ID, "ERROR", err)
+		}
+	}
+
+	if b.worker == nil {
+		job(ctx)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, renditionTimeout)
+	defer cancel()
+
+	if _, err := b.worker.Start(ctx, job); err != nil {
+		b.log.Error(ctx, "todobus: renditions: start", "attachmentID", att.ID, "ERROR", err)
+	}
+}
+
+// createRenditions renders every size, stores each one beside the contents
+// and records it on the attachment.
+func (b *Business) createRenditions(ctx context.Context, att Attachment, key string, data []byte) error {
+	thumbs, err := thumbnail.Generate(data, RenditionSizes)
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	for _, thumb := range thumbs {
+		fileID, err := b.s3Client.Upload(ctx, renditionKey(key, thumb.Size), thumb.Data)
+		if err != nil {
+			return fmt.Errorf("s3 upload failed: size[%d]: %w", thumb.Size, err)
+		}
+
+		rnd := Rendition{
+			Size:        thumb.Size,
+			FileID:      fileID,
+			ContentType: thumb.ContentType,
+			Width:       thumb.Width,
+			Height:      thumb.Height,
+		}
+
+		if err := b.storer.CreateRendition(ctx, att.ID, rnd); err != nil {
+			return fmt.Errorf("createrendition: size[%d]: %w", thumb.Size, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package todobus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// blobPrefix is where blob contents live in the bucket. The key of a blob is
+// derived from its checksum, so the same contents always land on the same
+// object no matter who uploads them or what the file is called.
+const blobPrefix = "blobs/"
+
+// QueryBlobByFileID finds the blob stored under the specified file id.
+func (b *Business) QueryBlobByFileID(ctx context.Context, fileID string) (Blob, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.queryblobbyfileid")
+	defer span.End()
+
+	blob, err := b.storer.QueryBlobByFileID(ctx, fileID)
+	if err != nil {
+		return Blob{}, fmt.Errorf("query: fileID[%s]: %w", fileID, err)
+	}
+
+	return blob, nil
+}
+
+// =============================================================================
+
+// storeBlob takes a reference on the blob for the data, uploading the
+// contents only when they have not been stored before. Every successful call
+// must be paired with a call to releaseBlob.
+func (b *Business) storeBlob(ctx context.Context, data []byte, contentType string) (Blob, error) {
+	sum := sha256.Sum256(data)
+
+	blob := Blob{
+		Checksum:    hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+		Size:        len(data),
+		DateCreated: time.Now(),
+	}
+
+	blob, err := b.storer.AcquireBlob(ctx, blob)
+	if err != nil {
+		return Blob{}, fmt.Errorf("acquireblob: %w", err)
+	}
+
+	// A blob with a file id has been stored already. Without one, this is
+	// either the first reference or an earlier upload of the same contents
+	// is still in progress. Uploading again is harmless as the key is the
+	// same and so are the contents.
+	if blob.FileID != "" {
+		return blob, nil
+	}
+
+	fileID, err := b.s3Client.Upload(ctx, blobPrefix+blob.Checksum, data)
+	if err != nil {
+		b.abandonBlob(ctx, blob)
+		return Blob{}, fmt.Errorf("s3 upload failed: %w", err)
+	}
+	blob.FileID = fileID
+
+	if err := b.storer.UpdateBlob(ctx, blob); err != nil {
+		b.abandonBlob(ctx, blob)
+		return Blob{}, fmt.Errorf("updateblob: %w", err)
+	}
+
+	return blob, nil
+}
+
+// releaseBlob drops a reference on the blob. When that was the last
+// reference the blob is removed and returned, and the caller must delete its
+// files with deleteBlobFiles once any transaction it is part of commits.
+func (b *Business) releaseBlob(ctx context.Context, checksum string) (Blob, error) {
+	blob, err := b.storer.ReleaseBlob(ctx, checksum)
+	if err != nil {
+		return Blob{}, fmt.Errorf("releaseblob: checksum[%s]: %w", checksum, err)
+	}
+
+	if blob.RefCount > 0 {
+		return Blob{}, nil
+	}
+
+	if err := b.storer.DeleteBlob(ctx, checksum); err != nil {
+		if errors.Is(err, ErrBlobInUse) {
+			return Blob{}, nil
+		}
+		return Blob{}, fmt.Errorf("deleteblob: checksum[%s]: %w", checksum, err)
+	}
+
+	return blob, nil
+}
+
+// abandonBlob gives back the reference taken by a store that failed. Inside a
+// transaction the rollback does the same, so failures are only logged.
+func (b *Business) abandonBlob(ctx context.Context, blob Blob) {
+	if _, err := b.releaseBlob(ctx, blob.Checksum); err != nil {
+		b.log.Error(ctx, "todobus: abandonblob", "checksum", blob.Checksum, "ERROR", err)
+	}
+}
+
+// deleteBlobFiles removes the contents of a blob that is no longer stored and
+// any renditions made from them. Nothing is left to undo by then, so failures
+// are only logged.
+func (b *Business) deleteBlobFiles(ctx context.Context, blob Blob) {
+	if blob.FileID == "" {
+		return
+	}
+
+	fileIDs := []string{blob.FileID}
+	for _, size := range RenditionSizes {
+		fileIDs = append(fileIDs, renditionKey(blob.FileID, size))
+	}
+
+	for _, fileID := range fileIDs {
+		if err := b.s3Client.Delete(ctx, fileID); err != nil {
+			b.log.Warn(ctx, "failed to delete file from S3", "fileID", fileID, "error", err)
+		}
+	}
+}
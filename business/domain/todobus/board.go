@@ -0,0 +1,228 @@
+package todobus
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/rank"
+	"github.com/himynamej/todo/business/types/status"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// BoardColumns lists the statuses that make up the columns of a board, in
+// the order they are shown.
+var BoardColumns = []status.Status{status.Open, status.InProgress, status.Done}
+
+// MaxColumnItems is the largest number of items shown in a column of a
+// board.
+const MaxColumnItems = 500
+
+// RebalanceLength is the length of rank above which the periodic pass
+// spreads the ranks of a column out again, well before moves would have to.
+const RebalanceLength = rank.MaxLength / 2
+
+// QueryBoard retrieves the items that match the filter arranged in the
+// columns of a board.
+func (b *Business) QueryBoard(ctx context.Context, filter QueryFilter) (Board, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.queryboard")
+	defer span.End()
+
+	orderBy := order.NewBy(OrderByRank, order.ASC)
+	pg := page.MustParse("1", fmt.Sprint(MaxColumnItems))
+
+	board := Board{
+		Columns: make([]BoardColumn, len(BoardColumns)),
+	}
+
+	for i, sts := range BoardColumns {
+		filter.Status = &sts
+
+		items, err := b.storer.Query(ctx, filter, orderBy, pg)
+		if err != nil {
+			return Board{}, fmt.Errorf("query: status[%s]: %w", sts, err)
+		}
+
+		board.Columns[i] = BoardColumn{
+			Status: sts,
+			Items:  items,
+		}
+	}
+
+	return board, nil
+}
+
+// Move places the item on the board of its owner. Moving it into another
+// column changes its status like any other update. Moves on the same board
+// are made one at a time and the neighbours are looked up once the board is
+// locked, so moves that race each other all land where their neighbours are
+// by then. When both neighbours are given but are no longer next to each
+// other, the item goes right after BeforeID.
+func (b *Business) Move(ctx context.Context, item TodoItem, mv Move) (TodoItem, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.move")
+	defer span.End()
+
+	if (mv.BeforeID != nil && *mv.BeforeID == item.ID) || (mv.AfterID != nil && *mv.AfterID == item.ID) {
+		return TodoItem{}, ErrInvalidNeighbour
+	}
+
+	if mv.Status != nil && *mv.Status != item.Status {
+		var err error
+		if item, err = b.Update(ctx, item, UpdateTodoItem{Status: mv.Status}); err != nil {
+			return TodoItem{}, fmt.Errorf("update: %w", err)
+		}
+	}
+
+	col := Column{
+		UserID: item.UserID,
+		Status: item.Status,
+	}
+
+	err := b.withTran(ctx, func(bus *Business) error {
+		if err := bus.storer.LockBoard(ctx, col.UserID); err != nil {
+			return fmt.Errorf("lockboard: %w", err)
+		}
+
+		items, err := bus.storer.QueryColumn(ctx, col)
+		if err != nil {
+			return fmt.Errorf("querycolumn: %w", err)
+		}
+
+		items = slices.DeleteFunc(items, func(it TodoItem) bool {
+			return it.ID == item.ID
+		})
+
+		pos, err := position(items, mv)
+		if err != nil {
+			return err
+		}
+
+		var lo, hi rank.Rank
+		if pos > 0 {
+			lo = items[pos-1].Rank
+		}
+		if pos < len(items) {
+			hi = items[pos].Rank
+		}
+
+		// An item dropped after one that has no rank yet, between two with
+		// the same rank, or with a rank grown too long is only placed by
+		// spreading out the ranks of the whole column.
+		r, err := rank.Between(lo, hi)
+		if err != nil || (pos > 0 && lo.IsZero()) || len(r.String()) > rank.MaxLength {
+			items = slices.Insert(items, pos, item)
+			if err := bus.spread(ctx, items); err != nil {
+				return fmt.Errorf("spread: %w", err)
+			}
+
+			item.Rank = items[pos].Rank
+			return nil
+		}
+
+		if err := bus.storer.UpdateRank(ctx, item.ID, r); err != nil {
+			return fmt.Errorf("updaterank: %w", err)
+		}
+		item.Rank = r
+
+		return nil
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+
+	return item, nil
+}
+
+// RebalanceBoards spreads out the ranks of every column that has grown long
+// ranks or holds items without one. It returns the number of columns that
+// were rebalanced.
+func (b *Business) RebalanceBoards(ctx context.Context) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.rebalanceboards")
+	defer span.End()
+
+	cols, err := b.storer.QueryUnbalancedColumns(ctx, RebalanceLength)
+	if err != nil {
+		return 0, fmt.Errorf("queryunbalancedcolumns: %w", err)
+	}
+
+	for i, col := range cols {
+		err := b.withTran(ctx, func(bus *Business) error {
+			if err := bus.storer.LockBoard(ctx, col.UserID); err != nil {
+				return fmt.Errorf("lockboard: %w", err)
+			}
+
+			items, err := bus.storer.QueryColumn(ctx, col)
+			if err != nil {
+				return fmt.Errorf("querycolumn: %w", err)
+			}
+
+			return bus.spread(ctx, items)
+		})
+		if err != nil {
+			return i, fmt.Errorf("rebalance: userID[%s] status[%s]: %w", col.UserID, col.Status, err)
+		}
+	}
+
+	return len(cols), nil
+}
+
+// =============================================================================
+
+// spread gives the items evenly spaced ranks in the order they are in,
+// updating the ones whose rank changes.
+func (b *Business) spread(ctx context.Context, items []TodoItem) error {
+	ranks := rank.Spread(len(items))
+
+	for i := range items {
+		if items[i].Rank.Equal(ranks[i]) {
+			continue
+		}
+
+		if err := b.storer.UpdateRank(ctx, items[i].ID, ranks[i]); err != nil {
+			return fmt.Errorf("updaterank: itemID[%s]: %w", items[i].ID, err)
+		}
+		items[i].Rank = ranks[i]
+	}
+
+	return nil
+}
+
+// endOfColumn returns a rank after the last ranked item in the column.
+func (b *Business) endOfColumn(ctx context.Context, col Column) (rank.Rank, error) {
+	last, err := b.storer.QueryLastRank(ctx, col)
+	if err != nil {
+		return rank.Rank{}, fmt.Errorf("querylastrank: %w", err)
+	}
+
+	return rank.Between(last, rank.Rank{})
+}
+
+// position returns the index in the column the moved item goes to.
+func position(items []TodoItem, mv Move) (int, error) {
+	index := func(id uuid.UUID) (int, error) {
+		idx := slices.IndexFunc(items, func(item TodoItem) bool {
+			return item.ID == id
+		})
+		if idx == -1 {
+			return 0, fmt.Errorf("itemID[%s]: %w", id, ErrNeighbourNotFound)
+		}
+		return idx, nil
+	}
+
+	switch {
+	case mv.BeforeID != nil:
+		idx, err := index(*mv.BeforeID)
+		if err != nil {
+			return 0, err
+		}
+		return idx + 1, nil
+
+	case mv.AfterID != nil:
+		return index(*mv.AfterID)
+	}
+
+	return len(items), nil
+}
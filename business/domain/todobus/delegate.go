@@ -0,0 +1,64 @@
+package todobus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/page"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, b.actionUserUpdated)
+	}
+}
+
+// actionUserUpdated is executed by the user domain indirectly when a user is
+// updated. When a user is disabled, every todo item assigned to them is handed
+// back to its owner.
+func (b *Business) actionUserUpdated(ctx context.Context, data delegate.Data) error {
+	var params userbus.ActionUpdatedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	b.log.Info(ctx, "action-userupdated", "user_id", params.UserID, "enabled", params.Enabled)
+
+	if params.Enabled == nil || *params.Enabled {
+		return nil
+	}
+
+	filter := QueryFilter{
+		AssigneeID: &params.UserID,
+	}
+
+	for {
+		items, err := b.storer.Query(ctx, filter, DefaultOrderBy, page.MustParse("1", "100"))
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+
+		var reassigned int
+		for _, item := range items {
+			ownerID := item.UserID
+			if ownerID == params.UserID {
+				ownerID = uuid.Nil
+			}
+
+			if _, err := b.assign(ctx, item, ownerID, uuid.Nil); err != nil {
+				return fmt.Errorf("assign: itemID[%s]: %w", item.ID, err)
+			}
+			reassigned++
+		}
+
+		if len(items) < 100 || reassigned == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package todobus
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/status"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// MaxGraphItems is the largest number of items a dependency graph is drawn
+// for.
+const MaxGraphItems = 1000
+
+// AddBlocker records that the item is blocked by the blocker until the
+// blocker is done. A dependency that would make an item wait on itself,
+// directly or through other items, is rejected.
+func (b *Business) AddBlocker(ctx context.Context, item TodoItem, blocker TodoItem) (Dependency, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.addblocker")
+	defer span.End()
+
+	if item.ID == blocker.ID {
+		return Dependency{}, ErrSelfDependency
+	}
+
+	dep := Dependency{
+		ItemID:      item.ID,
+		BlockerID:   blocker.ID,
+		DateCreated: time.Now(),
+	}
+
+	// Dependencies are changed one at a time, so two changes can't each pass
+	// the check and form a cycle together.
+	err := b.withTran(ctx, func(bus *Business) error {
+		if err := bus.storer.LockDependencies(ctx); err != nil {
+			return fmt.Errorf("lockdependencies: %w", err)
+		}
+
+		path, err := bus.path(ctx, blocker.ID, item.ID)
+		if err != nil {
+			return fmt.Errorf("path: %w", err)
+		}
+
+		if path != nil {
+			return fmt.Errorf("%s: %w", formatPath(append([]uuid.UUID{item.ID}, path...)), ErrDependencyCycle)
+		}
+
+		if err := bus.storer.CreateDependency(ctx, dep); err != nil {
+			return fmt.Errorf("createdependency: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	return dep, nil
+}
+
+// RemoveBlocker removes the dependency of the item on the blocker. When it
+// was the last item the item was waiting on, the item is unblocked.
+func (b *Business) RemoveBlocker(ctx context.Context, item TodoItem, blockerID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.removeblocker")
+	defer span.End()
+
+	deps, err := b.storer.QueryDependencies(ctx, []uuid.UUID{item.ID})
+	if err != nil {
+		return fmt.Errorf("querydependencies: itemID[%s]: %w", item.ID, err)
+	}
+
+	idx := slices.IndexFunc(deps, func(dep Dependency) bool {
+		return dep.BlockerID == blockerID
+	})
+	if idx == -1 {
+		return ErrDependencyNotFound
+	}
+
+	if err := b.storer.DeleteDependency(ctx, deps[idx]); err != nil {
+		return fmt.Errorf("deletedependency: %w", err)
+	}
+
+	if !item.Blocked || item.Status == status.Done {
+		return nil
+	}
+
+	item, err = b.storer.QueryByID(ctx, item.ID)
+	if err != nil {
+		return fmt.Errorf("querybyid: itemID[%s]: %w", item.ID, err)
+	}
+
+	if !item.Blocked {
+		b.notify(ctx, ActionUnblockedData(item))
+	}
+
+	return nil
+}
+
+// QueryBlockers retrieves the dependencies of the item on the items it is
+// blocked by.
+func (b *Business) QueryBlockers(ctx context.Context, itemID uuid.UUID) ([]Dependency, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.queryblockers")
+	defer span.End()
+
+	deps, err := b.storer.QueryDependencies(ctx, []uuid.UUID{itemID})
+	if err != nil {
+		return nil, fmt.Errorf("querydependencies: itemID[%s]: %w", itemID, err)
+	}
+
+	return deps, nil
+}
+
+// QueryGraph retrieves the items that match the filter along with their
+// dependencies. Items outside the filter that block one of them are added,
+// so every dependency in the graph joins two of its items.
+func (b *Business) QueryGraph(ctx context.Context, filter QueryFilter) (Graph, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.querygraph")
+	defer span.End()
+
+	const rows = 100
+
+	pg := page.MustParse("1", fmt.Sprint(rows))
+
+	var items []TodoItem
+	for {
+		batch, err := b.storer.Query(ctx, filter, DefaultOrderBy, pg)
+		if err != nil {
+			return Graph{}, fmt.Errorf("query: %w", err)
+		}
+		items = append(items, batch...)
+
+		if len(items) > MaxGraphItems {
+			return Graph{}, fmt.Errorf("more than %d items: %w", MaxGraphItems, ErrGraphTooLarge)
+		}
+
+		if len(batch) < rows {
+			break
+		}
+
+		if pg, err = page.FromCursor(Cursor(batch[len(batch)-1], DefaultOrderBy), rows); err != nil {
+			return Graph{}, fmt.Errorf("page: %w", err)
+		}
+	}
+
+	itemIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		itemIDs[i] = item.ID
+	}
+
+	deps, err := b.storer.QueryDependencies(ctx, itemIDs)
+	if err != nil {
+		return Graph{}, fmt.Errorf("querydependencies: %w", err)
+	}
+
+	for _, dep := range deps {
+		if slices.Contains(itemIDs, dep.BlockerID) {
+			continue
+		}
+
+		blocker, err := b.storer.QueryByID(ctx, dep.BlockerID)
+		if err != nil {
+			return Graph{}, fmt.Errorf("querybyid: itemID[%s]: %w", dep.BlockerID, err)
+		}
+		items = append(items, blocker)
+		itemIDs = append(itemIDs, blocker.ID)
+	}
+
+	graph := Graph{
+		Items:        items,
+		Dependencies: deps,
+	}
+
+	return graph, nil
+}
+
+// =============================================================================
+
+// path looks for a chain of dependencies that leads from the item to the
+// target, walking the graph one level of blockers at a time. It returns the
+// chain, starting with the item and ending with the target, or nil when there
+// is none.
+func (b *Business) path(ctx context.Context, itemID uuid.UUID, targetID uuid.UUID) ([]uuid.UUID, error) {
+	if itemID == targetID {
+		return []uuid.UUID{itemID}, nil
+	}
+
+	// The item each visited item was reached from.
+	from := map[uuid.UUID]uuid.UUID{itemID: uuid.Nil}
+
+	frontier := []uuid.UUID{itemID}
+	for len(frontier) > 0 {
+		deps, err := b.storer.QueryDependencies(ctx, frontier)
+		if err != nil {
+			return nil, fmt.Errorf("querydependencies: %w", err)
+		}
+
+		frontier = frontier[:0]
+		for _, dep := range deps {
+			if _, visited := from[dep.BlockerID]; visited {
+				continue
+			}
+			from[dep.BlockerID] = dep.ItemID
+
+			if dep.BlockerID == targetID {
+				var path []uuid.UUID
+				for id := targetID; id != uuid.Nil; id = from[id] {
+					path = append(path, id)
+				}
+				slices.Reverse(path)
+
+				return path, nil
+			}
+
+			frontier = append(frontier, dep.BlockerID)
+		}
+	}
+
+	return nil, nil
+}
+
+// notifyUnblocked lets other domains know about the items that were only
+// waiting on the blocker, which is done now.
+func (b *Business) notifyUnblocked(ctx context.Context, blockerID uuid.UUID) error {
+	items, err := b.storer.QueryUnblocked(ctx, blockerID)
+	if err != nil {
+		return fmt.Errorf("queryunblocked: blockerID[%s]: %w", blockerID, err)
+	}
+
+	for _, item := range items {
+		b.notify(ctx, ActionUnblockedData(item))
+	}
+
+	return nil
+}
+
+// formatPath writes a chain of dependencies the way it reads: each item is
+// blocked by the one after it.
+func formatPath(path []uuid.UUID) string {
+	ids := make([]string, len(path))
+	for i, id := range path {
+		ids[i] = id.String()
+	}
+
+	return strings.Join(ids, " -> ")
+}
@@ -0,0 +1,157 @@
+package todobus
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/recurrence"
+	"github.com/himynamej/todo/business/types/status"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "todo"
+
+// Set of delegate actions.
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionCompleted = "completed"
+	ActionDeleted   = "deleted"
+	ActionAssigned  = "assigned"
+	ActionUnblocked = "unblocked"
+)
+
+// ActionItemParms represents the parameters for the created, updated,
+// completed, deleted and unblocked actions.
+type ActionItemParms struct {
+	TodoID      uuid.UUID
+	OrgID       uuid.UUID
+	OwnerID     uuid.UUID
+	AssigneeID  uuid.UUID
+	Description string
+	DueDate     time.Time
+	AllDay      bool
+	Status      status.Status
+	Priority    priority.Priority
+	Recurrence  recurrence.Rule
+	List        string
+	Labels      []string
+}
+
+// String returns a string representation of the action parameters.
+func (ai *ActionItemParms) String() string {
+	return fmt.Sprintf("&EventParamsItem{TodoID:%v, OwnerID:%v, Status:%v}", ai.TodoID, ai.OwnerID, ai.Status)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (ai *ActionItemParms) Marshal() ([]byte, error) {
+	return json.Marshal(ai)
+}
+
+// ActionCreatedData constructs the data for the created action.
+func ActionCreatedData(item TodoItem) delegate.Data {
+	return actionItemData(ActionCreated, item)
+}
+
+// ActionUpdatedData constructs the data for the updated action.
+func ActionUpdatedData(item TodoItem) delegate.Data {
+	return actionItemData(ActionUpdated, item)
+}
+
+// ActionCompletedData constructs the data for the completed action.
+func ActionCompletedData(item TodoItem) delegate.Data {
+	return actionItemData(ActionCompleted, item)
+}
+
+// ActionDeletedData constructs the data for the deleted action.
+func ActionDeletedData(item TodoItem) delegate.Data {
+	return actionItemData(ActionDeleted, item)
+}
+
+// ActionUnblockedData constructs the data for the unblocked action, which
+// is sent for an item once the last of the items it is blocked by is done.
+func ActionUnblockedData(item TodoItem) delegate.Data {
+	return actionItemData(ActionUnblocked, item)
+}
+
+func actionItemData(action string, item TodoItem) delegate.Data {
+	labels := make([]string, len(item.Labels))
+	for i, lbl := range item.Labels {
+		labels[i] = lbl.String()
+	}
+
+	params := ActionItemParms{
+		TodoID:      item.ID,
+		OrgID:       item.OrgID,
+		OwnerID:     item.UserID,
+		AssigneeID:  item.AssigneeID,
+		Description: item.Description,
+		DueDate:     item.DueDate,
+		AllDay:      item.AllDay,
+		Status:      item.Status,
+		Priority:    item.Priority,
+		Recurrence:  item.Recurrence,
+		List:        item.List.String(),
+		Labels:      labels,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    action,
+		RawParams: rawParams,
+	}
+}
+
+// ActionAssignedParms represents the parameters for the assigned action.
+type ActionAssignedParms struct {
+	TodoID             uuid.UUID
+	OrgID              uuid.UUID
+	OwnerID            uuid.UUID
+	AssigneeID         uuid.UUID
+	PreviousAssigneeID uuid.UUID
+	ActorID            uuid.UUID
+	List               string
+}
+
+// String returns a string representation of the action parameters.
+func (aa *ActionAssignedParms) String() string {
+	return fmt.Sprintf("&EventParamsAssigned{TodoID:%v, AssigneeID:%v, PreviousAssigneeID:%v}", aa.TodoID, aa.AssigneeID, aa.PreviousAssigneeID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (aa *ActionAssignedParms) Marshal() ([]byte, error) {
+	return json.Marshal(aa)
+}
+
+// ActionAssignedData constructs the data for the assigned action.
+func ActionAssignedData(item TodoItem, previousAssigneeID uuid.UUID, actorID uuid.UUID) delegate.Data {
+	params := ActionAssignedParms{
+		TodoID:             item.ID,
+		OrgID:              item.OrgID,
+		OwnerID:            item.UserID,
+		AssigneeID:         item.AssigneeID,
+		PreviousAssigneeID: previousAssigneeID,
+		ActorID:            actorID,
+		List:               item.List.String(),
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionAssigned,
+		RawParams: rawParams,
+	}
+}
@@ -0,0 +1,29 @@
+package todobus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/status"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+//
+// The due date range is inclusive of the start and exclusive of the end.
+// Timed items are compared as instants while all-day items are compared by
+// calendar date, using the dates the bounds fall on in their own location.
+type QueryFilter struct {
+	ID            *uuid.UUID
+	UserID        *uuid.UUID
+	AssigneeID    *uuid.UUID
+	Status        *status.Status
+	ExcludeStatus *status.Status
+	StartDueDate  *time.Time
+	EndDueDate    *time.Time
+	NoDueDate     *bool
+	List          *name.Name
+	Label         *label.Label
+}
@@ -7,10 +7,15 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 	todobus "github.com/himynamej/todo/business/domain/todobus"
+	order "github.com/himynamej/todo/business/sdk/order"
+	page "github.com/himynamej/todo/business/sdk/page"
+	sqldb "github.com/himynamej/todo/business/sdk/sqldb"
+	rank "github.com/himynamej/todo/business/types/rank"
 )
 
 // MockS3Client is a mock of S3Client interface.
@@ -65,6 +70,20 @@ func (mr *MockS3ClientMockRecorder) Download(ctx, fileID interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockS3Client)(nil).Download), ctx, fileID)
 }
 
+// List mocks base method.
+func (m *MockS3Client) List(ctx context.Context, prefix string, fn func([]todobus.Object) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, prefix, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// List indicates an expected call of List.
+func (mr *MockS3ClientMockRecorder) List(ctx, prefix, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockS3Client)(nil).List), ctx, prefix, fn)
+}
+
 // Upload mocks base method.
 func (m *MockS3Client) Upload(ctx context.Context, fileName string, data []byte) (string, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +188,66 @@ func (m *MockStorer) EXPECT() *MockStorerMockRecorder {
 	return m.recorder
 }
 
+// AcquireBlob mocks base method.
+func (m *MockStorer) AcquireBlob(ctx context.Context, blob todobus.Blob) (todobus.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireBlob", ctx, blob)
+	ret0, _ := ret[0].(todobus.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireBlob indicates an expected call of AcquireBlob.
+func (mr *MockStorerMockRecorder) AcquireBlob(ctx, blob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireBlob", reflect.TypeOf((*MockStorer)(nil).AcquireBlob), ctx, blob)
+}
+
+// AcquireBlobByFileID mocks base method.
+func (m *MockStorer) AcquireBlobByFileID(ctx context.Context, fileID string) (todobus.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireBlobByFileID", ctx, fileID)
+	ret0, _ := ret[0].(todobus.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireBlobByFileID indicates an expected call of AcquireBlobByFileID.
+func (mr *MockStorerMockRecorder) AcquireBlobByFileID(ctx, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireBlobByFileID", reflect.TypeOf((*MockStorer)(nil).AcquireBlobByFileID), ctx, fileID)
+}
+
+// CanAccessFile mocks base method.
+func (m *MockStorer) CanAccessFile(ctx context.Context, userID uuid.UUID, fileID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanAccessFile", ctx, userID, fileID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanAccessFile indicates an expected call of CanAccessFile.
+func (mr *MockStorerMockRecorder) CanAccessFile(ctx, userID, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanAccessFile", reflect.TypeOf((*MockStorer)(nil).CanAccessFile), ctx, userID, fileID)
+}
+
+// Count mocks base method.
+func (m *MockStorer) Count(ctx context.Context, filter todobus.QueryFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockStorerMockRecorder) Count(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockStorer)(nil).Count), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockStorer) Create(ctx context.Context, item todobus.TodoItem) error {
 	m.ctrl.T.Helper()
@@ -183,6 +262,62 @@ func (mr *MockStorerMockRecorder) Create(ctx, item interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorer)(nil).Create), ctx, item)
 }
 
+// CreateAttachment mocks base method.
+func (m *MockStorer) CreateAttachment(ctx context.Context, att todobus.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachment", ctx, att)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAttachment indicates an expected call of CreateAttachment.
+func (mr *MockStorerMockRecorder) CreateAttachment(ctx, att interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachment", reflect.TypeOf((*MockStorer)(nil).CreateAttachment), ctx, att)
+}
+
+// CreateDependency mocks base method.
+func (m *MockStorer) CreateDependency(ctx context.Context, dep todobus.Dependency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDependency", ctx, dep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDependency indicates an expected call of CreateDependency.
+func (mr *MockStorerMockRecorder) CreateDependency(ctx, dep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDependency", reflect.TypeOf((*MockStorer)(nil).CreateDependency), ctx, dep)
+}
+
+// CreateHistory mocks base method.
+func (m *MockStorer) CreateHistory(ctx context.Context, hst todobus.History) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHistory", ctx, hst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHistory indicates an expected call of CreateHistory.
+func (mr *MockStorerMockRecorder) CreateHistory(ctx, hst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHistory", reflect.TypeOf((*MockStorer)(nil).CreateHistory), ctx, hst)
+}
+
+// CreateRendition mocks base method.
+func (m *MockStorer) CreateRendition(ctx context.Context, attachmentID uuid.UUID, rnd todobus.Rendition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRendition", ctx, attachmentID, rnd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRendition indicates an expected call of CreateRendition.
+func (mr *MockStorerMockRecorder) CreateRendition(ctx, attachmentID, rnd interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRendition", reflect.TypeOf((*MockStorer)(nil).CreateRendition), ctx, attachmentID, rnd)
+}
+
 // Delete mocks base method.
 func (m *MockStorer) Delete(ctx context.Context, item todobus.TodoItem) error {
 	m.ctrl.T.Helper()
@@ -197,19 +332,164 @@ func (mr *MockStorerMockRecorder) Delete(ctx, item interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorer)(nil).Delete), ctx, item)
 }
 
+// DeleteAttachment mocks base method.
+func (m *MockStorer) DeleteAttachment(ctx context.Context, att todobus.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, att)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockStorerMockRecorder) DeleteAttachment(ctx, att interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockStorer)(nil).DeleteAttachment), ctx, att)
+}
+
+// DeleteBlob mocks base method.
+func (m *MockStorer) DeleteBlob(ctx context.Context, checksum string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlob", ctx, checksum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlob indicates an expected call of DeleteBlob.
+func (mr *MockStorerMockRecorder) DeleteBlob(ctx, checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlob", reflect.TypeOf((*MockStorer)(nil).DeleteBlob), ctx, checksum)
+}
+
+// DeleteDependency mocks base method.
+func (m *MockStorer) DeleteDependency(ctx context.Context, dep todobus.Dependency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDependency", ctx, dep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDependency indicates an expected call of DeleteDependency.
+func (mr *MockStorerMockRecorder) DeleteDependency(ctx, dep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDependency", reflect.TypeOf((*MockStorer)(nil).DeleteDependency), ctx, dep)
+}
+
+// IsFileOwner mocks base method.
+func (m *MockStorer) IsFileOwner(ctx context.Context, userID uuid.UUID, fileID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFileOwner", ctx, userID, fileID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFileOwner indicates an expected call of IsFileOwner.
+func (mr *MockStorerMockRecorder) IsFileOwner(ctx, userID, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFileOwner", reflect.TypeOf((*MockStorer)(nil).IsFileOwner), ctx, userID, fileID)
+}
+
+// LockBoard mocks base method.
+func (m *MockStorer) LockBoard(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBoard", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockBoard indicates an expected call of LockBoard.
+func (mr *MockStorerMockRecorder) LockBoard(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBoard", reflect.TypeOf((*MockStorer)(nil).LockBoard), ctx, userID)
+}
+
+// LockDependencies mocks base method.
+func (m *MockStorer) LockDependencies(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDependencies", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockDependencies indicates an expected call of LockDependencies.
+func (mr *MockStorerMockRecorder) LockDependencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDependencies", reflect.TypeOf((*MockStorer)(nil).LockDependencies), ctx)
+}
+
+// LockItem mocks base method.
+func (m *MockStorer) LockItem(ctx context.Context, itemID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockItem", ctx, itemID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockItem indicates an expected call of LockItem.
+func (mr *MockStorerMockRecorder) LockItem(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockItem", reflect.TypeOf((*MockStorer)(nil).LockItem), ctx, itemID)
+}
+
+// NewWithTx mocks base method.
+func (m *MockStorer) NewWithTx(tx sqldb.CommitRollbacker) (todobus.Storer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewWithTx", tx)
+	ret0, _ := ret[0].(todobus.Storer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewWithTx indicates an expected call of NewWithTx.
+func (mr *MockStorerMockRecorder) NewWithTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewWithTx", reflect.TypeOf((*MockStorer)(nil).NewWithTx), tx)
+}
+
 // Query mocks base method.
-func (m *MockStorer) Query(ctx context.Context) ([]todobus.TodoItem, error) {
+func (m *MockStorer) Query(ctx context.Context, filter todobus.QueryFilter, orderBy order.By, page page.Page) ([]todobus.TodoItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Query", ctx)
+	ret := m.ctrl.Call(m, "Query", ctx, filter, orderBy, page)
 	ret0, _ := ret[0].([]todobus.TodoItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Query indicates an expected call of Query.
-func (mr *MockStorerMockRecorder) Query(ctx interface{}) *gomock.Call {
+func (mr *MockStorerMockRecorder) Query(ctx, filter, orderBy, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockStorer)(nil).Query), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockStorer)(nil).Query), ctx, filter, orderBy, page)
+}
+
+// QueryAttachmentByID mocks base method.
+func (m *MockStorer) QueryAttachmentByID(ctx context.Context, attachmentID uuid.UUID) (todobus.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAttachmentByID", ctx, attachmentID)
+	ret0, _ := ret[0].(todobus.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAttachmentByID indicates an expected call of QueryAttachmentByID.
+func (mr *MockStorerMockRecorder) QueryAttachmentByID(ctx, attachmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAttachmentByID", reflect.TypeOf((*MockStorer)(nil).QueryAttachmentByID), ctx, attachmentID)
+}
+
+// QueryBlobByFileID mocks base method.
+func (m *MockStorer) QueryBlobByFileID(ctx context.Context, fileID string) (todobus.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryBlobByFileID", ctx, fileID)
+	ret0, _ := ret[0].(todobus.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBlobByFileID indicates an expected call of QueryBlobByFileID.
+func (mr *MockStorerMockRecorder) QueryBlobByFileID(ctx, fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlobByFileID", reflect.TypeOf((*MockStorer)(nil).QueryBlobByFileID), ctx, fileID)
 }
 
 // QueryByID mocks base method.
@@ -227,6 +507,187 @@ func (mr *MockStorerMockRecorder) QueryByID(ctx, itemID interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryByID", reflect.TypeOf((*MockStorer)(nil).QueryByID), ctx, itemID)
 }
 
+// QueryChanges mocks base method.
+func (m *MockStorer) QueryChanges(ctx context.Context, userID uuid.UUID, after, upTo int64, limit int) ([]todobus.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryChanges", ctx, userID, after, upTo, limit)
+	ret0, _ := ret[0].([]todobus.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryChanges indicates an expected call of QueryChanges.
+func (mr *MockStorerMockRecorder) QueryChanges(ctx, userID, after, upTo, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryChanges", reflect.TypeOf((*MockStorer)(nil).QueryChanges), ctx, userID, after, upTo, limit)
+}
+
+// QueryColumn mocks base method.
+func (m *MockStorer) QueryColumn(ctx context.Context, col todobus.Column) ([]todobus.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryColumn", ctx, col)
+	ret0, _ := ret[0].([]todobus.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryColumn indicates an expected call of QueryColumn.
+func (mr *MockStorerMockRecorder) QueryColumn(ctx, col interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryColumn", reflect.TypeOf((*MockStorer)(nil).QueryColumn), ctx, col)
+}
+
+// QueryDependencies mocks base method.
+func (m *MockStorer) QueryDependencies(ctx context.Context, itemIDs []uuid.UUID) ([]todobus.Dependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryDependencies", ctx, itemIDs)
+	ret0, _ := ret[0].([]todobus.Dependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryDependencies indicates an expected call of QueryDependencies.
+func (mr *MockStorerMockRecorder) QueryDependencies(ctx, itemIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryDependencies", reflect.TypeOf((*MockStorer)(nil).QueryDependencies), ctx, itemIDs)
+}
+
+// QueryHistory mocks base method.
+func (m *MockStorer) QueryHistory(ctx context.Context, itemID uuid.UUID) ([]todobus.History, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryHistory", ctx, itemID)
+	ret0, _ := ret[0].([]todobus.History)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryHistory indicates an expected call of QueryHistory.
+func (mr *MockStorerMockRecorder) QueryHistory(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryHistory", reflect.TypeOf((*MockStorer)(nil).QueryHistory), ctx, itemID)
+}
+
+// QueryLastRank mocks base method.
+func (m *MockStorer) QueryLastRank(ctx context.Context, col todobus.Column) (rank.Rank, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryLastRank", ctx, col)
+	ret0, _ := ret[0].(rank.Rank)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryLastRank indicates an expected call of QueryLastRank.
+func (mr *MockStorerMockRecorder) QueryLastRank(ctx, col interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLastRank", reflect.TypeOf((*MockStorer)(nil).QueryLastRank), ctx, col)
+}
+
+// QueryReferencedFileIDs mocks base method.
+func (m *MockStorer) QueryReferencedFileIDs(ctx context.Context, fileIDs []string, cutoff time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryReferencedFileIDs", ctx, fileIDs, cutoff)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryReferencedFileIDs indicates an expected call of QueryReferencedFileIDs.
+func (mr *MockStorerMockRecorder) QueryReferencedFileIDs(ctx, fileIDs, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryReferencedFileIDs", reflect.TypeOf((*MockStorer)(nil).QueryReferencedFileIDs), ctx, fileIDs, cutoff)
+}
+
+// QueryTombstones mocks base method.
+func (m *MockStorer) QueryTombstones(ctx context.Context, userID uuid.UUID, after, upTo int64, limit int) ([]todobus.Tombstone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryTombstones", ctx, userID, after, upTo, limit)
+	ret0, _ := ret[0].([]todobus.Tombstone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTombstones indicates an expected call of QueryTombstones.
+func (mr *MockStorerMockRecorder) QueryTombstones(ctx, userID, after, upTo, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTombstones", reflect.TypeOf((*MockStorer)(nil).QueryTombstones), ctx, userID, after, upTo, limit)
+}
+
+// QueryUnbalancedColumns mocks base method.
+func (m *MockStorer) QueryUnbalancedColumns(ctx context.Context, length int) ([]todobus.Column, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryUnbalancedColumns", ctx, length)
+	ret0, _ := ret[0].([]todobus.Column)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryUnbalancedColumns indicates an expected call of QueryUnbalancedColumns.
+func (mr *MockStorerMockRecorder) QueryUnbalancedColumns(ctx, length interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUnbalancedColumns", reflect.TypeOf((*MockStorer)(nil).QueryUnbalancedColumns), ctx, length)
+}
+
+// QueryUnblocked mocks base method.
+func (m *MockStorer) QueryUnblocked(ctx context.Context, blockerID uuid.UUID) ([]todobus.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryUnblocked", ctx, blockerID)
+	ret0, _ := ret[0].([]todobus.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryUnblocked indicates an expected call of QueryUnblocked.
+func (mr *MockStorerMockRecorder) QueryUnblocked(ctx, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUnblocked", reflect.TypeOf((*MockStorer)(nil).QueryUnblocked), ctx, blockerID)
+}
+
+// QueryUnlinkedAttachments mocks base method.
+func (m *MockStorer) QueryUnlinkedAttachments(ctx context.Context, cutoff time.Time) ([]todobus.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryUnlinkedAttachments", ctx, cutoff)
+	ret0, _ := ret[0].([]todobus.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryUnlinkedAttachments indicates an expected call of QueryUnlinkedAttachments.
+func (mr *MockStorerMockRecorder) QueryUnlinkedAttachments(ctx, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUnlinkedAttachments", reflect.TypeOf((*MockStorer)(nil).QueryUnlinkedAttachments), ctx, cutoff)
+}
+
+// QueryWatermark mocks base method.
+func (m *MockStorer) QueryWatermark(ctx context.Context, orgID uuid.UUID) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryWatermark", ctx, orgID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryWatermark indicates an expected call of QueryWatermark.
+func (mr *MockStorerMockRecorder) QueryWatermark(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWatermark", reflect.TypeOf((*MockStorer)(nil).QueryWatermark), ctx, orgID)
+}
+
+// ReleaseBlob mocks base method.
+func (m *MockStorer) ReleaseBlob(ctx context.Context, checksum string) (todobus.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseBlob", ctx, checksum)
+	ret0, _ := ret[0].(todobus.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseBlob indicates an expected call of ReleaseBlob.
+func (mr *MockStorerMockRecorder) ReleaseBlob(ctx, checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseBlob", reflect.TypeOf((*MockStorer)(nil).ReleaseBlob), ctx, checksum)
+}
+
 // Update mocks base method.
 func (m *MockStorer) Update(ctx context.Context, item todobus.TodoItem) error {
 	m.ctrl.T.Helper()
@@ -240,3 +701,31 @@ func (mr *MockStorerMockRecorder) Update(ctx, item interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStorer)(nil).Update), ctx, item)
 }
+
+// UpdateBlob mocks base method.
+func (m *MockStorer) UpdateBlob(ctx context.Context, blob todobus.Blob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBlob", ctx, blob)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBlob indicates an expected call of UpdateBlob.
+func (mr *MockStorerMockRecorder) UpdateBlob(ctx, blob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBlob", reflect.TypeOf((*MockStorer)(nil).UpdateBlob), ctx, blob)
+}
+
+// UpdateRank mocks base method.
+func (m *MockStorer) UpdateRank(ctx context.Context, itemID uuid.UUID, r rank.Rank) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRank", ctx, itemID, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRank indicates an expected call of UpdateRank.
+func (mr *MockStorerMockRecorder) UpdateRank(ctx, itemID, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRank", reflect.TypeOf((*MockStorer)(nil).UpdateRank), ctx, itemID, r)
+}
@@ -4,26 +4,241 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/rank"
+	"github.com/himynamej/todo/business/types/recurrence"
+	"github.com/himynamej/todo/business/types/status"
 )
 
-// TodoItem represents the structure for a todo item.
+// TodoItem represents the structure for a todo item. A zero DueDate means
+// the item has no due date. When AllDay is set the DueDate holds a calendar
+// date at midnight UTC which is read in the zone of whoever views it. An item
+// with a Recurrence is followed by its next occurrence once it is completed.
+// Blocked is worked out when the item is read and reports whether any of the
+// items it is blocked by is not done yet. Rank is the position of the item in
+// the column of its status on the board of its owner.
 type TodoItem struct {
-	ID          uuid.UUID
-	Description string
-	DueDate     time.Time
-	FileID      string
+	ID            uuid.UUID
+	OrgID         uuid.UUID
+	UserID        uuid.UUID
+	AssigneeID    uuid.UUID
+	Description   string
+	DueDate       time.Time
+	AllDay        bool
+	FileID        string
+	Status        status.Status
+	Priority      priority.Priority
+	Recurrence    recurrence.Rule
+	List          name.Null
+	Labels        []label.Label
+	Checklist     []ChecklistItem
+	Blocked       bool
+	Rank          rank.Rank
+	DateCreated   time.Time
+	DateUpdated   time.Time
+	DateCompleted time.Time
+}
+
+// ChecklistItem represents one step that is ticked off on the way to
+// completing a todo item.
+type ChecklistItem struct {
+	Text string
+	Done bool
 }
 
 // NewTodoItem contains information needed to create a new TodoItem.
 type NewTodoItem struct {
+	ID          uuid.UUID // Chosen by the caller, such as an offline client, when not zero
+	OrgID       uuid.UUID // The organization access is scoped to when zero
+	UserID      uuid.UUID
+	AssigneeID  uuid.UUID
 	Description string
 	DueDate     time.Time
-	FileData    []byte
-	FileName    string
+	AllDay      bool
+	Priority    priority.Priority // The default priority when zero
+	Recurrence  recurrence.Rule
+	List        name.Null
+	Labels      []label.Label
+	Checklist   []ChecklistItem
+	FileData    []byte // New contents to store for the item
+	FileID      string // Or a reference to a file that was already uploaded
 }
 
 // UpdateTodoItem contains information needed to update an existing TodoItem.
 type UpdateTodoItem struct {
 	Description *string
 	DueDate     *time.Time
+	AllDay      *bool
+	Status      *status.Status
+	Priority    *priority.Priority
+	Recurrence  *recurrence.Rule
+	List        *name.Null
+	Labels      []label.Label
+	Checklist   []ChecklistItem
+}
+
+// Dependency represents a todo item that is blocked by another one until
+// the other one is done.
+type Dependency struct {
+	ItemID      uuid.UUID
+	BlockerID   uuid.UUID
+	DateCreated time.Time
+}
+
+// Graph represents a set of todo items and the dependencies between them.
+type Graph struct {
+	Items        []TodoItem
+	Dependencies []Dependency
+}
+
+// Column identifies a column on the board of a user. The items of every
+// status form a column of their own.
+type Column struct {
+	UserID uuid.UUID
+	Status status.Status
+}
+
+// BoardColumn represents the items of one status in the order they are
+// shown on the board.
+type BoardColumn struct {
+	Status status.Status
+	Items  []TodoItem
+}
+
+// Board represents the items of a user arranged in a column per status.
+type Board struct {
+	Columns []BoardColumn
+}
+
+// Move represents where an item is dropped on the board. The item goes into
+// the column of Status, or stays in its own without it, right after the item
+// BeforeID and right before the item AfterID. Without either neighbour the
+// item goes to the end of the column.
+type Move struct {
+	Status   *status.Status
+	BeforeID *uuid.UUID
+	AfterID  *uuid.UUID
+}
+
+// History represents a recorded change to a todo item.
+type History struct {
+	ID          uuid.UUID
+	TodoID      uuid.UUID
+	ActorID     uuid.UUID
+	Field       string
+	OldValue    string
+	NewValue    string
+	DateCreated time.Time
+}
+
+// Blob represents stored file contents. Blobs are addressed by the SHA-256
+// of their contents, so identical files are stored once and shared by every
+// attachment and todo item that refers to them.
+type Blob struct {
+	Checksum    string
+	FileID      string
+	ContentType string
+	Size        int
+	RefCount    int
+	DateCreated time.Time
+}
+
+// Object represents a file as it is stored in the bucket.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Attachment represents a file uploaded by a user. Image attachments gain
+// renditions once they have been generated in the background.
+type Attachment struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	FileID      string
+	Checksum    string
+	Name        string
+	ContentType string
+	Size        int
+	Renditions  []Rendition
+	DateCreated time.Time
+}
+
+// Rendition represents a downscaled copy of an image attachment that fits
+// within a square of Size pixels.
+type Rendition struct {
+	Size        int
+	FileID      string
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// normalizeDue returns the due date the way it is kept. All-day due dates are
+// kept as their calendar date and timed due dates to the second, so an item
+// reads back exactly as it was written.
+func normalizeDue(t time.Time, allDay bool) time.Time {
+	if allDay {
+		return dueDay(t)
+	}
+	return t.Truncate(time.Second)
+}
+
+// dueDay returns the calendar date of t as midnight UTC, which is how all-day
+// due dates are kept.
+func dueDay(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Change represents a todo item as it is after a change, along with the
+// version the change gave it. Versions come from one sequence shared by all
+// items, so they also order changes across items.
+type Change struct {
+	Item    TodoItem
+	Version int64
+}
+
+// Tombstone represents a todo item a user can no longer see, because it was
+// deleted or handed to another assignee.
+type Tombstone struct {
+	ItemID      uuid.UUID
+	Version     int64
+	DateCreated time.Time
+}
+
+// Mutation represents a change made to a todo item on a client while it was
+// offline. Creates carry the id the client gave the item. Updates and
+// deletes carry the version of the item the client changed, and are only
+// applied while the item is still at that version.
+type Mutation struct {
+	Type    MutationType
+	ItemID  uuid.UUID
+	Version int64
+	Create  NewTodoItem
+	Update  UpdateTodoItem
+}
+
+// MutationResult reports what became of a mutation. Err is nil when the
+// mutation was applied, or had been already.
+type MutationResult struct {
+	ItemID uuid.UUID
+	Err    error
+}
+
+// Sync represents the outcome of a sync: the results of the mutations sent
+// and the changes made since the token that was sent, oldest first. Token
+// is sent with the next sync. More reports that changes were left out, and
+// the client should sync again straight away.
+type Sync struct {
+	Results    []MutationResult
+	Changes    []Change
+	Tombstones []Tombstone
+	Token      int64
+	More       bool
 }
@@ -0,0 +1,55 @@
+package todobus
+
+import (
+	"time"
+
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+)
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDueDate, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "item_id"
+	OrderByDescription = "description"
+	OrderByDueDate     = "due_date"
+	OrderByStatus      = "status"
+	OrderByDateCreated = "date_created"
+	OrderByRank        = "rank"
+)
+
+// Cursor returns the position of the item in the order, for the next page to
+// start after it. The values are formatted the way the store reads them.
+func Cursor(item TodoItem, orderBy order.By) page.Cursor {
+	var value *string
+	set := func(v string) {
+		value = &v
+	}
+
+	switch orderBy.Field {
+	case OrderByID:
+		set(item.ID.String())
+	case OrderByDescription:
+		set(item.Description)
+	case OrderByDueDate:
+		if !item.DueDate.IsZero() {
+			set(item.DueDate.UTC().Format(time.RFC3339Nano))
+		}
+	case OrderByStatus:
+		set(item.Status.String())
+	case OrderByDateCreated:
+		set(item.DateCreated.UTC().Format(time.RFC3339Nano))
+	case OrderByRank:
+		if !item.Rank.IsZero() {
+			set(item.Rank.String())
+		}
+	}
+
+	return page.Cursor{
+		Order: orderBy,
+		Value: value,
+		ID:    item.ID.String(),
+	}
+}
@@ -0,0 +1,135 @@
+package todobus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// OrphanOptions controls a run of the orphaned file collector.
+type OrphanOptions struct {
+	Prefix      string        // Only keys starting with the prefix are looked at
+	GracePeriod time.Duration // Files younger than this are left alone
+	DryRun      bool          // Find the orphans but do not delete them
+	Report      bool          // List every orphan found in the report
+}
+
+// OrphanReport summarises a run of the orphaned file collector. Unlinked
+// counts the uploads no todo item took up within the grace period.
+type OrphanReport struct {
+	Unlinked    int
+	Scanned     int
+	Referenced  int
+	Recent      int
+	Orphans     int
+	OrphanBytes int64
+	Deleted     int
+	Failed      int
+	Objects     []Object
+}
+
+// CollectOrphans removes the uploads no todo item took up and then walks the
+// files in the bucket and deletes the ones nothing in the database refers to
+// any more. Uploads are made before the rows that point to them are
+// committed, and a todo item is created after its upload, so nothing younger
+// than the grace period is ever removed; the grace period must be longer than
+// any upload and the todo item that follows it can take.
+func (b *Business) CollectOrphans(ctx context.Context, opts OrphanOptions) (OrphanReport, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.collectorphans")
+	defer span.End()
+
+	cutoff := time.Now().Add(-opts.GracePeriod)
+
+	var report OrphanReport
+
+	// Removing an unlinked upload releases its contents like any other
+	// attachment, so its quota is refunded and the files go once nothing else
+	// shares them.
+	atts, err := b.storer.QueryUnlinkedAttachments(ctx, cutoff)
+	if err != nil {
+		return report, fmt.Errorf("queryunlinkedattachments: %w", err)
+	}
+
+	for _, att := range atts {
+		report.Unlinked++
+
+		if opts.DryRun {
+			continue
+		}
+
+		if err := b.DeleteAttachment(ctx, att); err != nil {
+			b.log.Warn(ctx, "failed to remove unlinked attachment", "attachmentID", att.ID, "error", err)
+			report.Failed++
+		}
+	}
+
+	page := func(objs []Object) error {
+		report.Scanned += len(objs)
+
+		var candidates []Object
+		for _, obj := range objs {
+			if obj.LastModified.After(cutoff) {
+				report.Recent++
+				continue
+			}
+			candidates = append(candidates, obj)
+		}
+
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		fileIDs := make([]string, len(candidates))
+		for i, obj := range candidates {
+			fileIDs[i] = obj.Key
+		}
+
+		referenced, err := b.storer.QueryReferencedFileIDs(ctx, fileIDs, cutoff)
+		if err != nil {
+			return fmt.Errorf("queryreferencedfileids: %w", err)
+		}
+
+		inUse := make(map[string]struct{}, len(referenced))
+		for _, fileID := range referenced {
+			inUse[fileID] = struct{}{}
+		}
+
+		for _, obj := range candidates {
+			if _, exists := inUse[obj.Key]; exists {
+				report.Referenced++
+				continue
+			}
+
+			report.Orphans++
+			report.OrphanBytes += obj.Size
+
+			if opts.Report {
+				report.Objects = append(report.Objects, obj)
+			}
+
+			if opts.DryRun {
+				continue
+			}
+
+			if err := b.s3Client.Delete(ctx, obj.Key); err != nil {
+				b.log.Warn(ctx, "failed to delete orphaned file from S3", "fileID", obj.Key, "error", err)
+				report.Failed++
+				continue
+			}
+
+			report.Deleted++
+		}
+
+		return nil
+	}
+
+	if err := b.s3Client.List(ctx, opts.Prefix, page); err != nil {
+		return report, fmt.Errorf("list: prefix[%s]: %w", opts.Prefix, err)
+	}
+
+	b.log.Info(ctx, "todobus: collectorphans", "unlinked", report.Unlinked, "scanned", report.Scanned, "orphans", report.Orphans, "deleted", report.Deleted, "failed", report.Failed, "dryRun", opts.DryRun)
+
+	return report, nil
+}
@@ -0,0 +1,105 @@
+package todobus_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/todobus/mocks"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+func Test_CollectOrphans(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	objs := []todobus.Object{
+		{Key: "blobs/referenced", Size: 10, LastModified: now.Add(-48 * time.Hour)},
+		{Key: "blobs/orphan", Size: 20, LastModified: now.Add(-48 * time.Hour)},
+		{Key: "blobs/recent", Size: 30, LastModified: now.Add(-time.Minute)},
+	}
+
+	// An upload from before contents were deduplicated that no todo item
+	// ever took up.
+	unlinked := todobus.Attachment{ID: uuid.New(), FileID: "legacy", Size: 40}
+
+	tests := []struct {
+		name    string
+		opts    todobus.OrphanOptions
+		deletes int
+		exp     todobus.OrphanReport
+	}{
+		{
+			name:    "delete",
+			opts:    todobus.OrphanOptions{GracePeriod: 24 * time.Hour},
+			deletes: 1,
+			exp: todobus.OrphanReport{
+				Unlinked:    1,
+				Scanned:     3,
+				Referenced:  1,
+				Recent:      1,
+				Orphans:     1,
+				OrphanBytes: 20,
+				Deleted:     1,
+			},
+		},
+		{
+			name: "dryrun",
+			opts: todobus.OrphanOptions{GracePeriod: 24 * time.Hour, DryRun: true, Report: true},
+			exp: todobus.OrphanReport{
+				Unlinked:    1,
+				Scanned:     3,
+				Referenced:  1,
+				Recent:      1,
+				Orphans:     1,
+				OrphanBytes: 20,
+				Objects:     []todobus.Object{objs[1]},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			traceIDFn := func(ctx context.Context) string {
+				return otel.GetTraceID(ctx)
+			}
+			log := logger.New(os.Stdout, logger.LevelInfo, "SALES", traceIDFn)
+
+			mockStorer := mocks.NewMockStorer(ctrl)
+			mockS3Client := mocks.NewMockS3Client(ctrl)
+
+			bus := todobus.NewBusiness(log, nil, nil, nil, mockStorer, nil, mockS3Client, nil, nil, nil)
+
+			mockS3Client.EXPECT().List(gomock.Any(), tt.opts.Prefix, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, fn func([]todobus.Object) error) error {
+					return fn(objs)
+				})
+
+			mockStorer.EXPECT().QueryUnlinkedAttachments(gomock.Any(), gomock.Any()).Return([]todobus.Attachment{unlinked}, nil)
+			mockStorer.EXPECT().DeleteAttachment(gomock.Any(), unlinked).Return(nil).Times(tt.deletes)
+
+			// Objects inside the grace period are never looked up.
+			mockStorer.EXPECT().QueryReferencedFileIDs(gomock.Any(), []string{"blobs/referenced", "blobs/orphan"}, gomock.Any()).Return([]string{"blobs/referenced"}, nil)
+			mockS3Client.EXPECT().Delete(gomock.Any(), "blobs/orphan").Return(nil).Times(tt.deletes)
+
+			report, err := bus.CollectOrphans(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("Should be able to collect orphans: %s", err)
+			}
+
+			if diff := cmp.Diff(report, tt.exp); diff != "" {
+				t.Errorf("Should get the expected report:\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,8 +2,13 @@ package todobus
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/types/rank"
 )
 
 // S3Client defines the interface for S3 operations.
@@ -11,6 +16,7 @@ type S3Client interface {
 	Upload(ctx context.Context, fileName string, data []byte) (string, error)
 	Download(ctx context.Context, fileID string) ([]byte, error)
 	Delete(ctx context.Context, fileID string) error
+	List(ctx context.Context, prefix string, fn func(objs []Object) error) error
 }
 
 // SQSClient defines the interface for SQS operations.
@@ -23,9 +29,41 @@ type SQSClient interface {
 // Storer interface declares the behavior this package needs to persist and
 // retrieve data.
 type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, item TodoItem) error
 	Update(ctx context.Context, item TodoItem) error
 	Delete(ctx context.Context, item TodoItem) error
 	QueryByID(ctx context.Context, itemID uuid.UUID) (TodoItem, error)
-	Query(ctx context.Context) ([]TodoItem, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]TodoItem, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	CreateHistory(ctx context.Context, hst History) error
+	QueryHistory(ctx context.Context, itemID uuid.UUID) ([]History, error)
+	AcquireBlob(ctx context.Context, blob Blob) (Blob, error)
+	AcquireBlobByFileID(ctx context.Context, fileID string) (Blob, error)
+	UpdateBlob(ctx context.Context, blob Blob) error
+	ReleaseBlob(ctx context.Context, checksum string) (Blob, error)
+	DeleteBlob(ctx context.Context, checksum string) error
+	QueryBlobByFileID(ctx context.Context, fileID string) (Blob, error)
+	QueryReferencedFileIDs(ctx context.Context, fileIDs []string, cutoff time.Time) ([]string, error)
+	QueryUnlinkedAttachments(ctx context.Context, cutoff time.Time) ([]Attachment, error)
+	CreateAttachment(ctx context.Context, att Attachment) error
+	DeleteAttachment(ctx context.Context, att Attachment) error
+	QueryAttachmentByID(ctx context.Context, attachmentID uuid.UUID) (Attachment, error)
+	CanAccessFile(ctx context.Context, userID uuid.UUID, fileID string) (bool, error)
+	IsFileOwner(ctx context.Context, userID uuid.UUID, fileID string) (bool, error)
+	CreateRendition(ctx context.Context, attachmentID uuid.UUID, rnd Rendition) error
+	LockDependencies(ctx context.Context) error
+	CreateDependency(ctx context.Context, dep Dependency) error
+	DeleteDependency(ctx context.Context, dep Dependency) error
+	QueryDependencies(ctx context.Context, itemIDs []uuid.UUID) ([]Dependency, error)
+	QueryUnblocked(ctx context.Context, blockerID uuid.UUID) ([]TodoItem, error)
+	LockBoard(ctx context.Context, userID uuid.UUID) error
+	QueryColumn(ctx context.Context, col Column) ([]TodoItem, error)
+	QueryLastRank(ctx context.Context, col Column) (rank.Rank, error)
+	UpdateRank(ctx context.Context, itemID uuid.UUID, r rank.Rank) error
+	QueryUnbalancedColumns(ctx context.Context, length int) ([]Column, error)
+	QueryWatermark(ctx context.Context, orgID uuid.UUID) (int64, bool, error)
+	LockItem(ctx context.Context, itemID uuid.UUID) (int64, error)
+	QueryChanges(ctx context.Context, userID uuid.UUID, after int64, upTo int64, limit int) ([]Change, error)
+	QueryTombstones(ctx context.Context, userID uuid.UUID, after int64, upTo int64, limit int) ([]Tombstone, error)
 }
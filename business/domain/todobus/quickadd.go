@@ -0,0 +1,663 @@
+package todobus
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/recurrence"
+)
+
+// ErrQuickAddEmpty is returned when nothing is left of a quick-add text to
+// describe the item once the rest of it was read.
+var ErrQuickAddEmpty = errors.New("quick add: description is required")
+
+// ParseQuickAdd turns a line of text such as "Pay rent every month on the
+// 1st #finance !p1" or "call mom tomorrow 6pm" into the item it describes.
+// Dates and times are read in the location of the user at the moment now.
+// The words that are not part of the due date, recurrence, priority or
+// labels make up the description. The caller still has to set the owner.
+//
+// The grammar is made of these parts, in any order:
+//
+//	#label @label    labels
+//	!p1 .. !p4       priority
+//	today, tomorrow, monday, next friday, in 3 days, jan 5, 5 jan 2025,
+//	2025-01-05, the 1st                       due date
+//	6pm, 6:30 pm, 18:00, noon, in 2 hours     due time
+//	daily, weekly, monthly, yearly, every day, every 2 weeks,
+//	every other month, every monday, every 15th, every jan 5   recurrence
+//
+// A date or time may follow "on", "at", "by" or "due". Only the first date,
+// time and recurrence are read, any later ones stay in the description. A
+// time without a date is today, or tomorrow once it has passed, and a
+// recurring item that would be due in the past is moved to its next
+// occurrence.
+func ParseQuickAdd(text string, now time.Time, loc *time.Location) (NewTodoItem, error) {
+	now = now.In(loc)
+
+	p := quickAdd{
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc),
+		raw:   strings.Fields(text),
+	}
+
+	p.words = make([]string, len(p.raw))
+	for i, word := range p.raw {
+		p.words[i] = strings.TrimRight(strings.ToLower(word), ",.;:!?")
+	}
+
+	for i := 0; i < len(p.words); {
+		if n := p.match(i); n > 0 {
+			i += n
+			continue
+		}
+
+		p.description = append(p.description, p.raw[i])
+		i++
+	}
+
+	if len(p.description) == 0 {
+		return NewTodoItem{}, ErrQuickAddEmpty
+	}
+
+	labels, err := label.ParseMany(p.labels)
+	if err != nil {
+		return NewTodoItem{}, err
+	}
+
+	nt := NewTodoItem{
+		Description: strings.Join(p.description, " "),
+		Priority:    p.priority,
+		Labels:      labels,
+	}
+
+	nt.DueDate, nt.AllDay = p.due()
+
+	if p.repeats {
+		var monthDay int
+		if p.frequency == recurrence.Monthly || p.frequency == recurrence.Yearly {
+			monthDay = nt.DueDate.Day()
+		}
+
+		if nt.Recurrence, err = recurrence.New(p.frequency, p.interval, monthDay); err != nil {
+			return NewTodoItem{}, err
+		}
+
+		nt.DueDate = p.upcoming(nt.Recurrence, nt.DueDate, nt.AllDay)
+	}
+
+	if nt.Priority.IsZero() {
+		nt.Priority = priority.Default
+	}
+
+	return nt, nil
+}
+
+// =============================================================================
+
+// maxOccurrences bounds the search for the first occurrence of a recurring
+// item that is not in the past.
+const maxOccurrences = 1000
+
+// quickAdd holds the state of reading a quick-add text.
+type quickAdd struct {
+	now   time.Time // In the location of the user
+	today time.Time // Midnight of today in the location of the user
+	raw   []string  // The words as they were written
+	words []string  // The words in lower case without trailing punctuation
+
+	description []string
+	labels      []string
+	priority    priority.Priority
+
+	date    time.Time // Midnight of the due day in the location of the user
+	hasDate bool
+
+	hour    int
+	minute  int
+	hasTime bool
+
+	moment    time.Time // Exact due moment, for "in 2 hours"
+	hasMoment bool
+
+	frequency recurrence.Frequency
+	interval  int
+	repeats   bool
+}
+
+// due returns the due date the parts that were read add up to.
+func (p *quickAdd) due() (time.Time, bool) {
+	switch {
+	case p.hasMoment:
+		return p.moment, false
+
+	case p.hasTime:
+		day := p.date
+		at := time.Date(day.Year(), day.Month(), day.Day(), p.hour, p.minute, 0, 0, p.now.Location())
+
+		if !p.hasDate && !p.repeats && at.Before(p.now) {
+			at = at.AddDate(0, 0, 1)
+		}
+
+		return at, false
+
+	case p.hasDate || p.repeats:
+		return dueDay(p.date), true
+	}
+
+	return time.Time{}, false
+}
+
+// upcoming moves the due date of a recurring item forward until it is not
+// in the past.
+func (p *quickAdd) upcoming(rule recurrence.Rule, dueDate time.Time, allDay bool) time.Time {
+	past := func(t time.Time) bool {
+		if allDay {
+			return t.Before(dueDay(p.today))
+		}
+		return t.Before(p.now)
+	}
+
+	for range maxOccurrences {
+		if !past(dueDate) {
+			break
+		}
+		dueDate = rule.Next(dueDate)
+	}
+
+	return dueDate
+}
+
+// match reads the part that starts at the word and returns how many words
+// it took, zero when the word is not the start of a part.
+func (p *quickAdd) match(i int) int {
+	word := p.words[i]
+
+	switch {
+	case len(word) > 1 && (word[0] == '#' || word[0] == '@'):
+		if _, err := label.Parse(word[1:]); err != nil {
+			return 0
+		}
+		p.labels = append(p.labels, word[1:])
+		return 1
+
+	case len(word) == 3 && word[0] == '!' && p.priority.IsZero():
+		pri, err := priority.Parse(word[1:])
+		if err != nil {
+			return 0
+		}
+		p.priority = pri
+		return 1
+	}
+
+	return p.matchWhen(i, false)
+}
+
+// matchWhen reads a due date, due time or recurrence. A word that joins
+// them to the description, such as "on" or "at", is read along with them.
+func (p *quickAdd) matchWhen(i int, joined bool) int {
+	switch p.words[i] {
+	case "on", "at", "by", "due":
+		if i+1 < len(p.words) {
+			if n := p.matchWhen(i+1, true); n > 0 {
+				return n + 1
+			}
+		}
+		return 0
+	}
+
+	if !p.repeats {
+		if n := p.matchRecurrence(i); n > 0 {
+			return n
+		}
+	}
+
+	if !p.hasDate && !p.hasMoment {
+		if n := p.matchDate(i, joined); n > 0 {
+			return n
+		}
+	}
+
+	if !p.hasTime && !p.hasMoment {
+		if n := p.matchTime(i); n > 0 {
+			return n
+		}
+	}
+
+	return 0
+}
+
+// matchRecurrence reads how often the item repeats.
+func (p *quickAdd) matchRecurrence(i int) int {
+	repeat := func(frequency recurrence.Frequency, interval int) {
+		p.frequency = frequency
+		p.interval = interval
+		p.repeats = true
+		if !p.hasDate {
+			p.date = p.today
+		}
+	}
+
+	switch p.words[i] {
+	case "daily":
+		repeat(recurrence.Daily, 1)
+		return 1
+	case "weekly":
+		repeat(recurrence.Weekly, 1)
+		return 1
+	case "monthly":
+		repeat(recurrence.Monthly, 1)
+		return 1
+	case "yearly", "annually":
+		repeat(recurrence.Yearly, 1)
+		return 1
+	case "every":
+	default:
+		return 0
+	}
+
+	if i+1 >= len(p.words) {
+		return 0
+	}
+	next := p.words[i+1]
+
+	// every day, every week, ...
+	if frequency, exists := units[next]; exists {
+		repeat(frequency, 1)
+		return 2
+	}
+
+	// every other week, every 3 months, ...
+	if i+2 < len(p.words) {
+		interval, isNumber := parseNumber(next)
+		if next == "other" {
+			interval, isNumber = 2, true
+		}
+
+		if frequency, exists := units[p.words[i+2]]; exists && isNumber && interval > 0 {
+			repeat(frequency, interval)
+			return 3
+		}
+	}
+
+	// every monday
+	if wd, exists := weekdays[next]; exists && !p.hasDate {
+		repeat(recurrence.Weekly, 1)
+		p.date = nextWeekday(p.today, wd, false)
+		p.hasDate = true
+		return 2
+	}
+
+	// every 15th
+	if day, ok := parseOrdinal(next); ok && !p.hasDate {
+		repeat(recurrence.Monthly, 1)
+		p.date = nextMonthDay(p.today, day)
+		p.hasDate = true
+		return 2
+	}
+
+	// every jan 5
+	if !p.hasDate {
+		if date, n := p.parseMonthDay(i + 1); n > 0 {
+			repeat(recurrence.Yearly, 1)
+			p.date = date
+			p.hasDate = true
+			return n + 1
+		}
+	}
+
+	return 0
+}
+
+// matchDate reads the due date. A bare ordinal such as "1st" is only read as
+// a date when it was joined to the description, as in "on 1st".
+func (p *quickAdd) matchDate(i int, joined bool) int {
+	word := p.words[i]
+
+	setDate := func(date time.Time, n int) int {
+		p.date = date
+		p.hasDate = true
+		return n
+	}
+
+	switch word {
+	case "today":
+		return setDate(p.today, 1)
+
+	case "tomorrow", "tmrw", "tmr":
+		return setDate(p.today.AddDate(0, 0, 1), 1)
+	}
+
+	if wd, exists := weekdays[word]; exists {
+		return setDate(nextWeekday(p.today, wd, false), 1)
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, word, p.now.Location()); err == nil {
+		return setDate(t, 1)
+	}
+
+	if date, n := p.parseMonthDay(i); n > 0 {
+		return setDate(date, n)
+	}
+
+	if day, ok := parseOrdinal(word); ok && joined {
+		return setDate(nextMonthDay(p.today, day), 1)
+	}
+
+	if i+1 >= len(p.words) {
+		return 0
+	}
+	next := p.words[i+1]
+
+	switch word {
+	case "next", "this":
+		if wd, exists := weekdays[next]; exists {
+			return setDate(nextWeekday(p.today, wd, word == "next"), 2)
+		}
+
+		if word == "next" {
+			switch next {
+			case "week":
+				return setDate(p.today.AddDate(0, 0, 7), 2)
+			case "month":
+				return setDate(p.today.AddDate(0, 1, 0), 2)
+			case "year":
+				return setDate(p.today.AddDate(1, 0, 0), 2)
+			}
+		}
+
+	case "the":
+		if day, ok := parseOrdinal(next); ok {
+			return setDate(nextMonthDay(p.today, day), 2)
+		}
+
+	case "in":
+		if i+2 >= len(p.words) {
+			return 0
+		}
+
+		n, isNumber := parseNumber(next)
+		if !isNumber || n <= 0 {
+			return 0
+		}
+
+		switch p.words[i+2] {
+		case "day", "days":
+			return setDate(p.today.AddDate(0, 0, n), 3)
+		case "week", "weeks":
+			return setDate(p.today.AddDate(0, 0, 7*n), 3)
+		case "month", "months":
+			return setDate(p.today.AddDate(0, n, 0), 3)
+		case "year", "years":
+			return setDate(p.today.AddDate(n, 0, 0), 3)
+
+		case "hour", "hours", "hr", "hrs":
+			if p.hasDate || p.hasTime {
+				return 0
+			}
+			p.moment = p.now.Add(time.Duration(n) * time.Hour).Truncate(time.Minute)
+			p.hasMoment = true
+			return 3
+
+		case "minute", "minutes", "min", "mins":
+			if p.hasDate || p.hasTime {
+				return 0
+			}
+			p.moment = p.now.Add(time.Duration(n) * time.Minute).Truncate(time.Minute)
+			p.hasMoment = true
+			return 3
+		}
+	}
+
+	return 0
+}
+
+// parseMonthDay reads a date written as "jan 5", "january 5th", "5 jan" or
+// "5th of january", followed by an optional year. Without a year the date is
+// the next one that is not in the past.
+func (p *quickAdd) parseMonthDay(i int) (time.Time, int) {
+	var month time.Month
+	var day int
+	var n int
+
+	at := func(j int) string {
+		if j < len(p.words) {
+			return p.words[j]
+		}
+		return ""
+	}
+
+	switch m, exists := months[at(i)]; {
+	case exists:
+		d, ok := parseDay(at(i + 1))
+		if !ok {
+			return time.Time{}, 0
+		}
+		month, day, n = m, d, 2
+
+	default:
+		d, ok := parseDay(at(i))
+		if !ok {
+			return time.Time{}, 0
+		}
+
+		j := i + 1
+		if at(j) == "of" {
+			j++
+		}
+
+		m, exists := months[at(j)]
+		if !exists {
+			return time.Time{}, 0
+		}
+		month, day, n = m, d, j-i+1
+	}
+
+	loc := p.now.Location()
+
+	if year, err := strconv.Atoi(at(i + n)); err == nil && len(at(i+n)) == 4 {
+		date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		if date.Day() != day {
+			return time.Time{}, 0
+		}
+		return date, n + 1
+	}
+
+	for year := p.today.Year(); year <= p.today.Year()+8; year++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		if date.Day() == day && !date.Before(p.today) {
+			return date, n
+		}
+	}
+
+	return time.Time{}, 0
+}
+
+var (
+	clock12RegEx = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+	clock24RegEx = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+)
+
+// matchTime reads the due time.
+func (p *quickAdd) matchTime(i int) int {
+	setTime := func(hour int, minute int, n int) int {
+		if hour > 23 || minute > 59 {
+			return 0
+		}
+		p.hour = hour
+		p.minute = minute
+		p.hasTime = true
+		if !p.hasDate && !p.repeats {
+			p.date = p.today
+		}
+		return n
+	}
+
+	word := p.words[i]
+
+	if word == "noon" {
+		return setTime(12, 0, 1)
+	}
+
+	// 6 pm
+	if i+1 < len(p.words) && (p.words[i+1] == "am" || p.words[i+1] == "pm") {
+		word += p.words[i+1]
+		if m := clock12RegEx.FindStringSubmatch(word); m != nil {
+			hour, minute, ok := to24(m[1], m[2], m[3])
+			if !ok {
+				return 0
+			}
+			return setTime(hour, minute, 2)
+		}
+		return 0
+	}
+
+	if m := clock12RegEx.FindStringSubmatch(word); m != nil {
+		hour, minute, ok := to24(m[1], m[2], m[3])
+		if !ok {
+			return 0
+		}
+		return setTime(hour, minute, 1)
+	}
+
+	if m := clock24RegEx.FindStringSubmatch(word); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		return setTime(hour, minute, 1)
+	}
+
+	return 0
+}
+
+// to24 converts the parts of a 12-hour clock time to a 24-hour one.
+func to24(hour string, minute string, meridiem string) (int, int, bool) {
+	h, _ := strconv.Atoi(hour)
+	if h < 1 || h > 12 {
+		return 0, 0, false
+	}
+
+	var m int
+	if minute != "" {
+		m, _ = strconv.Atoi(minute)
+	}
+
+	h %= 12
+	if meridiem == "pm" {
+		h += 12
+	}
+
+	return h, m, true
+}
+
+// =============================================================================
+
+var units = map[string]recurrence.Frequency{
+	"day": recurrence.Daily, "days": recurrence.Daily,
+	"week": recurrence.Weekly, "weeks": recurrence.Weekly,
+	"month": recurrence.Monthly, "months": recurrence.Monthly,
+	"year": recurrence.Yearly, "years": recurrence.Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var numbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var ordinalRegEx = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)$`)
+
+// parseNumber reads a count written in digits or as a word.
+func parseNumber(word string) (int, bool) {
+	if n, exists := numbers[word]; exists {
+		return n, true
+	}
+
+	n, err := strconv.Atoi(word)
+	if err != nil || n > 999 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// parseOrdinal reads a day of the month written as "1st" or "15th".
+func parseOrdinal(word string) (int, bool) {
+	m := ordinalRegEx.FindStringSubmatch(word)
+	if m == nil {
+		return 0, false
+	}
+
+	day, _ := strconv.Atoi(m[1])
+	if day < 1 || day > 31 {
+		return 0, false
+	}
+
+	return day, true
+}
+
+// parseDay reads a day of the month written as "5" or "5th".
+func parseDay(word string) (int, bool) {
+	if day, ok := parseOrdinal(word); ok {
+		return day, true
+	}
+
+	day, err := strconv.Atoi(word)
+	if err != nil || len(word) > 2 || day < 1 || day > 31 {
+		return 0, false
+	}
+
+	return day, true
+}
+
+// nextWeekday returns the first day on the weekday from today on. A strict
+// search starts tomorrow.
+func nextWeekday(today time.Time, wd time.Weekday, strict bool) time.Time {
+	days := (int(wd) - int(today.Weekday()) + 7) % 7
+	if days == 0 && strict {
+		days = 7
+	}
+
+	return today.AddDate(0, 0, days)
+}
+
+// nextMonthDay returns the first date on the day of the month from today
+// on, skipping months that are too short to have it.
+func nextMonthDay(today time.Time, day int) time.Time {
+	for i := range 12 {
+		date := time.Date(today.Year(), today.Month()+time.Month(i), day, 0, 0, 0, 0, today.Location())
+		if date.Day() == day && !date.Before(today) {
+			return date
+		}
+	}
+
+	return today
+}
@@ -0,0 +1,314 @@
+package todobus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/recurrence"
+)
+
+func Test_ParseQuickAdd(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %s", err)
+	}
+
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatalf("load location: %s", err)
+	}
+
+	// 2024-03-13 14:00 UTC is Wednesday 10:00 in New York, three days after
+	// daylight saving time started, and already Thursday in Auckland.
+	now := time.Date(2024, time.March, 13, 14, 0, 0, 0, time.UTC)
+
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	at := func(month time.Month, d int, hour int, minute int) time.Time {
+		return time.Date(2024, month, d, hour, minute, 0, 0, newYork)
+	}
+	labels := func(values ...string) []label.Label {
+		lbls := make([]label.Label, len(values))
+		for i, v := range values {
+			lbls[i] = label.MustParse(v)
+		}
+		return lbls
+	}
+
+	tests := []struct {
+		name string
+		text string
+		loc  *time.Location
+		exp  NewTodoItem
+		err  error
+	}{
+		// Descriptions, labels and priorities.
+		{
+			name: "plain",
+			text: "buy milk",
+			exp:  NewTodoItem{Description: "buy milk", Priority: priority.P4},
+		},
+		{
+			name: "labels-priority",
+			text: "email bob@example.com #Work, !P2 @home",
+			exp:  NewTodoItem{Description: "email bob@example.com", Priority: priority.P2, Labels: labels("work", "home")},
+		},
+		{
+			name: "duplicate-labels",
+			text: "#home fix sink #home",
+			exp:  NewTodoItem{Description: "fix sink", Priority: priority.P4, Labels: labels("home")},
+		},
+		{
+			name: "invalid-tags",
+			text: "fix #! and !p9 now",
+			exp:  NewTodoItem{Description: "fix #! and !p9 now", Priority: priority.P4},
+		},
+		{
+			name: "second-priority",
+			text: "ship !p1 !p3",
+			exp:  NewTodoItem{Description: "ship !p3", Priority: priority.P1},
+		},
+		{
+			name: "only-tags",
+			text: "!p1 #work",
+			err:  ErrQuickAddEmpty,
+		},
+		{
+			name: "only-date",
+			text: "tomorrow at 6pm",
+			err:  ErrQuickAddEmpty,
+		},
+		{
+			name: "empty",
+			text: "   ",
+			err:  ErrQuickAddEmpty,
+		},
+
+		// Dates.
+		{
+			name: "today",
+			text: "buy milk today",
+			exp:  NewTodoItem{Description: "buy milk", DueDate: day(2024, time.March, 13), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "tomorrow-auckland",
+			text: "call tomorrow",
+			loc:  auckland,
+			exp:  NewTodoItem{Description: "call", DueDate: day(2024, time.March, 15), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "weekday-today",
+			text: "review wednesday",
+			exp:  NewTodoItem{Description: "review", DueDate: day(2024, time.March, 13), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "next-weekday",
+			text: "review next wed",
+			exp:  NewTodoItem{Description: "review", DueDate: day(2024, time.March, 20), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "joined-weekday",
+			text: "call back on monday, maybe",
+			exp:  NewTodoItem{Description: "call back maybe", DueDate: day(2024, time.March, 18), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "next-week",
+			text: "plan next week",
+			exp:  NewTodoItem{Description: "plan", DueDate: day(2024, time.March, 20), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "in-days",
+			text: "ship in 3 days",
+			exp:  NewTodoItem{Description: "ship", DueDate: day(2024, time.March, 16), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "in-a-month",
+			text: "renew in a month",
+			exp:  NewTodoItem{Description: "renew", DueDate: day(2024, time.April, 13), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "month-day",
+			text: "taxes due apr 15th",
+			exp:  NewTodoItem{Description: "taxes", DueDate: day(2024, time.April, 15), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "day-of-month-past",
+			text: "party 5th of january",
+			exp:  NewTodoItem{Description: "party", DueDate: day(2025, time.January, 5), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "month-day-year",
+			text: "trip jan 5 2026",
+			exp:  NewTodoItem{Description: "trip", DueDate: day(2026, time.January, 5), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "iso-date",
+			text: "report 2024-05-01",
+			exp:  NewTodoItem{Description: "report", DueDate: day(2024, time.May, 1), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "the-ordinal",
+			text: "rent on the 1st",
+			exp:  NewTodoItem{Description: "rent", DueDate: day(2024, time.April, 1), AllDay: true, Priority: priority.P4},
+		},
+		{
+			name: "bare-ordinal",
+			text: "write 1st draft",
+			exp:  NewTodoItem{Description: "write 1st draft", Priority: priority.P4},
+		},
+		{
+			name: "may-as-a-word",
+			text: "may the force be with you",
+			exp:  NewTodoItem{Description: "may the force be with you", Priority: priority.P4},
+		},
+		{
+			name: "numbers-as-words",
+			text: "read 5 books in march",
+			exp:  NewTodoItem{Description: "read 5 books in march", Priority: priority.P4},
+		},
+		{
+			name: "second-date",
+			text: "move friday to monday",
+			exp:  NewTodoItem{Description: "move to monday", DueDate: day(2024, time.March, 15), AllDay: true, Priority: priority.P4},
+		},
+
+		// Times.
+		{
+			name: "tomorrow-time",
+			text: "call mom tomorrow 6pm",
+			exp:  NewTodoItem{Description: "call mom", DueDate: at(time.March, 14, 18, 0), Priority: priority.P4},
+		},
+		{
+			name: "time-before-date",
+			text: "dentist at 3 pm friday",
+			exp:  NewTodoItem{Description: "dentist", DueDate: at(time.March, 15, 15, 0), Priority: priority.P4},
+		},
+		{
+			name: "time-today",
+			text: "lunch at noon",
+			exp:  NewTodoItem{Description: "lunch", DueDate: at(time.March, 13, 12, 0), Priority: priority.P4},
+		},
+		{
+			name: "time-passed",
+			text: "standup 9:30am",
+			exp:  NewTodoItem{Description: "standup", DueDate: at(time.March, 14, 9, 30), Priority: priority.P4},
+		},
+		{
+			name: "time-24h",
+			text: "deploy 17:45",
+			exp:  NewTodoItem{Description: "deploy", DueDate: at(time.March, 13, 17, 45), Priority: priority.P4},
+		},
+		{
+			name: "midnight-12am",
+			text: "backup tomorrow 12am",
+			exp:  NewTodoItem{Description: "backup", DueDate: at(time.March, 14, 0, 0), Priority: priority.P4},
+		},
+		{
+			name: "invalid-time",
+			text: "meet 25:00 13pm",
+			exp:  NewTodoItem{Description: "meet 25:00 13pm", Priority: priority.P4},
+		},
+		{
+			name: "second-time",
+			text: "meet 6pm 7pm",
+			exp:  NewTodoItem{Description: "meet 7pm", DueDate: at(time.March, 13, 18, 0), Priority: priority.P4},
+		},
+		{
+			name: "in-hours",
+			text: "check oven in 2 hours",
+			exp:  NewTodoItem{Description: "check oven", DueDate: at(time.March, 13, 12, 0), Priority: priority.P4},
+		},
+		{
+			name: "date-and-time",
+			text: "finish in 2 weeks by 5pm",
+			exp:  NewTodoItem{Description: "finish", DueDate: at(time.March, 27, 17, 0), Priority: priority.P4},
+		},
+
+		// Recurrence.
+		{
+			name: "request-example",
+			text: "Pay rent every month on the 1st #finance !p1 @home",
+			exp: NewTodoItem{
+				Description: "Pay rent",
+				DueDate:     day(2024, time.April, 1),
+				AllDay:      true,
+				Priority:    priority.P1,
+				Recurrence:  recurrence.MustNew(recurrence.Monthly, 1, 1),
+				Labels:      labels("finance", "home"),
+			},
+		},
+		{
+			name: "daily",
+			text: "stretch daily",
+			exp:  NewTodoItem{Description: "stretch", DueDate: day(2024, time.March, 13), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Daily, 1, 0)},
+		},
+		{
+			name: "every-other-day",
+			text: "water plants every other day",
+			exp:  NewTodoItem{Description: "water plants", DueDate: day(2024, time.March, 13), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Daily, 2, 0)},
+		},
+		{
+			name: "every-two-weeks",
+			text: "sync every two weeks",
+			exp:  NewTodoItem{Description: "sync", DueDate: day(2024, time.March, 13), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Weekly, 2, 0)},
+		},
+		{
+			name: "every-weekday-time",
+			text: "gym every monday 7am",
+			exp:  NewTodoItem{Description: "gym", DueDate: at(time.March, 18, 7, 0), Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Weekly, 1, 0)},
+		},
+		{
+			name: "every-today-passed",
+			text: "backup every day at 9am",
+			exp:  NewTodoItem{Description: "backup", DueDate: at(time.March, 14, 9, 0), Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Daily, 1, 0)},
+		},
+		{
+			name: "every-ordinal",
+			text: "invoice every 15th",
+			exp:  NewTodoItem{Description: "invoice", DueDate: day(2024, time.March, 15), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Monthly, 1, 15)},
+		},
+		{
+			name: "every-month-end",
+			text: "close books monthly on the 31st",
+			exp:  NewTodoItem{Description: "close books", DueDate: day(2024, time.March, 31), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Monthly, 1, 31)},
+		},
+		{
+			name: "every-date",
+			text: "birthday every mar 1",
+			exp:  NewTodoItem{Description: "birthday", DueDate: day(2025, time.March, 1), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Yearly, 1, 1)},
+		},
+		{
+			name: "past-date-recurring",
+			text: "review 2024-01-10 every 4 weeks",
+			exp:  NewTodoItem{Description: "review", DueDate: day(2024, time.April, 3), AllDay: true, Priority: priority.P4, Recurrence: recurrence.MustNew(recurrence.Weekly, 4, 0)},
+		},
+		{
+			name: "every-unknown",
+			text: "smile every weekday",
+			exp:  NewTodoItem{Description: "smile every weekday", Priority: priority.P4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := tt.loc
+			if loc == nil {
+				loc = newYork
+			}
+
+			got, err := ParseQuickAdd(tt.text, now, loc)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, exp %v", err, tt.err)
+			}
+
+			if diff := cmp.Diff(got, tt.exp); diff != "" {
+				t.Errorf("got diff:\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,12 +7,23 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/himynamej/todo/business/domain/todobus"
 )
 
+// Config represents the bucket to use and how to reach it. Credentials come
+// from the default AWS chain: the environment, shared files or the instance
+// role.
+type Config struct {
+	Bucket   string
+	Region   string
+	Endpoint string // Set for S3 compatible stores such as MinIO
+}
+
 // Client manages interactions with S3.
 type Client struct {
 	s3Client     s3iface.S3API
@@ -31,7 +42,27 @@ func NewClient(s3Client s3iface.S3API, uploader s3manageriface.UploaderAPI, down
 	}
 }
 
-// Upload uploads a file to S3 and returns its file ID (key).
+// Open constructs a client for the configured bucket.
+func Open(cfg Config) (*Client, error) {
+	awsCfg := aws.Config{
+		Region: aws.String(cfg.Region),
+	}
+
+	if cfg.Endpoint != "" {
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+		awsCfg.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	sess, err := session.NewSession(&awsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("aws session: %w", err)
+	}
+
+	return NewClient(s3.New(sess), s3manager.NewUploader(sess), s3manager.NewDownloader(sess), cfg.Bucket), nil
+}
+
+// Upload uploads a file to S3 and returns its file ID, which is the key, so
+// it can be handed straight back to Download and Delete.
 func (c *Client) Upload(ctx context.Context, fileName string, data []byte) (string, error) {
 	input := &s3manager.UploadInput{
 		Bucket: aws.String(c.bucketName),
@@ -39,12 +70,11 @@ func (c *Client) Upload(ctx context.Context, fileName string, data []byte) (stri
 		Body:   aws.ReadSeekCloser(bytes.NewReader(data)),
 	}
 
-	result, err := c.s3Uploader.UploadWithContext(ctx, input)
-	if err != nil {
+	if _, err := c.s3Uploader.UploadWithContext(ctx, input); err != nil {
 		return "", fmt.Errorf("failed to upload file to S3: %w", err)
 	}
 
-	return result.Location, nil
+	return fileName, nil
 }
 
 // Download retrieves a file from S3 by its file ID (key).
@@ -74,3 +104,33 @@ func (c *Client) Delete(ctx context.Context, fileID string) error {
 
 	return nil
 }
+
+// List walks the files whose keys start with the prefix, handing them to fn
+// one page at a time. Walking stops at the first error fn returns.
+func (c *Client) List(ctx context.Context, prefix string, fn func(objs []todobus.Object) error) error {
+	input := s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucketName),
+		Prefix: aws.String(prefix),
+	}
+
+	var fnErr error
+	page := func(out *s3.ListObjectsV2Output, lastPage bool) bool {
+		objs := make([]todobus.Object, len(out.Contents))
+		for i, obj := range out.Contents {
+			objs[i] = todobus.Object{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+			}
+		}
+
+		fnErr = fn(objs)
+		return fnErr == nil
+	}
+
+	if err := c.s3Client.ListObjectsV2PagesWithContext(ctx, &input, page); err != nil {
+		return fmt.Errorf("failed to list files in S3: %w", err)
+	}
+
+	return fnErr
+}
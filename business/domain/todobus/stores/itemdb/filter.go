@@ -0,0 +1,84 @@
+package itemdb
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/todobus"
+)
+
+// orgScope limits a query to the rows of the organization in :org_id. Every
+// row passes when it is null, which is how work that spans organizations
+// reads.
+const orgScope = "(CAST(:org_id AS UUID) IS NULL OR org_id = CAST(:org_id AS UUID))"
+
+func applyFilter(filter todobus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
+
+	if filter.ID != nil {
+		data["item_id"] = *filter.ID
+		wc = append(wc, "item_id = :item_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.AssigneeID != nil {
+		data["assignee_id"] = *filter.AssigneeID
+		wc = append(wc, "assignee_id = :assignee_id")
+	}
+
+	if filter.Status != nil {
+		data["status"] = filter.Status.String()
+		wc = append(wc, "status = :status")
+	}
+
+	if filter.ExcludeStatus != nil {
+		data["exclude_status"] = filter.ExcludeStatus.String()
+		wc = append(wc, "status <> :exclude_status")
+	}
+
+	if filter.StartDueDate != nil {
+		data["start_due_date"] = filter.StartDueDate.UTC()
+		data["start_due_day"] = dueDay(*filter.StartDueDate)
+		wc = append(wc, "((all_day = FALSE AND due_date >= :start_due_date) OR (all_day = TRUE AND due_date >= :start_due_day))")
+	}
+
+	if filter.EndDueDate != nil {
+		data["end_due_date"] = filter.EndDueDate.UTC()
+		data["end_due_day"] = dueDay(*filter.EndDueDate)
+		wc = append(wc, "((all_day = FALSE AND due_date < :end_due_date) OR (all_day = TRUE AND due_date < :end_due_day))")
+	}
+
+	if filter.NoDueDate != nil {
+		switch *filter.NoDueDate {
+		case true:
+			wc = append(wc, "due_date IS NULL")
+		default:
+			wc = append(wc, "due_date IS NOT NULL")
+		}
+	}
+
+	if filter.List != nil {
+		data["list"] = filter.List.String()
+		wc = append(wc, "list = :list")
+	}
+
+	if filter.Label != nil {
+		data["label"] = filter.Label.String()
+		wc = append(wc, ":label = ANY(labels)")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
+
+// dueDay returns the calendar date t falls on in its own location as midnight
+// UTC, matching how all-day due dates are stored.
+func dueDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -4,16 +4,30 @@ package itemdb
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
 	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/sqldb/dbarray"
+	"github.com/himynamej/todo/business/types/rank"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/jmoiron/sqlx"
 )
 
+// blockedColumn works out whether an item is blocked by any item that is not
+// done yet.
+const blockedColumn = `EXISTS (
+			SELECT 1 FROM todo_dependencies d JOIN todo_items b ON b.item_id = d.blocker_id
+			WHERE d.item_id = todo_items.item_id AND b.status <> 'DONE'
+		) AS blocked`
+
 // Store manages the set of APIs for TodoItem database access.
 type Store struct {
 	log *logger.Logger
@@ -48,9 +62,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (todobus.Storer, error) {
 func (s *Store) Create(ctx context.Context, item todobus.TodoItem) error {
 	const q = `
 	INSERT INTO todo_items
-		(item_id, description, due_date, file_id, date_created, date_updated)
+		(item_id, org_id, user_id, assignee_id, description, due_date, all_day, file_id, status, priority, recurrence, list, labels, checklist, rank, date_created, date_updated, date_completed)
 	VALUES
-		(:item_id, :description, :due_date, :file_id, :date_created, :date_updated)`
+		(:item_id, :org_id, :user_id, :assignee_id, :description, :due_date, :all_day, :file_id, :status, :priority, :recurrence, :list, :labels, :checklist, :rank, :date_created, :date_updated, :date_completed)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBTodoItem(item)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -67,8 +81,18 @@ func (s *Store) Update(ctx context.Context, item todobus.TodoItem) error {
 	SET 
 		description = :description,
 		due_date = :due_date,
+		all_day = :all_day,
 		file_id = :file_id,
-		date_updated = :date_updated
+		assignee_id = :assignee_id,
+		status = :status,
+		priority = :priority,
+		recurrence = :recurrence,
+		list = :list,
+		labels = :labels,
+		checklist = :checklist,
+		rank = :rank,
+		date_updated = :date_updated,
+		date_completed = :date_completed
 	WHERE
 		item_id = :item_id`
 
@@ -95,38 +119,102 @@ func (s *Store) Delete(ctx context.Context, item todobus.TodoItem) error {
 }
 
 // Query retrieves a list of existing TodoItems from the database.
-func (s *Store) Query(ctx context.Context) ([]todobus.TodoItem, error) {
+func (s *Store) Query(ctx context.Context, filter todobus.QueryFilter, orderBy order.By, page page.Page) ([]todobus.TodoItem, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+		"org_id":        sqldb.OrgArg(ctx),
+	}
+
 	const q = `
-    SELECT
-        item_id, description, due_date, file_id, date_created, date_updated
-    FROM
-        todo_items`
+	SELECT
+		item_id, org_id, user_id, assignee_id, description, due_date, all_day, file_id, status, priority, recurrence, list, labels, checklist, rank, date_created, date_updated, date_completed,
+		` + blockedColumn + `
+	FROM
+		todo_items`
 
 	buf := bytes.NewBufferString(q)
 
+	cur, isCursor := page.Cursor()
+
+	switch isCursor {
+	case true:
+		column, err := orderByColumn(cur.Order)
+		if err != nil {
+			return nil, err
+		}
+
+		where, orderByClause := sqldb.Keyset(cur, column, idColumn, data)
+		applyFilter(filter, data, buf, orgScope, where)
+		buf.WriteString(orderByClause)
+		buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	default:
+		orderByClause, err := orderByClause(orderBy)
+		if err != nil {
+			return nil, err
+		}
+
+		applyFilter(filter, data, buf, orgScope)
+		buf.WriteString(orderByClause)
+		buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+	}
+
 	var dbItems []dbTodoItem
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, buf, &dbItems); err != nil {
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbItems); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
+	if isCursor && cur.Before {
+		slices.Reverse(dbItems)
+	}
+
 	return toBusTodoItems(dbItems)
 }
 
+// Count returns the total number of TodoItems in the DB.
+func (s *Store) Count(ctx context.Context, filter todobus.QueryFilter) (int, error) {
+	data := map[string]any{
+		"org_id": sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		todo_items`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf, orgScope)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
 // QueryByID retrieves a specific TodoItem from the database by ID.
 func (s *Store) QueryByID(ctx context.Context, itemID uuid.UUID) (todobus.TodoItem, error) {
 	data := struct {
-		ID string `db:"item_id"`
+		ID    string        `db:"item_id"`
+		OrgID uuid.NullUUID `db:"org_id"`
 	}{
-		ID: itemID.String(),
+		ID:    itemID.String(),
+		OrgID: sqldb.OrgArg(ctx),
 	}
 
 	const q = `
 	SELECT
-		item_id, description, due_date, file_id, date_created, date_updated
+		item_id, org_id, user_id, assignee_id, description, due_date, all_day, file_id, status, priority, recurrence, list, labels, checklist, rank, date_created, date_updated, date_completed,
+		` + blockedColumn + `
 	FROM
 		todo_items
-	WHERE 
-		item_id = :item_id`
+	WHERE
+		item_id = :item_id AND ` + orgScope
 
 	var dbItem dbTodoItem
 	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbItem); err != nil {
@@ -138,3 +226,815 @@ func (s *Store) QueryByID(ctx context.Context, itemID uuid.UUID) (todobus.TodoIt
 
 	return toBusTodoItem(dbItem)
 }
+
+// CreateHistory inserts a new history entry into the database.
+func (s *Store) CreateHistory(ctx context.Context, hst todobus.History) error {
+	const q = `
+	INSERT INTO todo_history
+		(history_id, item_id, actor_id, field, old_value, new_value, date_created)
+	VALUES
+		(:history_id, :item_id, :actor_id, :field, :old_value, :new_value, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBHistory(hst)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryHistory retrieves the history entries for a TodoItem, oldest first.
+func (s *Store) QueryHistory(ctx context.Context, itemID uuid.UUID) ([]todobus.History, error) {
+	data := struct {
+		ID string `db:"item_id"`
+	}{
+		ID: itemID.String(),
+	}
+
+	const q = `
+	SELECT
+		history_id, item_id, actor_id, field, old_value, new_value, date_created
+	FROM
+		todo_history
+	WHERE
+		item_id = :item_id
+	ORDER BY
+		date_created`
+
+	var dbHsts []dbHistory
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbHsts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusHistories(dbHsts), nil
+}
+
+// AcquireBlob records a reference to the blob, inserting it when these
+// contents have not been stored before. The returned blob carries the file id
+// of the stored contents, which is empty while the contents still need to be
+// uploaded.
+func (s *Store) AcquireBlob(ctx context.Context, blob todobus.Blob) (todobus.Blob, error) {
+	const q = `
+	INSERT INTO blobs
+		(checksum, file_id, content_type, size, ref_count, date_created)
+	VALUES
+		(:checksum, :file_id, :content_type, :size, 1, :date_created)
+	ON CONFLICT (checksum) DO UPDATE SET
+		ref_count = blobs.ref_count + 1
+	RETURNING
+		checksum, file_id, content_type, size, ref_count, date_created`
+
+	var dbBlb dbBlob
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBBlob(blob), &dbBlb); err != nil {
+		return todobus.Blob{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toBusBlob(dbBlb), nil
+}
+
+// AcquireBlobByFileID records another reference to the blob stored under the
+// specified file id.
+func (s *Store) AcquireBlobByFileID(ctx context.Context, fileID string) (todobus.Blob, error) {
+	data := struct {
+		FileID string `db:"file_id"`
+	}{
+		FileID: fileID,
+	}
+
+	const q = `
+	UPDATE
+		blobs
+	SET
+		ref_count = ref_count + 1
+	WHERE
+		file_id = :file_id
+	RETURNING
+		checksum, file_id, content_type, size, ref_count, date_created`
+
+	var dbBlb dbBlob
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbBlb); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return todobus.Blob{}, fmt.Errorf("db: %w", todobus.ErrBlobNotFound)
+		}
+		return todobus.Blob{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusBlob(dbBlb), nil
+}
+
+// UpdateBlob records where the contents of the blob were stored.
+func (s *Store) UpdateBlob(ctx context.Context, blob todobus.Blob) error {
+	const q = `
+	UPDATE
+		blobs
+	SET
+		file_id = :file_id
+	WHERE
+		checksum = :checksum`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBBlob(blob)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseBlob drops a reference to the blob and returns it with the
+// remaining reference count.
+func (s *Store) ReleaseBlob(ctx context.Context, checksum string) (todobus.Blob, error) {
+	data := struct {
+		Checksum string `db:"checksum"`
+	}{
+		Checksum: checksum,
+	}
+
+	const q = `
+	UPDATE
+		blobs
+	SET
+		ref_count = ref_count - 1
+	WHERE
+		checksum = :checksum
+	RETURNING
+		checksum, file_id, content_type, size, ref_count, date_created`
+
+	var dbBlb dbBlob
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbBlb); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return todobus.Blob{}, fmt.Errorf("db: %w", todobus.ErrBlobNotFound)
+		}
+		return todobus.Blob{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusBlob(dbBlb), nil
+}
+
+// DeleteBlob removes the blob once nothing references it. A blob that was
+// referenced again in the meantime is left alone and ErrBlobInUse returned.
+func (s *Store) DeleteBlob(ctx context.Context, checksum string) error {
+	data := struct {
+		Checksum string `db:"checksum"`
+	}{
+		Checksum: checksum,
+	}
+
+	const q = `
+	DELETE FROM
+		blobs
+	WHERE
+		checksum = :checksum AND ref_count <= 0
+	RETURNING
+		checksum, file_id, content_type, size, ref_count, date_created`
+
+	var dbBlb dbBlob
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbBlb); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", todobus.ErrBlobInUse)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// QueryBlobByFileID gets the blob stored under the specified file id.
+func (s *Store) QueryBlobByFileID(ctx context.Context, fileID string) (todobus.Blob, error) {
+	data := struct {
+		FileID string `db:"file_id"`
+	}{
+		FileID: fileID,
+	}
+
+	const q = `
+	SELECT
+		checksum, file_id, content_type, size, ref_count, date_created
+	FROM
+		blobs
+	WHERE
+		file_id = :file_id`
+
+	var dbBlb dbBlob
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbBlb); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return todobus.Blob{}, fmt.Errorf("db: %w", todobus.ErrBlobNotFound)
+		}
+		return todobus.Blob{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusBlob(dbBlb), nil
+}
+
+// QueryReferencedFileIDs returns the subset of the specified file ids that
+// are still referenced by a blob, a rendition or a todo item. An attachment
+// only refers to its file until the cutoff, after that a todo item must hold
+// the file as well.
+func (s *Store) QueryReferencedFileIDs(ctx context.Context, fileIDs []string, cutoff time.Time) ([]string, error) {
+	data := struct {
+		FileIDs dbarray.String `db:"file_ids"`
+		Cutoff  time.Time      `db:"cutoff"`
+	}{
+		FileIDs: fileIDs,
+		Cutoff:  cutoff.UTC(),
+	}
+
+	const q = `
+	SELECT file_id FROM blobs WHERE file_id = ANY(:file_ids)
+	UNION
+	SELECT file_id FROM attachments WHERE file_id = ANY(:file_ids) AND date_created > :cutoff
+	UNION
+	SELECT file_id FROM attachment_renditions WHERE file_id = ANY(:file_ids)
+	UNION
+	SELECT file_id FROM todo_items WHERE file_id = ANY(:file_ids)`
+
+	var refs []struct {
+		FileID string `db:"file_id"`
+	}
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &refs); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	referenced := make([]string, len(refs))
+	for i, ref := range refs {
+		referenced[i] = ref.FileID
+	}
+
+	return referenced, nil
+}
+
+// QueryUnlinkedAttachments returns the attachments created before the
+// cutoff whose file no todo item holds. Their renditions are not loaded.
+func (s *Store) QueryUnlinkedAttachments(ctx context.Context, cutoff time.Time) ([]todobus.Attachment, error) {
+	data := struct {
+		Cutoff time.Time `db:"cutoff"`
+	}{
+		Cutoff: cutoff.UTC(),
+	}
+
+	const q = `
+	SELECT
+		attachment_id, org_id, user_id, file_id, checksum, name, content_type, size, date_created
+	FROM
+		attachments a
+	WHERE
+		date_created <= :cutoff AND
+		NOT EXISTS (SELECT 1 FROM todo_items t WHERE t.file_id = a.file_id)
+	ORDER BY
+		date_created`
+
+	var dbAtts []dbAttachment
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbAtts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	atts := make([]todobus.Attachment, len(dbAtts))
+	for i, dbAtt := range dbAtts {
+		atts[i] = toBusAttachment(dbAtt, nil)
+	}
+
+	return atts, nil
+}
+
+// CreateAttachment inserts a new attachment into the database.
+func (s *Store) CreateAttachment(ctx context.Context, att todobus.Attachment) error {
+	const q = `
+	INSERT INTO attachments
+		(attachment_id, org_id, user_id, file_id, checksum, name, content_type, size, date_created)
+	VALUES
+		(:attachment_id, :org_id, :user_id, :file_id, :checksum, :name, :content_type, :size, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBAttachment(att)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAttachment removes an attachment and its renditions from the
+// database.
+func (s *Store) DeleteAttachment(ctx context.Context, att todobus.Attachment) error {
+	const q = `
+	DELETE FROM
+		attachments
+	WHERE
+		attachment_id = :attachment_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBAttachment(att)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryAttachmentByID gets the specified attachment and its renditions from
+// the database.
+func (s *Store) QueryAttachmentByID(ctx context.Context, attachmentID uuid.UUID) (todobus.Attachment, error) {
+	data := struct {
+		ID    string        `db:"attachment_id"`
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		ID:    attachmentID.String(),
+		OrgID: sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		attachment_id, org_id, user_id, file_id, checksum, name, content_type, size, date_created
+	FROM
+		attachments
+	WHERE
+		attachment_id = :attachment_id AND ` + orgScope
+
+	var dbAtt dbAttachment
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbAtt); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return todobus.Attachment{}, fmt.Errorf("db: %w", todobus.ErrAttachmentNotFound)
+		}
+		return todobus.Attachment{}, fmt.Errorf("db: %w", err)
+	}
+
+	const qr = `
+	SELECT
+		attachment_id, size, file_id, content_type, width, height
+	FROM
+		attachment_renditions
+	WHERE
+		attachment_id = :attachment_id
+	ORDER BY
+		size`
+
+	var dbRnds []dbRendition
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, qr, data, &dbRnds); err != nil {
+		return todobus.Attachment{}, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAttachment(dbAtt, dbRnds), nil
+}
+
+// CanAccessFile reports whether the user uploaded an attachment holding the
+// file or owns or is assigned a todo item that holds it.
+func (s *Store) CanAccessFile(ctx context.Context, userID uuid.UUID, fileID string) (bool, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		FileID string        `db:"file_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: userID.String(),
+		FileID: fileID,
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT EXISTS (
+		SELECT 1 FROM attachments
+		WHERE file_id = :file_id AND user_id = :user_id AND ` + orgScope + `
+		UNION ALL
+		SELECT 1 FROM todo_items
+		WHERE file_id = :file_id AND (user_id = :user_id OR assignee_id = :user_id) AND ` + orgScope + `
+	) AS access`
+
+	var access struct {
+		Access bool `db:"access"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &access); err != nil {
+		return false, fmt.Errorf("db: %w", err)
+	}
+
+	return access.Access, nil
+}
+
+// IsFileOwner reports whether the user uploaded an attachment holding the
+// file or owns a todo item that holds it.
+func (s *Store) IsFileOwner(ctx context.Context, userID uuid.UUID, fileID string) (bool, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		FileID string        `db:"file_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: userID.String(),
+		FileID: fileID,
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT EXISTS (
+		SELECT 1 FROM attachments
+		WHERE file_id = :file_id AND user_id = :user_id AND ` + orgScope + `
+		UNION ALL
+		SELECT 1 FROM todo_items
+		WHERE file_id = :file_id AND user_id = :user_id AND ` + orgScope + `
+	) AS owner`
+
+	var owner struct {
+		Owner bool `db:"owner"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &owner); err != nil {
+		return false, fmt.Errorf("db: %w", err)
+	}
+
+	return owner.Owner, nil
+}
+
+// CreateRendition records a rendition of an attachment. Rendering the same
+// size again replaces the earlier rendition.
+func (s *Store) CreateRendition(ctx context.Context, attachmentID uuid.UUID, rnd todobus.Rendition) error {
+	const q = `
+	INSERT INTO attachment_renditions
+		(attachment_id, size, file_id, content_type, width, height)
+	VALUES
+		(:attachment_id, :size, :file_id, :content_type, :width, :height)
+	ON CONFLICT (attachment_id, size) DO UPDATE SET
+		file_id = EXCLUDED.file_id,
+		content_type = EXCLUDED.content_type,
+		width = EXCLUDED.width,
+		height = EXCLUDED.height`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRendition(attachmentID, rnd)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// LockDependencies holds the lock that serializes changes to the
+// dependencies until the transaction ends.
+func (s *Store) LockDependencies(ctx context.Context) error {
+	const q = `
+	SELECT
+		pg_advisory_xact_lock(hashtext('todo_dependencies'))`
+
+	if err := sqldb.ExecContext(ctx, s.log, s.db, q); err != nil {
+		return fmt.Errorf("execcontext: %w", err)
+	}
+
+	return nil
+}
+
+// CreateDependency inserts a new dependency into the database.
+func (s *Store) CreateDependency(ctx context.Context, dep todobus.Dependency) error {
+	const q = `
+	INSERT INTO todo_dependencies
+		(item_id, blocker_id, date_created)
+	VALUES
+		(:item_id, :blocker_id, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDependency(dep)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", todobus.ErrDependencyExists)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDependency removes a dependency from the database.
+func (s *Store) DeleteDependency(ctx context.Context, dep todobus.Dependency) error {
+	const q = `
+	DELETE FROM
+		todo_dependencies
+	WHERE
+		item_id = :item_id AND blocker_id = :blocker_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDependency(dep)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryDependencies retrieves the dependencies of the specified items on the
+// items they are blocked by, oldest first.
+func (s *Store) QueryDependencies(ctx context.Context, itemIDs []uuid.UUID) ([]todobus.Dependency, error) {
+	ids := make([]string, len(itemIDs))
+	for i, id := range itemIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		ItemIDs dbarray.String `db:"item_ids"`
+	}{
+		ItemIDs: ids,
+	}
+
+	const q = `
+	SELECT
+		item_id, blocker_id, date_created
+	FROM
+		todo_dependencies
+	WHERE
+		item_id = ANY(CAST(:item_ids AS UUID[]))
+	ORDER BY
+		date_created, blocker_id`
+
+	var dbDeps []dbDependency
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbDeps); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDependencies(dbDeps)
+}
+
+// QueryUnblocked retrieves the items blocked by the specified item that are
+// not done and no longer wait on any item that is not done.
+func (s *Store) QueryUnblocked(ctx context.Context, blockerID uuid.UUID) ([]todobus.TodoItem, error) {
+	data := struct {
+		ID string `db:"blocker_id"`
+	}{
+		ID: blockerID.String(),
+	}
+
+	const q = `
+	SELECT
+		item_id, org_id, user_id, assignee_id, description, due_date, all_day, file_id, status, priority, recurrence, list, labels, checklist, rank, date_created, date_updated, date_completed,
+		` + blockedColumn + `
+	FROM
+		todo_items
+	WHERE
+		item_id IN (SELECT item_id FROM todo_dependencies WHERE blocker_id = :blocker_id) AND
+		status <> 'DONE'`
+
+	var dbItems []dbTodoItem
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbItems); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	items, err := toBusTodoItems(dbItems)
+	if err != nil {
+		return nil, err
+	}
+
+	unblocked := slices.DeleteFunc(items, func(item todobus.TodoItem) bool {
+		return item.Blocked
+	})
+
+	return unblocked, nil
+}
+
+// LockBoard holds the lock that serializes changes to the order of the items
+// on the board of the user until the transaction ends.
+func (s *Store) LockBoard(ctx context.Context, userID uuid.UUID) error {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		pg_advisory_xact_lock(hashtext('todo_board'), hashtext(:user_id))`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryColumn retrieves the items in a column of the board of a user in the
+// order they are shown. Items without a rank come last, oldest first.
+func (s *Store) QueryColumn(ctx context.Context, col todobus.Column) ([]todobus.TodoItem, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		Status string        `db:"status"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: col.UserID.String(),
+		Status: col.Status.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		item_id, org_id, user_id, assignee_id, description, due_date, all_day, file_id, status, priority, recurrence, list, labels, checklist, rank, date_created, date_updated, date_completed,
+		` + blockedColumn + `
+	FROM
+		todo_items
+	WHERE
+		user_id = :user_id AND status = :status AND ` + orgScope + `
+	ORDER BY
+		rank, date_created, item_id`
+
+	var dbItems []dbTodoItem
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbItems); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusTodoItems(dbItems)
+}
+
+// QueryLastRank retrieves the highest rank in a column of the board of a
+// user. The rank is zero when no item in the column has one.
+func (s *Store) QueryLastRank(ctx context.Context, col todobus.Column) (rank.Rank, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		Status string        `db:"status"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: col.UserID.String(),
+		Status: col.Status.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		rank
+	FROM
+		todo_items
+	WHERE
+		user_id = :user_id AND status = :status AND rank IS NOT NULL AND ` + orgScope + `
+	ORDER BY
+		rank DESC
+	FETCH NEXT 1 ROWS ONLY`
+
+	var dest struct {
+		Rank string `db:"rank"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return rank.Rank{}, nil
+		}
+		return rank.Rank{}, fmt.Errorf("db: %w", err)
+	}
+
+	return rank.Parse(dest.Rank)
+}
+
+// UpdateRank moves an item to a new position in its column, leaving the
+// rest of the item as it is.
+func (s *Store) UpdateRank(ctx context.Context, itemID uuid.UUID, r rank.Rank) error {
+	data := struct {
+		ID   string `db:"item_id"`
+		Rank string `db:"rank"`
+	}{
+		ID:   itemID.String(),
+		Rank: r.String(),
+	}
+
+	const q = `
+	UPDATE
+		todo_items
+	SET
+		rank = :rank
+	WHERE
+		item_id = :item_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryUnbalancedColumns retrieves the columns that hold an item without a
+// rank or with a rank longer than the specified length.
+func (s *Store) QueryUnbalancedColumns(ctx context.Context, length int) ([]todobus.Column, error) {
+	data := struct {
+		Length int `db:"length"`
+	}{
+		Length: length,
+	}
+
+	const q = `
+	SELECT DISTINCT
+		user_id, status
+	FROM
+		todo_items
+	WHERE
+		user_id IS NOT NULL AND (rank IS NULL OR length(rank) > :length)`
+
+	var dbCols []dbColumn
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbCols); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusColumns(dbCols)
+}
+
+// QueryWatermark retrieves the last version given to a change, provided no
+// change to the items of the organization is being written. The boolean is
+// false while one is.
+func (s *Store) QueryWatermark(ctx context.Context, orgID uuid.UUID) (int64, bool, error) {
+	data := struct {
+		OrgID string `db:"org_id"`
+	}{
+		OrgID: orgID.String(),
+	}
+
+	const q = `
+	SELECT
+		CASE WHEN pg_try_advisory_xact_lock(hashtext('todo_changes'), hashtext(:org_id)) THEN
+			(SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM todo_changes)
+		END AS watermark`
+
+	var dest struct {
+		Watermark sql.NullInt64 `db:"watermark"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		return 0, false, fmt.Errorf("db: %w", err)
+	}
+
+	return dest.Watermark.Int64, dest.Watermark.Valid, nil
+}
+
+// LockItem locks the specified item until the transaction ends and
+// retrieves its current version.
+func (s *Store) LockItem(ctx context.Context, itemID uuid.UUID) (int64, error) {
+	data := struct {
+		ID string `db:"item_id"`
+	}{
+		ID: itemID.String(),
+	}
+
+	const q = `
+	SELECT
+		change_seq
+	FROM
+		todo_items
+	WHERE
+		item_id = :item_id
+	FOR UPDATE`
+
+	var dest struct {
+		ChangeSeq int64 `db:"change_seq"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return 0, fmt.Errorf("db: %w", todobus.ErrNotFound)
+		}
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return dest.ChangeSeq, nil
+}
+
+// QueryChanges retrieves the items the user owns or is assigned to that were
+// changed after one version up to another, in the order they were changed.
+func (s *Store) QueryChanges(ctx context.Context, userID uuid.UUID, after int64, upTo int64, limit int) ([]todobus.Change, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		After  int64         `db:"after"`
+		UpTo   int64         `db:"up_to"`
+		Limit  int           `db:"limit"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: userID.String(),
+		After:  after,
+		UpTo:   upTo,
+		Limit:  limit,
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		item_id, org_id, user_id, assignee_id, description, due_date, all_day, file_id, status, priority, recurrence, list, labels, checklist, rank, date_created, date_updated, date_completed,
+		` + blockedColumn + `, change_seq
+	FROM
+		todo_items
+	WHERE
+		(user_id = :user_id OR assignee_id = :user_id) AND change_seq > :after AND change_seq <= :up_to AND ` + orgScope + `
+	ORDER BY
+		change_seq
+	FETCH NEXT :limit ROWS ONLY`
+
+	var dbChgs []dbChange
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbChgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusChanges(dbChgs)
+}
+
+// QueryTombstones retrieves the items the user could no longer see after one
+// version up to another, in the order they went.
+func (s *Store) QueryTombstones(ctx context.Context, userID uuid.UUID, after int64, upTo int64, limit int) ([]todobus.Tombstone, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+		After  int64         `db:"after"`
+		UpTo   int64         `db:"up_to"`
+		Limit  int           `db:"limit"`
+	}{
+		UserID: userID.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+		After:  after,
+		UpTo:   upTo,
+		Limit:  limit,
+	}
+
+	const q = `
+	SELECT
+		item_id, change_seq, date_created
+	FROM
+		todo_tombstones
+	WHERE
+		user_id = :user_id AND change_seq > :after AND change_seq <= :up_to AND ` + orgScope + `
+	ORDER BY
+		change_seq
+	FETCH NEXT :limit ROWS ONLY`
+
+	var dbTombs []dbTombstone
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbTombs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusTombstones(dbTombs)
+}
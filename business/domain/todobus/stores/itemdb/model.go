@@ -1,32 +1,82 @@
 package itemdb
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/sdk/sqldb/dbarray"
+	"github.com/himynamej/todo/business/types/label"
+	"github.com/himynamej/todo/business/types/name"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/rank"
+	"github.com/himynamej/todo/business/types/recurrence"
+	"github.com/himynamej/todo/business/types/status"
 )
 
 // dbTodoItem represents the database structure of a TodoItem.
 type dbTodoItem struct {
-	ID          string    `db:"item_id"`
-	Description string    `db:"description"`
-	DueDate     time.Time `db:"due_date"`
-	FileID      string    `db:"file_id"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID            string         `db:"item_id"`
+	OrgID         uuid.UUID      `db:"org_id"`
+	UserID        uuid.NullUUID  `db:"user_id"`
+	AssigneeID    uuid.NullUUID  `db:"assignee_id"`
+	Description   string         `db:"description"`
+	DueDate       sql.NullTime   `db:"due_date"`
+	AllDay        bool           `db:"all_day"`
+	FileID        string         `db:"file_id"`
+	Status        string         `db:"status"`
+	Priority      string         `db:"priority"`
+	Recurrence    sql.NullString `db:"recurrence"`
+	List          sql.NullString `db:"list"`
+	Labels        dbarray.String `db:"labels"`
+	Checklist     dbChecklist    `db:"checklist"`
+	Blocked       bool           `db:"blocked"`
+	Rank          sql.NullString `db:"rank"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+	DateCompleted sql.NullTime   `db:"date_completed"`
 }
 
 // toDBTodoItem converts a business-level TodoItem to a database-level TodoItem.
 func toDBTodoItem(item todobus.TodoItem) dbTodoItem {
 	return dbTodoItem{
 		ID:          item.ID.String(),
+		OrgID:       item.OrgID,
+		UserID:      toNullUUID(item.UserID),
+		AssigneeID:  toNullUUID(item.AssigneeID),
 		Description: item.Description,
-		DueDate:     item.DueDate,
-		FileID:      item.FileID,
-		DateCreated: time.Now(),
-		DateUpdated: time.Now(),
+		DueDate: sql.NullTime{
+			Time:  item.DueDate.UTC(),
+			Valid: !item.DueDate.IsZero(),
+		},
+		AllDay:   item.AllDay,
+		FileID:   item.FileID,
+		Status:   item.Status.String(),
+		Priority: item.Priority.String(),
+		Recurrence: sql.NullString{
+			String: item.Recurrence.String(),
+			Valid:  item.Recurrence.Valid(),
+		},
+		List: sql.NullString{
+			String: item.List.String(),
+			Valid:  item.List.Valid(),
+		},
+		Labels:    label.ParseToString(item.Labels),
+		Checklist: toDBChecklist(item.Checklist),
+		Rank: sql.NullString{
+			String: item.Rank.String(),
+			Valid:  !item.Rank.IsZero(),
+		},
+		DateCreated: item.DateCreated.UTC(),
+		DateUpdated: item.DateUpdated.UTC(),
+		DateCompleted: sql.NullTime{
+			Time:  item.DateCompleted.UTC(),
+			Valid: !item.DateCompleted.IsZero(),
+		},
 	}
 }
 
@@ -37,11 +87,72 @@ func toBusTodoItem(dbItem dbTodoItem) (todobus.TodoItem, error) {
 		return todobus.TodoItem{}, fmt.Errorf("parse UUID: %w", err)
 	}
 
+	sts, err := status.Parse(dbItem.Status)
+	if err != nil {
+		return todobus.TodoItem{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	pri, err := priority.Parse(dbItem.Priority)
+	if err != nil {
+		return todobus.TodoItem{}, fmt.Errorf("parse priority: %w", err)
+	}
+
+	rule, err := recurrence.Parse(dbItem.Recurrence.String)
+	if err != nil {
+		return todobus.TodoItem{}, fmt.Errorf("parse recurrence: %w", err)
+	}
+
+	list, err := name.ParseNull(dbItem.List.String)
+	if err != nil {
+		return todobus.TodoItem{}, fmt.Errorf("parse list: %w", err)
+	}
+
+	labels, err := label.ParseMany(dbItem.Labels)
+	if err != nil {
+		return todobus.TodoItem{}, fmt.Errorf("parse labels: %w", err)
+	}
+
+	r, err := rank.Parse(dbItem.Rank.String)
+	if err != nil {
+		return todobus.TodoItem{}, fmt.Errorf("parse rank: %w", err)
+	}
+
+	var dateCompleted time.Time
+	if dbItem.DateCompleted.Valid {
+		dateCompleted = dbItem.DateCompleted.Time.In(time.Local)
+	}
+
+	// All-day dates are kept at midnight UTC and must stay there, timed
+	// dates are handed back in local time like every other timestamp.
+	var dueDate time.Time
+	switch {
+	case !dbItem.DueDate.Valid:
+	case dbItem.AllDay:
+		dueDate = dbItem.DueDate.Time.UTC()
+	default:
+		dueDate = dbItem.DueDate.Time.In(time.Local)
+	}
+
 	return todobus.TodoItem{
-		ID:          id,
-		Description: dbItem.Description,
-		DueDate:     dbItem.DueDate,
-		FileID:      dbItem.FileID,
+		ID:            id,
+		OrgID:         dbItem.OrgID,
+		UserID:        dbItem.UserID.UUID,
+		AssigneeID:    dbItem.AssigneeID.UUID,
+		Description:   dbItem.Description,
+		DueDate:       dueDate,
+		AllDay:        dbItem.AllDay,
+		FileID:        dbItem.FileID,
+		Status:        sts,
+		Priority:      pri,
+		Recurrence:    rule,
+		List:          list,
+		Labels:        labels,
+		Checklist:     toBusChecklist(dbItem.Checklist),
+		Blocked:       dbItem.Blocked,
+		Rank:          r,
+		DateCreated:   dbItem.DateCreated.In(time.Local),
+		DateUpdated:   dbItem.DateUpdated.In(time.Local),
+		DateCompleted: dateCompleted,
 	}, nil
 }
 
@@ -57,3 +168,353 @@ func toBusTodoItems(dbItems []dbTodoItem) ([]todobus.TodoItem, error) {
 	}
 	return items, nil
 }
+
+// dbChecklist represents the checklist of a todo item, which is kept as a
+// JSON array.
+type dbChecklist []dbChecklistItem
+
+type dbChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Value implements the driver.Valuer interface.
+func (c dbChecklist) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (c *dbChecklist) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("checklist: unsupported type %T", src)
+	}
+
+	return json.Unmarshal(data, c)
+}
+
+func toDBChecklist(checklist []todobus.ChecklistItem) dbChecklist {
+	db := make(dbChecklist, len(checklist))
+	for i, ci := range checklist {
+		db[i] = dbChecklistItem(ci)
+	}
+
+	return db
+}
+
+func toBusChecklist(db dbChecklist) []todobus.ChecklistItem {
+	if len(db) == 0 {
+		return nil
+	}
+
+	checklist := make([]todobus.ChecklistItem, len(db))
+	for i, ci := range db {
+		checklist[i] = todobus.ChecklistItem(ci)
+	}
+
+	return checklist
+}
+
+// =============================================================================
+
+// dbHistory represents the database structure of a history entry.
+type dbHistory struct {
+	ID          uuid.UUID     `db:"history_id"`
+	TodoID      uuid.UUID     `db:"item_id"`
+	ActorID     uuid.NullUUID `db:"actor_id"`
+	Field       string        `db:"field"`
+	OldValue    string        `db:"old_value"`
+	NewValue    string        `db:"new_value"`
+	DateCreated time.Time     `db:"date_created"`
+}
+
+func toDBHistory(hst todobus.History) dbHistory {
+	return dbHistory{
+		ID:          hst.ID,
+		TodoID:      hst.TodoID,
+		ActorID:     toNullUUID(hst.ActorID),
+		Field:       hst.Field,
+		OldValue:    hst.OldValue,
+		NewValue:    hst.NewValue,
+		DateCreated: hst.DateCreated.UTC(),
+	}
+}
+
+func toBusHistories(dbHsts []dbHistory) []todobus.History {
+	hsts := make([]todobus.History, len(dbHsts))
+	for i, dbHst := range dbHsts {
+		hsts[i] = todobus.History{
+			ID:          dbHst.ID,
+			TodoID:      dbHst.TodoID,
+			ActorID:     dbHst.ActorID.UUID,
+			Field:       dbHst.Field,
+			OldValue:    dbHst.OldValue,
+			NewValue:    dbHst.NewValue,
+			DateCreated: dbHst.DateCreated.In(time.Local),
+		}
+	}
+	return hsts
+}
+
+// toNullUUID maps the zero id to a database NULL.
+func toNullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{
+		UUID:  id,
+		Valid: id != uuid.Nil,
+	}
+}
+
+// =============================================================================
+
+// dbBlob represents the database structure of stored file contents.
+type dbBlob struct {
+	Checksum    string    `db:"checksum"`
+	FileID      string    `db:"file_id"`
+	ContentType string    `db:"content_type"`
+	Size        int       `db:"size"`
+	RefCount    int       `db:"ref_count"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBBlob(blob todobus.Blob) dbBlob {
+	return dbBlob{
+		Checksum:    blob.Checksum,
+		FileID:      blob.FileID,
+		ContentType: blob.ContentType,
+		Size:        blob.Size,
+		RefCount:    blob.RefCount,
+		DateCreated: blob.DateCreated.UTC(),
+	}
+}
+
+func toBusBlob(dbBlb dbBlob) todobus.Blob {
+	return todobus.Blob{
+		Checksum:    dbBlb.Checksum,
+		FileID:      dbBlb.FileID,
+		ContentType: dbBlb.ContentType,
+		Size:        dbBlb.Size,
+		RefCount:    dbBlb.RefCount,
+		DateCreated: dbBlb.DateCreated.In(time.Local),
+	}
+}
+
+// =============================================================================
+
+// dbAttachment represents the database structure of an attachment.
+type dbAttachment struct {
+	ID          uuid.UUID `db:"attachment_id"`
+	OrgID       uuid.UUID `db:"org_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	FileID      string    `db:"file_id"`
+	Checksum    string    `db:"checksum"`
+	Name        string    `db:"name"`
+	ContentType string    `db:"content_type"`
+	Size        int       `db:"size"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBAttachment(att todobus.Attachment) dbAttachment {
+	return dbAttachment{
+		ID:          att.ID,
+		OrgID:       att.OrgID,
+		UserID:      att.UserID,
+		FileID:      att.FileID,
+		Checksum:    att.Checksum,
+		Name:        att.Name,
+		ContentType: att.ContentType,
+		Size:        att.Size,
+		DateCreated: att.DateCreated.UTC(),
+	}
+}
+
+func toBusAttachment(dbAtt dbAttachment, dbRnds []dbRendition) todobus.Attachment {
+	rnds := make([]todobus.Rendition, len(dbRnds))
+	for i, dbRnd := range dbRnds {
+		rnds[i] = todobus.Rendition{
+			Size:        dbRnd.Size,
+			FileID:      dbRnd.FileID,
+			ContentType: dbRnd.ContentType,
+			Width:       dbRnd.Width,
+			Height:      dbRnd.Height,
+		}
+	}
+
+	return todobus.Attachment{
+		ID:          dbAtt.ID,
+		OrgID:       dbAtt.OrgID,
+		UserID:      dbAtt.UserID,
+		FileID:      dbAtt.FileID,
+		Checksum:    dbAtt.Checksum,
+		Name:        dbAtt.Name,
+		ContentType: dbAtt.ContentType,
+		Size:        dbAtt.Size,
+		Renditions:  rnds,
+		DateCreated: dbAtt.DateCreated.In(time.Local),
+	}
+}
+
+// dbRendition represents the database structure of an attachment rendition.
+type dbRendition struct {
+	AttachmentID uuid.UUID `db:"attachment_id"`
+	Size         int       `db:"size"`
+	FileID       string    `db:"file_id"`
+	ContentType  string    `db:"content_type"`
+	Width        int       `db:"width"`
+	Height       int       `db:"height"`
+}
+
+func toDBRendition(attachmentID uuid.UUID, rnd todobus.Rendition) dbRendition {
+	return dbRendition{
+		AttachmentID: attachmentID,
+		Size:         rnd.Size,
+		FileID:       rnd.FileID,
+		ContentType:  rnd.ContentType,
+		Width:        rnd.Width,
+		Height:       rnd.Height,
+	}
+}
+
+// =============================================================================
+
+// dbDependency represents the database structure of a dependency.
+type dbDependency struct {
+	ItemID      string    `db:"item_id"`
+	BlockerID   string    `db:"blocker_id"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBDependency(dep todobus.Dependency) dbDependency {
+	return dbDependency{
+		ItemID:      dep.ItemID.String(),
+		BlockerID:   dep.BlockerID.String(),
+		DateCreated: dep.DateCreated.UTC(),
+	}
+}
+
+func toBusDependency(dbDep dbDependency) (todobus.Dependency, error) {
+	itemID, err := uuid.Parse(dbDep.ItemID)
+	if err != nil {
+		return todobus.Dependency{}, fmt.Errorf("parse item id: %w", err)
+	}
+
+	blockerID, err := uuid.Parse(dbDep.BlockerID)
+	if err != nil {
+		return todobus.Dependency{}, fmt.Errorf("parse blocker id: %w", err)
+	}
+
+	return todobus.Dependency{
+		ItemID:      itemID,
+		BlockerID:   blockerID,
+		DateCreated: dbDep.DateCreated.In(time.Local),
+	}, nil
+}
+
+func toBusDependencies(dbDeps []dbDependency) ([]todobus.Dependency, error) {
+	deps := make([]todobus.Dependency, len(dbDeps))
+	for i, dbDep := range dbDeps {
+		dep, err := toBusDependency(dbDep)
+		if err != nil {
+			return nil, err
+		}
+		deps[i] = dep
+	}
+
+	return deps, nil
+}
+
+// =============================================================================
+
+// dbColumn represents the database structure of a column of a board.
+type dbColumn struct {
+	UserID string `db:"user_id"`
+	Status string `db:"status"`
+}
+
+func toBusColumns(dbCols []dbColumn) ([]todobus.Column, error) {
+	cols := make([]todobus.Column, len(dbCols))
+	for i, dbCol := range dbCols {
+		userID, err := uuid.Parse(dbCol.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("parse user id: %w", err)
+		}
+
+		sts, err := status.Parse(dbCol.Status)
+		if err != nil {
+			return nil, fmt.Errorf("parse status: %w", err)
+		}
+
+		cols[i] = todobus.Column{
+			UserID: userID,
+			Status: sts,
+		}
+	}
+
+	return cols, nil
+}
+
+// =============================================================================
+
+// dbChange represents the database structure of a change to a todo item.
+type dbChange struct {
+	dbTodoItem
+	ChangeSeq int64 `db:"change_seq"`
+}
+
+func toBusChanges(dbChgs []dbChange) ([]todobus.Change, error) {
+	chgs := make([]todobus.Change, len(dbChgs))
+	for i, dbChg := range dbChgs {
+		item, err := toBusTodoItem(dbChg.dbTodoItem)
+		if err != nil {
+			return nil, err
+		}
+
+		chgs[i] = todobus.Change{
+			Item:    item,
+			Version: dbChg.ChangeSeq,
+		}
+	}
+
+	return chgs, nil
+}
+
+// dbTombstone represents the database structure of a tombstone.
+type dbTombstone struct {
+	ItemID      string    `db:"item_id"`
+	ChangeSeq   int64     `db:"change_seq"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toBusTombstones(dbTombs []dbTombstone) ([]todobus.Tombstone, error) {
+	tombs := make([]todobus.Tombstone, len(dbTombs))
+	for i, dbTomb := range dbTombs {
+		itemID, err := uuid.Parse(dbTomb.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("parse item id: %w", err)
+		}
+
+		tombs[i] = todobus.Tombstone{
+			ItemID:      itemID,
+			Version:     dbTomb.ChangeSeq,
+			DateCreated: dbTomb.DateCreated.In(time.Local),
+		}
+	}
+
+	return tombs, nil
+}
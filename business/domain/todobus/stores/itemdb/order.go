@@ -0,0 +1,38 @@
+package itemdb
+
+import (
+	"fmt"
+
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+)
+
+var idColumn = sqldb.KeysetColumn{Name: "item_id", Type: "UUID"}
+
+var orderByFields = map[string]sqldb.KeysetColumn{
+	todobus.OrderByID:          idColumn,
+	todobus.OrderByDescription: {Name: "description", Type: "TEXT"},
+	todobus.OrderByDueDate:     {Name: "due_date", Type: "TIMESTAMPTZ", Nullable: true},
+	todobus.OrderByStatus:      {Name: "status", Type: "TEXT"},
+	todobus.OrderByDateCreated: {Name: "date_created", Type: "TIMESTAMP"},
+	todobus.OrderByRank:        {Name: "rank", Type: "TEXT", Nullable: true},
+}
+
+func orderByColumn(orderBy order.By) (sqldb.KeysetColumn, error) {
+	column, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return sqldb.KeysetColumn{}, fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return column, nil
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	column, err := orderByColumn(orderBy)
+	if err != nil {
+		return "", err
+	}
+
+	return sqldb.OrderByKeyset(column, idColumn, orderBy.Direction), nil
+}
@@ -0,0 +1,289 @@
+package todobus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// The set of mutations a client can send.
+var (
+	MutationCreate = newMutationType("create")
+	MutationUpdate = newMutationType("update")
+	MutationDelete = newMutationType("delete")
+)
+
+// Set of known mutation types.
+var mutationTypes = make(map[string]MutationType)
+
+// MutationType represents what a mutation does to a todo item.
+type MutationType struct {
+	value string
+}
+
+func newMutationType(mutationType string) MutationType {
+	m := MutationType{mutationType}
+	mutationTypes[mutationType] = m
+	return m
+}
+
+// ParseMutationType parses the string value and returns a mutation type if
+// one exists.
+func ParseMutationType(value string) (MutationType, error) {
+	m, exists := mutationTypes[value]
+	if !exists {
+		return MutationType{}, fmt.Errorf("invalid mutation type %q", value)
+	}
+
+	return m, nil
+}
+
+// String returns the name of the mutation type.
+func (m MutationType) String() string {
+	return m.value
+}
+
+// =============================================================================
+
+// MaxSyncMutations is the largest number of mutations a client can send in
+// one sync.
+const MaxSyncMutations = 100
+
+// MaxSyncChanges is the largest number of changes returned by one sync.
+const MaxSyncChanges = 500
+
+// watermarkAttempts and watermarkBackoff bound how long a sync waits for the
+// changes being written to finish.
+const (
+	watermarkAttempts = 50
+	watermarkBackoff  = 20 * time.Millisecond
+)
+
+// Sync applies the mutations a client made to the items of the user while
+// it was offline, in the order they were made, and returns the changes made
+// to the items of the user since the token. A zero token returns every item
+// of the user. Mutations that fail don't stop the ones after them, and the
+// changes returned include the ones the mutations made.
+func (b *Business) Sync(ctx context.Context, userID uuid.UUID, token int64, muts []Mutation) (Sync, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.sync")
+	defer span.End()
+
+	if len(muts) > MaxSyncMutations {
+		return Sync{}, fmt.Errorf("%d mutations, at most %d: %w", len(muts), MaxSyncMutations, ErrTooManyMutations)
+	}
+
+	if token < 0 {
+		return Sync{}, ErrInvalidSyncToken
+	}
+
+	results := make([]MutationResult, len(muts))
+	for i, mut := range muts {
+		results[i] = MutationResult{
+			ItemID: mut.ItemID,
+			Err:    b.apply(ctx, userID, mut),
+		}
+	}
+
+	watermark, err := b.watermark(ctx)
+	if err != nil {
+		return Sync{}, fmt.Errorf("watermark: %w", err)
+	}
+
+	if token > watermark {
+		return Sync{}, ErrInvalidSyncToken
+	}
+
+	changes, err := b.storer.QueryChanges(ctx, userID, token, watermark, MaxSyncChanges+1)
+	if err != nil {
+		return Sync{}, fmt.Errorf("querychanges: userID[%s]: %w", userID, err)
+	}
+
+	tombs, err := b.storer.QueryTombstones(ctx, userID, token, watermark, MaxSyncChanges+1)
+	if err != nil {
+		return Sync{}, fmt.Errorf("querytombstones: userID[%s]: %w", userID, err)
+	}
+
+	sync := Sync{
+		Results: results,
+		Token:   watermark,
+	}
+
+	// Changes and tombstones are merged in the order of their versions, so a
+	// sync that is cut short ends at a version every earlier change has been
+	// returned for.
+	var c, t int
+	for c+t < MaxSyncChanges && (c < len(changes) || t < len(tombs)) {
+		switch {
+		case t == len(tombs) || (c < len(changes) && changes[c].Version < tombs[t].Version):
+			c++
+		default:
+			t++
+		}
+	}
+
+	sync.Changes = changes[:c]
+	sync.Tombstones = tombs[:t]
+
+	if c < len(changes) || t < len(tombs) {
+		sync.More = true
+		sync.Token = max(lastVersion(sync.Changes), lastTombstone(sync.Tombstones))
+	}
+
+	return sync, nil
+}
+
+// =============================================================================
+
+// apply makes the change of one mutation on behalf of the user. Creates and
+// deletes that were applied before, by an earlier sync that the client did
+// not hear back from, succeed again without doing anything.
+func (b *Business) apply(ctx context.Context, userID uuid.UUID, mut Mutation) error {
+	if mut.ItemID == uuid.Nil {
+		return fmt.Errorf("item id is required: %w", ErrNotFound)
+	}
+
+	switch mut.Type {
+	case MutationCreate:
+		item, err := b.storer.QueryByID(ctx, mut.ItemID)
+		switch {
+		case err == nil:
+			if item.UserID != userID {
+				return ErrSyncConflict
+			}
+			return nil
+
+		case !errors.Is(err, ErrNotFound):
+			return fmt.Errorf("querybyid: itemID[%s]: %w", mut.ItemID, err)
+		}
+
+		nt := mut.Create
+		nt.ID = mut.ItemID
+		nt.UserID = userID
+
+		if _, err := b.Create(ctx, nt); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+
+		return nil
+
+	case MutationUpdate:
+		return b.withTran(ctx, func(bus *Business) error {
+			item, err := bus.lockVersion(ctx, mut)
+			if err != nil {
+				return err
+			}
+
+			if !permitted(item, userID, mut.Update) {
+				return ErrNotPermitted
+			}
+
+			if _, err := bus.Update(ctx, item, mut.Update); err != nil {
+				return fmt.Errorf("update: %w", err)
+			}
+
+			return nil
+		})
+
+	case MutationDelete:
+		item, err := b.storer.QueryByID(ctx, mut.ItemID)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil
+			}
+			return fmt.Errorf("querybyid: itemID[%s]: %w", mut.ItemID, err)
+		}
+
+		if item.UserID != userID {
+			return ErrNotPermitted
+		}
+
+		return b.delete(ctx, item, func(bus *Business) error {
+			_, err := bus.lockVersion(ctx, mut)
+			return err
+		})
+	}
+
+	return fmt.Errorf("unknown mutation type %q", mut.Type)
+}
+
+// lockVersion locks the item of the mutation until the transaction ends and
+// checks it is still at the version the mutation was made to.
+func (b *Business) lockVersion(ctx context.Context, mut Mutation) (TodoItem, error) {
+	version, err := b.storer.LockItem(ctx, mut.ItemID)
+	if err != nil {
+		return TodoItem{}, fmt.Errorf("lockitem: itemID[%s]: %w", mut.ItemID, err)
+	}
+
+	if version != mut.Version {
+		return TodoItem{}, fmt.Errorf("version %d, now %d: %w", mut.Version, version, ErrSyncConflict)
+	}
+
+	item, err := b.storer.QueryByID(ctx, mut.ItemID)
+	if err != nil {
+		return TodoItem{}, fmt.Errorf("querybyid: itemID[%s]: %w", mut.ItemID, err)
+	}
+
+	return item, nil
+}
+
+// watermark returns the version up to which every change to the items of the
+// organization has been written. Changes are given their versions as they
+// are written, so one that started earlier can still be writing when a later
+// one is done. The watermark is only read once no change to the items of the
+// organization is being written, which makes sure a client never moves past
+// a version that has yet to show up. Changes in other organizations don't
+// hold it up since a sync never returns them.
+func (b *Business) watermark(ctx context.Context) (int64, error) {
+	orgID := orgOf(ctx)
+
+	for range watermarkAttempts {
+		watermark, ok, err := b.storer.QueryWatermark(ctx, orgID)
+		if err != nil {
+			return 0, fmt.Errorf("querywatermark: %w", err)
+		}
+
+		if ok {
+			return watermark, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(watermarkBackoff):
+		}
+	}
+
+	return 0, ErrSyncBusy
+}
+
+// permitted reports whether the user may make the update to the item. The
+// owner may change anything, while the assignee may only change its status.
+func permitted(item TodoItem, userID uuid.UUID, ut UpdateTodoItem) bool {
+	if item.UserID == userID {
+		return true
+	}
+
+	statusOnly := ut.Description == nil && ut.DueDate == nil && ut.AllDay == nil &&
+		ut.Priority == nil && ut.Recurrence == nil && ut.List == nil &&
+		ut.Labels == nil && ut.Checklist == nil
+
+	return item.AssigneeID == userID && statusOnly
+}
+
+func lastVersion(changes []Change) int64 {
+	if len(changes) == 0 {
+		return 0
+	}
+	return changes[len(changes)-1].Version
+}
+
+func lastTombstone(tombs []Tombstone) int64 {
+	if len(tombs) == 0 {
+		return 0
+	}
+	return tombs[len(tombs)-1].Version
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"math/rand"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // TestNewTodoItems is a helper method for generating new todo items with random data for testing.
-func TestNewTodoItems(n int) []NewTodoItem {
+func TestNewTodoItems(n int, userID uuid.UUID) []NewTodoItem {
 	newItems := make([]NewTodoItem, n)
 
 	idx := rand.Intn(10000)
@@ -16,10 +18,10 @@ func TestNewTodoItems(n int) []NewTodoItem {
 		idx++
 
 		item := NewTodoItem{
+			UserID:      userID,
 			Description: fmt.Sprintf("Description%d", idx),
 			DueDate:     time.Now().Add(time.Duration(rand.Intn(100)) * time.Hour),
 			FileData:    []byte(fmt.Sprintf("Test file data %d", idx)),
-			FileName:    fmt.Sprintf("TestFile%d.txt", idx),
 		}
 
 		newItems[i] = item
@@ -29,12 +31,12 @@ func TestNewTodoItems(n int) []NewTodoItem {
 }
 
 // TestSeedTodoItems is a helper method for seeding TodoItem data into the system for testing.
-func TestSeedTodoItems(ctx context.Context, n int, api *Business) ([]TodoItem, error) {
-	newItems := TestNewTodoItems(n)
+func TestSeedTodoItems(ctx context.Context, n int, userID uuid.UUID, api *Business) ([]TodoItem, error) {
+	newItems := TestNewTodoItems(n, userID)
 
 	items := make([]TodoItem, len(newItems))
 	for i, newItem := range newItems {
-		item, err := api.Create(ctx, newItem.Description, newItem.DueDate, newItem.FileData, newItem.FileName)
+		item, err := api.Create(ctx, newItem)
 		if err != nil {
 			return nil, fmt.Errorf("seeding todo item: idx: %d : %w", i, err)
 		}
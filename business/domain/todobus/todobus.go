@@ -3,107 +3,512 @@ package todobus
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/rank"
+	"github.com/himynamej/todo/business/types/status"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/himynamej/todo/foundation/otel"
+	"github.com/himynamej/todo/foundation/worker"
 )
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound = errors.New("todo item not found")
+	ErrNotFound           = errors.New("todo item not found")
+	ErrAssigneeNotFound   = errors.New("assignee not found")
+	ErrAssigneeDisabled   = errors.New("assignee is disabled")
+	ErrAssigneeNotMember  = errors.New("assignee is not a member of the organization")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrRenditionNotFound  = errors.New("rendition not found")
+	ErrBlobNotFound       = errors.New("file not found")
+	ErrBlobInUse          = errors.New("file still in use")
+	ErrFileNotOwned       = errors.New("file was not uploaded by the owner")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrDependencyExists   = errors.New("dependency already exists")
+	ErrDependencyCycle    = errors.New("dependency would form a cycle")
+	ErrSelfDependency     = errors.New("todo item cannot be blocked by itself")
+	ErrGraphTooLarge      = errors.New("dependency graph has too many items")
+	ErrNeighbourNotFound  = errors.New("neighbour not found in column")
+	ErrInvalidNeighbour   = errors.New("todo item cannot be its own neighbour")
+	ErrSyncConflict       = errors.New("todo item changed since the given version")
+	ErrNotPermitted       = errors.New("todo item cannot be changed by this user")
+	ErrInvalidSyncToken   = errors.New("invalid sync token")
+	ErrTooManyMutations   = errors.New("too many mutations")
+	ErrSyncBusy           = errors.New("changes are being written, try again")
+)
+
+// Set of fields recorded in the history of a todo item.
+const (
+	HistoryFieldAssignee = "assignee"
 )
 
 // Business manages the set of APIs for TodoItem access.
 type Business struct {
 	log      *logger.Logger
 	storer   Storer
+	delegate *delegate.Delegate
+	userBus  *userbus.Business
+	quotaBus *quotabus.Business
 	sqsQueue SQSClient
 	s3Client S3Client
+	uploader *upload.Pipeline
+	worker   *worker.Worker
+	beginner sqldb.Beginner
 }
 
-// NewBusiness constructs a TodoItem business API for use.
-func NewBusiness(log *logger.Logger, storer Storer, sqsQueue SQSClient, s3Client S3Client) *Business {
-	return &Business{
+// NewBusiness constructs a TodoItem business API for use. Without a quota
+// business stored files are not counted against any quota, and without a
+// beginner changes to files are not made in a transaction.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, userBus *userbus.Business, quotaBus *quotabus.Business, storer Storer, sqsQueue SQSClient, s3Client S3Client, uploader *upload.Pipeline, worker *worker.Worker, beginner sqldb.Beginner) *Business {
+	b := Business{
 		log:      log,
 		storer:   storer,
+		delegate: delegate,
+		userBus:  userBus,
+		quotaBus: quotaBus,
 		sqsQueue: sqsQueue,
 		s3Client: s3Client,
+		uploader: uploader,
+		worker:   worker,
+		beginner: beginner,
 	}
+
+	b.registerDelegateFunctions()
+
+	return &b
 }
 
-// Create adds a new TodoItem to the system, uploads the file to S3, and sends an SQS message.
-func (b *Business) Create(ctx context.Context, description string, dueDate time.Time, fileData []byte, fileName string) (TodoItem, error) {
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaBus := b.quotaBus
+	if quotaBus != nil {
+		if quotaBus, err = quotaBus.NewWithTx(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	bus := Business{
+		log:      b.log,
+		storer:   storer,
+		delegate: b.delegate,
+		userBus:  b.userBus,
+		quotaBus: quotaBus,
+		sqsQueue: b.sqsQueue,
+		s3Client: b.s3Client,
+		uploader: b.uploader,
+		worker:   b.worker,
+	}
+
+	// The new value has no beginner, so work it does joins the transaction
+	// instead of starting another.
+
+	return &bus, nil
+}
+
+// Create adds a new TodoItem to the system, stores or references its file,
+// and sends an SQS message.
+func (b *Business) Create(ctx context.Context, nt NewTodoItem) (TodoItem, error) {
 	ctx, span := otel.AddSpan(ctx, "business.todobus.create")
 	defer span.End()
 
-	item := TodoItem{
-		ID:          uuid.New(),
-		Description: description,
-		DueDate:     dueDate,
+	items, err := b.create(ctx, []NewTodoItem{nt})
+	if err != nil {
+		return TodoItem{}, err
 	}
 
-	// Upload file to S3
-	fileID, err := b.s3Client.Upload(ctx, fileName, fileData)
-	if err != nil {
-		return TodoItem{}, fmt.Errorf("s3 upload failed: %w", err)
+	return items[0], nil
+}
+
+// CreateBatch adds a set of TodoItems to the system in one transaction, so
+// either all of them are created or none are.
+func (b *Business) CreateBatch(ctx context.Context, nts []NewTodoItem) ([]TodoItem, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.createbatch")
+	defer span.End()
+
+	if len(nts) == 0 {
+		return []TodoItem{}, nil
 	}
-	item.FileID = fileID
 
-	// Store item in the database
-	if err := b.storer.Create(ctx, item); err != nil {
-		return TodoItem{}, fmt.Errorf("create: %w", err)
+	return b.create(ctx, nts)
+}
+
+// create stores the new items in one transaction and only lets other
+// domains know about them once all of them are committed.
+func (b *Business) create(ctx context.Context, nts []NewTodoItem) ([]TodoItem, error) {
+	for _, nt := range nts {
+		if nt.AssigneeID != uuid.Nil {
+			orgID := nt.OrgID
+			if orgID == uuid.Nil {
+				orgID = orgOf(ctx)
+			}
+
+			if err := b.checkAssignee(ctx, orgID, nt.AssigneeID); err != nil {
+				return nil, fmt.Errorf("checkassignee: %w", err)
+			}
+		}
 	}
 
-	// Send item data to SQS queue
-	if err := b.sqsQueue.SendMessage(ctx, item); err != nil {
-		b.log.Warn(ctx, "failed to send message to SQS", "error", err)
+	now := time.Now()
+
+	items := make([]TodoItem, len(nts))
+	for i, nt := range nts {
+		item := TodoItem{
+			ID:          nt.ID,
+			OrgID:       nt.OrgID,
+			UserID:      nt.UserID,
+			AssigneeID:  nt.AssigneeID,
+			Description: nt.Description,
+			DueDate:     nt.DueDate,
+			AllDay:      nt.AllDay,
+			Status:      status.Open,
+			Priority:    nt.Priority,
+			Recurrence:  nt.Recurrence,
+			List:        nt.List,
+			Labels:      nt.Labels,
+			Checklist:   nt.Checklist,
+			DateCreated: now,
+			DateUpdated: now,
+		}
+
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+
+		if item.OrgID == uuid.Nil {
+			item.OrgID = orgOf(ctx)
+		}
+
+		if item.Priority.IsZero() {
+			item.Priority = priority.Default
+		}
+
+		item.DueDate = normalizeDue(item.DueDate, item.AllDay)
+
+		items[i] = item
 	}
 
-	return item, nil
+	// The item holds a reference on its file for as long as it exists, and
+	// the file counts against the quota of the owner for as long too.
+	err := b.withTran(ctx, func(bus *Business) error {
+		// New items go to the end of the open column of their owner, one
+		// after the other.
+		ends := make(map[Column]rank.Rank)
+		for i := range items {
+			col := Column{UserID: items[i].UserID, Status: items[i].Status}
+
+			end, exists := ends[col]
+			if !exists {
+				var err error
+				if end, err = bus.endOfColumn(ctx, col); err != nil {
+					return fmt.Errorf("endofcolumn: %w", err)
+				}
+			} else {
+				end, _ = rank.Between(end, rank.Rank{})
+			}
+
+			items[i].Rank = end
+			ends[col] = end
+		}
+
+		for i, nt := range nts {
+			var blob Blob
+			switch {
+			case len(nt.FileData) > 0:
+				if err := bus.charge(ctx, items[i].UserID, len(nt.FileData)); err != nil {
+					return fmt.Errorf("charge: %w", err)
+				}
+
+				var err error
+				if blob, err = bus.storeBlob(ctx, nt.FileData, upload.Sniff(nt.FileData)); err != nil {
+					return fmt.Errorf("storeblob: %w", err)
+				}
+
+			case nt.FileID != "":
+				owner, err := bus.storer.IsFileOwner(ctx, items[i].UserID, nt.FileID)
+				if err != nil {
+					return fmt.Errorf("isfileowner: fileID[%s]: %w", nt.FileID, err)
+				}
+
+				if !owner {
+					return fmt.Errorf("fileID[%s]: %w", nt.FileID, ErrFileNotOwned)
+				}
+
+				if blob, err = bus.storer.AcquireBlobByFileID(ctx, nt.FileID); err != nil {
+					return fmt.Errorf("acquireblobbyfileid: fileID[%s]: %w", nt.FileID, err)
+				}
+
+				if err := bus.charge(ctx, items[i].UserID, blob.Size); err != nil {
+					return fmt.Errorf("charge: %w", err)
+				}
+			}
+			items[i].FileID = blob.FileID
+
+			// Store item in the database
+			if err := bus.storer.Create(ctx, items[i]); err != nil {
+				return fmt.Errorf("create: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		b.notify(ctx, ActionCreatedData(item))
+
+		if item.AssigneeID != uuid.Nil {
+			if err := b.recordAssignment(ctx, item, uuid.Nil, item.UserID); err != nil {
+				return nil, fmt.Errorf("recordassignment: %w", err)
+			}
+		}
+
+		// Send item data to SQS queue
+		if err := b.sqsQueue.SendMessage(ctx, item); err != nil {
+			b.log.Warn(ctx, "failed to send message to SQS", "error", err)
+		}
+	}
+
+	return items, nil
 }
 
 // Update modifies an existing TodoItem.
-func (b *Business) Update(ctx context.Context, itemID uuid.UUID, newDescription string, newDueDate time.Time) (TodoItem, error) {
+func (b *Business) Update(ctx context.Context, item TodoItem, ut UpdateTodoItem) (TodoItem, error) {
 	ctx, span := otel.AddSpan(ctx, "business.todobus.update")
 	defer span.End()
 
-	item, err := b.storer.QueryByID(ctx, itemID)
-	if err != nil {
-		return TodoItem{}, fmt.Errorf("update: %w", err)
+	if ut.Description != nil {
+		item.Description = *ut.Description
+	}
+
+	if ut.DueDate != nil {
+		item.DueDate = *ut.DueDate
+	}
+
+	if ut.AllDay != nil {
+		item.AllDay = *ut.AllDay
+	}
+
+	item.DueDate = normalizeDue(item.DueDate, item.AllDay)
+
+	if ut.Priority != nil {
+		item.Priority = *ut.Priority
 	}
 
-	item.Description = newDescription
-	item.DueDate = newDueDate
+	if ut.Recurrence != nil {
+		item.Recurrence = *ut.Recurrence
+	}
+
+	if ut.List != nil {
+		item.List = *ut.List
+	}
+
+	if ut.Labels != nil {
+		item.Labels = ut.Labels
+	}
+
+	if ut.Checklist != nil {
+		item.Checklist = ut.Checklist
+	}
+
+	var completed bool
+	if ut.Status != nil {
+		completed = *ut.Status == status.Done && item.Status != status.Done
+
+		// An item that changes status goes to the end of its new column.
+		if *ut.Status != item.Status {
+			end, err := b.endOfColumn(ctx, Column{UserID: item.UserID, Status: *ut.Status})
+			if err != nil {
+				return TodoItem{}, fmt.Errorf("endofcolumn: %w", err)
+			}
+			item.Rank = end
+		}
+
+		item.Status = *ut.Status
+	}
+
+	item.DateUpdated = time.Now()
+
+	// The completion date is what the reports count completions by, so it
+	// follows the item in and out of done.
+	switch {
+	case completed:
+		item.DateCompleted = item.DateUpdated
+	case item.Status != status.Done:
+		item.DateCompleted = time.Time{}
+	}
 
 	if err := b.storer.Update(ctx, item); err != nil {
 		return TodoItem{}, fmt.Errorf("update: %w", err)
 	}
 
+	b.notify(ctx, ActionUpdatedData(item))
+
+	if completed {
+		b.notify(ctx, ActionCompletedData(item))
+
+		if err := b.notifyUnblocked(ctx, item.ID); err != nil {
+			return TodoItem{}, fmt.Errorf("notifyunblocked: %w", err)
+		}
+
+		if item.Recurrence.Valid() && !item.DueDate.IsZero() {
+			if _, err := b.createNext(ctx, item); err != nil {
+				return TodoItem{}, fmt.Errorf("createnext: %w", err)
+			}
+		}
+	}
+
 	return item, nil
 }
 
+// createNext adds the occurrence that follows a completed recurring item.
+// Timed occurrences keep their time of day in the zone of the owner, so they
+// don't move an hour when daylight saving time starts or ends.
+func (b *Business) createNext(ctx context.Context, item TodoItem) (TodoItem, error) {
+	dueDate := item.DueDate
+
+	switch item.AllDay {
+	case true:
+		dueDate = item.Recurrence.Next(dueDate)
+
+	default:
+		usr, err := b.userBus.QueryByID(ctx, item.UserID)
+		if err != nil {
+			return TodoItem{}, fmt.Errorf("querybyid: userID[%s]: %w", item.UserID, err)
+		}
+		dueDate = item.Recurrence.Next(dueDate.In(usr.TimeZone.Location()))
+	}
+
+	// The next occurrence starts with none of its steps done.
+	var checklist []ChecklistItem
+	for _, ci := range item.Checklist {
+		checklist = append(checklist, ChecklistItem{Text: ci.Text})
+	}
+
+	nt := NewTodoItem{
+		OrgID:       item.OrgID,
+		UserID:      item.UserID,
+		AssigneeID:  item.AssigneeID,
+		Description: item.Description,
+		DueDate:     dueDate,
+		AllDay:      item.AllDay,
+		Priority:    item.Priority,
+		Recurrence:  item.Recurrence,
+		List:        item.List,
+		Labels:      item.Labels,
+		Checklist:   checklist,
+	}
+
+	next, err := b.Create(ctx, nt)
+	if err != nil {
+		return TodoItem{}, fmt.Errorf("create: %w", err)
+	}
+
+	return next, nil
+}
+
+// Assign hands the TodoItem to the specified user on behalf of the actor. A
+// zero assignee id removes the current assignee.
+func (b *Business) Assign(ctx context.Context, item TodoItem, assigneeID uuid.UUID, actorID uuid.UUID) (TodoItem, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.assign")
+	defer span.End()
+
+	if assigneeID != uuid.Nil {
+		if err := b.checkAssignee(ctx, item.OrgID, assigneeID); err != nil {
+			return TodoItem{}, fmt.Errorf("checkassignee: %w", err)
+		}
+	}
+
+	return b.assign(ctx, item, assigneeID, actorID)
+}
+
+// QueryHistory retrieves the recorded changes for the specified TodoItem.
+func (b *Business) QueryHistory(ctx context.Context, itemID uuid.UUID) ([]History, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.queryhistory")
+	defer span.End()
+
+	hsts, err := b.storer.QueryHistory(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("queryhistory: itemID[%s]: %w", itemID, err)
+	}
+
+	return hsts, nil
+}
+
 // Delete removes a specified TodoItem.
-func (b *Business) Delete(ctx context.Context, itemID uuid.UUID) error {
+func (b *Business) Delete(ctx context.Context, item TodoItem) error {
 	ctx, span := otel.AddSpan(ctx, "business.todobus.delete")
 	defer span.End()
 
-	item, err := b.storer.QueryByID(ctx, itemID)
+	return b.delete(ctx, item, nil)
+}
+
+// delete removes the item once check, when given, has passed in the same
+// transaction.
+func (b *Business) delete(ctx context.Context, item TodoItem, check func(bus *Business) error) error {
+	var unused Blob
+	err := b.withTran(ctx, func(bus *Business) error {
+		if check != nil {
+			if err := check(bus); err != nil {
+				return err
+			}
+		}
+
+		if err := bus.storer.Delete(ctx, item); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+
+		if item.FileID == "" {
+			return nil
+		}
+
+		// Files stored before contents were deduplicated have no blob and
+		// may be shared under the same name, so they are left in place.
+		blob, err := bus.storer.QueryBlobByFileID(ctx, item.FileID)
+		if err != nil {
+			if errors.Is(err, ErrBlobNotFound) {
+				return nil
+			}
+			return fmt.Errorf("queryblobbyfileid: fileID[%s]: %w", item.FileID, err)
+		}
+
+		if err := bus.refund(ctx, item.UserID, blob.Size); err != nil {
+			return fmt.Errorf("refund: %w", err)
+		}
+
+		if unused, err = bus.releaseBlob(ctx, blob.Checksum); err != nil {
+			return fmt.Errorf("releaseblob: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("delete: %w", err)
+		return err
 	}
 
-	if err := b.storer.Delete(ctx, item); err != nil {
-		return fmt.Errorf("delete: %w", err)
-	}
+	b.deleteBlobFiles(ctx, unused)
 
-	// Optionally delete the file from S3 if needed
-	if err := b.s3Client.Delete(ctx, item.FileID); err != nil {
-		b.log.Warn(ctx, "failed to delete file from S3", "fileID", item.FileID, "error", err)
-	}
+	b.notify(ctx, ActionDeletedData(item))
 
 	return nil
 }
@@ -122,11 +527,11 @@ func (b *Business) QueryByID(ctx context.Context, itemID uuid.UUID) (TodoItem, e
 }
 
 // Query retrieves a list of existing TodoItems.
-func (b *Business) Query(ctx context.Context) ([]TodoItem, error) {
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]TodoItem, error) {
 	ctx, span := otel.AddSpan(ctx, "business.todobus.query")
 	defer span.End()
 
-	items, err := b.storer.Query(ctx)
+	items, err := b.storer.Query(ctx, filter, orderBy, page)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -134,22 +539,68 @@ func (b *Business) Query(ctx context.Context) ([]TodoItem, error) {
 	return items, nil
 }
 
-// UploadFile uploads a file to S3 and returns the generated file ID.
-func (b *Business) UploadFile(ctx context.Context, fileName string, fileData []byte) (string, error) {
+// Count returns the total number of TodoItems.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.todobus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+// UploadFile runs the file through the upload pipeline, stores its contents
+// and records it as an attachment. Contents are stored once under their
+// SHA-256, so identical files share storage. A file the pipeline refuses
+// comes back as an upload.Rejection. Renditions for images are generated in
+// the background.
+func (b *Business) UploadFile(ctx context.Context, userID uuid.UUID, file upload.File) (Attachment, error) {
 	ctx, span := otel.AddSpan(ctx, "business.todobus.uploadfile")
 	defer span.End()
 
-	// Validate inputs
-	if fileName == "" || fileData == nil {
-		return "", fmt.Errorf("uploadfile: invalid input: fileName[%s]", fileName)
+	validated, err := b.uploader.Run(ctx, file)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("uploadfile: name[%s]: %w", file.Name, err)
 	}
 
-	fileID, err := b.s3Client.Upload(ctx, fileName, fileData)
+	var att Attachment
+	err = b.withTran(ctx, func(bus *Business) error {
+		if err := bus.charge(ctx, userID, len(validated.Data)); err != nil {
+			return fmt.Errorf("charge: %w", err)
+		}
+
+		blob, err := bus.storeBlob(ctx, validated.Data, validated.ContentType)
+		if err != nil {
+			return fmt.Errorf("storeblob: %w", err)
+		}
+
+		att = Attachment{
+			ID:          uuid.New(),
+			OrgID:       orgOf(ctx),
+			UserID:      userID,
+			FileID:      blob.FileID,
+			Checksum:    blob.Checksum,
+			Name:        validated.Name,
+			ContentType: validated.ContentType,
+			Size:        len(validated.Data),
+			DateCreated: time.Now(),
+		}
+
+		if err := bus.storer.CreateAttachment(ctx, att); err != nil {
+			return fmt.Errorf("createattachment: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("s3 upload failed: %w", err)
+		return Attachment{}, err
+	}
+
+	// Renditions are started once the attachment is committed, as they are
+	// recorded against it from another goroutine.
+	if hasRenditions(att.ContentType) {
+		b.startRenditions(ctx, att, att.FileID, validated.Data)
 	}
 
-	return fileID, nil
+	return att, nil
 }
 
 // GetFile retrieves a file from S3 by its file ID.
@@ -168,3 +619,173 @@ func (b *Business) GetFile(ctx context.Context, fileID string) ([]byte, error) {
 
 	return fileData, nil
 }
+
+// withTran runs fn with a business whose store calls share one transaction,
+// committing when fn succeeds. Without a beginner, fn runs with b itself.
+func (b *Business) withTran(ctx context.Context, fn func(bus *Business) error) error {
+	if b.beginner == nil {
+		return fn(b)
+	}
+
+	tx, err := b.beginner.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			b.log.Error(ctx, "todobus: rollback", "ERROR", err)
+		}
+	}()
+
+	bus, err := b.NewWithTx(tx)
+	if err != nil {
+		return fmt.Errorf("newwithtx: %w", err)
+	}
+
+	if err := fn(bus); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// orgOf returns the organization new items and attachments go into, which
+// is the one access is scoped to or the default one for work that isn't.
+func orgOf(ctx context.Context) uuid.UUID {
+	if orgID, ok := sqldb.GetOrgID(ctx); ok {
+		return orgID
+	}
+
+	return orgbus.DefaultID
+}
+
+// charge counts size bytes against the quota of the user and their
+// department.
+func (b *Business) charge(ctx context.Context, userID uuid.UUID, size int) error {
+	if b.quotaBus == nil {
+		return nil
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("querybyid: userID[%s]: %w", userID, err)
+	}
+
+	return b.quotaBus.Charge(ctx, usr, int64(size))
+}
+
+// refund gives size bytes back to the quota of the user and their department.
+func (b *Business) refund(ctx context.Context, userID uuid.UUID, size int) error {
+	if b.quotaBus == nil {
+		return nil
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("querybyid: userID[%s]: %w", userID, err)
+	}
+
+	return b.quotaBus.Refund(ctx, usr, int64(size))
+}
+
+// assign changes the assignee without validating the new assignee, which
+// allows the system to hand work back to an owner who is no longer enabled.
+func (b *Business) assign(ctx context.Context, item TodoItem, assigneeID uuid.UUID, actorID uuid.UUID) (TodoItem, error) {
+	if item.AssigneeID == assigneeID {
+		return item, nil
+	}
+
+	previousAssigneeID := item.AssigneeID
+	item.AssigneeID = assigneeID
+	item.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, item); err != nil {
+		return TodoItem{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := b.recordAssignment(ctx, item, previousAssigneeID, actorID); err != nil {
+		return TodoItem{}, fmt.Errorf("recordassignment: %w", err)
+	}
+
+	return item, nil
+}
+
+// checkAssignee makes sure work in the organization can be handed to the
+// specified user, who must be one of its members.
+func (b *Business) checkAssignee(ctx context.Context, orgID uuid.UUID, assigneeID uuid.UUID) error {
+	usr, err := b.userBus.QueryByID(ctx, assigneeID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return ErrAssigneeNotFound
+		}
+		return fmt.Errorf("querybyid: userID[%s]: %w", assigneeID, err)
+	}
+
+	if !usr.Enabled {
+		return ErrAssigneeDisabled
+	}
+
+	filter := userbus.QueryFilter{
+		ID:    &assigneeID,
+		OrgID: &orgID,
+	}
+
+	n, err := b.userBus.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: userID[%s] orgID[%s]: %w", assigneeID, orgID, err)
+	}
+
+	if n == 0 {
+		return ErrAssigneeNotMember
+	}
+
+	return nil
+}
+
+// recordAssignment adds a history entry for an assignment change and lets
+// other domains know the assignment happened.
+func (b *Business) recordAssignment(ctx context.Context, item TodoItem, previousAssigneeID uuid.UUID, actorID uuid.UUID) error {
+	hst := History{
+		ID:          uuid.New(),
+		TodoID:      item.ID,
+		ActorID:     actorID,
+		Field:       HistoryFieldAssignee,
+		OldValue:    uuidString(previousAssigneeID),
+		NewValue:    uuidString(item.AssigneeID),
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.CreateHistory(ctx, hst); err != nil {
+		return fmt.Errorf("createhistory: %w", err)
+	}
+
+	b.notify(ctx, ActionAssignedData(item, previousAssigneeID, actorID))
+
+	return nil
+}
+
+// notify lets other domains know something happened to a todo item. The
+// change has already been made, so failures are only logged.
+func (b *Business) notify(ctx context.Context, data delegate.Data) {
+	if b.delegate == nil {
+		return
+	}
+
+	if err := b.delegate.Call(ctx, data); err != nil {
+		b.log.Error(ctx, "todobus: notify", "action", data.Action, "err", err)
+	}
+}
+
+// uuidString returns the string form of the id, or an empty string when the
+// id is not set.
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/himynamej/todo/business/domain/todobus"
 	"github.com/himynamej/todo/business/domain/todobus/mocks"
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/types/rank"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/himynamej/todo/foundation/otel"
 )
@@ -27,22 +29,27 @@ func BenchmarkInsertTodoItem(b *testing.B) {
 	mockSQSClient := mocks.NewMockSQSClient(ctrl)
 	mockS3Client := mocks.NewMockS3Client(ctrl)
 
-	bus := todobus.NewBusiness(mockLogger, mockStorer, mockSQSClient, mockS3Client)
+	bus := todobus.NewBusiness(mockLogger, nil, nil, nil, mockStorer, mockSQSClient, mockS3Client, upload.TestNewPipeline(), nil, nil)
 
 	// Create a sample TodoItem.
-	description := "Sample TodoItem"
-	dueDate := time.Now().Add(24 * time.Hour)
-	fileData := []byte("Sample file data")
-	fileName := "sample.txt"
+	nt := todobus.NewTodoItem{
+		UserID:      uuid.New(),
+		Description: "Sample TodoItem",
+		DueDate:     time.Now().Add(24 * time.Hour),
+		FileData:    []byte("Sample file data"),
+	}
 
 	// Mock the expected interactions
+	mockStorer.EXPECT().QueryLastRank(gomock.Any(), gomock.Any()).Return(rank.Rank{}, nil).AnyTimes()
 	mockStorer.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	mockS3Client.EXPECT().Upload(gomock.Any(), fileName, fileData).Return("mock-file-id", nil).AnyTimes()
+	mockStorer.EXPECT().AcquireBlob(gomock.Any(), gomock.Any()).Return(todobus.Blob{Checksum: "mock-checksum", RefCount: 1}, nil).AnyTimes()
+	mockStorer.EXPECT().UpdateBlob(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockS3Client.EXPECT().Upload(gomock.Any(), gomock.Any(), nt.FileData).Return("mock-file-id", nil).AnyTimes()
 	mockSQSClient.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := bus.Create(context.Background(), description, dueDate, fileData, fileName)
+		_, err := bus.Create(context.Background(), nt)
 		if err != nil {
 			b.Fatalf("failed to insert TodoItem: %v", err)
 		}
@@ -1,16 +1,32 @@
 package todobus_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"image"
+	"image/png"
+	"slices"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
 	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/page"
 	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/types/priority"
+	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/status"
+	"github.com/himynamej/todo/business/types/timezone"
 )
 
 func Test_TodoItem(t *testing.T) {
@@ -26,8 +42,15 @@ func Test_TodoItem(t *testing.T) {
 	// -------------------------------------------------------------------------
 
 	unitest.Run(t, query(db.BusDomain, sd), "query")
-	unitest.Run(t, create(db.BusDomain), "create")
+	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, assign(db.BusDomain, sd), "assign")
+	unitest.Run(t, disable(db.BusDomain, sd), "disable")
+	unitest.Run(t, views(db.BusDomain, sd), "views")
+	unitest.Run(t, attachments(db.BusDomain, sd), "attachments")
+	unitest.Run(t, dependencies(db.BusDomain, sd), "dependencies")
+	unitest.Run(t, board(db.BusDomain, sd), "board")
+	unitest.Run(t, sync(db.BusDomain), "sync")
 
 }
 
@@ -36,14 +59,33 @@ func Test_TodoItem(t *testing.T) {
 func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
 	ctx := context.Background()
 
-	// Seed 2 new TodoItems using TestSeedTodoItems function
-	todos, err := todobus.TestSeedTodoItems(ctx, 2, busDomain.Todo)
+	usrs, err := userbus.TestSeedUsers(ctx, 3, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	// Seed 3 new TodoItems using TestSeedTodoItems function
+	todos, err := todobus.TestSeedTodoItems(ctx, 3, usrs[0].ID, busDomain.Todo)
 	if err != nil {
 		return unitest.SeedData{}, fmt.Errorf("seeding todo items: %w", err)
 	}
 
+	// Work is only handed to members of the organization of the items.
+	for _, usr := range usrs[1:] {
+		nm := orgbus.NewMember{
+			OrgID:  todos[0].OrgID,
+			UserID: usr.ID,
+			Role:   orgbus.RoleMember,
+		}
+
+		if _, err := busDomain.Org.AddMember(ctx, nm); err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding members: %w", err)
+		}
+	}
+
 	// Populate SeedData structure
 	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}, {User: usrs[2]}},
 		Todos: todos,
 	}
 
@@ -82,7 +124,8 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 				expResp := exp.([]todobus.TodoItem)
 				for i := range gotResp {
-					gotResp[i].DueDate = expResp[i].DueDate
+					gotResp[i].DateCreated = expResp[i].DateCreated
+					gotResp[i].DateUpdated = expResp[i].DateUpdated
 				}
 
 				return cmp.Diff(gotResp, expResp)
@@ -97,9 +140,6 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 					return err
 				}
 
-				// Normalize to UTC and truncate precision for consistent comparison
-				resp.DueDate = resp.DueDate.UTC().Truncate(time.Second)
-
 				return resp
 			},
 			CmpFunc: func(got any, exp any) string {
@@ -109,7 +149,8 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				}
 
 				expResp := exp.(todobus.TodoItem)
-				gotResp.DueDate = expResp.DueDate
+				gotResp.DateCreated = expResp.DateCreated
+				gotResp.DateUpdated = expResp.DateUpdated
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
@@ -117,27 +158,34 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
-func create(busDomain dbtest.BusDomain) []unitest.Table {
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	fileData := []byte("new file data")
+	dueDate := time.Date(2030, time.March, 4, 15, 30, 0, 0, time.UTC)
 
 	table := []unitest.Table{
 		{
 			Name: "basic",
 			ExpResp: todobus.TodoItem{
+				UserID:      sd.Users[0].ID,
+				AssigneeID:  sd.Users[1].ID,
 				Description: "New TodoItem",
-				DueDate:     time.Now().Add(72 * time.Hour).UTC(), // Ensure consistent UTC comparison
-				FileID:      "mock-file-id",
+				DueDate:     dueDate,
+				FileID:      blobKey(fileData),
+				Status:      status.Open,
+				Priority:    priority.Default,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				// Generate new item data
 				nu := todobus.NewTodoItem{
+					UserID:      sd.Users[0].ID,
+					AssigneeID:  sd.Users[1].ID,
 					Description: "New TodoItem",
-					DueDate:     time.Now().Add(72 * time.Hour).UTC(), // Ensure UTC for consistent comparison
-					FileData:    []byte("new file data"),
-					FileName:    "mock-file-id.txt",
+					DueDate:     dueDate,
+					FileData:    fileData,
 				}
 
 				// Create the new TodoItem
-				resp, err := busDomain.Todo.Create(ctx, nu.Description, nu.DueDate, nu.FileData, nu.FileName)
+				resp, err := busDomain.Todo.Create(ctx, nu)
 				if err != nil {
 					return err
 				}
@@ -153,33 +201,109 @@ func create(busDomain dbtest.BusDomain) []unitest.Table {
 				expResp := exp.(todobus.TodoItem)
 
 				// Normalize ID and date fields for comparison
-				expResp.ID = gotResp.ID                 // Since IDs are generated, set it dynamically for comparison
-				gotResp.DueDate = gotResp.DueDate.UTC() // Ensure UTC normalization
-				expResp.DueDate = expResp.DueDate.UTC() // Ensure UTC normalization
-				gotResp.DueDate = expResp.DueDate
+				expResp.ID = gotResp.ID // Since IDs are generated, set it dynamically for comparison
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Rank = gotResp.Rank
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "batch-checklist",
+			ExpResp: []todobus.ChecklistItem{{Text: "Pack"}, {Text: "Book hotel", Done: true}},
+			ExcFunc: func(ctx context.Context) any {
+				nts := []todobus.NewTodoItem{
+					{
+						UserID:      sd.Users[2].ID,
+						Description: "Plan trip",
+						Checklist:   []todobus.ChecklistItem{{Text: "Pack"}, {Text: "Book hotel", Done: true}},
+					},
+					{
+						UserID:      sd.Users[2].ID,
+						Description: "Take trip",
+					},
+				}
+
+				items, err := busDomain.Todo.CreateBatch(ctx, nts)
+				if err != nil {
+					return err
+				}
+
+				item, err := busDomain.Todo.QueryByID(ctx, items[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return item.Checklist
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "batch-rollback",
+			ExpResp: 2,
+			ExcFunc: func(ctx context.Context) any {
+				nts := []todobus.NewTodoItem{
+					{
+						UserID:      sd.Users[2].ID,
+						Description: "Stored first",
+					},
+					{
+						UserID:      sd.Users[2].ID,
+						Description: "Refers to a missing file",
+						FileID:      "missing",
+					},
+				}
+
+				if _, err := busDomain.Todo.CreateBatch(ctx, nts); !errors.Is(err, todobus.ErrBlobNotFound) {
+					return fmt.Errorf("got error %v, exp %v", err, todobus.ErrBlobNotFound)
+				}
+
+				// Only the items of the earlier batch exist.
+				filter := todobus.QueryFilter{UserID: &sd.Users[2].ID}
+
+				count, err := busDomain.Todo.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
 }
 
 func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	dueDate := time.Date(2030, time.March, 5, 9, 0, 0, 0, time.UTC)
+
 	table := []unitest.Table{
 		{
 			Name: "basic",
 			ExpResp: todobus.TodoItem{
 				ID:          sd.Todos[0].ID,
+				UserID:      sd.Todos[0].UserID,
 				Description: "Updated TodoItem",
-				DueDate:     time.Now().Add(96 * time.Hour),
+				DueDate:     dueDate,
 				FileID:      sd.Todos[0].FileID,
+				Status:      status.InProgress,
+				Priority:    sd.Todos[0].Priority,
 			},
 			ExcFunc: func(ctx context.Context) any {
-				newDescription := "Updated TodoItem"
-				newDueDate := time.Now().Add(96 * time.Hour)
+				sts := status.InProgress
 
-				resp, err := busDomain.Todo.Update(ctx, sd.Todos[0].ID, newDescription, newDueDate)
+				ut := todobus.UpdateTodoItem{
+					Description: dbtest.StringPointer("Updated TodoItem"),
+					DueDate:     &dueDate,
+					Status:      &sts,
+				}
+
+				resp, err := busDomain.Todo.Update(ctx, sd.Todos[0], ut)
 				if err != nil {
 					return err
 				}
@@ -193,11 +317,858 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				}
 
 				expResp := exp.(todobus.TodoItem)
-				gotResp.DueDate = expResp.DueDate
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+				expResp.Rank = gotResp.Rank
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func assign(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "history",
+			ExpResp: []todobus.History{
+				{
+					TodoID:   sd.Todos[1].ID,
+					ActorID:  sd.Users[0].ID,
+					Field:    todobus.HistoryFieldAssignee,
+					OldValue: "",
+					NewValue: sd.Users[1].ID.String(),
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.Todo.Assign(ctx, sd.Todos[1], sd.Users[1].ID, sd.Users[0].ID); err != nil {
+					return err
+				}
+
+				hsts, err := busDomain.Todo.QueryHistory(ctx, sd.Todos[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return hsts
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]todobus.History)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]todobus.History)
+				for i := range gotResp {
+					if i < len(expResp) {
+						expResp[i].ID = gotResp[i].ID
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "inbox",
+			ExpResp: []uuid.UUID{sd.Todos[1].ID},
+			ExcFunc: func(ctx context.Context) any {
+				filter := todobus.QueryFilter{
+					AssigneeID: &sd.Users[1].ID,
+				}
+
+				items, err := busDomain.Todo.Query(ctx, filter, todobus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				ids := make([]uuid.UUID, len(items))
+				for i, item := range items {
+					ids[i] = item.ID
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "not-member",
+			ExpResp: todobus.ErrAssigneeNotMember,
+			ExcFunc: func(ctx context.Context) any {
+				usrs, err := userbus.TestSeedUsers(ctx, 1, role.User, busDomain.User)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Todo.Assign(ctx, sd.Todos[1], usrs[0].ID, sd.Users[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "unknown-assignee",
+			ExpResp: todobus.ErrAssigneeNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Todo.Assign(ctx, sd.Todos[1], uuid.New(), sd.Users[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func disable(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "reassign-to-owner",
+			ExpResp: sd.Users[0].ID,
+			ExcFunc: func(ctx context.Context) any {
+				item, err := busDomain.Todo.Assign(ctx, sd.Todos[2], sd.Users[2].ID, sd.Users[0].ID)
+				if err != nil {
+					return err
+				}
+
+				uu := userbus.UpdateUser{
+					Enabled: dbtest.BoolPointer(false),
+				}
+
+				if _, err := busDomain.User.Update(ctx, sd.Users[2].User, uu); err != nil {
+					return err
+				}
+
+				item, err = busDomain.Todo.QueryByID(ctx, item.ID)
+				if err != nil {
+					return err
+				}
+
+				return item.AssigneeID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func dependencies(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	var items []todobus.TodoItem
+
+	isErr := func(got any, exp any) string {
+		err, ok := got.(error)
+		if !ok || !errors.Is(err, exp.(error)) {
+			return fmt.Sprintf("got %v, exp %v", got, exp)
+		}
+		return ""
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "blocked",
+			ExpResp: []bool{true, true, false},
+			ExcFunc: func(ctx context.Context) any {
+				var err error
+				items, err = todobus.TestSeedTodoItems(ctx, 3, sd.Users[0].ID, busDomain.Todo)
+				if err != nil {
+					return err
+				}
+
+				// 0 is blocked by 1, which is blocked by 2.
+				for i := range 2 {
+					if _, err := busDomain.Todo.AddBlocker(ctx, items[i], items[i+1]); err != nil {
+						return err
+					}
+				}
+
+				blocked := make([]bool, len(items))
+				for i, item := range items {
+					if items[i], err = busDomain.Todo.QueryByID(ctx, item.ID); err != nil {
+						return err
+					}
+					blocked[i] = items[i].Blocked
+				}
+
+				return blocked
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "cycle",
+			ExpResp: todobus.ErrDependencyCycle,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Todo.AddBlocker(ctx, items[2], items[0])
+				return err
+			},
+			CmpFunc: isErr,
+		},
+		{
+			Name:    "self",
+			ExpResp: todobus.ErrSelfDependency,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Todo.AddBlocker(ctx, items[0], items[0])
+				return err
+			},
+			CmpFunc: isErr,
+		},
+		{
+			Name:    "exists",
+			ExpResp: todobus.ErrDependencyExists,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Todo.AddBlocker(ctx, items[0], items[1])
+				return err
+			},
+			CmpFunc: isErr,
+		},
+		{
+			Name:    "unblock",
+			ExpResp: []bool{true, false},
+			ExcFunc: func(ctx context.Context) any {
+				sts := status.Done
+				if _, err := busDomain.Todo.Update(ctx, items[2], todobus.UpdateTodoItem{Status: &sts}); err != nil {
+					return err
+				}
+
+				blocked := make([]bool, 2)
+				for i := range blocked {
+					item, err := busDomain.Todo.QueryByID(ctx, items[i].ID)
+					if err != nil {
+						return err
+					}
+					blocked[i] = item.Blocked
+				}
+
+				return blocked
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "graph",
+			ExpResp: 2,
+			ExcFunc: func(ctx context.Context) any {
+				filter := todobus.QueryFilter{
+					UserID: &sd.Users[0].ID,
+				}
+
+				graph, err := busDomain.Todo.QueryGraph(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				for _, dep := range graph.Dependencies {
+					if dep.ItemID == items[2].ID || dep.BlockerID == items[0].ID {
+						return fmt.Errorf("unexpected dependency %s -> %s", dep.ItemID, dep.BlockerID)
+					}
+				}
+
+				return len(graph.Dependencies)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func board(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	var items []todobus.TodoItem
+
+	// columns returns the ids of the items in each column of the board, as
+	// indexes into items.
+	columns := func(ctx context.Context) (map[string][]int, error) {
+		filter := todobus.QueryFilter{
+			UserID: &sd.Users[1].ID,
+		}
+
+		brd, err := busDomain.Todo.QueryBoard(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		cols := make(map[string][]int)
+		for _, col := range brd.Columns {
+			for _, item := range col.Items {
+				idx := slices.IndexFunc(items, func(it todobus.TodoItem) bool {
+					return it.ID == item.ID
+				})
+				cols[col.Status.String()] = append(cols[col.Status.String()], idx)
+			}
+		}
+
+		return cols, nil
+	}
+
+	move := func(ctx context.Context, i int, mv todobus.Move) any {
+		item, err := busDomain.Todo.QueryByID(ctx, items[i].ID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := busDomain.Todo.Move(ctx, item, mv); err != nil {
+			return err
+		}
+
+		cols, err := columns(ctx)
+		if err != nil {
+			return err
+		}
+
+		return cols
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "created",
+			ExpResp: map[string][]int{"OPEN": {0, 1, 2}},
+			ExcFunc: func(ctx context.Context) any {
+				var err error
+				items, err = todobus.TestSeedTodoItems(ctx, 3, sd.Users[1].ID, busDomain.Todo)
+				if err != nil {
+					return err
+				}
+
+				cols, err := columns(ctx)
+				if err != nil {
+					return err
+				}
+
+				return cols
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "to-top",
+			ExpResp: map[string][]int{"OPEN": {2, 0, 1}},
+			ExcFunc: func(ctx context.Context) any {
+				return move(ctx, 2, todobus.Move{AfterID: &items[0].ID})
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "between",
+			ExpResp: map[string][]int{"OPEN": {2, 1, 0}},
+			ExcFunc: func(ctx context.Context) any {
+				return move(ctx, 1, todobus.Move{BeforeID: &items[2].ID, AfterID: &items[0].ID})
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "stale-neighbours",
+			ExpResp: map[string][]int{"OPEN": {2, 0, 1}},
+			ExcFunc: func(ctx context.Context) any {
+				// The neighbours are no longer next to each other, so the
+				// item goes right after the one before it.
+				return move(ctx, 0, todobus.Move{BeforeID: &items[2].ID, AfterID: &items[2].ID})
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "column",
+			ExpResp: map[string][]int{"OPEN": {2, 1}, "IN_PROGRESS": {0}},
+			ExcFunc: func(ctx context.Context) any {
+				sts := status.InProgress
+				return move(ctx, 0, todobus.Move{Status: &sts})
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "neighbour-not-found",
+			ExpResp: todobus.ErrNeighbourNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				return move(ctx, 1, todobus.Move{BeforeID: &items[0].ID})
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+// syncResult sums up a sync by the errors of its mutations and the ids of
+// the items changed and dropped.
+type syncResult struct {
+	Errs       []string
+	Changes    []uuid.UUID
+	Tombstones []uuid.UUID
+}
+
+func sync(busDomain dbtest.BusDomain) []unitest.Table {
+	var (
+		userID  uuid.UUID
+		token   int64
+		ids     = []uuid.UUID{uuid.New(), uuid.New()}
+		version = make(map[uuid.UUID]int64)
+	)
+
+	run := func(ctx context.Context, muts ...todobus.Mutation) any {
+		snc, err := busDomain.Todo.Sync(ctx, userID, token, muts)
+		if err != nil {
+			return err
+		}
+		token = snc.Token
+
+		var res syncResult
+		for _, mr := range snc.Results {
+			switch {
+			case mr.Err == nil:
+				res.Errs = append(res.Errs, "")
+			case errors.Is(mr.Err, todobus.ErrSyncConflict):
+				res.Errs = append(res.Errs, "conflict")
+			default:
+				res.Errs = append(res.Errs, mr.Err.Error())
+			}
+		}
+		for _, chg := range snc.Changes {
+			version[chg.Item.ID] = chg.Version
+			res.Changes = append(res.Changes, chg.Item.ID)
+		}
+		for _, tomb := range snc.Tombstones {
+			res.Tombstones = append(res.Tombstones, tomb.ItemID)
+		}
+
+		return res
+	}
+
+	description := func(d string) todobus.UpdateTodoItem {
+		return todobus.UpdateTodoItem{Description: &d}
+	}
+
+	table := []unitest.Table{
+		{
+			Name: "create",
+			ExpResp: syncResult{
+				Errs:    []string{"", ""},
+				Changes: ids,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				usrs, err := userbus.TestSeedUsers(ctx, 1, role.User, busDomain.User)
+				if err != nil {
+					return err
+				}
+				userID = usrs[0].ID
+
+				return run(ctx,
+					todobus.Mutation{Type: todobus.MutationCreate, ItemID: ids[0], Create: todobus.NewTodoItem{Description: "Offline one"}},
+					todobus.Mutation{Type: todobus.MutationCreate, ItemID: ids[1], Create: todobus.NewTodoItem{Description: "Offline two"}},
+				)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "conflict",
+			ExpResp: syncResult{
+				Errs:    []string{"conflict", ""},
+				Changes: []uuid.UUID{ids[1]},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx,
+					todobus.Mutation{Type: todobus.MutationUpdate, ItemID: ids[0], Version: version[ids[0]] - 1, Update: description("Stale")},
+					todobus.Mutation{Type: todobus.MutationUpdate, ItemID: ids[1], Version: version[ids[1]], Update: description("Fresh")},
+				)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "delete",
+			ExpResp: syncResult{
+				Errs:       []string{""},
+				Tombstones: []uuid.UUID{ids[0]},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx,
+					todobus.Mutation{Type: todobus.MutationDelete, ItemID: ids[0], Version: version[ids[0]]},
+				)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "replay",
+			ExpResp: syncResult{
+				Errs: []string{"", ""},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				return run(ctx,
+					todobus.Mutation{Type: todobus.MutationCreate, ItemID: ids[1], Create: todobus.NewTodoItem{Description: "Offline two"}},
+					todobus.Mutation{Type: todobus.MutationDelete, ItemID: ids[0], Version: version[ids[0]]},
+				)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "future-token",
+			ExpResp: todobus.ErrInvalidSyncToken,
+			ExcFunc: func(ctx context.Context) any {
+				token += 1000
+				return run(ctx)
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func views(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	tokyo := timezone.MustParse("Asia/Tokyo").Location()
+	ownerID := sd.Users[1].ID
+
+	queryView := func(ctx context.Context, view todobus.View, nt todobus.NewTodoItem) any {
+		item, err := busDomain.Todo.Create(ctx, nt)
+		if err != nil {
+			return err
+		}
+
+		filter := todobus.QueryFilter{
+			ID:     &item.ID,
+			UserID: &ownerID,
+		}
+		view.Apply(&filter, time.Now(), tokyo)
+
+		items, err := busDomain.Todo.Query(ctx, filter, todobus.DefaultOrderBy, page.MustParse("1", "10"))
+		if err != nil {
+			return err
+		}
+
+		return len(items)
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "today-all-day",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				nt := todobus.NewTodoItem{
+					UserID:      ownerID,
+					Description: "All day today in Tokyo",
+					DueDate:     time.Now().In(tokyo),
+					AllDay:      true,
+				}
+				return queryView(ctx, todobus.ViewToday, nt)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "overdue-timed",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				nt := todobus.NewTodoItem{
+					UserID:      ownerID,
+					Description: "Timed an hour ago",
+					DueDate:     time.Now().Add(-time.Hour),
+				}
+				return queryView(ctx, todobus.ViewOverdue, nt)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "nodate",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				nt := todobus.NewTodoItem{
+					UserID:      ownerID,
+					Description: "Someday",
+				}
+				return queryView(ctx, todobus.ViewNoDate, nt)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func attachments(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 300)))
+
+	key := blobKey(buf.Bytes())
+	notes := []byte("the same notes, uploaded twice")
+
+	table := []unitest.Table{
+		{
+			Name: "renditions",
+			ExpResp: []todobus.Rendition{
+				{Size: 64, FileID: key + "_64", ContentType: "image/png", Width: 64, Height: 48},
+				{Size: 256, FileID: key + "_256", ContentType: "image/png", Width: 256, Height: 192},
+				{Size: 1024, FileID: key + "_1024", ContentType: "image/png", Width: 400, Height: 300},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				file := upload.File{
+					Name: "photo.png",
+					Data: buf.Bytes(),
+				}
+
+				att, err := busDomain.Todo.UploadFile(ctx, sd.Users[0].ID, file)
+				if err != nil {
+					return err
+				}
+
+				// The test business has no worker, so the renditions are
+				// rendered before the upload returns.
+				att, err = busDomain.Todo.QueryAttachmentByID(ctx, att.ID)
+				if err != nil {
+					return err
+				}
+
+				return att.Renditions
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "rejected",
+			ExpResp: upload.FieldFileName,
+			ExcFunc: func(ctx context.Context) any {
+				file := upload.File{
+					Name: "photo.jpg",
+					Data: buf.Bytes(),
+				}
+
+				_, err := busDomain.Todo.UploadFile(ctx, sd.Users[0].ID, file)
+
+				rej := upload.GetRejection(err)
+				if rej == nil {
+					return err
+				}
+
+				return rej.Field
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "dedup",
+			ExpResp: todobus.Blob{FileID: blobKey(notes), ContentType: upload.TypeText, Size: len(notes), RefCount: 2},
+			ExcFunc: func(ctx context.Context) any {
+				var fileIDs []string
+				for _, usr := range sd.Users[:2] {
+					file := upload.File{
+						Name: "notes.txt",
+						Data: notes,
+					}
+
+					att, err := busDomain.Todo.UploadFile(ctx, usr.ID, file)
+					if err != nil {
+						return err
+					}
+
+					fileIDs = append(fileIDs, att.FileID)
+				}
+
+				if fileIDs[0] != fileIDs[1] {
+					return fmt.Errorf("expected one file, got %v", fileIDs)
+				}
+
+				blob, err := busDomain.Todo.QueryBlobByFileID(ctx, fileIDs[0])
+				if err != nil {
+					return err
+				}
+
+				return blob
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(todobus.Blob)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.(todobus.Blob)
+				expResp.Checksum = gotResp.Checksum
+				expResp.DateCreated = gotResp.DateCreated
+
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "access",
+			ExpResp: []bool{true, false, true},
+			ExcFunc: func(ctx context.Context) any {
+				file := upload.File{
+					Name: "plan.txt",
+					Data: []byte("only for the owner and the assignee"),
+				}
+
+				att, err := busDomain.Todo.UploadFile(ctx, sd.Users[0].ID, file)
+				if err != nil {
+					return err
+				}
+
+				var access []bool
+				for _, userID := range []uuid.UUID{sd.Users[0].ID, sd.Users[1].ID} {
+					ok, err := busDomain.Todo.CanAccessFile(ctx, userID, att.FileID)
+					if err != nil {
+						return err
+					}
+					access = append(access, ok)
+				}
+
+				nt := todobus.NewTodoItem{
+					UserID:      sd.Users[0].ID,
+					AssigneeID:  sd.Users[1].ID,
+					Description: "Read the plan",
+					FileID:      att.FileID,
+				}
+
+				if _, err := busDomain.Todo.Create(ctx, nt); err != nil {
+					return err
+				}
+
+				ok, err := busDomain.Todo.CanAccessFile(ctx, sd.Users[1].ID, att.FileID)
+				if err != nil {
+					return err
+				}
+
+				return append(access, ok)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "not-owned",
+			ExpResp: todobus.ErrFileNotOwned,
+			ExcFunc: func(ctx context.Context) any {
+				file := upload.File{
+					Name: "secret.txt",
+					Data: []byte("known only by its hash"),
+				}
+
+				att, err := busDomain.Todo.UploadFile(ctx, sd.Users[0].ID, file)
+				if err != nil {
+					return err
+				}
+
+				nt := todobus.NewTodoItem{
+					UserID:      sd.Users[1].ID,
+					Description: "Borrowed file",
+					FileID:      att.FileID,
+				}
+
+				_, err = busDomain.Todo.Create(ctx, nt)
+
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, want %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "release",
+			ExpResp: todobus.ErrBlobNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				nt := todobus.NewTodoItem{
+					UserID:      sd.Users[0].ID,
+					Description: "Shared file",
+					FileData:    []byte("shared by two items"),
+				}
+
+				first, err := busDomain.Todo.Create(ctx, nt)
+				if err != nil {
+					return err
+				}
+
+				nt.FileData = nil
+				nt.FileID = first.FileID
+
+				second, err := busDomain.Todo.Create(ctx, nt)
+				if err != nil {
+					return err
+				}
+
+				for _, item := range []todobus.TodoItem{first, second} {
+					if _, err := busDomain.Todo.QueryBlobByFileID(ctx, item.FileID); err != nil {
+						return err
+					}
+
+					if err := busDomain.Todo.Delete(ctx, item); err != nil {
+						return err
+					}
+				}
+
+				_, err = busDomain.Todo.QueryBlobByFileID(ctx, first.FileID)
+
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, want %v", got, exp)
+				}
+				return ""
+			},
+		},
 	}
 
 	return table
 }
+
+// blobKey returns the file ID the contents are stored under.
+func blobKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "blobs/" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,78 @@
+package todobus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/himynamej/todo/business/types/status"
+)
+
+// The set of smart views that can be used.
+var (
+	ViewToday    = newView("today")
+	ViewOverdue  = newView("overdue")
+	ViewUpcoming = newView("upcoming")
+	ViewNoDate   = newView("nodate")
+)
+
+// upcomingDays is how far ahead the upcoming view looks, starting tomorrow.
+const upcomingDays = 7
+
+// Set of known views.
+var views = make(map[string]View)
+
+// View represents a server side smart view over todo items. Views are
+// evaluated in the time zone of the user asking for them.
+type View struct {
+	value string
+}
+
+func newView(view string) View {
+	v := View{view}
+	views[view] = v
+	return v
+}
+
+// ParseView parses the string value and returns a view if one exists.
+func ParseView(value string) (View, error) {
+	v, exists := views[value]
+	if !exists {
+		return View{}, fmt.Errorf("invalid view %q", value)
+	}
+
+	return v, nil
+}
+
+// String returns the name of the view.
+func (v View) String() string {
+	return v.value
+}
+
+// Apply narrows the filter to the items in the view for a user in the
+// specified location at the specified moment.
+func (v View) Apply(filter *QueryFilter, now time.Time, loc *time.Location) {
+	now = now.In(loc)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch v {
+	case ViewToday:
+		end := startOfDay.AddDate(0, 0, 1)
+		filter.StartDueDate = &startOfDay
+		filter.EndDueDate = &end
+
+	case ViewOverdue:
+		done := status.Done
+		filter.EndDueDate = &now
+		filter.ExcludeStatus = &done
+
+	case ViewUpcoming:
+		start := startOfDay.AddDate(0, 0, 1)
+		end := startOfDay.AddDate(0, 0, 1+upcomingDays)
+		filter.StartDueDate = &start
+		filter.EndDueDate = &end
+
+	case ViewNoDate:
+		noDueDate := true
+		filter.NoDueDate = &noDueDate
+	}
+}
@@ -0,0 +1,115 @@
+package todobus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/business/types/status"
+)
+
+func Test_ViewApply(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("load location: %s", err)
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %s", err)
+	}
+
+	// 2024-03-10 23:30 UTC is already the 11th in Tokyo but still the 10th
+	// in New York.
+	now := time.Date(2024, time.March, 10, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		view      View
+		loc       *time.Location
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "today-tokyo",
+			view:      ViewToday,
+			loc:       tokyo,
+			wantStart: time.Date(2024, time.March, 11, 0, 0, 0, 0, tokyo),
+			wantEnd:   time.Date(2024, time.March, 12, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:      "today-newyork",
+			view:      ViewToday,
+			loc:       newYork,
+			wantStart: time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork),
+			wantEnd:   time.Date(2024, time.March, 11, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:      "upcoming-tokyo",
+			view:      ViewUpcoming,
+			loc:       tokyo,
+			wantStart: time.Date(2024, time.March, 12, 0, 0, 0, 0, tokyo),
+			wantEnd:   time.Date(2024, time.March, 19, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:    "overdue-newyork",
+			view:    ViewOverdue,
+			loc:     newYork,
+			wantEnd: now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter QueryFilter
+			tt.view.Apply(&filter, now, tt.loc)
+
+			if !tt.wantStart.IsZero() {
+				if filter.StartDueDate == nil || !filter.StartDueDate.Equal(tt.wantStart) {
+					t.Fatalf("start: got %v, want %v", filter.StartDueDate, tt.wantStart)
+				}
+
+				if dueDay(*filter.StartDueDate) != dueDay(tt.wantStart) {
+					t.Fatalf("start day: got %v, want %v", dueDay(*filter.StartDueDate), dueDay(tt.wantStart))
+				}
+			}
+
+			if filter.EndDueDate == nil || !filter.EndDueDate.Equal(tt.wantEnd) {
+				t.Fatalf("end: got %v, want %v", filter.EndDueDate, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func Test_ViewOverdueSkipsDone(t *testing.T) {
+	var filter QueryFilter
+	ViewOverdue.Apply(&filter, time.Now(), time.UTC)
+
+	if filter.ExcludeStatus == nil || !filter.ExcludeStatus.Equal(status.Done) {
+		t.Fatalf("overdue should exclude done items, got %v", filter.ExcludeStatus)
+	}
+}
+
+func Test_ViewNoDate(t *testing.T) {
+	var filter QueryFilter
+	ViewNoDate.Apply(&filter, time.Now(), time.UTC)
+
+	if filter.NoDueDate == nil || !*filter.NoDueDate {
+		t.Fatal("nodate should select items without a due date")
+	}
+
+	if filter.StartDueDate != nil || filter.EndDueDate != nil {
+		t.Fatal("nodate should not bound the due date")
+	}
+}
+
+func Test_ParseView(t *testing.T) {
+	for _, value := range []string{"today", "overdue", "upcoming", "nodate"} {
+		if _, err := ParseView(value); err != nil {
+			t.Fatalf("%q: %s", value, err)
+		}
+	}
+
+	if _, err := ParseView("someday"); err == nil {
+		t.Fatal("expected an error for an unknown view")
+	}
+}
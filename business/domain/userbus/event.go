@@ -13,9 +13,67 @@ const DomainName = "user"
 
 // Set of delegate actions.
 const (
+	ActionCreated = "created"
 	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
 )
 
+// ActionUserParms represents the parameters for the created and deleted
+// actions.
+type ActionUserParms struct {
+	UserID     uuid.UUID
+	Name       string
+	Email      string
+	Roles      []string
+	Department string
+}
+
+// String returns a string representation of the action parameters.
+func (au *ActionUserParms) String() string {
+	return fmt.Sprintf("&EventParamsUser{UserID:%v}", au.UserID)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (au *ActionUserParms) Marshal() ([]byte, error) {
+	return json.Marshal(au)
+}
+
+// ActionCreatedData constructs the data for the created action.
+func ActionCreatedData(usr User) delegate.Data {
+	return actionUserData(ActionCreated, usr)
+}
+
+// ActionDeletedData constructs the data for the deleted action.
+func ActionDeletedData(usr User) delegate.Data {
+	return actionUserData(ActionDeleted, usr)
+}
+
+func actionUserData(action string, usr User) delegate.Data {
+	roles := make([]string, len(usr.Roles))
+	for i, r := range usr.Roles {
+		roles[i] = r.String()
+	}
+
+	params := ActionUserParms{
+		UserID:     usr.ID,
+		Name:       usr.Name.String(),
+		Email:      usr.Email.Address,
+		Roles:      roles,
+		Department: usr.Department.String(),
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    action,
+		RawParams: rawParams,
+	}
+}
+
 // ActionUpdatedParms represents the parameters for the updated action.
 type ActionUpdatedParms struct {
 	UserID uuid.UUID
@@ -14,6 +14,7 @@ type QueryFilter struct {
 	ID               *uuid.UUID
 	Name             *name.Name
 	Email            *mail.Address
+	OrgID            *uuid.UUID // Members of the organization
 	StartCreatedDate *time.Time
 	EndCreatedDate   *time.Time
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/himynamej/todo/business/types/name"
 	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/timezone"
 	"github.com/google/uuid"
 )
 
@@ -17,6 +18,7 @@ type User struct {
 	Roles        []role.Role
 	PasswordHash []byte
 	Department   name.Null
+	TimeZone     timezone.TimeZone
 	Enabled      bool
 	DateCreated  time.Time
 	DateUpdated  time.Time
@@ -28,7 +30,9 @@ type NewUser struct {
 	Email      mail.Address
 	Roles      []role.Role
 	Department name.Null
+	TimeZone   timezone.TimeZone
 	Password   string
+	Disabled   bool // Can't authenticate until enabled, as with signups yet to be verified
 }
 
 // UpdateUser contains information needed to update a user.
@@ -37,6 +41,7 @@ type UpdateUser struct {
 	Email      *mail.Address
 	Roles      []role.Role
 	Department *name.Null
+	TimeZone   *timezone.TimeZone
 	Password   *string
 	Enabled    *bool
 }
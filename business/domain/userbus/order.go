@@ -1,6 +1,13 @@
 package userbus
 
-import "github.com/himynamej/todo/business/sdk/order"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/types/role"
+)
 
 // DefaultOrderBy represents the default way we sort.
 var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
@@ -13,3 +20,28 @@ const (
 	OrderByRoles   = "roles"
 	OrderByEnabled = "enabled"
 )
+
+// Cursor returns the position of the user in the order, for the next page to
+// start after it. The values are formatted the way the store reads them.
+func Cursor(usr User, orderBy order.By) page.Cursor {
+	var value string
+
+	switch orderBy.Field {
+	case OrderByID:
+		value = usr.ID.String()
+	case OrderByName:
+		value = usr.Name.String()
+	case OrderByEmail:
+		value = usr.Email.Address
+	case OrderByRoles:
+		value = "{" + strings.Join(role.ParseToString(usr.Roles), ",") + "}"
+	case OrderByEnabled:
+		value = strconv.FormatBool(usr.Enabled)
+	}
+
+	return page.Cursor{
+		Order: orderBy,
+		Value: &value,
+		ID:    usr.ID.String(),
+	}
+}
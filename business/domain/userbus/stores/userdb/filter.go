@@ -8,8 +8,7 @@ import (
 	"github.com/himynamej/todo/business/domain/userbus"
 )
 
-func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
-	var wc []string
+func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buffer, wc ...string) {
 
 	if filter.ID != nil {
 		data["user_id"] = *filter.ID
@@ -22,10 +21,15 @@ func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buf
 	}
 
 	if filter.Email != nil {
-		data["email"] = (*filter.Email).String()
+		data["email"] = filter.Email.Address
 		wc = append(wc, "email = :email")
 	}
 
+	if filter.OrgID != nil {
+		data["org_id"] = *filter.OrgID
+		wc = append(wc, "user_id IN (SELECT user_id FROM org_members WHERE org_id = :org_id)")
+	}
+
 	if filter.StartCreatedDate != nil {
 		data["start_date_created"] = filter.StartCreatedDate.UTC()
 		wc = append(wc, "date_created >= :start_date_created")
@@ -10,6 +10,7 @@ import (
 	"github.com/himynamej/todo/business/sdk/sqldb/dbarray"
 	"github.com/himynamej/todo/business/types/name"
 	"github.com/himynamej/todo/business/types/role"
+	"github.com/himynamej/todo/business/types/timezone"
 	"github.com/google/uuid"
 )
 
@@ -20,6 +21,7 @@ type user struct {
 	Roles        dbarray.String `db:"roles"`
 	PasswordHash []byte         `db:"password_hash"`
 	Department   sql.NullString `db:"department"`
+	TimeZone     string         `db:"time_zone"`
 	Enabled      bool           `db:"enabled"`
 	DateCreated  time.Time      `db:"date_created"`
 	DateUpdated  time.Time      `db:"date_updated"`
@@ -36,6 +38,7 @@ func toDBUser(bus userbus.User) user {
 			String: bus.Department.String(),
 			Valid:  bus.Department.Valid(),
 		},
+		TimeZone:    bus.TimeZone.String(),
 		Enabled:     bus.Enabled,
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
@@ -62,6 +65,11 @@ func toBusUser(db user) (userbus.User, error) {
 		return userbus.User{}, fmt.Errorf("parse department: %w", err)
 	}
 
+	tz, err := timezone.Parse(db.TimeZone)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("parse time zone: %w", err)
+	}
+
 	bus := userbus.User{
 		ID:           db.ID,
 		Name:         nme,
@@ -70,6 +78,7 @@ func toBusUser(db user) (userbus.User, error) {
 		PasswordHash: db.PasswordHash,
 		Enabled:      db.Enabled,
 		Department:   department,
+		TimeZone:     tz,
 		DateCreated:  db.DateCreated.In(time.Local),
 		DateUpdated:  db.DateUpdated.In(time.Local),
 	}
@@ -5,21 +5,33 @@ import (
 
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/sqldb"
 )
 
-var orderByFields = map[string]string{
-	userbus.OrderByID:      "user_id",
-	userbus.OrderByName:    "name",
-	userbus.OrderByEmail:   "email",
-	userbus.OrderByRoles:   "roles",
-	userbus.OrderByEnabled: "enabled",
+var idColumn = sqldb.KeysetColumn{Name: "user_id", Type: "UUID"}
+
+var orderByFields = map[string]sqldb.KeysetColumn{
+	userbus.OrderByID:      idColumn,
+	userbus.OrderByName:    {Name: "name", Type: "TEXT"},
+	userbus.OrderByEmail:   {Name: "email", Type: "TEXT"},
+	userbus.OrderByRoles:   {Name: "roles", Type: "TEXT[]"},
+	userbus.OrderByEnabled: {Name: "enabled", Type: "BOOLEAN"},
 }
 
-func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
+func orderByColumn(orderBy order.By) (sqldb.KeysetColumn, error) {
+	column, exists := orderByFields[orderBy.Field]
 	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+		return sqldb.KeysetColumn{}, fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return column, nil
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	column, err := orderByColumn(orderBy)
+	if err != nil {
+		return "", err
 	}
 
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+	return sqldb.OrderByKeyset(column, idColumn, orderBy.Direction), nil
 }
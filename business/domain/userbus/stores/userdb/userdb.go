@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
 
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/sdk/order"
@@ -51,9 +52,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (userbus.Storer, error) {
 func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 	const q = `
 	INSERT INTO users
-		(user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated)
+		(user_id, name, email, password_hash, roles, department, time_zone, enabled, date_created, date_updated)
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated)`
+		(:user_id, :name, :email, :password_hash, :roles, :department, :time_zone, :enabled, :date_created, :date_updated)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
 		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
@@ -76,6 +77,7 @@ func (s *Store) Update(ctx context.Context, usr userbus.User) error {
 		"roles" = :roles,
 		"password_hash" = :password_hash,
 		"department" = :department,
+		"time_zone" = :time_zone,
 		"enabled" = :enabled,
 		"date_updated" = :date_updated
 	WHERE
@@ -115,26 +117,46 @@ func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy o
 
 	const q = `
 	SELECT
-		user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+		user_id, name, email, password_hash, roles, department, time_zone, enabled, date_created, date_updated
 	FROM
 		users`
 
 	buf := bytes.NewBufferString(q)
-	applyFilter(filter, data, buf)
 
-	orderByClause, err := orderByClause(orderBy)
-	if err != nil {
-		return nil, err
-	}
+	cur, isCursor := page.Cursor()
+
+	switch isCursor {
+	case true:
+		column, err := orderByColumn(cur.Order)
+		if err != nil {
+			return nil, err
+		}
 
-	buf.WriteString(orderByClause)
-	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+		where, orderByClause := sqldb.Keyset(cur, column, idColumn, data)
+		applyFilter(filter, data, buf, where)
+		buf.WriteString(orderByClause)
+		buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	default:
+		orderByClause, err := orderByClause(orderBy)
+		if err != nil {
+			return nil, err
+		}
+
+		applyFilter(filter, data, buf)
+		buf.WriteString(orderByClause)
+		buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+	}
 
 	var dbUsrs []user
 	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
+	if isCursor && cur.Before {
+		slices.Reverse(dbUsrs)
+	}
+
 	return toBusUsers(dbUsrs)
 }
 
@@ -171,7 +193,7 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User,
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+        user_id, name, email, password_hash, roles, department, time_zone, enabled, date_created, date_updated
 	FROM
 		users
 	WHERE 
@@ -198,7 +220,7 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+        user_id, name, email, password_hash, roles, department, time_zone, enabled, date_created, date_updated
 	FROM
 		users
 	WHERE
@@ -90,7 +90,8 @@ func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
 		PasswordHash: hash,
 		Roles:        nu.Roles,
 		Department:   nu.Department,
-		Enabled:      true,
+		TimeZone:     nu.TimeZone,
+		Enabled:      !nu.Disabled,
 		DateCreated:  now,
 		DateUpdated:  now,
 	}
@@ -99,6 +100,8 @@ func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
 		return User{}, fmt.Errorf("create: %w", err)
 	}
 
+	b.notify(ctx, ActionCreatedData(usr))
+
 	return usr, nil
 }
 
@@ -131,6 +134,10 @@ func (b *Business) Update(ctx context.Context, usr User, uu UpdateUser) (User, e
 		usr.Department = *uu.Department
 	}
 
+	if uu.TimeZone != nil {
+		usr.TimeZone = *uu.TimeZone
+	}
+
 	if uu.Enabled != nil {
 		usr.Enabled = *uu.Enabled
 	}
@@ -158,6 +165,8 @@ func (b *Business) Delete(ctx context.Context, usr User) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	b.notify(ctx, ActionDeletedData(usr))
+
 	return nil
 }
 
@@ -226,3 +235,16 @@ func (b *Business) Authenticate(ctx context.Context, email mail.Address, passwor
 
 	return usr, nil
 }
+
+// notify lets other domains know a user was created or deleted. The change
+// has already been made, so failures are only logged. Tools that manage
+// users directly run without a delegate.
+func (b *Business) notify(ctx context.Context, data delegate.Data) {
+	if b.delegate == nil {
+		return
+	}
+
+	if err := b.delegate.Call(ctx, data); err != nil {
+		b.log.Error(ctx, "userbus: notify", "action", data.Action, "err", err)
+	}
+}
@@ -0,0 +1,81 @@
+package webhookbus
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, which is as private as
+// the RFC 1918 ranges but not reported by netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewClient constructs the client deliveries are sent with. Subscribers pick
+// the url, so the client only connects to publicly routable addresses. The
+// address is checked as the connection is made, after the name is resolved,
+// so a name that resolves to a private address later is refused too.
+// Redirects are not followed, the response that asked for one is recorded
+// instead.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("address[%s]: %w", address, err)
+			}
+
+			if !isPublic(addrPort.Addr()) {
+				return fmt.Errorf("address[%s]: %w", address, ErrPrivateAddress)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	client := http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: noRedirect,
+	}
+
+	return &client
+}
+
+// noRedirect stops the client at the first response.
+func noRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// isPublic reports whether the address is reachable on the public internet,
+// which rules out loopback, private, link-local and cloud metadata addresses.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	switch {
+	case !addr.IsValid(),
+		addr.IsUnspecified(),
+		addr.IsLoopback(),
+		addr.IsPrivate(),
+		addr.IsLinkLocalUnicast(),
+		addr.IsLinkLocalMulticast(),
+		addr.IsInterfaceLocalMulticast(),
+		addr.IsMulticast(),
+		sharedAddressSpace.Contains(addr):
+		return false
+	}
+
+	// 0.0.0.0/8 means this network.
+	if addr.Is4() && addr.As4()[0] == 0 {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,32 @@
+package webhookbus_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/webhookbus"
+)
+
+func Test_ClientPrivate(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := webhookbus.NewClient(time.Second)
+
+	resp, err := client.Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Should NOT be able to reach a loopback address")
+	}
+
+	if !errors.Is(err, webhookbus.ErrPrivateAddress) {
+		t.Fatalf("Should get a private address error: %s", err)
+	}
+}
@@ -0,0 +1,212 @@
+package webhookbus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/foundation/otel"
+)
+
+// claimLease is how long a delivery being sent is hidden from others looking
+// for work. A delivery still being sent after that may be sent twice, which
+// receivers can detect from the delivery id.
+const claimLease = 5 * time.Minute
+
+// dueBatch is how many deliveries DeliverDue claims at a time.
+const dueBatch = 50
+
+// DeliverDue sends every delivery that is waiting for its next attempt,
+// including those whose first attempt could not be started straight away.
+// It returns the number of deliveries attempted.
+func (b *Business) DeliverDue(ctx context.Context) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.deliverdue")
+	defer span.End()
+
+	var attempted int
+	for {
+		now := time.Now()
+
+		dlvs, err := b.storer.ClaimDueDeliveries(ctx, now, now.Add(claimLease), dueBatch)
+		if err != nil {
+			return attempted, fmt.Errorf("claimduedeliveries: %w", err)
+		}
+
+		for _, dlv := range dlvs {
+			if err := b.deliver(ctx, dlv); err != nil {
+				b.log.Error(ctx, "webhookbus: deliverdue", "deliveryID", dlv.ID, "ERROR", err)
+			}
+			attempted++
+		}
+
+		if len(dlvs) < dueBatch || ctx.Err() != nil {
+			return attempted, nil
+		}
+	}
+}
+
+// =============================================================================
+
+// queue records a delivery of the event to the subscription and starts the
+// first attempt.
+func (b *Business) queue(ctx context.Context, sub Subscription, eventID uuid.UUID, event string, payload []byte) (Delivery, error) {
+	now := time.Now()
+
+	dlv := Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		EventID:        eventID,
+		Event:          event,
+		Payload:        payload,
+		Status:         DeliveryPending,
+		NextAttempt:    now,
+		DateCreated:    now,
+		DateUpdated:    now,
+	}
+
+	if err := b.storer.CreateDelivery(ctx, dlv); err != nil {
+		return Delivery{}, fmt.Errorf("createdelivery: %w", err)
+	}
+
+	b.start(ctx, dlv)
+
+	return dlv, nil
+}
+
+// start hands the first attempt of a delivery to the worker so the caller
+// does not wait on the receiver. Without a worker, or when the worker is
+// busy, the delivery is left for DeliverDue.
+func (b *Business) start(ctx context.Context, dlv Delivery) {
+	if b.worker == nil {
+		return
+	}
+
+	job := func(ctx context.Context) {
+		now := time.Now()
+
+		claimed, err := b.storer.ClaimDelivery(ctx, dlv.ID, now, now.Add(claimLease))
+		if err != nil {
+			if !errors.Is(err, ErrNotDue) {
+				b.log.Error(ctx, "webhookbus: claim", "deliveryID", dlv.ID, "ERROR", err)
+			}
+			return
+		}
+
+		if err := b.deliver(ctx, claimed); err != nil {
+			b.log.Error(ctx, "webhookbus: deliver", "deliveryID", dlv.ID, "ERROR", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.cfg.Client.Timeout+10*time.Second)
+	defer cancel()
+
+	if _, err := b.worker.Start(ctx, job); err != nil {
+		b.log.Info(ctx, "webhookbus: start: left for retry", "deliveryID", dlv.ID, "ERROR", err)
+	}
+}
+
+// deliver makes one attempt at sending a claimed delivery and records the
+// outcome. Once the attempts run out the delivery fails, and so does its
+// subscription, which receives no more events until it is activated again.
+func (b *Business) deliver(ctx context.Context, dlv Delivery) error {
+	sub, err := b.storer.QueryByID(ctx, dlv.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("querybyid: subscriptionID[%s]: %w", dlv.SubscriptionID, err)
+	}
+
+	now := time.Now()
+	dlv.DateUpdated = now
+
+	if sub.Status != StatusActive {
+		dlv.Status = DeliveryFailed
+		dlv.Error = fmt.Sprintf("subscription is %s", sub.Status)
+
+		if err := b.storer.UpdateDelivery(ctx, dlv); err != nil {
+			return fmt.Errorf("updatedelivery: %w", err)
+		}
+		return nil
+	}
+
+	code, sendErr := b.send(ctx, sub, dlv)
+
+	dlv.Attempts++
+	dlv.ResponseCode = code
+
+	switch {
+	case sendErr == nil:
+		dlv.Status = DeliveryDelivered
+		dlv.Error = ""
+
+	case dlv.Attempts >= b.cfg.MaxAttempts:
+		dlv.Status = DeliveryFailed
+		dlv.Error = sendErr.Error()
+
+		sub.Status = StatusFailed
+		sub.DateUpdated = now
+		if err := b.storer.Update(ctx, sub); err != nil {
+			return fmt.Errorf("update: subscriptionID[%s]: %w", sub.ID, err)
+		}
+
+		b.log.Info(ctx, "webhookbus: subscription failed", "subscriptionID", sub.ID, "attempts", dlv.Attempts)
+
+	default:
+		dlv.Error = sendErr.Error()
+		dlv.NextAttempt = now.Add(b.backoff(dlv.Attempts))
+	}
+
+	if err := b.storer.UpdateDelivery(ctx, dlv); err != nil {
+		return fmt.Errorf("updatedelivery: %w", err)
+	}
+
+	return nil
+}
+
+// send posts the payload of the delivery to the subscription, signed with
+// its secret. Any response outside of 2xx counts as a failure.
+func (b *Business) send(ctx context.Context, sub Subscription, dlv Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(dlv.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("new request: %w", err)
+	}
+
+	now := time.Now()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, dlv.ID.String())
+	req.Header.Set(HeaderEvent, dlv.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, now, dlv.Payload))
+
+	resp, err := b.cfg.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain what little the receiver sends back so the connection can be
+	// reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns how long to wait after the specified number of failed
+// attempts. The wait doubles with every attempt up to the maximum.
+func (b *Business) backoff(attempts int) time.Duration {
+	d := b.cfg.Backoff
+	for i := 1; i < attempts && d < b.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, b.cfg.MaxBackoff)
+}
@@ -0,0 +1,132 @@
+package webhookbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+)
+
+// Set of events a subscription can ask for. An event is named after the
+// domain and action it comes from.
+const (
+	EventTodoCreated   = todobus.DomainName + "." + todobus.ActionCreated
+	EventTodoUpdated   = todobus.DomainName + "." + todobus.ActionUpdated
+	EventTodoCompleted = todobus.DomainName + "." + todobus.ActionCompleted
+	EventTodoDeleted   = todobus.DomainName + "." + todobus.ActionDeleted
+	EventTodoAssigned  = todobus.DomainName + "." + todobus.ActionAssigned
+	EventTodoUnblocked = todobus.DomainName + "." + todobus.ActionUnblocked
+	EventUserCreated   = userbus.DomainName + "." + userbus.ActionCreated
+	EventUserUpdated   = userbus.DomainName + "." + userbus.ActionUpdated
+	EventUserDeleted   = userbus.DomainName + "." + userbus.ActionDeleted
+)
+
+// Events lists every event a subscription can ask for.
+var Events = []string{
+	EventTodoCreated,
+	EventTodoUpdated,
+	EventTodoCompleted,
+	EventTodoDeleted,
+	EventTodoAssigned,
+	EventTodoUnblocked,
+	EventUserCreated,
+	EventUserUpdated,
+	EventUserDeleted,
+}
+
+// Payload is the body of every delivery. Data holds the parameters of the
+// action the event comes from.
+type Payload struct {
+	ID         uuid.UUID       `json:"id"`
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// registerDelegateFunctions will register action functions with the delegate
+// system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate == nil {
+		return
+	}
+
+	for _, action := range []string{todobus.ActionCreated, todobus.ActionUpdated, todobus.ActionCompleted, todobus.ActionDeleted, todobus.ActionAssigned, todobus.ActionUnblocked} {
+		b.delegate.Register(todobus.DomainName, action, b.actionEvent)
+	}
+
+	for _, action := range []string{userbus.ActionCreated, userbus.ActionUpdated, userbus.ActionDeleted} {
+		b.delegate.Register(userbus.DomainName, action, b.actionEvent)
+	}
+}
+
+// eventUsers picks the users an event is about, and the organization of a
+// todo item, out of the parameters of any of the actions that are subscribed
+// to.
+type eventUsers struct {
+	OrgID              uuid.UUID
+	UserID             uuid.UUID
+	OwnerID            uuid.UUID
+	AssigneeID         uuid.UUID
+	PreviousAssigneeID uuid.UUID
+}
+
+// actionEvent is executed by the todo and user domains indirectly for every
+// action a subscription can ask for. A delivery is queued for each
+// subscription that wants the event.
+func (b *Business) actionEvent(ctx context.Context, data delegate.Data) error {
+	var users eventUsers
+	if err := json.Unmarshal(data.RawParams, &users); err != nil {
+		return fmt.Errorf("expected encoded action parameters: %w", err)
+	}
+
+	var userIDs []uuid.UUID
+	for _, userID := range []uuid.UUID{users.UserID, users.OwnerID, users.AssigneeID, users.PreviousAssigneeID} {
+		if userID != uuid.Nil {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	event := data.Domain + "." + data.Action
+
+	// Events about users carry no organization, they belong to the one the
+	// change was made in.
+	orgID := users.OrgID
+	if orgID == uuid.Nil {
+		orgID, _ = sqldb.GetOrgID(ctx)
+	}
+
+	subs, err := b.storer.QueryByEvent(ctx, event, orgID, userIDs)
+	if err != nil {
+		return fmt.Errorf("querybyevent: event[%s]: %w", event, err)
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	p := Payload{
+		ID:         uuid.New(),
+		Event:      event,
+		OccurredAt: time.Now().UTC(),
+		Data:       data.RawParams,
+	}
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	for _, sub := range subs {
+		if _, err := b.queue(ctx, sub, p.ID, event, payload); err != nil {
+			b.log.Error(ctx, "webhookbus: actionevent", "event", event, "subscriptionID", sub.ID, "ERROR", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package webhookbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of subscription statuses.
+const (
+	StatusActive = "active"
+	StatusPaused = "paused"
+	StatusFailed = "failed"
+)
+
+// Set of delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Subscription represents a URL that is sent the events a user asked for.
+// Subscriptions for all users receive events about every todo item and user
+// of the organization, the rest only those about items and accounts the user
+// owns or is assigned.
+type Subscription struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	URL         string
+	Secret      string
+	Events      []string
+	AllUsers    bool
+	Status      string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewSubscription contains information needed to create a new subscription.
+// A secret is generated when none is provided.
+type NewSubscription struct {
+	UserID   uuid.UUID
+	URL      string
+	Secret   string
+	Events   []string
+	AllUsers bool
+}
+
+// UpdateSubscription contains information needed to update a subscription.
+// Activating a subscription that failed starts sending it events again.
+type UpdateSubscription struct {
+	URL    *string
+	Secret *string
+	Events []string
+	Active *bool
+}
+
+// Delivery represents one event being sent to one subscription, along with
+// the outcome of the last attempt to send it.
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	Event          string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	ResponseCode   int
+	Error          string
+	NextAttempt    time.Time
+	DateCreated    time.Time
+	DateUpdated    time.Time
+}
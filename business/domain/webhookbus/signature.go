@@ -0,0 +1,66 @@
+package webhookbus
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Set of headers sent with every delivery.
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix names the scheme so it can change without breaking
+// receivers that check it.
+const signaturePrefix = "v1="
+
+// Set of errors returned when a signature does not check out.
+var (
+	ErrSignatureInvalid = errors.New("signature invalid")
+	ErrSignatureExpired = errors.New("signature timestamp outside of tolerance")
+)
+
+// Sign returns the signature header for a body sent at the timestamp. The
+// timestamp is part of what is signed, so a captured delivery cannot be sent
+// again later with a fresh timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery the way a
+// receiver should. Deliveries signed longer than the tolerance ago are
+// rejected to stop them being replayed.
+func Verify(secret string, signature string, timestamp string, body []byte, tolerance time.Duration) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	ts := time.Unix(unix, 0)
+
+	if d := time.Since(ts); d > tolerance || d < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrSignatureInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package webhookbus_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/business/domain/webhookbus"
+)
+
+func Test_Signature(t *testing.T) {
+	t.Parallel()
+
+	const secret = "s3cr3t"
+	body := []byte(`{"event":"todo.created"}`)
+
+	now := time.Now()
+	stale := now.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signed    time.Time
+		timestamp time.Time
+		body      []byte
+		exp       error
+	}{
+		{name: "valid", secret: secret, signed: now, timestamp: now, body: body},
+		{name: "secret", secret: "other", signed: now, timestamp: now, body: body, exp: webhookbus.ErrSignatureInvalid},
+		{name: "body", secret: secret, signed: now, timestamp: now, body: []byte(`{}`), exp: webhookbus.ErrSignatureInvalid},
+		{name: "replayed", secret: secret, signed: stale, timestamp: now, body: body, exp: webhookbus.ErrSignatureInvalid},
+		{name: "expired", secret: secret, signed: stale, timestamp: stale, body: body, exp: webhookbus.ErrSignatureExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := webhookbus.Sign(tt.secret, tt.signed, tt.body)
+
+			err := webhookbus.Verify(secret, sig, strconv.FormatInt(tt.timestamp.Unix(), 10), body, 5*time.Minute)
+			if !errors.Is(err, tt.exp) {
+				t.Errorf("Should get the expected error: got %v, exp %v", err, tt.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package webhookdb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/sdk/sqldb/dbarray"
+)
+
+type subscription struct {
+	ID          uuid.UUID      `db:"subscription_id"`
+	OrgID       uuid.UUID      `db:"org_id"`
+	UserID      uuid.UUID      `db:"user_id"`
+	URL         string         `db:"url"`
+	Secret      string         `db:"secret"`
+	Events      dbarray.String `db:"events"`
+	AllUsers    bool           `db:"all_users"`
+	Status      string         `db:"status"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBSubscription(bus webhookbus.Subscription) subscription {
+	return subscription{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		UserID:      bus.UserID,
+		URL:         bus.URL,
+		Secret:      bus.Secret,
+		Events:      bus.Events,
+		AllUsers:    bus.AllUsers,
+		Status:      bus.Status,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusSubscription(db subscription) webhookbus.Subscription {
+	return webhookbus.Subscription{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		URL:         db.URL,
+		Secret:      db.Secret,
+		Events:      db.Events,
+		AllUsers:    db.AllUsers,
+		Status:      db.Status,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
+
+func toBusSubscriptions(dbs []subscription) []webhookbus.Subscription {
+	bus := make([]webhookbus.Subscription, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusSubscription(db)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type delivery struct {
+	ID             uuid.UUID `db:"delivery_id"`
+	SubscriptionID uuid.UUID `db:"subscription_id"`
+	EventID        uuid.UUID `db:"event_id"`
+	Event          string    `db:"event"`
+	Payload        []byte    `db:"payload"`
+	Status         string    `db:"status"`
+	Attempts       int       `db:"attempts"`
+	ResponseCode   int       `db:"response_code"`
+	Error          string    `db:"error"`
+	NextAttempt    time.Time `db:"next_attempt"`
+	DateCreated    time.Time `db:"date_created"`
+	DateUpdated    time.Time `db:"date_updated"`
+}
+
+func toDBDelivery(bus webhookbus.Delivery) delivery {
+	return delivery{
+		ID:             bus.ID,
+		SubscriptionID: bus.SubscriptionID,
+		EventID:        bus.EventID,
+		Event:          bus.Event,
+		Payload:        bus.Payload,
+		Status:         bus.Status,
+		Attempts:       bus.Attempts,
+		ResponseCode:   bus.ResponseCode,
+		Error:          bus.Error,
+		NextAttempt:    bus.NextAttempt.UTC(),
+		DateCreated:    bus.DateCreated.UTC(),
+		DateUpdated:    bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusDelivery(db delivery) webhookbus.Delivery {
+	return webhookbus.Delivery{
+		ID:             db.ID,
+		SubscriptionID: db.SubscriptionID,
+		EventID:        db.EventID,
+		Event:          db.Event,
+		Payload:        db.Payload,
+		Status:         db.Status,
+		Attempts:       db.Attempts,
+		ResponseCode:   db.ResponseCode,
+		Error:          db.Error,
+		NextAttempt:    db.NextAttempt.In(time.Local),
+		DateCreated:    db.DateCreated.In(time.Local),
+		DateUpdated:    db.DateUpdated.In(time.Local),
+	}
+}
+
+func toBusDeliveries(dbs []delivery) []webhookbus.Delivery {
+	bus := make([]webhookbus.Delivery, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusDelivery(db)
+	}
+
+	return bus
+}
@@ -0,0 +1,412 @@
+// Package webhookdb contains webhook subscription and delivery related CRUD
+// functionality.
+package webhookdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/sqldb/dbarray"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// orgScope limits a query to the subscriptions of the organization in
+// :org_id. Every subscription passes when it is null, which is how work that
+// spans organizations reads.
+const orgScope = "(CAST(:org_id AS UUID) IS NULL OR org_id = CAST(:org_id AS UUID))"
+
+// Store manages the set of APIs for webhook database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (webhookbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new subscription into the database.
+func (s *Store) Create(ctx context.Context, sub webhookbus.Subscription) error {
+	const q = `
+	INSERT INTO webhook_subscriptions
+		(subscription_id, org_id, user_id, url, secret, events, all_users, status, date_created, date_updated)
+	VALUES
+		(:subscription_id, :org_id, :user_id, :url, :secret, :events, :all_users, :status, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSubscription(sub)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a subscription document in the database.
+func (s *Store) Update(ctx context.Context, sub webhookbus.Subscription) error {
+	const q = `
+	UPDATE
+		webhook_subscriptions
+	SET
+		"url" = :url,
+		"secret" = :secret,
+		"events" = :events,
+		"all_users" = :all_users,
+		"status" = :status,
+		"date_updated" = :date_updated
+	WHERE
+		subscription_id = :subscription_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSubscription(sub)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a subscription and its deliveries from the database.
+func (s *Store) Delete(ctx context.Context, sub webhookbus.Subscription) error {
+	const q = `
+	DELETE FROM
+		webhook_subscriptions
+	WHERE
+		subscription_id = :subscription_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSubscription(sub)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified subscription from the database.
+func (s *Store) QueryByID(ctx context.Context, subscriptionID uuid.UUID) (webhookbus.Subscription, error) {
+	data := struct {
+		ID    string        `db:"subscription_id"`
+		OrgID uuid.NullUUID `db:"org_id"`
+	}{
+		ID:    subscriptionID.String(),
+		OrgID: sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		subscription_id, org_id, user_id, url, secret, events, all_users, status, date_created, date_updated
+	FROM
+		webhook_subscriptions
+	WHERE
+		subscription_id = :subscription_id AND ` + orgScope
+
+	var dbSub subscription
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbSub); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return webhookbus.Subscription{}, fmt.Errorf("db: %w", webhookbus.ErrNotFound)
+		}
+		return webhookbus.Subscription{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusSubscription(dbSub), nil
+}
+
+// QueryByUserID gets the subscriptions of the specified user.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]webhookbus.Subscription, error) {
+	data := struct {
+		UserID string        `db:"user_id"`
+		OrgID  uuid.NullUUID `db:"org_id"`
+	}{
+		UserID: userID.String(),
+		OrgID:  sqldb.OrgArg(ctx),
+	}
+
+	const q = `
+	SELECT
+		subscription_id, org_id, user_id, url, secret, events, all_users, status, date_created, date_updated
+	FROM
+		webhook_subscriptions
+	WHERE
+		user_id = :user_id AND ` + orgScope + `
+	ORDER BY
+		date_created`
+
+	var dbSubs []subscription
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbSubs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusSubscriptions(dbSubs), nil
+}
+
+// QueryByEvent gets the active subscriptions that asked for the event and
+// either belong to one of the users or are for all users, within the
+// organization the event belongs to. Without one, as for signups, they go to
+// the subscriptions of the organizations the users are members of.
+func (s *Store) QueryByEvent(ctx context.Context, event string, orgID uuid.UUID, userIDs []uuid.UUID) ([]webhookbus.Subscription, error) {
+	ids := make(dbarray.String, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = userID.String()
+	}
+
+	data := struct {
+		Event   string         `db:"event"`
+		Status  string         `db:"status"`
+		OrgID   uuid.NullUUID  `db:"org_id"`
+		UserIDs dbarray.String `db:"user_ids"`
+	}{
+		Event:   event,
+		Status:  webhookbus.StatusActive,
+		OrgID:   uuid.NullUUID{UUID: orgID, Valid: orgID != uuid.Nil},
+		UserIDs: ids,
+	}
+
+	const q = `
+	SELECT
+		subscription_id, org_id, user_id, url, secret, events, all_users, status, date_created, date_updated
+	FROM
+		webhook_subscriptions
+	WHERE
+		status = :status AND
+		:event = ANY(events) AND
+		(all_users OR user_id = ANY(CAST(:user_ids AS UUID[]))) AND
+		(
+			org_id = CAST(:org_id AS UUID) OR
+			(CAST(:org_id AS UUID) IS NULL AND org_id IN (
+				SELECT org_id FROM org_members WHERE user_id = ANY(CAST(:user_ids AS UUID[]))
+			))
+		)`
+
+	var dbSubs []subscription
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbSubs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusSubscriptions(dbSubs), nil
+}
+
+// =============================================================================
+
+// CreateDelivery inserts a new delivery into the database.
+func (s *Store) CreateDelivery(ctx context.Context, dlv webhookbus.Delivery) error {
+	const q = `
+	INSERT INTO webhook_deliveries
+		(delivery_id, subscription_id, event_id, event, payload, status, attempts, response_code, error, next_attempt, date_created, date_updated)
+	VALUES
+		(:delivery_id, :subscription_id, :event_id, :event, :payload, :status, :attempts, :response_code, :error, :next_attempt, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDelivery(dlv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDelivery records the outcome of an attempt at a delivery.
+func (s *Store) UpdateDelivery(ctx context.Context, dlv webhookbus.Delivery) error {
+	const q = `
+	UPDATE
+		webhook_deliveries
+	SET
+		"status" = :status,
+		"attempts" = :attempts,
+		"response_code" = :response_code,
+		"error" = :error,
+		"next_attempt" = :next_attempt,
+		"date_updated" = :date_updated
+	WHERE
+		delivery_id = :delivery_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBDelivery(dlv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryDeliveryByID gets the specified delivery from the database.
+func (s *Store) QueryDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (webhookbus.Delivery, error) {
+	data := struct {
+		ID string `db:"delivery_id"`
+	}{
+		ID: deliveryID.String(),
+	}
+
+	const q = `
+	SELECT
+		delivery_id, subscription_id, event_id, event, payload, status, attempts, response_code, error, next_attempt, date_created, date_updated
+	FROM
+		webhook_deliveries
+	WHERE
+		delivery_id = :delivery_id`
+
+	var dbDlv delivery
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbDlv); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return webhookbus.Delivery{}, fmt.Errorf("db: %w", webhookbus.ErrDeliveryNotFound)
+		}
+		return webhookbus.Delivery{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusDelivery(dbDlv), nil
+}
+
+// QueryDeliveries gets a page of the deliveries of a subscription, newest
+// first.
+func (s *Store) QueryDeliveries(ctx context.Context, subscriptionID uuid.UUID, page page.Page) ([]webhookbus.Delivery, error) {
+	data := map[string]any{
+		"subscription_id": subscriptionID.String(),
+		"offset":          (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page":   page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		delivery_id, subscription_id, event_id, event, payload, status, attempts, response_code, error, next_attempt, date_created, date_updated
+	FROM
+		webhook_deliveries
+	WHERE
+		subscription_id = :subscription_id
+	ORDER BY
+		date_created DESC
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbDlvs []delivery
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbDlvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDeliveries(dbDlvs), nil
+}
+
+// CountDeliveries returns the total number of deliveries of a subscription.
+func (s *Store) CountDeliveries(ctx context.Context, subscriptionID uuid.UUID) (int, error) {
+	data := struct {
+		SubscriptionID string `db:"subscription_id"`
+	}{
+		SubscriptionID: subscriptionID.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		webhook_deliveries
+	WHERE
+		subscription_id = :subscription_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// ClaimDelivery takes the delivery for an attempt when it is due, hiding it
+// from others until the specified time.
+func (s *Store) ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, now time.Time, until time.Time) (webhookbus.Delivery, error) {
+	data := struct {
+		ID     string    `db:"delivery_id"`
+		Status string    `db:"status"`
+		Now    time.Time `db:"now"`
+		Until  time.Time `db:"until"`
+	}{
+		ID:     deliveryID.String(),
+		Status: webhookbus.DeliveryPending,
+		Now:    now.UTC(),
+		Until:  until.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		webhook_deliveries
+	SET
+		next_attempt = :until
+	WHERE
+		delivery_id = :delivery_id AND
+		status = :status AND
+		next_attempt <= :now
+	RETURNING
+		delivery_id, subscription_id, event_id, event, payload, status, attempts, response_code, error, next_attempt, date_created, date_updated`
+
+	var dbDlv delivery
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbDlv); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return webhookbus.Delivery{}, fmt.Errorf("db: %w", webhookbus.ErrNotDue)
+		}
+		return webhookbus.Delivery{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusDelivery(dbDlv), nil
+}
+
+// ClaimDueDeliveries takes up to limit deliveries that are due for an
+// attempt, oldest first, hiding them from others until the specified time.
+// Deliveries another caller is claiming at the same moment are skipped.
+func (s *Store) ClaimDueDeliveries(ctx context.Context, now time.Time, until time.Time, limit int) ([]webhookbus.Delivery, error) {
+	data := struct {
+		Status string    `db:"status"`
+		Now    time.Time `db:"now"`
+		Until  time.Time `db:"until"`
+		Limit  int       `db:"limit"`
+	}{
+		Status: webhookbus.DeliveryPending,
+		Now:    now.UTC(),
+		Until:  until.UTC(),
+		Limit:  limit,
+	}
+
+	const q = `
+	UPDATE
+		webhook_deliveries
+	SET
+		next_attempt = :until
+	WHERE
+		delivery_id IN (
+			SELECT
+				delivery_id
+			FROM
+				webhook_deliveries
+			WHERE
+				status = :status AND
+				next_attempt <= :now
+			ORDER BY
+				next_attempt
+			LIMIT :limit
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		delivery_id, subscription_id, event_id, event, payload, status, attempts, response_code, error, next_attempt, date_created, date_updated`
+
+	var dbDlvs []delivery
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbDlvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusDeliveries(dbDlvs), nil
+}
@@ -0,0 +1,365 @@
+// Package webhookbus provides business access to webhook subscriptions and
+// the delivery of events to them.
+package webhookbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/himynamej/todo/foundation/otel"
+	"github.com/himynamej/todo/foundation/worker"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound         = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrUnknownEvent     = errors.New("unknown event")
+	ErrInvalidURL       = errors.New("url must be an absolute http or https url")
+	ErrPrivateAddress   = errors.New("address is not publicly routable")
+	ErrInactive         = errors.New("webhook subscription is not active")
+	ErrNotDue           = errors.New("webhook delivery is not due")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, sub Subscription) error
+	Update(ctx context.Context, sub Subscription) error
+	Delete(ctx context.Context, sub Subscription) error
+	QueryByID(ctx context.Context, subscriptionID uuid.UUID) (Subscription, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Subscription, error)
+	QueryByEvent(ctx context.Context, event string, orgID uuid.UUID, userIDs []uuid.UUID) ([]Subscription, error)
+	CreateDelivery(ctx context.Context, dlv Delivery) error
+	UpdateDelivery(ctx context.Context, dlv Delivery) error
+	QueryDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (Delivery, error)
+	QueryDeliveries(ctx context.Context, subscriptionID uuid.UUID, page page.Page) ([]Delivery, error)
+	CountDeliveries(ctx context.Context, subscriptionID uuid.UUID) (int, error)
+	ClaimDelivery(ctx context.Context, deliveryID uuid.UUID, now time.Time, until time.Time) (Delivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, until time.Time, limit int) ([]Delivery, error)
+}
+
+// Config controls how deliveries are sent and retried. Zero values are
+// replaced with the defaults.
+type Config struct {
+	Client       *http.Client  // Default: NewClient with a 10 second timeout
+	MaxAttempts  int           // Default: 8
+	Backoff      time.Duration // Delay before the first retry, doubled after each. Default: 30s
+	MaxBackoff   time.Duration // Default: 6h
+	AllowPrivate bool          // Accept urls on private addresses, only for tests and local development
+}
+
+// Business manages the set of APIs for webhook access.
+type Business struct {
+	log      *logger.Logger
+	storer   Storer
+	delegate *delegate.Delegate
+	worker   *worker.Worker
+	cfg      Config
+}
+
+// NewBusiness constructs a webhook business API for use. With a delegate,
+// every todo and user event is offered to the subscriptions that want it.
+// With a worker, new deliveries are sent straight away; otherwise, and for
+// every retry, they wait for DeliverDue to pick them up.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, worker *worker.Worker, cfg Config) *Business {
+	switch {
+	case cfg.Client != nil:
+	case cfg.AllowPrivate:
+		cfg.Client = &http.Client{Timeout: 10 * time.Second, CheckRedirect: noRedirect}
+	default:
+		cfg.Client = NewClient(10 * time.Second)
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 6 * time.Hour
+	}
+
+	b := Business{
+		log:      log,
+		storer:   storer,
+		delegate: delegate,
+		worker:   worker,
+		cfg:      cfg,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		storer:   storer,
+		delegate: b.delegate,
+		worker:   b.worker,
+		cfg:      b.cfg,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new subscription to the system.
+func (b *Business) Create(ctx context.Context, ns NewSubscription) (Subscription, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.create")
+	defer span.End()
+
+	if err := b.checkURL(ns.URL); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := checkEvents(ns.Events); err != nil {
+		return Subscription{}, err
+	}
+
+	secret := ns.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return Subscription{}, fmt.Errorf("newsecret: %w", err)
+		}
+	}
+
+	now := time.Now()
+
+	sub := Subscription{
+		ID:          uuid.New(),
+		OrgID:       orgOf(ctx),
+		UserID:      ns.UserID,
+		URL:         ns.URL,
+		Secret:      secret,
+		Events:      ns.Events,
+		AllUsers:    ns.AllUsers,
+		Status:      StatusActive,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, sub); err != nil {
+		return Subscription{}, fmt.Errorf("create: %w", err)
+	}
+
+	return sub, nil
+}
+
+// Update modifies information about a subscription.
+func (b *Business) Update(ctx context.Context, sub Subscription, us UpdateSubscription) (Subscription, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.update")
+	defer span.End()
+
+	if us.URL != nil {
+		if err := b.checkURL(*us.URL); err != nil {
+			return Subscription{}, err
+		}
+		sub.URL = *us.URL
+	}
+
+	if us.Secret != nil {
+		sub.Secret = *us.Secret
+	}
+
+	if us.Events != nil {
+		if err := checkEvents(us.Events); err != nil {
+			return Subscription{}, err
+		}
+		sub.Events = us.Events
+	}
+
+	if us.Active != nil {
+		sub.Status = StatusPaused
+		if *us.Active {
+			sub.Status = StatusActive
+		}
+	}
+
+	sub.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, sub); err != nil {
+		return Subscription{}, fmt.Errorf("update: %w", err)
+	}
+
+	return sub, nil
+}
+
+// Delete removes the specified subscription along with its deliveries.
+func (b *Business) Delete(ctx context.Context, sub Subscription) error {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.delete")
+	defer span.End()
+
+	if err := b.storer.Delete(ctx, sub); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the subscription by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, subscriptionID uuid.UUID) (Subscription, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.querybyid")
+	defer span.End()
+
+	sub, err := b.storer.QueryByID(ctx, subscriptionID)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("query: subscriptionID[%s]: %w", subscriptionID, err)
+	}
+
+	return sub, nil
+}
+
+// QueryByUserID retrieves the subscriptions of the specified user.
+func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Subscription, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.querybyuserid")
+	defer span.End()
+
+	subs, err := b.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return subs, nil
+}
+
+// QueryDeliveries retrieves the delivery log of a subscription, newest first.
+func (b *Business) QueryDeliveries(ctx context.Context, subscriptionID uuid.UUID, page page.Page) ([]Delivery, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.querydeliveries")
+	defer span.End()
+
+	dlvs, err := b.storer.QueryDeliveries(ctx, subscriptionID, page)
+	if err != nil {
+		return nil, fmt.Errorf("querydeliveries: subscriptionID[%s]: %w", subscriptionID, err)
+	}
+
+	return dlvs, nil
+}
+
+// CountDeliveries returns the total number of deliveries of a subscription.
+func (b *Business) CountDeliveries(ctx context.Context, subscriptionID uuid.UUID) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.countdeliveries")
+	defer span.End()
+
+	return b.storer.CountDeliveries(ctx, subscriptionID)
+}
+
+// QueryDeliveryByID finds the delivery by the specified ID.
+func (b *Business) QueryDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (Delivery, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.querydeliverybyid")
+	defer span.End()
+
+	dlv, err := b.storer.QueryDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("querydelivery: deliveryID[%s]: %w", deliveryID, err)
+	}
+
+	return dlv, nil
+}
+
+// Redeliver sends the event of an earlier delivery to the subscription
+// again. The earlier delivery is kept in the log as it was and the new one
+// starts over with a full set of attempts.
+func (b *Business) Redeliver(ctx context.Context, sub Subscription, dlv Delivery) (Delivery, error) {
+	ctx, span := otel.AddSpan(ctx, "business.webhookbus.redeliver")
+	defer span.End()
+
+	if dlv.SubscriptionID != sub.ID {
+		return Delivery{}, fmt.Errorf("redeliver: deliveryID[%s]: %w", dlv.ID, ErrDeliveryNotFound)
+	}
+
+	if sub.Status != StatusActive {
+		return Delivery{}, fmt.Errorf("redeliver: subscriptionID[%s]: %w", sub.ID, ErrInactive)
+	}
+
+	redlv, err := b.queue(ctx, sub, dlv.EventID, dlv.Event, dlv.Payload)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("queue: %w", err)
+	}
+
+	return redlv, nil
+}
+
+// =============================================================================
+
+// checkURL refuses urls that are not http or https, and urls that name a
+// private address outright. Names are checked again as deliveries connect.
+func (b *Business) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	if b.cfg.AllowPrivate {
+		return nil
+	}
+
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("host[%s]: %w", host, ErrPrivateAddress)
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublic(addr) {
+		return fmt.Errorf("host[%s]: %w", host, ErrPrivateAddress)
+	}
+
+	return nil
+}
+
+func checkEvents(events []string) error {
+	if len(events) == 0 {
+		return fmt.Errorf("no events: %w", ErrUnknownEvent)
+	}
+
+	for _, event := range events {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("event[%s]: %w", event, ErrUnknownEvent)
+		}
+	}
+
+	return nil
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// orgOf returns the organization new subscriptions go into, which is the one
+// access is scoped to or the default one for work that isn't.
+func orgOf(ctx context.Context) uuid.UUID {
+	if orgID, ok := sqldb.GetOrgID(ctx); ok {
+		return orgID
+	}
+
+	return orgbus.DefaultID
+}
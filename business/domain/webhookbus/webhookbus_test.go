@@ -0,0 +1,396 @@
+package webhookbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/domain/todobus"
+	"github.com/himynamej/todo/business/domain/userbus"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/sdk/dbtest"
+	"github.com/himynamej/todo/business/sdk/page"
+	"github.com/himynamej/todo/business/sdk/unitest"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Webhook(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Webhook")
+
+	rcv := newReceiver()
+	defer rcv.Close()
+
+	sd, err := insertSeedData(db.BusDomain, rcv)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, deliver(db.BusDomain, sd, rcv), "deliver")
+	unitest.Run(t, retry(db.BusDomain, sd, rcv), "retry")
+	unitest.Run(t, redeliver(db.BusDomain, sd, rcv), "redeliver")
+}
+
+// =============================================================================
+
+// receiver stands in for the service a subscription points at. It checks the
+// signature of everything it receives and answers with the status it is told
+// to.
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	secret   string
+	status   int
+	payloads []webhookbus.Payload
+}
+
+func newReceiver() *receiver {
+	rcv := receiver{
+		status: http.StatusOK,
+	}
+
+	rcv.Server = httptest.NewServer(http.HandlerFunc(rcv.handle))
+
+	return &rcv
+}
+
+func (rcv *receiver) handle(w http.ResponseWriter, r *http.Request) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := webhookbus.Verify(rcv.secret, r.Header.Get(webhookbus.HeaderSignature), r.Header.Get(webhookbus.HeaderTimestamp), body, time.Minute); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var p webhookbus.Payload
+	if err := json.Unmarshal(body, &p); err != nil || p.Event != r.Header.Get(webhookbus.HeaderEvent) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if rcv.status == http.StatusOK {
+		rcv.payloads = append(rcv.payloads, p)
+	}
+
+	w.WriteHeader(rcv.status)
+}
+
+func (rcv *receiver) respond(status int) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	rcv.status = status
+}
+
+func (rcv *receiver) received() []string {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	events := make([]string, len(rcv.payloads))
+	for i, p := range rcv.payloads {
+		events[i] = p.Event
+	}
+	rcv.payloads = nil
+
+	return events
+}
+
+// =============================================================================
+
+type seedData struct {
+	unitest.SeedData
+	Subscription webhookbus.Subscription
+}
+
+func insertSeedData(busDomain dbtest.BusDomain, rcv *receiver) (seedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	ns := webhookbus.NewSubscription{
+		UserID: usrs[0].ID,
+		URL:    rcv.URL,
+		Events: []string{webhookbus.EventTodoCreated, webhookbus.EventTodoDeleted},
+	}
+
+	sub, err := busDomain.Webhook.Create(ctx, ns)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding subscription : %w", err)
+	}
+	rcv.secret = sub.Secret
+
+	sd := seedData{
+		SeedData: unitest.SeedData{
+			Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+		},
+		Subscription: sub,
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func deliver(busDomain dbtest.BusDomain, sd seedData, rcv *receiver) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "owned",
+			ExpResp: []string{webhookbus.EventTodoCreated},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[0].ID, busDomain.Todo); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+					return err
+				}
+
+				return rcv.received()
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name:    "other",
+			ExpResp: []string{},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[1].ID, busDomain.Todo); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+					return err
+				}
+
+				return rcv.received()
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name:    "unsubscribed",
+			ExpResp: []string{},
+			ExcFunc: func(ctx context.Context) any {
+				items, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[0].ID, busDomain.Todo)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+					return err
+				}
+				rcv.received()
+
+				desc := "Not subscribed to"
+				if _, err := busDomain.Todo.Update(ctx, items[0], todobus.UpdateTodoItem{Description: &desc}); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+					return err
+				}
+
+				return rcv.received()
+			},
+			CmpFunc: cmpEvents,
+		},
+	}
+
+	return table
+}
+
+func retry(busDomain dbtest.BusDomain, sd seedData, rcv *receiver) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "failed",
+			ExpResp: delivered{
+				Status:       webhookbus.DeliveryFailed,
+				Attempts:     dbtest.TestWebhookConfig.MaxAttempts,
+				ResponseCode: http.StatusInternalServerError,
+				Subscription: webhookbus.StatusFailed,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				rcv.respond(http.StatusInternalServerError)
+				defer rcv.respond(http.StatusOK)
+
+				if _, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[0].ID, busDomain.Todo); err != nil {
+					return err
+				}
+
+				for range dbtest.TestWebhookConfig.MaxAttempts {
+					if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+						return err
+					}
+					time.Sleep(5 * time.Millisecond)
+				}
+
+				return latest(ctx, busDomain, sd)
+			},
+			CmpFunc: cmpDelivered,
+		},
+		{
+			Name:    "inactive",
+			ExpResp: []string{},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := todobus.TestSeedTodoItems(ctx, 1, sd.Users[0].ID, busDomain.Todo); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+					return err
+				}
+
+				return rcv.received()
+			},
+			CmpFunc: cmpEvents,
+		},
+	}
+
+	return table
+}
+
+func redeliver(busDomain dbtest.BusDomain, sd seedData, rcv *receiver) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "inactive",
+			ExpResp: webhookbus.ErrInactive,
+			ExcFunc: func(ctx context.Context) any {
+				sub, err := busDomain.Webhook.QueryByID(ctx, sd.Subscription.ID)
+				if err != nil {
+					return err
+				}
+
+				dlvs, err := busDomain.Webhook.QueryDeliveries(ctx, sub.ID, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Webhook.Redeliver(ctx, sub, dlvs[0])
+				return err
+			},
+			CmpFunc: cmpErr,
+		},
+		{
+			Name: "active",
+			ExpResp: delivered{
+				Status:       webhookbus.DeliveryDelivered,
+				Attempts:     1,
+				ResponseCode: http.StatusOK,
+				Subscription: webhookbus.StatusActive,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				sub, err := busDomain.Webhook.QueryByID(ctx, sd.Subscription.ID)
+				if err != nil {
+					return err
+				}
+
+				active := true
+				if sub, err = busDomain.Webhook.Update(ctx, sub, webhookbus.UpdateSubscription{Active: &active}); err != nil {
+					return err
+				}
+
+				dlvs, err := busDomain.Webhook.QueryDeliveries(ctx, sub.ID, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.Redeliver(ctx, sub, dlvs[0]); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Webhook.DeliverDue(ctx); err != nil {
+					return err
+				}
+
+				if events := rcv.received(); len(events) != 1 {
+					return fmt.Errorf("expected the event to be received once, got %v", events)
+				}
+
+				return latest(ctx, busDomain, sd)
+			},
+			CmpFunc: cmpDelivered,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+// delivered is the outcome of the latest delivery to the subscription.
+type delivered struct {
+	Status       string
+	Attempts     int
+	ResponseCode int
+	Subscription string
+}
+
+func latest(ctx context.Context, busDomain dbtest.BusDomain, sd seedData) any {
+	dlvs, err := busDomain.Webhook.QueryDeliveries(ctx, sd.Subscription.ID, page.MustParse("1", "1"))
+	if err != nil {
+		return err
+	}
+
+	sub, err := busDomain.Webhook.QueryByID(ctx, sd.Subscription.ID)
+	if err != nil {
+		return err
+	}
+
+	return delivered{
+		Status:       dlvs[0].Status,
+		Attempts:     dlvs[0].Attempts,
+		ResponseCode: dlvs[0].ResponseCode,
+		Subscription: sub.Status,
+	}
+}
+
+func cmpEvents(got any, exp any) string {
+	gotResp, exists := got.([]string)
+	if !exists {
+		return fmt.Sprintf("error occurred: %v", got)
+	}
+
+	return cmp.Diff(gotResp, exp)
+}
+
+func cmpDelivered(got any, exp any) string {
+	gotResp, exists := got.(delivered)
+	if !exists {
+		return fmt.Sprintf("error occurred: %v", got)
+	}
+
+	return cmp.Diff(gotResp, exp)
+}
+
+func cmpErr(got any, exp any) string {
+	gotErr, ok := got.(error)
+	if !ok {
+		return fmt.Sprintf("expected an error, got %v", got)
+	}
+
+	if !errors.Is(gotErr, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", gotErr, exp)
+	}
+
+	return ""
+}
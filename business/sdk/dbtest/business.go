@@ -1,25 +1,102 @@
 package dbtest
 
 import (
+	"context"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/himynamej/todo/business/domain/auditbus"
+	"github.com/himynamej/todo/business/domain/auditbus/stores/auditdb"
+	"github.com/himynamej/todo/business/domain/commentbus"
+	"github.com/himynamej/todo/business/domain/commentbus/stores/commentdb"
+	"github.com/himynamej/todo/business/domain/idempotencybus"
+	"github.com/himynamej/todo/business/domain/idempotencybus/stores/idempotencydb"
+	"github.com/himynamej/todo/business/domain/orgbus"
+	"github.com/himynamej/todo/business/domain/orgbus/stores/orgdb"
+	"github.com/himynamej/todo/business/domain/passwordbus"
+	"github.com/himynamej/todo/business/domain/passwordbus/stores/passworddb"
+	"github.com/himynamej/todo/business/domain/quotabus"
+	"github.com/himynamej/todo/business/domain/quotabus/stores/quotadb"
+	"github.com/himynamej/todo/business/domain/reportingbus"
+	"github.com/himynamej/todo/business/domain/reportingbus/stores/reportingdb"
+	"github.com/himynamej/todo/business/domain/sessionbus"
+	"github.com/himynamej/todo/business/domain/sessionbus/stores/sessiondb"
+	"github.com/himynamej/todo/business/domain/signupbus"
+	"github.com/himynamej/todo/business/domain/signupbus/stores/signupdb"
+	"github.com/himynamej/todo/business/domain/streambus"
+	"github.com/himynamej/todo/business/domain/streambus/stores/streamdb"
+	"github.com/himynamej/todo/business/domain/templatebus"
+	"github.com/himynamej/todo/business/domain/templatebus/stores/templatedb"
+	"github.com/himynamej/todo/business/domain/timebus"
+	"github.com/himynamej/todo/business/domain/timebus/stores/timedb"
 	"github.com/himynamej/todo/business/domain/todobus"
 	"github.com/himynamej/todo/business/domain/todobus/mocks"
 	"github.com/himynamej/todo/business/domain/todobus/stores/itemdb"
 	"github.com/himynamej/todo/business/domain/userbus"
 	"github.com/himynamej/todo/business/domain/userbus/stores/usercache"
 	"github.com/himynamej/todo/business/domain/userbus/stores/userdb"
+	"github.com/himynamej/todo/business/domain/webhookbus"
+	"github.com/himynamej/todo/business/domain/webhookbus/stores/webhookdb"
 	"github.com/himynamej/todo/business/sdk/delegate"
+	"github.com/himynamej/todo/business/sdk/mailer/outbox"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/types/role"
 	"github.com/himynamej/todo/foundation/logger"
 	"github.com/jmoiron/sqlx"
 )
 
 // BusDomain represents all the business domain APIs needed for testing.
 type BusDomain struct {
-	Delegate *delegate.Delegate
-	User     *userbus.Business
-	Todo     *todobus.Business
+	Delegate    *delegate.Delegate
+	User        *userbus.Business
+	Todo        *todobus.Business
+	Quota       *quotabus.Business
+	Comment     *commentbus.Business
+	Reporting   *reportingbus.Business
+	Idempotency *idempotencybus.Business
+	Webhook     *webhookbus.Business
+	Stream      *streambus.Business
+	Template    *templatebus.Business
+	Time        *timebus.Business
+	Org         *orgbus.Business
+	Signup      *signupbus.Business
+	Audit       *auditbus.Business
+	Password    *passwordbus.Business
+	Session     *sessionbus.Business
+	Mail        *outbox.Mailer // Holds the email the business domains sent
+}
+
+// TestWebhookConfig retries failed deliveries straight away and gives up
+// after a few attempts so tests do not have to wait. Private addresses are
+// allowed since the test receivers listen on loopback.
+var TestWebhookConfig = webhookbus.Config{
+	MaxAttempts:  3,
+	Backoff:      time.Millisecond,
+	MaxBackoff:   time.Millisecond,
+	AllowPrivate: true,
+}
+
+// TestSignupConfig is how the test business domains verify signups.
+var TestSignupConfig = signupbus.Config{
+	Key:       []byte("test-signup-key"),
+	TTL:       time.Hour,
+	VerifyURL: "http://localhost:3000/v1/signup/verify",
+}
+
+// TestPasswordConfig is how the test business domains send reset links.
+var TestPasswordConfig = passwordbus.Config{
+	TTL:      time.Hour,
+	ResetURL: "http://localhost:3000/reset-password",
+}
+
+// TestQuotaLimits are the storage quotas the test business domains enforce.
+var TestQuotaLimits = quotabus.Limits{
+	Roles: map[role.Role]int64{
+		role.User:  1 << 20,
+		role.Admin: 10 << 20,
+	},
+	Department: 5 << 20,
 }
 
 func newBusDomains(log *logger.Logger, db *sqlx.DB, ctrl *gomock.Controller) BusDomain {
@@ -33,18 +110,52 @@ func newBusDomains(log *logger.Logger, db *sqlx.DB, ctrl *gomock.Controller) Bus
 
 	mockS3Client.EXPECT().
 		Upload(gomock.Any(), gomock.Any(), gomock.Any()).
-		Return("mock-file-id", nil).AnyTimes() // Adjust as necessary
+		DoAndReturn(func(_ context.Context, fileName string, _ []byte) (string, error) {
+			return fileName, nil
+		}).AnyTimes() // Like S3, the file ID is the key.
+
+	mockS3Client.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
 
 	mockSQSClient.EXPECT().
 		SendMessage(gomock.Any(), gomock.Any()).
 		Return(nil).AnyTimes() // You can adjust the return value and times as needed.
 	// Construct the Todo business logic
 
-	todoBus := todobus.NewBusiness(log, todostore, mockSQSClient, mockS3Client)
+	quotaBus := quotabus.NewBusiness(log, quotadb.NewStore(log, db), TestQuotaLimits)
+	todoBus := todobus.NewBusiness(log, delegate, userBus, quotaBus, todostore, mockSQSClient, mockS3Client, upload.TestNewPipeline(), nil, sqldb.NewBeginner(db))
+	commentBus := commentbus.NewBusiness(log, delegate, userBus, todoBus, commentdb.NewStore(log, db))
+	reportingBus := reportingbus.NewBusiness(log, reportingdb.NewStore(log, db))
+	idempotencyBus := idempotencybus.NewBusiness(log, idempotencydb.NewStore(log, db))
+	webhookBus := webhookbus.NewBusiness(log, delegate, webhookdb.NewStore(log, db), nil, TestWebhookConfig)
+	streamBus := streambus.NewBusiness(log, delegate, streamdb.NewStore(log, db), streambus.Config{})
+	templateBus := templatebus.NewBusiness(log, todoBus, templatedb.NewStore(log, db))
+	timeBus := timebus.NewBusiness(log, timedb.NewStore(log, db))
+	orgBus := orgbus.NewBusiness(log, delegate, orgdb.NewStore(log, db), sqldb.NewBeginner(db))
+	mail := outbox.New(log, db)
+	signupBus := signupbus.NewBusiness(log, userBus, mail, signupdb.NewStore(log, db), TestSignupConfig, sqldb.NewBeginner(db))
+	auditBus := auditbus.NewBusiness(log, auditdb.NewStore(log, db))
+	sessionBus := sessionbus.NewBusiness(log, delegate, sessiondb.NewStore(log, db), sessionbus.Config{})
+	passwordBus := passwordbus.NewBusiness(log, userBus, auditBus, sessionBus, mail, passworddb.NewStore(log, db), TestPasswordConfig, sqldb.NewBeginner(db))
 
 	return BusDomain{
-		Delegate: delegate,
-		User:     userBus,
-		Todo:     todoBus,
+		Delegate:    delegate,
+		User:        userBus,
+		Todo:        todoBus,
+		Quota:       quotaBus,
+		Comment:     commentBus,
+		Reporting:   reportingBus,
+		Idempotency: idempotencyBus,
+		Webhook:     webhookBus,
+		Stream:      streamBus,
+		Template:    templateBus,
+		Time:        timeBus,
+		Org:         orgBus,
+		Signup:      signupBus,
+		Audit:       auditBus,
+		Password:    passwordBus,
+		Session:     sessionBus,
+		Mail:        mail,
 	}
 }
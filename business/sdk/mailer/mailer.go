@@ -0,0 +1,20 @@
+// Package mailer defines how the service sends email, so the business
+// packages do not depend on how it is delivered.
+package mailer
+
+import (
+	"context"
+	"net/mail"
+)
+
+// Message represents a plain text email to a single recipient.
+type Message struct {
+	To      mail.Address
+	Subject string
+	Body    string
+}
+
+// Mailer sends email on behalf of the service.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,97 @@
+// Package outbox provides a mailer that writes email to the mail_outbox
+// table instead of sending it, for tests and for development without an
+// SMTP server.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/himynamej/todo/business/sdk/mailer"
+	"github.com/himynamej/todo/business/sdk/sqldb"
+	"github.com/himynamej/todo/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Mailer keeps every message it is given in the database.
+type Mailer struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// New constructs a mailer that writes to the outbox table.
+func New(log *logger.Logger, db *sqlx.DB) *Mailer {
+	return &Mailer{
+		log: log,
+		db:  db,
+	}
+}
+
+// Send writes the message to the outbox.
+func (m *Mailer) Send(ctx context.Context, msg mailer.Message) error {
+	data := dbMessage{
+		ID:          uuid.NewString(),
+		Recipient:   msg.To.Address,
+		Subject:     msg.Subject,
+		Body:        msg.Body,
+		DateCreated: time.Now().UTC(),
+	}
+
+	const q = `
+	INSERT INTO mail_outbox
+		(message_id, recipient, subject, body, date_created)
+	VALUES
+		(:message_id, :recipient, :subject, :body, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, m.log, m.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns the messages sent to the address, oldest first.
+func (m *Mailer) Query(ctx context.Context, to mail.Address) ([]mailer.Message, error) {
+	data := struct {
+		Recipient string `db:"recipient"`
+	}{
+		Recipient: to.Address,
+	}
+
+	const q = `
+	SELECT
+		message_id, recipient, subject, body, date_created
+	FROM
+		mail_outbox
+	WHERE
+		recipient = :recipient
+	ORDER BY
+		date_created, message_id`
+
+	var dbMsgs []dbMessage
+	if err := sqldb.NamedQuerySlice(ctx, m.log, m.db, q, data, &dbMsgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	msgs := make([]mailer.Message, len(dbMsgs))
+	for i, dbMsg := range dbMsgs {
+		msgs[i] = mailer.Message{
+			To:      mail.Address{Address: dbMsg.Recipient},
+			Subject: dbMsg.Subject,
+			Body:    dbMsg.Body,
+		}
+	}
+
+	return msgs, nil
+}
+
+type dbMessage struct {
+	ID          string    `db:"message_id"`
+	Recipient   string    `db:"recipient"`
+	Subject     string    `db:"subject"`
+	Body        string    `db:"body"`
+	DateCreated time.Time `db:"date_created"`
+}
@@ -0,0 +1,123 @@
+// Package smtp provides a mailer that hands email to an SMTP server, either
+// a real relay or a local test server such as MailHog or Mailpit.
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/himynamej/todo/business/sdk/mailer"
+)
+
+// Config represents the information needed to reach the SMTP server.
+type Config struct {
+	Host     string       // host:port of the server
+	From     mail.Address // Sender of every message
+	Username string       // No authentication when empty
+	Password string
+	Timeout  time.Duration
+}
+
+// Mailer sends email through an SMTP server.
+type Mailer struct {
+	cfg Config
+}
+
+// New constructs a mailer for the server in the config.
+func New(cfg Config) (*Mailer, error) {
+	if _, _, err := net.SplitHostPort(cfg.Host); err != nil {
+		return nil, fmt.Errorf("host: %w", err)
+	}
+
+	return &Mailer{cfg: cfg}, nil
+}
+
+// Send delivers the message to the server. The connection is upgraded to TLS
+// when the server offers it, and authentication is only attempted over TLS
+// or to a server on the same host.
+func (m *Mailer) Send(ctx context.Context, msg mailer.Message) error {
+	if m.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.Timeout)
+		defer cancel()
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.cfg.Host)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(m.cfg.Host)
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(m.cfg.From.Address); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+
+	if err := c.Rcpt(msg.To.Address); err != nil {
+		return fmt.Errorf("rcpt: %w", err)
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+
+	if _, err := w.Write(m.format(msg)); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// format renders the message with the headers every mail client expects.
+func (m *Mailer) format(msg mailer.Message) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.cfg.From.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+
+	return buf.Bytes()
+}
@@ -23,4 +23,673 @@ CREATE TABLE todo_items (
     file_id TEXT NOT NULL,
     date_created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     date_updated TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+-- Version: 1.03
+-- Description: Create table comments
+CREATE TABLE comments (
+	comment_id   UUID        NOT NULL,
+	item_id      UUID        NOT NULL,
+	user_id      UUID        NOT NULL,
+	body         TEXT        NOT NULL,
+	mentions     TEXT[]      NOT NULL DEFAULT '{}',
+	edited       BOOLEAN     NOT NULL DEFAULT FALSE,
+	date_created TIMESTAMP   NOT NULL,
+	date_updated TIMESTAMP   NOT NULL,
+
+	PRIMARY KEY (comment_id),
+	FOREIGN KEY (item_id) REFERENCES todo_items(item_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX comments_item_id_idx ON comments (item_id, date_created);
+
+-- Version: 1.04
+-- Description: Add owner, assignee and status to todo_items
+ALTER TABLE todo_items
+	ADD COLUMN user_id     UUID NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	ADD COLUMN assignee_id UUID NULL REFERENCES users(user_id) ON DELETE SET NULL,
+	ADD COLUMN status      TEXT NOT NULL DEFAULT 'OPEN';
+
+CREATE INDEX todo_items_user_id_idx ON todo_items (user_id);
+CREATE INDEX todo_items_assignee_id_idx ON todo_items (assignee_id);
+
+-- Version: 1.05
+-- Description: Create table todo_history
+CREATE TABLE todo_history (
+	history_id   UUID        NOT NULL,
+	item_id      UUID        NOT NULL,
+	actor_id     UUID        NULL,
+	field        TEXT        NOT NULL,
+	old_value    TEXT        NOT NULL,
+	new_value    TEXT        NOT NULL,
+	date_created TIMESTAMP   NOT NULL,
+
+	PRIMARY KEY (history_id),
+	FOREIGN KEY (item_id) REFERENCES todo_items(item_id) ON DELETE CASCADE,
+	FOREIGN KEY (actor_id) REFERENCES users(user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX todo_history_item_id_idx ON todo_history (item_id, date_created);
+
+-- Version: 1.06
+-- Description: Add time zone to users and time zone aware due dates to todo_items
+ALTER TABLE users
+	ADD COLUMN time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE todo_items
+	ALTER COLUMN due_date DROP NOT NULL,
+	ALTER COLUMN due_date TYPE TIMESTAMPTZ USING due_date AT TIME ZONE 'UTC',
+	ADD COLUMN all_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX todo_items_due_date_idx ON todo_items (due_date);
+
+-- Version: 1.07
+-- Description: Add list, labels and completion date to todo_items for reporting
+ALTER TABLE todo_items
+	ADD COLUMN list           TEXT      NULL,
+	ADD COLUMN labels         TEXT[]    NOT NULL DEFAULT '{}',
+	ADD COLUMN date_completed TIMESTAMP NULL;
+
+UPDATE todo_items SET date_completed = date_updated WHERE status = 'DONE';
+
+CREATE INDEX todo_items_date_created_idx ON todo_items (date_created);
+CREATE INDEX todo_items_date_completed_idx ON todo_items (date_completed) WHERE date_completed IS NOT NULL;
+CREATE INDEX todo_items_labels_idx ON todo_items USING GIN (labels);
+
+-- Version: 1.08
+-- Description: Create table idempotency_keys
+CREATE TABLE idempotency_keys (
+	idempotency_key TEXT      NOT NULL,
+	user_id         UUID      NOT NULL,
+	request_hash    TEXT      NOT NULL,
+	status_code     INT       NOT NULL DEFAULT 0,
+	content_type    TEXT      NOT NULL DEFAULT '',
+	body            BYTEA     NULL,
+	date_created    TIMESTAMP NOT NULL,
+	date_expires    TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (user_id, idempotency_key),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idempotency_keys_date_expires_idx ON idempotency_keys (date_expires);
+
+-- Version: 1.09
+-- Description: Create tables attachments and attachment_renditions
+CREATE TABLE attachments (
+	attachment_id UUID      NOT NULL,
+	user_id       UUID      NOT NULL,
+	file_id       TEXT      NOT NULL,
+	name          TEXT      NOT NULL,
+	content_type  TEXT      NOT NULL,
+	size          INT       NOT NULL,
+	date_created  TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (attachment_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX attachments_file_id_idx ON attachments (file_id);
+
+CREATE TABLE attachment_renditions (
+	attachment_id UUID NOT NULL,
+	size          INT  NOT NULL,
+	file_id       TEXT NOT NULL,
+	content_type  TEXT NOT NULL,
+	width         INT  NOT NULL,
+	height        INT  NOT NULL,
+
+	PRIMARY KEY (attachment_id, size),
+	FOREIGN KEY (attachment_id) REFERENCES attachments(attachment_id) ON DELETE CASCADE
+);
+
+-- Version: 1.10
+-- Description: Create table blobs for content addressed file storage
+CREATE TABLE blobs (
+	checksum     TEXT      NOT NULL,
+	file_id      TEXT      NOT NULL DEFAULT '',
+	content_type TEXT      NOT NULL,
+	size         BIGINT    NOT NULL,
+	ref_count    INT       NOT NULL DEFAULT 0,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (checksum)
+);
+
+CREATE INDEX blobs_file_id_idx ON blobs (file_id);
+
+ALTER TABLE attachments ADD COLUMN checksum TEXT NOT NULL DEFAULT '';
+
+-- Version: 1.11
+-- Description: Create table storage_usage and count the files already stored
+CREATE TABLE storage_usage (
+	scope        TEXT      NOT NULL,
+	owner        TEXT      NOT NULL,
+	bytes        BIGINT    NOT NULL DEFAULT 0,
+	quota        BIGINT    NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (scope, owner)
+);
+
+INSERT INTO storage_usage (scope, owner, bytes, date_updated)
+SELECT
+	'user', CAST(f.user_id AS TEXT), SUM(f.size), NOW()
+FROM (
+	SELECT user_id, size FROM attachments
+	UNION ALL
+	SELECT t.user_id, b.size FROM todo_items AS t JOIN blobs AS b ON b.file_id = t.file_id WHERE t.user_id IS NOT NULL
+) AS f
+GROUP BY
+	f.user_id;
+
+INSERT INTO storage_usage (scope, owner, bytes, date_updated)
+SELECT
+	'department', u.department, SUM(f.size), NOW()
+FROM (
+	SELECT user_id, size FROM attachments
+	UNION ALL
+	SELECT t.user_id, b.size FROM todo_items AS t JOIN blobs AS b ON b.file_id = t.file_id WHERE t.user_id IS NOT NULL
+) AS f
+JOIN
+	users AS u ON u.user_id = f.user_id
+WHERE
+	u.department IS NOT NULL
+GROUP BY
+	u.department;
+
+-- Version: 1.12
+-- Description: Create tables webhook_subscriptions and webhook_deliveries
+CREATE TABLE webhook_subscriptions (
+	subscription_id UUID      NOT NULL,
+	user_id         UUID      NOT NULL,
+	url             TEXT      NOT NULL,
+	secret          TEXT      NOT NULL,
+	events          TEXT[]    NOT NULL,
+	all_users       BOOLEAN   NOT NULL DEFAULT FALSE,
+	status          TEXT      NOT NULL,
+	date_created    TIMESTAMP NOT NULL,
+	date_updated    TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (subscription_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_subscriptions_user_id_idx ON webhook_subscriptions (user_id);
+
+CREATE TABLE webhook_deliveries (
+	delivery_id     UUID      NOT NULL,
+	subscription_id UUID      NOT NULL,
+	event_id        UUID      NOT NULL,
+	event           TEXT      NOT NULL,
+	payload         BYTEA     NOT NULL,
+	status          TEXT      NOT NULL,
+	attempts        INT       NOT NULL DEFAULT 0,
+	response_code   INT       NOT NULL DEFAULT 0,
+	error           TEXT      NOT NULL DEFAULT '',
+	next_attempt    TIMESTAMP NOT NULL,
+	date_created    TIMESTAMP NOT NULL,
+	date_updated    TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (delivery_id),
+	FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(subscription_id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id, date_created);
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt) WHERE status = 'pending';
+
+-- Version: 1.13
+-- Description: Create table todo_events
+CREATE TABLE todo_events (
+	event_id             BIGSERIAL NOT NULL,
+	type                 TEXT      NOT NULL,
+	todo_id              UUID      NOT NULL,
+	owner_id             UUID      NULL,
+	assignee_id          UUID      NULL,
+	previous_assignee_id UUID      NULL,
+	list                 TEXT      NULL,
+	data                 BYTEA     NOT NULL,
+	date_created         TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (event_id)
+);
+
+CREATE INDEX todo_events_date_created_idx ON todo_events (date_created);
+
+-- Version: 1.14
+-- Description: Add priority and recurrence to todo_items
+ALTER TABLE todo_items
+	ADD COLUMN priority   TEXT NOT NULL DEFAULT 'P4',
+	ADD COLUMN recurrence TEXT NULL;
+
+-- Version: 1.15
+-- Description: Add checklist to todo_items and create table templates
+ALTER TABLE todo_items
+	ADD COLUMN checklist JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE templates (
+	template_id  UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	name         TEXT      NOT NULL,
+	items        JSONB     NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (template_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX templates_user_id_idx ON templates (user_id);
+
+-- Version: 1.16
+-- Description: Create table todo_dependencies
+CREATE TABLE todo_dependencies (
+	item_id      UUID      NOT NULL,
+	blocker_id   UUID      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (item_id, blocker_id),
+	FOREIGN KEY (item_id) REFERENCES todo_items(item_id) ON DELETE CASCADE,
+	FOREIGN KEY (blocker_id) REFERENCES todo_items(item_id) ON DELETE CASCADE
+);
+
+CREATE INDEX todo_dependencies_blocker_id_idx ON todo_dependencies (blocker_id);
+
+-- Version: 1.17
+-- Description: Add rank to todo_items for the order of items on a board
+ALTER TABLE todo_items
+	ADD COLUMN rank TEXT COLLATE "C" NULL;
+
+CREATE INDEX todo_items_board_idx ON todo_items (user_id, status, rank);
+
+-- Version: 1.18
+-- Description: Create table time_entries
+CREATE TABLE time_entries (
+	entry_id     UUID           NOT NULL,
+	item_id      UUID           NOT NULL,
+	user_id      UUID           NOT NULL,
+	start_time   TIMESTAMP      NOT NULL,
+	end_time     TIMESTAMP      NULL,
+	note         TEXT           NOT NULL DEFAULT '',
+	hourly_rate  NUMERIC(10, 2) NULL,
+	date_created TIMESTAMP      NOT NULL,
+	date_updated TIMESTAMP      NOT NULL,
+
+	PRIMARY KEY (entry_id),
+	FOREIGN KEY (item_id) REFERENCES todo_items(item_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+	CHECK (end_time IS NULL OR end_time > start_time)
+);
+
+CREATE UNIQUE INDEX time_entries_running_idx ON time_entries (user_id) WHERE end_time IS NULL;
+CREATE INDEX time_entries_item_id_idx ON time_entries (item_id);
+CREATE INDEX time_entries_user_start_idx ON time_entries (user_id, start_time);
+
+-- Version: 1.19
+-- Description: Add change sequence and tombstones to todo_items for delta sync
+CREATE SEQUENCE todo_changes;
+
+ALTER TABLE todo_items
+	ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('todo_changes');
+
+CREATE INDEX todo_items_user_change_idx ON todo_items (user_id, change_seq);
+CREATE INDEX todo_items_assignee_change_idx ON todo_items (assignee_id, change_seq);
+
+CREATE TABLE todo_tombstones (
+	item_id      UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	change_seq   BIGINT    NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (item_id, user_id)
+);
+
+CREATE INDEX todo_tombstones_user_change_idx ON todo_tombstones (user_id, change_seq);
+
+CREATE FUNCTION todo_items_changed() RETURNS TRIGGER AS $$
+BEGIN
+	PERFORM pg_advisory_xact_lock_shared(hashtext('todo_changes'));
+
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO todo_tombstones (item_id, user_id, change_seq, date_created)
+		SELECT OLD.item_id, u.user_id, nextval('todo_changes'), now() AT TIME ZONE 'UTC'
+		FROM (SELECT DISTINCT unnest(ARRAY[OLD.user_id, OLD.assignee_id]) AS user_id) u
+		WHERE u.user_id IS NOT NULL
+		ON CONFLICT (item_id, user_id) DO UPDATE
+			SET change_seq = EXCLUDED.change_seq, date_created = EXCLUDED.date_created;
+
+		RETURN OLD;
+	END IF;
+
+	NEW.change_seq := nextval('todo_changes');
+
+	IF TG_OP = 'UPDATE' AND OLD.assignee_id IS NOT NULL AND
+		OLD.assignee_id IS DISTINCT FROM NEW.assignee_id AND
+		OLD.assignee_id IS DISTINCT FROM NEW.user_id THEN
+		INSERT INTO todo_tombstones (item_id, user_id, change_seq, date_created)
+		VALUES (OLD.item_id, OLD.assignee_id, nextval('todo_changes'), now() AT TIME ZONE 'UTC')
+		ON CONFLICT (item_id, user_id) DO UPDATE
+			SET change_seq = EXCLUDED.change_seq, date_created = EXCLUDED.date_created;
+	END IF;
+
+	DELETE FROM todo_tombstones
+	WHERE item_id = NEW.item_id AND user_id IN (NEW.user_id, NEW.assignee_id);
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER todo_items_changed_write
+	BEFORE INSERT OR UPDATE ON todo_items
+	FOR EACH ROW EXECUTE FUNCTION todo_items_changed();
+
+CREATE TRIGGER todo_items_changed_delete
+	AFTER DELETE ON todo_items
+	FOR EACH ROW EXECUTE FUNCTION todo_items_changed();
+
+-- Version: 1.20
+-- Description: Create organizations and scope todo_items and attachments to them
+CREATE TABLE organizations (
+	org_id       UUID      NOT NULL,
+	name         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (org_id)
+);
+
+CREATE TABLE org_members (
+	org_id       UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	role         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (org_id, user_id),
+	FOREIGN KEY (org_id) REFERENCES organizations(org_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX org_members_user_id_idx ON org_members (user_id);
+
+INSERT INTO organizations (org_id, name, date_created, date_updated)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', now() AT TIME ZONE 'UTC', now() AT TIME ZONE 'UTC');
+
+INSERT INTO org_members (org_id, user_id, role, date_created)
+SELECT '00000000-0000-0000-0000-000000000001', user_id, CASE WHEN 'ADMIN' = ANY(roles) THEN 'ADMIN' ELSE 'MEMBER' END, date_created
+FROM users;
+
+ALTER TABLE todo_items
+	ADD COLUMN org_id UUID NULL REFERENCES organizations(org_id) ON DELETE CASCADE;
+
+UPDATE todo_items SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE todo_items
+	ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX todo_items_org_id_idx ON todo_items (org_id, user_id);
+
+ALTER TABLE attachments
+	ADD COLUMN org_id UUID NULL REFERENCES organizations(org_id) ON DELETE CASCADE;
+
+UPDATE attachments SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE attachments
+	ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE todo_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todo_items FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY todo_items_org_isolation ON todo_items
+	USING (NULLIF(current_setting('app.org_id', true), '') IS NULL OR org_id = NULLIF(current_setting('app.org_id', true), '')::UUID);
+
+ALTER TABLE attachments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE attachments FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY attachments_org_isolation ON attachments
+	USING (NULLIF(current_setting('app.org_id', true), '') IS NULL OR org_id = NULLIF(current_setting('app.org_id', true), '')::UUID);
+
+-- Version: 1.21
+-- Description: Create signups and mail_outbox
+CREATE TABLE signups (
+	user_id       UUID      NOT NULL,
+	email         TEXT      NOT NULL,
+	date_created  TIMESTAMP NOT NULL,
+	date_verified TIMESTAMP NULL,
+
+	PRIMARY KEY (user_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE mail_outbox (
+	message_id   UUID      NOT NULL,
+	recipient    TEXT      NOT NULL,
+	subject      TEXT      NOT NULL,
+	body         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (message_id)
+);
+
+CREATE INDEX mail_outbox_recipient_idx ON mail_outbox (recipient, date_created);
+
+-- Version: 1.22
+-- Description: Create password_resets and auth_audit
+CREATE TABLE password_resets (
+	token_hash   TEXT      NOT NULL,
+	user_id      UUID      NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (token_hash),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX password_resets_user_id_idx ON password_resets (user_id);
+
+CREATE TABLE auth_audit (
+	event_id     UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	action       TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (event_id)
+);
+
+CREATE INDEX auth_audit_user_id_idx ON auth_audit (user_id, date_created);
+
+-- Version: 1.23
+-- Description: Create refresh_tokens and revoked_tokens
+CREATE TABLE refresh_tokens (
+	token_hash     TEXT      NOT NULL,
+	family_id      UUID      NOT NULL,
+	user_id        UUID      NOT NULL,
+	org_id         UUID      NOT NULL,
+	access_id      TEXT      NOT NULL,
+	access_expires TIMESTAMP NOT NULL,
+	date_expires   TIMESTAMP NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+	date_used      TIMESTAMP NULL,
+	date_revoked   TIMESTAMP NULL,
+
+	PRIMARY KEY (token_hash),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+
+CREATE TABLE revoked_tokens (
+	access_id    TEXT      NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (access_id)
+);
+
+-- Version: 1.24
+-- Description: Deny organization rows when no organization is set
+DROP POLICY todo_items_org_isolation ON todo_items;
+
+CREATE POLICY todo_items_org_isolation ON todo_items
+	USING (org_id = NULLIF(current_setting('app.org_id', true), '')::UUID);
+
+DROP POLICY attachments_org_isolation ON attachments;
+
+CREATE POLICY attachments_org_isolation ON attachments
+	USING (org_id = NULLIF(current_setting('app.org_id', true), '')::UUID);
+
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'sales_jobs') THEN
+		CREATE ROLE sales_jobs NOLOGIN BYPASSRLS;
+	END IF;
+END
+$$;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO sales_jobs;
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO sales_jobs;
+
+GRANT sales_jobs TO CURRENT_USER;
+
+-- Version: 1.25
+-- Description: Add org_id to todo_tombstones, todo_events and webhook_subscriptions
+ALTER TABLE todo_tombstones
+	ADD COLUMN org_id UUID NULL;
+
+UPDATE todo_tombstones SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE todo_tombstones
+	ALTER COLUMN org_id SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION todo_items_changed() RETURNS TRIGGER AS $$
+BEGIN
+	PERFORM pg_advisory_xact_lock_shared(hashtext('todo_changes'));
+
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO todo_tombstones (item_id, user_id, org_id, change_seq, date_created)
+		SELECT OLD.item_id, u.user_id, OLD.org_id, nextval('todo_changes'), now() AT TIME ZONE 'UTC'
+		FROM (SELECT DISTINCT unnest(ARRAY[OLD.user_id, OLD.assignee_id]) AS user_id) u
+		WHERE u.user_id IS NOT NULL
+		ON CONFLICT (item_id, user_id) DO UPDATE
+			SET change_seq = EXCLUDED.change_seq, date_created = EXCLUDED.date_created;
+
+		RETURN OLD;
+	END IF;
+
+	NEW.change_seq := nextval('todo_changes');
+
+	IF TG_OP = 'UPDATE' AND OLD.assignee_id IS NOT NULL AND
+		OLD.assignee_id IS DISTINCT FROM NEW.assignee_id AND
+		OLD.assignee_id IS DISTINCT FROM NEW.user_id THEN
+		INSERT INTO todo_tombstones (item_id, user_id, org_id, change_seq, date_created)
+		VALUES (OLD.item_id, OLD.assignee_id, OLD.org_id, nextval('todo_changes'), now() AT TIME ZONE 'UTC')
+		ON CONFLICT (item_id, user_id) DO UPDATE
+			SET change_seq = EXCLUDED.change_seq, date_created = EXCLUDED.date_created;
+	END IF;
+
+	DELETE FROM todo_tombstones
+	WHERE item_id = NEW.item_id AND user_id IN (NEW.user_id, NEW.assignee_id);
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE todo_events
+	ADD COLUMN org_id UUID NULL;
+
+UPDATE todo_events SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE todo_events
+	ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE webhook_subscriptions
+	ADD COLUMN org_id UUID NULL REFERENCES organizations(org_id) ON DELETE CASCADE;
+
+UPDATE webhook_subscriptions SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE webhook_subscriptions
+	ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX webhook_subscriptions_org_id_idx ON webhook_subscriptions (org_id, user_id);
+
+-- Version: 1.26
+-- Description: Key the todo_changes write lock by organization
+CREATE OR REPLACE FUNCTION todo_items_changed() RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		PERFORM pg_advisory_xact_lock_shared(hashtext('todo_changes'), hashtext(CAST(OLD.org_id AS TEXT)));
+
+		INSERT INTO todo_tombstones (item_id, user_id, org_id, change_seq, date_created)
+		SELECT OLD.item_id, u.user_id, OLD.org_id, nextval('todo_changes'), now() AT TIME ZONE 'UTC'
+		FROM (SELECT DISTINCT unnest(ARRAY[OLD.user_id, OLD.assignee_id]) AS user_id) u
+		WHERE u.user_id IS NOT NULL
+		ON CONFLICT (item_id, user_id) DO UPDATE
+			SET change_seq = EXCLUDED.change_seq, date_created = EXCLUDED.date_created;
+
+		RETURN OLD;
+	END IF;
+
+	PERFORM pg_advisory_xact_lock_shared(hashtext('todo_changes'), hashtext(CAST(NEW.org_id AS TEXT)));
+
+	NEW.change_seq := nextval('todo_changes');
+
+	IF TG_OP = 'UPDATE' AND OLD.assignee_id IS NOT NULL AND
+		OLD.assignee_id IS DISTINCT FROM NEW.assignee_id AND
+		OLD.assignee_id IS DISTINCT FROM NEW.user_id THEN
+		INSERT INTO todo_tombstones (item_id, user_id, org_id, change_seq, date_created)
+		VALUES (OLD.item_id, OLD.assignee_id, OLD.org_id, nextval('todo_changes'), now() AT TIME ZONE 'UTC')
+		ON CONFLICT (item_id, user_id) DO UPDATE
+			SET change_seq = EXCLUDED.change_seq, date_created = EXCLUDED.date_created;
+	END IF;
+
+	DELETE FROM todo_tombstones
+	WHERE item_id = NEW.item_id AND user_id IN (NEW.user_id, NEW.assignee_id);
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Version: 1.27
+-- Description: Add org_id to templates
+ALTER TABLE templates
+	ADD COLUMN org_id UUID NULL REFERENCES organizations(org_id) ON DELETE CASCADE;
+
+UPDATE templates SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE templates
+	ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX templates_org_id_idx ON templates (org_id, user_id);
+
+ALTER TABLE templates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE templates FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY templates_org_isolation ON templates
+	USING (org_id = NULLIF(current_setting('app.org_id', true), '')::UUID);
+
+-- Version: 1.28
+-- Description: Add the roles the service connects with
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'sales_app') THEN
+		CREATE ROLE sales_app NOLOGIN NOSUPERUSER NOBYPASSRLS;
+	END IF;
+
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'sales_api') THEN
+		CREATE ROLE sales_api LOGIN NOSUPERUSER NOBYPASSRLS INHERIT IN ROLE sales_app;
+	END IF;
+
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'sales_worker') THEN
+		CREATE ROLE sales_worker LOGIN NOSUPERUSER NOBYPASSRLS NOINHERIT IN ROLE sales_jobs;
+	END IF;
+END
+$$;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO sales_app;
+GRANT USAGE, SELECT, UPDATE ON ALL SEQUENCES IN SCHEMA public TO sales_app, sales_jobs;
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO sales_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT, UPDATE ON SEQUENCES TO sales_app, sales_jobs;
+
+REVOKE sales_jobs FROM CURRENT_USER;
@@ -0,0 +1,87 @@
+// Package opaque provides support for the tokens handed to clients that they
+// can not read or forge: random tokens the service keeps the hash of, and
+// values signed with a key the service holds.
+package opaque
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is returned when a signed value was not signed with the key and
+// purpose, was tampered with or can not be read.
+var ErrInvalid = errors.New("invalid signed value")
+
+// tokenSize is the number of random bytes in a token.
+const tokenSize = 32
+
+// New returns a random token and the hash of it to store.
+func New() (string, []byte, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("read: %w", err)
+	}
+
+	tkn := base64.RawURLEncoding.EncodeToString(b)
+
+	return tkn, Hash(tkn), nil
+}
+
+// Hash returns the hash a token is stored under.
+func Hash(tkn string) []byte {
+	sum := sha256.Sum256([]byte(tkn))
+	return sum[:]
+}
+
+// Sign returns the value encoded as JSON and signed with the key, as a string
+// clients send back as is. The purpose keeps a value signed for one use from
+// being accepted for another that shares the key.
+func Sign(key []byte, purpose string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(key, purpose, payload)), nil
+}
+
+// Verify reads a value signed with the same key and purpose into v.
+func Verify(key []byte, purpose string, value string, v any) error {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return ErrInvalid
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, sign(key, purpose, payload)) {
+		return ErrInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	return nil
+}
+
+func sign(key []byte, purpose string, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	if purpose != "" {
+		mac.Write([]byte(purpose + "."))
+	}
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,66 @@
+package opaque_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/himynamej/todo/business/sdk/opaque"
+)
+
+func Test_New(t *testing.T) {
+	tkn, hash, err := opaque.New()
+	if err != nil {
+		t.Fatalf("Should be able to create a token : %s", err)
+	}
+
+	if !bytes.Equal(hash, opaque.Hash(tkn)) {
+		t.Errorf("Should get the hash the token is stored under")
+	}
+
+	other, _, err := opaque.New()
+	if err != nil {
+		t.Fatalf("Should be able to create a token : %s", err)
+	}
+
+	if tkn == other {
+		t.Errorf("Should get a different token every time")
+	}
+}
+
+func Test_Sign(t *testing.T) {
+	type value struct {
+		ID string `json:"i"`
+	}
+
+	key := []byte("signing-key")
+
+	signed, err := opaque.Sign(key, "test", value{ID: "a1b2"})
+	if err != nil {
+		t.Fatalf("Should be able to sign the value : %s", err)
+	}
+
+	var got value
+	if err := opaque.Verify(key, "test", signed, &got); err != nil {
+		t.Fatalf("Should be able to verify the value : %s", err)
+	}
+
+	if got.ID != "a1b2" {
+		t.Errorf("Got %+v, exp %+v", got, value{ID: "a1b2"})
+	}
+
+	other, err := opaque.Sign(key, "test", value{ID: "ffff"})
+	if err != nil {
+		t.Fatalf("Should be able to sign the value : %s", err)
+	}
+	tampered := other[:len(other)-2] + signed[len(signed)-2:]
+
+	otherKey, _ := opaque.Sign([]byte("other-key"), "test", value{ID: "a1b2"})
+	otherPurpose, _ := opaque.Sign(key, "other", value{ID: "a1b2"})
+
+	for _, bad := range []string{"", "garbage", tampered, otherKey, otherPurpose} {
+		if err := opaque.Verify(key, "test", bad, &got); !errors.Is(err, opaque.ErrInvalid) {
+			t.Errorf("Verifying %q: got %v, exp %v", bad, err, opaque.ErrInvalid)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package page
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/himynamej/todo/business/sdk/opaque"
+	"github.com/himynamej/todo/business/sdk/order"
+)
+
+// ErrInvalidCursor is returned when a cursor was not handed out by the
+// service, was tampered with or can not be read.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the position of a row in an ordered result, so paging can
+// continue from it no matter how many rows were added or removed before it.
+// The position is the value of the column the rows are ordered by, with the
+// id of the row breaking ties.
+type Cursor struct {
+	Order  order.By // Order of the result the row was read from
+	Value  *string  // Value the row is ordered by, nil when NULL
+	ID     string   // Id of the row
+	Before bool     // Read the rows before the position instead of after
+}
+
+type cursor struct {
+	Field     string  `json:"f"`
+	Direction string  `json:"d"`
+	Value     *string `json:"v"`
+	ID        string  `json:"i"`
+	Before    bool    `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque string signed with the key, for
+// clients to send back as is.
+func (c Cursor) Encode(key []byte) string {
+	value, _ := opaque.Sign(key, "", cursor{
+		Field:     c.Order.Field,
+		Direction: c.Order.Direction,
+		Value:     c.Value,
+		ID:        c.ID,
+		Before:    c.Before,
+	})
+
+	return value
+}
+
+// DecodeCursor reads a cursor encoded with the same key.
+func DecodeCursor(key []byte, value string) (Cursor, error) {
+	var c cursor
+	if err := opaque.Verify(key, "", value, &c); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	cur := Cursor{
+		Order:  order.NewBy(c.Field, c.Direction),
+		Value:  c.Value,
+		ID:     c.ID,
+		Before: c.Before,
+	}
+
+	return cur, nil
+}
+
+// =============================================================================
+
+// Adjacent returns the encoded cursors of the pages before and after the
+// page of items that was read, using the function to find the position of an
+// item. A cursor is empty when there is no such page. A full page is assumed
+// to have items after it, so the last page may be followed by an empty one.
+func Adjacent[T any](key []byte, p Page, items []T, cursor func(item T) Cursor) (prev string, next string) {
+	if len(items) == 0 {
+		return "", ""
+	}
+
+	full := len(items) >= p.rows
+
+	hasPrev := p.number > 1
+	hasNext := full
+
+	if p.cursor != nil {
+		hasPrev = !p.cursor.Before || full
+		hasNext = p.cursor.Before || full
+	}
+
+	if hasPrev {
+		first := cursor(items[0])
+		first.Before = true
+		prev = first.Encode(key)
+	}
+
+	if hasNext {
+		last := cursor(items[len(items)-1])
+		last.Before = false
+		next = last.Encode(key)
+	}
+
+	return prev, next
+}
@@ -0,0 +1,106 @@
+package page_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+)
+
+var key = []byte("cursor-key")
+
+func Test_Cursor(t *testing.T) {
+	value := "2024-01-02T03:04:05Z"
+	orderBy := order.NewBy("due_date", order.DESC)
+
+	cur := page.Cursor{
+		Order: orderBy,
+		Value: &value,
+		ID:    "a1b2",
+	}
+
+	encoded := cur.Encode(key)
+
+	got, err := page.DecodeCursor(key, encoded)
+	if err != nil {
+		t.Fatalf("Should be able to decode the cursor : %s", err)
+	}
+
+	if got.Order != orderBy || got.ID != cur.ID || got.Value == nil || *got.Value != value || got.Before {
+		t.Errorf("Got %+v, exp %+v", got, cur)
+	}
+
+	tampered := page.Cursor{Order: orderBy, Value: &value, ID: "ffff"}.Encode(key)
+	tampered = tampered[:len(tampered)-2] + encoded[len(encoded)-2:]
+
+	for _, bad := range []string{"", "garbage", tampered, cur.Encode([]byte("other-key"))} {
+		if _, err := page.DecodeCursor(key, bad); !errors.Is(err, page.ErrInvalidCursor) {
+			t.Errorf("Decoding %q: got %v, exp %v", bad, err, page.ErrInvalidCursor)
+		}
+	}
+
+	if _, err := page.ParseCursor(key, encoded, "10", order.NewBy("due_date", order.ASC)); err == nil {
+		t.Errorf("Should not accept a cursor for another order")
+	}
+}
+
+func Test_Adjacent(t *testing.T) {
+	orderBy := order.NewBy("item_id", order.ASC)
+	position := func(id string) page.Cursor { return page.Cursor{Order: orderBy, ID: id} }
+
+	decode := func(t *testing.T, s string) page.Cursor {
+		t.Helper()
+		cur, err := page.DecodeCursor(key, s)
+		if err != nil {
+			t.Fatalf("Should be able to decode %q : %s", s, err)
+		}
+		return cur
+	}
+
+	first, _ := page.Parse("1", "2")
+	second, _ := page.Parse("2", "2")
+	after, _ := page.FromCursor(page.Cursor{Order: orderBy, ID: "b"}, 2)
+	before, _ := page.FromCursor(page.Cursor{Order: orderBy, ID: "c", Before: true}, 2)
+
+	tests := []struct {
+		name  string
+		page  page.Page
+		items []string
+		prev  string
+		next  string
+	}{
+		{name: "first", page: first, items: []string{"a", "b"}, next: "b"},
+		{name: "last", page: second, items: []string{"c"}, prev: "c"},
+		{name: "after", page: after, items: []string{"c", "d"}, prev: "c", next: "d"},
+		{name: "after-end", page: after, items: []string{"c"}, prev: "c"},
+		{name: "before-start", page: before, items: []string{"b"}, next: "b"},
+		{name: "empty", page: after},
+	}
+
+	for _, tt := range tests {
+		prev, next := page.Adjacent(key, tt.page, tt.items, position)
+
+		switch {
+		case tt.prev == "" && prev != "":
+			t.Errorf("%s: got a prev cursor, exp none", tt.name)
+		case tt.prev != "" && prev == "":
+			t.Errorf("%s: got no prev cursor, exp one", tt.name)
+		case tt.prev != "":
+			if cur := decode(t, prev); cur.ID != tt.prev || !cur.Before {
+				t.Errorf("%s: got prev %+v, exp before %s", tt.name, cur, tt.prev)
+			}
+		}
+
+		switch {
+		case tt.next == "" && next != "":
+			t.Errorf("%s: got a next cursor, exp none", tt.name)
+		case tt.next != "" && next == "":
+			t.Errorf("%s: got no next cursor, exp one", tt.name)
+		case tt.next != "":
+			if cur := decode(t, next); cur.ID != tt.next || cur.Before {
+				t.Errorf("%s: got next %+v, exp after %s", tt.name, cur, tt.next)
+			}
+		}
+	}
+}
@@ -4,12 +4,17 @@ package page
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/himynamej/todo/business/sdk/order"
 )
 
-// Page represents the requested page and rows per page.
+// Page represents the requested page and rows per page. A page is either
+// found by its number, or by a cursor that marks where the previous page
+// ended.
 type Page struct {
 	number int
 	rows   int
+	cursor *Cursor
 }
 
 // Parse parses the strings and validates the values are in reason.
@@ -36,12 +41,8 @@ func Parse(page string, rowsPerPage string) (Page, error) {
 		return Page{}, fmt.Errorf("page value too small, must be larger than 0")
 	}
 
-	if rows <= 0 {
-		return Page{}, fmt.Errorf("rows value too small, must be larger than 0")
-	}
-
-	if rows > 100 {
-		return Page{}, fmt.Errorf("rows value too large, must be less than 100")
+	if err := checkRows(rows); err != nil {
+		return Page{}, err
 	}
 
 	p := Page{
@@ -52,6 +53,59 @@ func Parse(page string, rowsPerPage string) (Page, error) {
 	return p, nil
 }
 
+// ParseCursor parses the cursor handed out with a previous page, signed with
+// the key, and the rows per page. A cursor only holds a position in the order
+// it was handed out for.
+func ParseCursor(key []byte, cursor string, rowsPerPage string, orderBy order.By) (Page, error) {
+	cur, err := DecodeCursor(key, cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	if cur.Order != orderBy {
+		return Page{}, fmt.Errorf("cursor is for the order %s,%s", cur.Order.Field, cur.Order.Direction)
+	}
+
+	rows := 10
+	if rowsPerPage != "" {
+		var err error
+		rows, err = strconv.Atoi(rowsPerPage)
+		if err != nil {
+			return Page{}, fmt.Errorf("rows conversion: %w", err)
+		}
+	}
+
+	return FromCursor(cur, rows)
+}
+
+// FromCursor constructs the page of rows that follow the cursor, or precede
+// it for a cursor that reads before.
+func FromCursor(cur Cursor, rowsPerPage int) (Page, error) {
+	if err := checkRows(rowsPerPage); err != nil {
+		return Page{}, err
+	}
+
+	p := Page{
+		number: 1,
+		rows:   rowsPerPage,
+		cursor: &cur,
+	}
+
+	return p, nil
+}
+
+func checkRows(rows int) error {
+	if rows <= 0 {
+		return fmt.Errorf("rows value too small, must be larger than 0")
+	}
+
+	if rows > 100 {
+		return fmt.Errorf("rows value too large, must be less than 100")
+	}
+
+	return nil
+}
+
 // MustParse creates a paging value for testing.
 func MustParse(page string, rowsPerPage string) Page {
 	pg, err := Parse(page, rowsPerPage)
@@ -64,6 +118,10 @@ func MustParse(page string, rowsPerPage string) Page {
 
 // String implements the stringer interface.
 func (p Page) String() string {
+	if p.cursor != nil {
+		return fmt.Sprintf("cursor: %s rows: %d", p.cursor.ID, p.rows)
+	}
+
 	return fmt.Sprintf("page: %d rows: %d", p.number, p.rows)
 }
 
@@ -76,3 +134,12 @@ func (p Page) Number() int {
 func (p Page) RowsPerPage() int {
 	return p.rows
 }
+
+// Cursor returns the cursor the page was found by, if it was.
+func (p Page) Cursor() (Cursor, bool) {
+	if p.cursor == nil {
+		return Cursor{}, false
+	}
+
+	return *p.cursor, true
+}
@@ -0,0 +1,80 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"github.com/himynamej/todo/business/sdk/order"
+	"github.com/himynamej/todo/business/sdk/page"
+)
+
+// KeysetColumn describes a column rows are ordered by for keyset paging.
+type KeysetColumn struct {
+	Name     string
+	Type     string // SQL type the cursor value is cast to
+	Nullable bool
+}
+
+// OrderByKeyset returns the order by clause for the column, with the id
+// column breaking ties so every row has a position of its own.
+func OrderByKeyset(column KeysetColumn, id KeysetColumn, direction string) string {
+	if column.Name == id.Name {
+		return fmt.Sprintf(" ORDER BY %s %s", id.Name, direction)
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", column.Name, direction, id.Name, direction)
+}
+
+// Keyset returns the condition that selects the rows after the position of
+// the cursor and the order they must be read in. The rows before a cursor
+// that reads before are read in reverse, so the caller has to reverse them
+// back. The cursor values are added to the data.
+func Keyset(cur page.Cursor, column KeysetColumn, id KeysetColumn, data map[string]any) (where string, orderBy string) {
+	dir := cur.Order.Direction
+	if cur.Before {
+		switch dir {
+		case order.DESC:
+			dir = order.ASC
+		default:
+			dir = order.DESC
+		}
+	}
+
+	op := ">"
+	if dir == order.DESC {
+		op = "<"
+	}
+
+	orderBy = OrderByKeyset(column, id, dir)
+
+	// The values are sent as text and cast by the database, so a cursor can
+	// hold the value of a column of any type.
+
+	data["cursor_id"] = cur.ID
+	idValue := fmt.Sprintf("CAST(CAST(:cursor_id AS TEXT) AS %s)", id.Type)
+
+	if column.Name == id.Name {
+		return fmt.Sprintf("%s %s %s", id.Name, op, idValue), orderBy
+	}
+
+	// NULLs are sorted after every value in ascending order and before every
+	// value in descending order.
+
+	switch {
+	case cur.Value == nil && dir == order.DESC:
+		where = fmt.Sprintf("(%s IS NOT NULL OR %s < %s)", column.Name, id.Name, idValue)
+
+	case cur.Value == nil:
+		where = fmt.Sprintf("(%s IS NULL AND %s > %s)", column.Name, id.Name, idValue)
+
+	default:
+		data["cursor_value"] = *cur.Value
+		value := fmt.Sprintf("CAST(CAST(:cursor_value AS TEXT) AS %s)", column.Type)
+
+		where = fmt.Sprintf("(%s, %s) %s (%s, %s)", column.Name, id.Name, op, value, idValue)
+		if column.Nullable && dir == order.ASC {
+			where = fmt.Sprintf("(%s OR %s IS NULL)", where, column.Name)
+		}
+	}
+
+	return where, orderBy
+}
@@ -0,0 +1,55 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// Listen waits for notifications on the specified channel over a connection
+// of its own, taken from the pool for as long as it runs. The function is
+// called with an empty payload once listening has started, so the caller can
+// catch up on anything it missed, and then with the payload of every
+// notification. Listen returns when the context is canceled, the function
+// fails or the connection is lost. The connection is closed rather than going
+// back to the pool.
+func Listen(ctx context.Context, db *sqlx.DB, channel string, fn func(ctx context.Context, payload string) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("conn: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		sc, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("listen needs a pgx connection")
+		}
+
+		pgxConn := sc.Conn()
+		defer pgxConn.Close(context.Background())
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("listen: %w", err)
+		}
+
+		if err := fn(ctx, ""); err != nil {
+			return err
+		}
+
+		for {
+			n, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return fmt.Errorf("wait: %w", err)
+			}
+
+			if err := fn(ctx, n.Payload); err != nil {
+				return err
+			}
+		}
+	})
+}
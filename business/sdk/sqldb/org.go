@@ -0,0 +1,85 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ctxKey int
+
+const orgIDKey ctxKey = 1
+
+// JobRole is the database role background work that spans organizations
+// connects with. It bypasses the row-level security policies, which let no
+// rows through to a connection without an organization set.
+const JobRole = "sales_jobs"
+
+// WithOrgID returns a context that scopes database access to the specified
+// organization. Stores only read and write the rows of the organization, and
+// every statement run with the context is held to it by the row-level
+// security policies.
+func WithOrgID(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, orgIDKey, orgID)
+}
+
+// GetOrgID returns the organization database access is scoped to. The
+// boolean is false when access is not scoped, as for background work that
+// spans organizations.
+func GetOrgID(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(orgIDKey).(uuid.UUID)
+	if !ok || v == uuid.Nil {
+		return uuid.Nil, false
+	}
+
+	return v, true
+}
+
+// OrgArg returns the organization the context scopes access to, as the value
+// of the :org_id parameter stores limit their queries with. It is null when
+// access is not scoped.
+func OrgArg(ctx context.Context) uuid.NullUUID {
+	orgID, ok := GetOrgID(ctx)
+	return uuid.NullUUID{UUID: orgID, Valid: ok}
+}
+
+// scoped runs fn with the organization of the context set on the connection,
+// so the row-level security policies let its rows through. The setting only
+// lasts until the transaction ends, so outside of one the statement is given
+// a transaction of its own. Without the setting the policies let no rows
+// through, which leaves work that spans organizations to the JobRole.
+func scoped(ctx context.Context, db sqlx.ExtContext, fn func(db sqlx.ExtContext) error) error {
+	orgID, ok := GetOrgID(ctx)
+	if !ok {
+		return fn(db)
+	}
+
+	const q = `SELECT set_config('app.org_id', $1, true)`
+
+	sqlxDB, ok := db.(*sqlx.DB)
+	if !ok {
+		if _, err := db.ExecContext(ctx, q, orgID.String()); err != nil {
+			return fmt.Errorf("set org: %w", err)
+		}
+
+		return fn(db)
+	}
+
+	tx, err := sqlxDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, q, orgID.String()); err != nil {
+		return fmt.Errorf("set org: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -42,6 +42,8 @@ type Config struct {
 	MaxIdleConns int
 	MaxOpenConns int
 	DisableTLS   bool
+	ReadOnly     bool
+	Role         string
 }
 
 // Open knows how to open a database connection based on the configuration.
@@ -54,9 +56,15 @@ func Open(cfg Config) (*sqlx.DB, error) {
 	q := make(url.Values)
 	q.Set("sslmode", sslMode)
 	q.Set("timezone", "utc")
+	if cfg.ReadOnly {
+		q.Set("default_transaction_read_only", "on")
+	}
 	if cfg.Schema != "" {
 		q.Set("search_path", cfg.Schema)
 	}
+	if cfg.Role != "" {
+		q.Set("role", cfg.Role)
+	}
 
 	u := url.URL{
 		Scheme:   "postgres",
@@ -135,7 +143,11 @@ func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.exec", attribute.String("query", q))
 	defer span.End()
 
-	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
+	err = scoped(ctx, db, func(db sqlx.ExtContext) error {
+		_, err := sqlx.NamedExecContext(ctx, db, query, data)
+		return err
+	})
+	if err != nil {
 		var pqerr *pgconn.PgError
 		if errors.As(err, &pqerr) {
 			switch pqerr.Code {
@@ -183,49 +195,25 @@ func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.Ext
 	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.queryslice", attribute.String("query", q))
 	defer span.End()
 
-	var rows *sqlx.Rows
-
-	switch withIn {
-	case true:
-		rows, err = func() (*sqlx.Rows, error) {
-			named, args, err := sqlx.Named(query, data)
-			if err != nil {
-				return nil, err
-			}
-
-			query, args, err := sqlx.In(named, args...)
-			if err != nil {
-				return nil, err
-			}
-
-			query = db.Rebind(query)
-			return db.QueryxContext(ctx, query, args...)
-		}()
-
-	default:
-		rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
-	}
-
-	if err != nil {
-		var pqerr *pgconn.PgError
-		if errors.As(err, &pqerr) && pqerr.Code == undefinedTable {
-			return ErrUndefinedTable
+	return scoped(ctx, db, func(db sqlx.ExtContext) error {
+		rows, err := queryRows(ctx, db, query, data, withIn)
+		if err != nil {
+			return err
 		}
-		return err
-	}
-	defer rows.Close()
+		defer rows.Close()
 
-	var slice []T
-	for rows.Next() {
-		v := new(T)
-		if err := rows.StructScan(v); err != nil {
-			return err
+		var slice []T
+		for rows.Next() {
+			v := new(T)
+			if err := rows.StructScan(v); err != nil {
+				return err
+			}
+			slice = append(slice, *v)
 		}
-		slice = append(slice, *v)
-	}
-	*dest = slice
+		*dest = slice
 
-	return nil
+		return nil
+	})
 }
 
 // QueryStruct is a helper function for executing queries that return a
@@ -259,7 +247,29 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	ctx, span := otel.AddSpan(ctx, "business.sdk.sqldb.query", attribute.String("query", q))
 	defer span.End()
 
+	return scoped(ctx, db, func(db sqlx.ExtContext) error {
+		rows, err := queryRows(ctx, db, query, data, withIn)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		if !rows.Next() {
+			return ErrDBNotFound
+		}
+
+		if err := rows.StructScan(dest); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// queryRows runs the query, expanding any IN clause when asked to.
+func queryRows(ctx context.Context, db sqlx.ExtContext, query string, data any, withIn bool) (*sqlx.Rows, error) {
 	var rows *sqlx.Rows
+	var err error
 
 	switch withIn {
 	case true:
@@ -285,21 +295,12 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	if err != nil {
 		var pqerr *pgconn.PgError
 		if errors.As(err, &pqerr) && pqerr.Code == undefinedTable {
-			return ErrUndefinedTable
+			return nil, ErrUndefinedTable
 		}
-		return err
-	}
-	defer rows.Close()
-
-	if !rows.Next() {
-		return ErrDBNotFound
-	}
-
-	if err := rows.StructScan(dest); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return rows, nil
 }
 
 // queryString provides a pretty print version of the query and parameters.
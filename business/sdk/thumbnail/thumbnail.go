@@ -0,0 +1,195 @@
+// Package thumbnail renders downscaled copies of JPEG, PNG and GIF images.
+// Renditions are re-encoded from decoded pixels so no metadata from the
+// original survives, and JPEG EXIF orientation is applied first so the
+// result displays upright.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxPixels bounds the size of images that will be decoded, so a small file
+// that declares enormous dimensions can't exhaust memory.
+const MaxPixels = 50_000_000
+
+// Set of error variables for rendering.
+var (
+	ErrUnsupported = errors.New("unsupported image format")
+	ErrTooLarge    = errors.New("image dimensions are too large")
+)
+
+// Thumbnail is a rendition of an image that fits within a square of Size
+// pixels.
+type Thumbnail struct {
+	Size        int
+	Width       int
+	Height      int
+	ContentType string
+	Data        []byte
+}
+
+// Generate renders a thumbnail for every size. Images are never enlarged, so
+// a size larger than the original produces a copy at the original
+// dimensions.
+func Generate(data []byte, sizes []int) ([]Thumbnail, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decodeconfig: %w", ErrUnsupported)
+	}
+
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%dx%d: %w", cfg.Width, cfg.Height, ErrTooLarge)
+	}
+
+	img, err := decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "jpeg" {
+		img = orient(img, orientation(data))
+	}
+
+	// Every size is scaled from the same pixels, so the source is converted
+	// only once.
+	src := toRGBA(img)
+
+	thumbs := make([]Thumbnail, len(sizes))
+	for i, size := range sizes {
+		w, h := fit(src.Rect.Dx(), src.Rect.Dy(), size)
+
+		data, contentType, err := encode(scale(src, w, h), format)
+		if err != nil {
+			return nil, fmt.Errorf("encode: size[%d]: %w", size, err)
+		}
+
+		thumbs[i] = Thumbnail{
+			Size:        size,
+			Width:       w,
+			Height:      h,
+			ContentType: contentType,
+			Data:        data,
+		}
+	}
+
+	return thumbs, nil
+}
+
+// =============================================================================
+
+func decode(data []byte, format string) (image.Image, error) {
+	r := bytes.NewReader(data)
+
+	var img image.Image
+	var err error
+
+	switch format {
+	case "jpeg":
+		img, err = jpeg.Decode(r)
+	case "png":
+		img, err = png.Decode(r)
+	case "gif":
+		img, err = gif.Decode(r)
+	default:
+		return nil, fmt.Errorf("format[%s]: %w", format, ErrUnsupported)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return img, nil
+}
+
+// encode writes JPEG sources back as JPEG and everything else as PNG so
+// transparency is kept. Animated GIFs keep only their first frame.
+func encode(img image.Image, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// fit returns the dimensions of a w by h image scaled to fit within a square
+// of size pixels, keeping the aspect ratio.
+func fit(w int, h int, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+
+	if w >= h {
+		return size, max(1, h*size/w)
+	}
+	return max(1, w*size/h), size
+}
+
+// toRGBA returns the image as RGBA pixels with its origin at zero.
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	if rgba, ok := img.(*image.RGBA); ok && b.Min == (image.Point{}) {
+		return rgba
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+
+	return rgba
+}
+
+// scale resizes the image by averaging every source pixel that falls under
+// each destination pixel, which gives clean results when shrinking.
+func scale(src *image.RGBA, w int, h int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	if sw == w && sh == h {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max(y0+1, (y+1)*sh/h)
+
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max(x0+1, (x+1)*sw/w)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					bl += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,166 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/himynamej/todo/business/sdk/thumbnail"
+)
+
+var sizes = []int{64, 256, 1024}
+
+func Test_Generate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		dims        [][2]int
+	}{
+		{name: "png", data: encodePNG(t, 300, 200), contentType: "image/png", dims: [][2]int{{64, 42}, {256, 170}, {300, 200}}},
+		{name: "gif", data: encodeGIF(t, 100, 400), contentType: "image/png", dims: [][2]int{{16, 64}, {64, 256}, {100, 400}}},
+		{name: "jpeg", data: encodeJPEG(t, 80, 40, 1), contentType: "image/jpeg", dims: [][2]int{{64, 32}, {80, 40}, {80, 40}}},
+		{name: "jpeg-rotated", data: encodeJPEG(t, 80, 40, 6), contentType: "image/jpeg", dims: [][2]int{{32, 64}, {40, 80}, {40, 80}}},
+	}
+
+	for _, tt := range tests {
+		thumbs, err := thumbnail.Generate(tt.data, sizes)
+		if err != nil {
+			t.Fatalf("%s: generate: %s", tt.name, err)
+		}
+
+		for i, thumb := range thumbs {
+			if thumb.ContentType != tt.contentType {
+				t.Errorf("%s: size[%d]: got %s, want %s", tt.name, thumb.Size, thumb.ContentType, tt.contentType)
+			}
+
+			if got := [2]int{thumb.Width, thumb.Height}; got != tt.dims[i] {
+				t.Errorf("%s: size[%d]: got %v, want %v", tt.name, thumb.Size, got, tt.dims[i])
+			}
+
+			if bytes.Contains(thumb.Data, []byte("Exif")) {
+				t.Errorf("%s: size[%d]: metadata was not stripped", tt.name, thumb.Size)
+			}
+
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb.Data))
+			if err != nil {
+				t.Fatalf("%s: size[%d]: decode: %s", tt.name, thumb.Size, err)
+			}
+
+			if cfg.Width != thumb.Width || cfg.Height != thumb.Height {
+				t.Errorf("%s: size[%d]: encoded %dx%d, reported %dx%d", tt.name, thumb.Size, cfg.Width, cfg.Height, thumb.Width, thumb.Height)
+			}
+		}
+	}
+}
+
+func Test_GenerateOrientation(t *testing.T) {
+	// The top half of the source is red. Orientation 3 turns the picture
+	// upside down, so the red half must end up at the bottom.
+	thumbs, err := thumbnail.Generate(encodeJPEG(t, 40, 40, 3), []int{40})
+	if err != nil {
+		t.Fatalf("generate: %s", err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(thumbs[0].Data))
+	if err != nil {
+		t.Fatalf("decode: %s", err)
+	}
+
+	r, _, _, _ := img.At(20, 35).RGBA()
+	if r>>8 < 200 {
+		t.Errorf("expected the bottom of the rendition to be red, got red channel %d", r>>8)
+	}
+}
+
+func Test_GenerateRejects(t *testing.T) {
+	if _, err := thumbnail.Generate([]byte("not an image"), sizes); !errors.Is(err, thumbnail.ErrUnsupported) {
+		t.Errorf("got %v, want %v", err, thumbnail.ErrUnsupported)
+	}
+
+	huge := encodePNG(t, 1, 1)
+	setPNGDimensions(huge, 20000, 20000)
+
+	if _, err := thumbnail.Generate(huge, sizes); !errors.Is(err, thumbnail.ErrTooLarge) {
+		t.Errorf("got %v, want %v", err, thumbnail.ErrTooLarge)
+	}
+}
+
+// =============================================================================
+
+func fill(w int, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{B: 255, A: 255}
+			if y < h/2 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, w int, h int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, fill(w, h)); err != nil {
+		t.Fatalf("encode png: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeGIF(t *testing.T, w int, h int) []byte {
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, fill(w, h), nil); err != nil {
+		t.Fatalf("encode gif: %s", err)
+	}
+	return buf.Bytes()
+}
+
+// encodeJPEG encodes a JPEG and inserts an EXIF segment carrying the
+// orientation, along with a camera make so stripping can be checked.
+func encodeJPEG(t *testing.T, w int, h int, orientation uint16) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, fill(w, h), &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("encode jpeg: %s", err)
+	}
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	app1 := []byte{0xff, 0xe1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	out = append(out, data[2:]...)
+
+	return out
+}
+
+// setPNGDimensions rewrites the IHDR chunk so the image claims to be w by h.
+func setPNGDimensions(data []byte, w uint32, h uint32) {
+	const ihdr = 8 + 4 + 4
+	binary.BigEndian.PutUint32(data[ihdr:], w)
+	binary.BigEndian.PutUint32(data[ihdr+4:], h)
+
+	crc := crc32.ChecksumIEEE(data[ihdr-4 : ihdr+13])
+	binary.BigEndian.PutUint32(data[ihdr+13:], crc)
+}
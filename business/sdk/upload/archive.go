@@ -0,0 +1,150 @@
+package upload
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+)
+
+// ArchiveLimits bounds how much work an archive may cause when it is
+// expanded.
+type ArchiveLimits struct {
+	MaxEntries int   // Entries across all nesting levels
+	MaxSize    int64 // Uncompressed bytes across all nesting levels
+	MaxRatio   int64 // Uncompressed to compressed ratio
+	MaxDepth   int   // Archives inside archives
+}
+
+// ratioFloor is the uncompressed size below which the ratio is not checked,
+// since small files of repetitive text legitimately compress very well.
+const ratioFloor = 1 << 20
+
+// GuardArchive expands zip based and gzip files with the limits applied and
+// rejects any that would exceed them. Other content types pass through.
+func GuardArchive(limits ArchiveLimits) Validator {
+	f := func(ctx context.Context, file *File) error {
+		if file.ContentType == "" {
+			file.ContentType = Sniff(file.Data)
+		}
+
+		if !isArchive(file.ContentType) {
+			return nil
+		}
+
+		g := guard{limits: limits}
+		if err := g.inspect(file.Data, file.ContentType, 0); err != nil {
+			return err
+		}
+
+		if g.size > ratioFloor && g.size > limits.MaxRatio*int64(len(file.Data)) {
+			return reject(FieldFile, "archive expands more than %d times its size", limits.MaxRatio)
+		}
+
+		return nil
+	}
+
+	return ValidatorFunc(f)
+}
+
+// =============================================================================
+
+type guard struct {
+	limits  ArchiveLimits
+	entries int
+	size    int64
+}
+
+func isArchive(mime string) bool {
+	switch mime {
+	case TypeZIP, TypeDOCX, TypeXLSX, TypePPTX, TypeGzip:
+		return true
+	}
+	return false
+}
+
+func (g *guard) inspect(data []byte, mime string, depth int) error {
+	if depth > g.limits.MaxDepth {
+		return reject(FieldFile, "archive is nested more than %d levels deep", g.limits.MaxDepth)
+	}
+
+	if mime == TypeGzip {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return reject(FieldFile, "archive is corrupt")
+		}
+		defer zr.Close()
+
+		return g.expand(zr, depth)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return reject(FieldFile, "archive is corrupt")
+	}
+
+	for _, f := range zr.File {
+		g.entries++
+		if g.entries > g.limits.MaxEntries {
+			return reject(FieldFile, "archive contains more than %d entries", g.limits.MaxEntries)
+		}
+
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return reject(FieldFile, "archive is corrupt")
+		}
+
+		err = g.expand(rc, depth)
+		rc.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expand reads the stream while charging it against the size budget. Nested
+// archives are buffered and inspected in turn.
+func (g *guard) expand(r io.Reader, depth int) error {
+	remaining := g.limits.MaxSize - g.size
+	br := bufio.NewReader(io.LimitReader(r, remaining+1))
+
+	// Zip needs the whole stream to find its central directory, so nested
+	// archives are recognized from the leading bytes alone.
+	head, _ := br.Peek(512)
+	mime := Sniff(head)
+
+	var n int64
+	var data []byte
+	var err error
+
+	if isArchive(mime) {
+		data, err = io.ReadAll(br)
+		n = int64(len(data))
+	} else {
+		n, err = io.Copy(io.Discard, br)
+	}
+
+	g.size += n
+
+	switch {
+	case g.size > g.limits.MaxSize:
+		return reject(FieldFile, "archive expands to more than %d bytes", g.limits.MaxSize)
+	case err != nil:
+		return reject(FieldFile, "archive is corrupt")
+	}
+
+	if data != nil {
+		return g.inspect(data, mime, depth+1)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+// Package clamd provides a scanner that speaks the clamd protocol, so it
+// works against ClamAV or any local stand-in that implements INSTREAM.
+package clamd
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/himynamej/todo/business/sdk/upload"
+)
+
+// chunkSize is the amount of data sent per INSTREAM chunk. It must stay
+// below the StreamMaxLength configured on the daemon.
+const chunkSize = 64 << 10
+
+// Client scans data by streaming it to a clamd daemon.
+type Client struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// New constructs a client for the daemon at the address, which is either
+// host:port, tcp://host:port or unix:///path/to/clamd.sock.
+func New(address string, timeout time.Duration) (*Client, error) {
+	network := "tcp"
+
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network = "unix"
+		address = strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+
+	if address == "" {
+		return nil, errors.New("clamd address is required")
+	}
+
+	c := Client{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+
+	return &c, nil
+}
+
+// Ping checks the daemon is reachable and answering commands.
+func (c *Client) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected ping reply %q", reply)
+	}
+
+	return nil
+}
+
+// Scan streams the data to the daemon with the INSTREAM command and reports
+// what it found.
+func (c *Client) Scan(ctx context.Context, r io.Reader) (upload.ScanResult, error) {
+	reply, err := c.command(ctx, "zINSTREAM\x00", r)
+	if err != nil {
+		return upload.ScanResult{}, err
+	}
+
+	return parseReply(reply)
+}
+
+// =============================================================================
+
+func (c *Client) command(ctx context.Context, cmd string, body io.Reader) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := io.WriteString(conn, cmd); err != nil {
+		return "", fmt.Errorf("write command: %w", err)
+	}
+
+	if body != nil {
+		if err := writeChunks(conn, body); err != nil {
+			return "", err
+		}
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read reply: %w", err)
+	}
+
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+// writeChunks sends the body as length prefixed chunks followed by the zero
+// length chunk that ends the stream.
+func writeChunks(w io.Writer, body io.Reader) error {
+	buf := make([]byte, 4+chunkSize)
+
+	for {
+		n, err := io.ReadFull(body, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, werr := w.Write(buf[:4+n]); werr != nil {
+				return fmt.Errorf("write chunk: %w", werr)
+			}
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read body: %w", err)
+		}
+	}
+
+	if _, err := w.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("write terminator: %w", err)
+	}
+
+	return nil
+}
+
+// parseReply interprets replies of the form "stream: OK",
+// "stream: <signature> FOUND" and "<message> ERROR".
+func parseReply(reply string) (upload.ScanResult, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case reply == "OK":
+		return upload.ScanResult{}, nil
+
+	case strings.HasSuffix(reply, " FOUND"):
+		result := upload.ScanResult{
+			Infected:  true,
+			Signature: strings.TrimSuffix(reply, " FOUND"),
+		}
+		return result, nil
+
+	case strings.HasSuffix(reply, " ERROR"):
+		return upload.ScanResult{}, fmt.Errorf("clamd: %s", strings.TrimSuffix(reply, " ERROR"))
+	}
+
+	return upload.ScanResult{}, fmt.Errorf("clamd: unexpected reply %q", reply)
+}
@@ -0,0 +1,117 @@
+package clamd_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/sdk/upload/clamd"
+)
+
+func Test_Scan(t *testing.T) {
+	addr := fakeDaemon(t)
+
+	client, err := clamd.New("tcp://"+addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("new: %s", err)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("ping: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want upload.ScanResult
+	}{
+		{name: "clean", data: []byte("hello"), want: upload.ScanResult{}},
+		{name: "large", data: bytes.Repeat([]byte("a"), 200<<10), want: upload.ScanResult{}},
+		{name: "eicar", data: []byte("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR"), want: upload.ScanResult{Infected: true, Signature: "Eicar-Test-Signature"}},
+	}
+
+	for _, tt := range tests {
+		got, err := client.Scan(context.Background(), bytes.NewReader(tt.data))
+		if err != nil {
+			t.Fatalf("%s: scan: %s", tt.name, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// fakeDaemon stands in for clamd. It answers PING and reassembles INSTREAM
+// chunks, flagging any stream that contains the EICAR marker.
+func fakeDaemon(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	cmd, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	switch strings.TrimRight(cmd, "\x00") {
+	case "zPING":
+		io.WriteString(conn, "PONG\x00")
+
+	case "zINSTREAM":
+		var data []byte
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			data = append(data, chunk...)
+		}
+
+		if bytes.Contains(data, []byte("EICAR")) {
+			io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+			return
+		}
+		io.WriteString(conn, "stream: OK\x00")
+
+	default:
+		io.WriteString(conn, "UNKNOWN COMMAND\x00")
+	}
+}
@@ -0,0 +1,38 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+)
+
+// ScanResult is the verdict of a malware scan.
+type ScanResult struct {
+	Infected  bool
+	Signature string
+}
+
+// Scanner defines the behavior required to scan file contents for malware.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (ScanResult, error)
+}
+
+// Scan rejects files the scanner reports as infected. When the scanner
+// can't be reached the upload fails rather than storing an unscanned file.
+func Scan(scanner Scanner) Validator {
+	f := func(ctx context.Context, file *File) error {
+		result, err := scanner.Scan(ctx, bytes.NewReader(file.Data))
+		if err != nil {
+			return fmt.Errorf("scan: %w", err)
+		}
+
+		if result.Infected {
+			return reject(FieldFile, "file failed the malware scan: %s", result.Signature)
+		}
+
+		return nil
+	}
+
+	return ValidatorFunc(f)
+}
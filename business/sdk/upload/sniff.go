@@ -0,0 +1,237 @@
+package upload
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// Set of content types the pipeline knows how to recognize.
+const (
+	TypePNG        = "image/png"
+	TypeJPEG       = "image/jpeg"
+	TypeGIF        = "image/gif"
+	TypeWebP       = "image/webp"
+	TypeBMP        = "image/bmp"
+	TypeTIFF       = "image/tiff"
+	TypeHEIC       = "image/heic"
+	TypeSVG        = "image/svg+xml"
+	TypeMP4        = "video/mp4"
+	TypeQuickTime  = "video/quicktime"
+	TypeMP3        = "audio/mpeg"
+	TypeOgg        = "audio/ogg"
+	TypeWAV        = "audio/wav"
+	TypePDF        = "application/pdf"
+	TypeDOCX       = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	TypeXLSX       = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	TypePPTX       = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	TypeZIP        = "application/zip"
+	TypeGzip       = "application/gzip"
+	Type7z         = "application/x-7z-compressed"
+	TypeRAR        = "application/vnd.rar"
+	TypeELF        = "application/x-executable"
+	TypePE         = "application/vnd.microsoft.portable-executable"
+	TypeMachO      = "application/x-mach-binary"
+	TypeHTML       = "text/html"
+	TypeText       = "text/plain"
+	TypeUnknown    = "application/octet-stream"
+	typeOOXMLProbe = "ooxml"
+)
+
+// fileType describes how to recognize a content type and which file name
+// extensions are consistent with it.
+type fileType struct {
+	mime  string
+	exts  []string
+	match func(data []byte) bool
+}
+
+// fileTypes is checked in order, so more specific signatures must come
+// before the general ones they overlap with.
+var fileTypes = []fileType{
+	{mime: TypePNG, exts: []string{".png"}, match: prefix("\x89PNG\r\n\x1a\n")},
+	{mime: TypeJPEG, exts: []string{".jpg", ".jpeg"}, match: prefix("\xff\xd8\xff")},
+	{mime: TypeGIF, exts: []string{".gif"}, match: prefix("GIF87a", "GIF89a")},
+	{mime: TypeWebP, exts: []string{".webp"}, match: riff("WEBP")},
+	{mime: TypeWAV, exts: []string{".wav"}, match: riff("WAVE")},
+	{mime: TypeBMP, exts: []string{".bmp"}, match: func(data []byte) bool { return len(data) >= 14 && bytes.HasPrefix(data, []byte("BM")) }},
+	{mime: TypeTIFF, exts: []string{".tif", ".tiff"}, match: prefix("II*\x00", "MM\x00*")},
+	{mime: TypeHEIC, exts: []string{".heic", ".heif"}, match: ftyp("heic", "heix", "mif1", "msf1")},
+	{mime: TypeQuickTime, exts: []string{".mov"}, match: ftyp("qt  ")},
+	{mime: TypeMP4, exts: []string{".mp4", ".m4v"}, match: ftyp()},
+	{mime: TypeMP3, exts: []string{".mp3"}, match: func(data []byte) bool {
+		return bytes.HasPrefix(data, []byte("ID3")) || (len(data) >= 2 && data[0] == 0xff && data[1]&0xe0 == 0xe0)
+	}},
+	{mime: TypeOgg, exts: []string{".ogg", ".oga", ".opus"}, match: prefix("OggS")},
+	{mime: TypePDF, exts: []string{".pdf"}, match: prefix("%PDF-")},
+	{mime: typeOOXMLProbe, match: prefix("PK\x03\x04")},
+	{mime: TypeZIP, exts: []string{".zip"}, match: prefix("PK\x03\x04", "PK\x05\x06")},
+	{mime: TypeGzip, exts: []string{".gz", ".tgz"}, match: prefix("\x1f\x8b")},
+	{mime: Type7z, exts: []string{".7z"}, match: prefix("7z\xbc\xaf\x27\x1c")},
+	{mime: TypeRAR, exts: []string{".rar"}, match: prefix("Rar!\x1a\x07")},
+	{mime: TypeELF, match: prefix("\x7fELF")},
+	{mime: TypePE, exts: []string{".exe", ".dll"}, match: prefix("MZ")},
+	{mime: TypeMachO, match: prefix("\xfe\xed\xfa\xce", "\xfe\xed\xfa\xcf", "\xce\xfa\xed\xfe", "\xcf\xfa\xed\xfe", "\xca\xfe\xba\xbe")},
+	{mime: TypeSVG, exts: []string{".svg"}, match: markup("<svg", "<?xml")},
+	{mime: TypeHTML, exts: []string{".html", ".htm"}, match: markup("<!doctype html", "<html", "<head", "<body", "<script", "<iframe")},
+	{mime: TypeText, exts: []string{".txt", ".md", ".csv", ".log"}, match: isText},
+}
+
+// ooxmlTypes maps the top level folder of an Office Open XML package to the
+// content type of the document.
+var ooxmlTypes = []fileType{
+	{mime: TypeDOCX, exts: []string{".docx"}, match: prefix("word/")},
+	{mime: TypeXLSX, exts: []string{".xlsx"}, match: prefix("xl/")},
+	{mime: TypePPTX, exts: []string{".pptx"}, match: prefix("ppt/")},
+}
+
+// Sniff determines the content type of the data from its leading bytes.
+// Data that is not recognized is reported as TypeUnknown. Unlike
+// http.DetectContentType it is safe to call on data of any length.
+func Sniff(data []byte) string {
+	for _, ft := range fileTypes {
+		if !ft.match(data) {
+			continue
+		}
+
+		if ft.mime == typeOOXMLProbe {
+			if mime := sniffOOXML(data); mime != "" {
+				return mime
+			}
+			continue
+		}
+
+		return ft.mime
+	}
+
+	return TypeUnknown
+}
+
+// Extensions returns the file name extensions consistent with the content
+// type.
+func Extensions(mime string) []string {
+	for _, ft := range ooxmlTypes {
+		if ft.mime == mime {
+			return ft.exts
+		}
+	}
+
+	for _, ft := range fileTypes {
+		if ft.mime == mime {
+			return ft.exts
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+func sniffOOXML(data []byte) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+
+	var contentTypes bool
+	for _, f := range zr.File {
+		if f.Name == "[Content_Types].xml" {
+			contentTypes = true
+			break
+		}
+	}
+
+	if !contentTypes {
+		return ""
+	}
+
+	for _, f := range zr.File {
+		for _, ft := range ooxmlTypes {
+			if ft.match([]byte(f.Name)) {
+				return ft.mime
+			}
+		}
+	}
+
+	return ""
+}
+
+func prefix(sigs ...string) func(data []byte) bool {
+	return func(data []byte) bool {
+		for _, sig := range sigs {
+			if bytes.HasPrefix(data, []byte(sig)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func riff(format string) func(data []byte) bool {
+	return func(data []byte) bool {
+		return len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && string(data[8:12]) == format
+	}
+}
+
+// ftyp matches ISO base media files, optionally restricted to a set of
+// major brands.
+func ftyp(brands ...string) func(data []byte) bool {
+	return func(data []byte) bool {
+		if len(data) < 12 || string(data[4:8]) != "ftyp" {
+			return false
+		}
+
+		if len(brands) == 0 {
+			return true
+		}
+
+		for _, brand := range brands {
+			if string(data[8:12]) == brand {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// markup matches text documents that open with one of the tags, ignoring
+// case, leading white space and a byte order mark.
+func markup(tags ...string) func(data []byte) bool {
+	return func(data []byte) bool {
+		head := data
+		if len(head) > 512 {
+			head = head[:512]
+		}
+
+		head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+		s := strings.ToLower(strings.TrimLeft(string(head), " \t\r\n"))
+
+		for _, tag := range tags {
+			if !strings.HasPrefix(s, tag) {
+				continue
+			}
+
+			// An XML prolog is only interesting when it introduces an SVG.
+			if tag == "<?xml" {
+				return strings.Contains(s, "<svg")
+			}
+			return true
+		}
+		return false
+	}
+}
+
+func isText(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,18 @@
+package upload
+
+// TestNewPipeline constructs a pipeline with the standard validators and no
+// malware scanner for use in tests.
+func TestNewPipeline() *Pipeline {
+	return NewPipeline(
+		Limit(nil, 5<<20),
+		SafeName(255),
+		Allow(DefaultAllowed...),
+		MatchExtension(),
+		GuardArchive(ArchiveLimits{
+			MaxEntries: 100,
+			MaxSize:    10 << 20,
+			MaxRatio:   100,
+			MaxDepth:   2,
+		}),
+	)
+}
@@ -0,0 +1,102 @@
+// Package upload provides an ordered pipeline of validators that files from
+// untrusted users must pass before they are stored.
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/himynamej/todo/business/types/role"
+)
+
+// Set of fields a rejection can be reported against.
+const (
+	FieldFile     = "file"
+	FieldFileName = "filename"
+)
+
+// File represents an uploaded file moving through the pipeline. ContentType
+// is filled in from the file contents by the Allow validator and should not
+// be trusted from the client.
+type File struct {
+	Name        string
+	Data        []byte
+	Roles       []role.Role
+	ContentType string
+}
+
+// Validator inspects a file and returns a Rejection when it must not be
+// stored. Any other error means the file could not be checked.
+type Validator interface {
+	Validate(ctx context.Context, file *File) error
+}
+
+// ValidatorFunc allows a function to be used as a Validator.
+type ValidatorFunc func(ctx context.Context, file *File) error
+
+// Validate implements the Validator interface.
+func (f ValidatorFunc) Validate(ctx context.Context, file *File) error {
+	return f(ctx, file)
+}
+
+// Pipeline runs a set of validators in order and stops at the first one
+// that refuses the file.
+type Pipeline struct {
+	validators []Validator
+}
+
+// NewPipeline constructs a pipeline that runs the validators in the order
+// they are provided. Cheap checks should come before expensive ones.
+func NewPipeline(validators ...Validator) *Pipeline {
+	return &Pipeline{
+		validators: validators,
+	}
+}
+
+// Run passes the file through every validator and returns the file as
+// updated by them.
+func (p *Pipeline) Run(ctx context.Context, file File) (File, error) {
+	for _, v := range p.validators {
+		if err := v.Validate(ctx, &file); err != nil {
+			return File{}, err
+		}
+	}
+
+	return file, nil
+}
+
+// =============================================================================
+
+// Rejection is returned when a validator refuses a file.
+type Rejection struct {
+	Field  string
+	Reason string
+}
+
+func reject(field string, format string, args ...any) *Rejection {
+	return &Rejection{
+		Field:  field,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error implements the error interface.
+func (r *Rejection) Error() string {
+	return r.Reason
+}
+
+// IsRejection checks if a Rejection exists in the error chain.
+func IsRejection(err error) bool {
+	var r *Rejection
+	return errors.As(err, &r)
+}
+
+// GetRejection returns the Rejection from the error chain or nil.
+func GetRejection(err error) *Rejection {
+	var r *Rejection
+	if !errors.As(err, &r) {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,183 @@
+package upload_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/himynamej/todo/business/sdk/upload"
+	"github.com/himynamej/todo/business/types/role"
+)
+
+func Test_Sniff(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "empty", data: nil, want: upload.TypeUnknown},
+		{name: "short", data: []byte("hi"), want: upload.TypeText},
+		{name: "png", data: []byte("\x89PNG\r\n\x1a\n0000"), want: upload.TypePNG},
+		{name: "jpeg", data: []byte("\xff\xd8\xff\xe0"), want: upload.TypeJPEG},
+		{name: "webp", data: []byte("RIFF0000WEBPVP8 "), want: upload.TypeWebP},
+		{name: "pdf", data: []byte("%PDF-1.7\n"), want: upload.TypePDF},
+		{name: "mp4", data: []byte("\x00\x00\x00\x18ftypisom"), want: upload.TypeMP4},
+		{name: "heic", data: []byte("\x00\x00\x00\x18ftypheic"), want: upload.TypeHEIC},
+		{name: "elf", data: []byte("\x7fELF\x02\x01"), want: upload.TypeELF},
+		{name: "pe", data: []byte("MZ\x90\x00"), want: upload.TypePE},
+		{name: "html", data: []byte("\xef\xbb\xbf  <!DOCTYPE html><p>"), want: upload.TypeHTML},
+		{name: "svg", data: []byte(`<?xml version="1.0"?><svg></svg>`), want: upload.TypeSVG},
+		{name: "binary", data: []byte{0x00, 0x01, 0x02}, want: upload.TypeUnknown},
+		{name: "zip", data: zipOf(t, map[string][]byte{"a.txt": []byte("a")}), want: upload.TypeZIP},
+		{name: "docx", data: zipOf(t, map[string][]byte{"[Content_Types].xml": nil, "word/document.xml": nil}), want: upload.TypeDOCX},
+	}
+
+	for _, tt := range tests {
+		if got := upload.Sniff(tt.data); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_Pipeline(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+
+	pipeline := upload.NewPipeline(
+		upload.Limit(map[role.Role]int{role.Admin: 64}, 32),
+		upload.SafeName(64),
+		upload.Allow(upload.DefaultAllowed...),
+		upload.MatchExtension(),
+		upload.GuardArchive(upload.ArchiveLimits{MaxEntries: 10, MaxSize: 1 << 10, MaxRatio: 100, MaxDepth: 1}),
+	)
+
+	tests := []struct {
+		name  string
+		file  upload.File
+		field string
+	}{
+		{name: "ok", file: upload.File{Name: "a.png", Data: png}},
+		{name: "small-text", file: upload.File{Name: "a.txt", Data: []byte("hi")}},
+		{name: "empty", file: upload.File{Name: "a.txt"}, field: upload.FieldFile},
+		{name: "too-large", file: upload.File{Name: "a.txt", Data: bytes.Repeat([]byte("a"), 40)}, field: upload.FieldFile},
+		{name: "admin-limit", file: upload.File{Name: "a.txt", Data: bytes.Repeat([]byte("a"), 40), Roles: []role.Role{role.Admin}}},
+		{name: "path", file: upload.File{Name: "../a.png", Data: png}, field: upload.FieldFileName},
+		{name: "executable", file: upload.File{Name: "a.png", Data: []byte("MZ\x90\x00")}, field: upload.FieldFile},
+		{name: "html", file: upload.File{Name: "a.txt", Data: []byte("<script>x</script>")}, field: upload.FieldFile},
+		{name: "mismatch", file: upload.File{Name: "a.jpg", Data: png}, field: upload.FieldFileName},
+		{name: "no-extension", file: upload.File{Name: "a", Data: png}, field: upload.FieldFileName},
+	}
+
+	for _, tt := range tests {
+		_, err := pipeline.Run(context.Background(), tt.file)
+
+		switch tt.field {
+		case "":
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tt.name, err)
+			}
+
+		default:
+			rej := upload.GetRejection(err)
+			if rej == nil {
+				t.Errorf("%s: expected a rejection, got %v", tt.name, err)
+				continue
+			}
+			if rej.Field != tt.field {
+				t.Errorf("%s: got field %s, want %s", tt.name, rej.Field, tt.field)
+			}
+		}
+	}
+}
+
+func Test_GuardArchive(t *testing.T) {
+	limits := upload.ArchiveLimits{MaxEntries: 3, MaxSize: 2 << 20, MaxRatio: 100, MaxDepth: 1}
+	guard := upload.GuardArchive(limits)
+
+	inner := zipOf(t, map[string][]byte{"a.txt": []byte("a")})
+
+	tests := []struct {
+		name   string
+		data   []byte
+		reject bool
+	}{
+		{name: "small", data: zipOf(t, map[string][]byte{"a.txt": []byte("hello")})},
+		{name: "nested", data: zipOf(t, map[string][]byte{"inner.zip": inner})},
+		{name: "too-deep", data: zipOf(t, map[string][]byte{"outer.zip": zipOf(t, map[string][]byte{"inner.zip": inner})}), reject: true},
+		{name: "entries", data: zipOf(t, map[string][]byte{"a": nil, "b": nil, "c": nil, "d": nil}), reject: true},
+		{name: "size", data: zipOf(t, map[string][]byte{"a": make([]byte, 3<<20)}), reject: true},
+		{name: "ratio", data: zipOf(t, map[string][]byte{"a": make([]byte, 3<<19)}), reject: true},
+	}
+
+	for _, tt := range tests {
+		file := upload.File{Name: "a.zip", Data: tt.data}
+
+		err := guard.Validate(context.Background(), &file)
+		if got := upload.IsRejection(err); got != tt.reject {
+			t.Errorf("%s: got rejection %v, want %v: %v", tt.name, got, tt.reject, err)
+		}
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanner upload.Scanner
+		reject  bool
+		fail    bool
+	}{
+		{name: "clean", scanner: scannerFunc(func() (upload.ScanResult, error) { return upload.ScanResult{}, nil })},
+		{name: "infected", scanner: scannerFunc(func() (upload.ScanResult, error) {
+			return upload.ScanResult{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+		}), reject: true},
+		{name: "unreachable", scanner: scannerFunc(func() (upload.ScanResult, error) {
+			return upload.ScanResult{}, errors.New("connection refused")
+		}), fail: true},
+	}
+
+	for _, tt := range tests {
+		file := upload.File{Name: "a.txt", Data: []byte("hello")}
+
+		err := upload.Scan(tt.scanner).Validate(context.Background(), &file)
+
+		if got := upload.IsRejection(err); got != tt.reject {
+			t.Errorf("%s: got rejection %v, want %v", tt.name, got, tt.reject)
+		}
+		if got := err != nil && !upload.IsRejection(err); got != tt.fail {
+			t.Errorf("%s: got failure %v, want %v", tt.name, got, tt.fail)
+		}
+	}
+}
+
+// =============================================================================
+
+type scannerFunc func() (upload.ScanResult, error)
+
+func (f scannerFunc) Scan(ctx context.Context, r io.Reader) (upload.ScanResult, error) {
+	return f()
+}
+
+func zipOf(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %s", name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("write %s: %s", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close: %s", err)
+	}
+
+	return buf.Bytes()
+}
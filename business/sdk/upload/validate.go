@@ -0,0 +1,109 @@
+package upload
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/himynamej/todo/business/types/role"
+)
+
+// DefaultAllowed is the set of content types accepted when a pipeline does
+// not specify its own. Executables, markup that a browser would render and
+// archives that cannot be inspected are deliberately left out.
+var DefaultAllowed = []string{
+	TypePNG, TypeJPEG, TypeGIF, TypeWebP, TypeHEIC,
+	TypeMP4, TypeQuickTime, TypeMP3, TypeOgg, TypeWAV,
+	TypePDF, TypeDOCX, TypeXLSX, TypePPTX, TypeZIP,
+	TypeText,
+}
+
+// Limit rejects empty files and files larger than the limit of the most
+// generous role the uploader holds. Uploaders without a listed role get the
+// fallback limit.
+func Limit(limits map[role.Role]int, fallback int) Validator {
+	f := func(ctx context.Context, file *File) error {
+		max := fallback
+		for _, r := range file.Roles {
+			if limit, exists := limits[r]; exists && limit > max {
+				max = limit
+			}
+		}
+
+		switch {
+		case len(file.Data) == 0:
+			return reject(FieldFile, "file is empty")
+		case len(file.Data) > max:
+			return reject(FieldFile, "file size %d exceeds the maximum allowed size of %d bytes", len(file.Data), max)
+		}
+
+		return nil
+	}
+
+	return ValidatorFunc(f)
+}
+
+// SafeName rejects file names that could be used to escape a storage path or
+// confuse whoever later downloads the file.
+func SafeName(maxLen int) Validator {
+	f := func(ctx context.Context, file *File) error {
+		name := file.Name
+
+		switch {
+		case strings.TrimSpace(name) == "":
+			return reject(FieldFileName, "file name is required")
+		case len(name) > maxLen:
+			return reject(FieldFileName, "file name must not be longer than %d characters", maxLen)
+		case strings.ContainsAny(name, `/\`), name == ".", name == "..":
+			return reject(FieldFileName, "file name must not contain a path")
+		case strings.ContainsFunc(name, unicode.IsControl):
+			return reject(FieldFileName, "file name must not contain control characters")
+		}
+
+		return nil
+	}
+
+	return ValidatorFunc(f)
+}
+
+// Allow sniffs the content type from the file contents, records it on the
+// file, and rejects anything not in the allowed set.
+func Allow(types ...string) Validator {
+	f := func(ctx context.Context, file *File) error {
+		file.ContentType = Sniff(file.Data)
+
+		if !slices.Contains(types, file.ContentType) {
+			return reject(FieldFile, "file type %s is not allowed", file.ContentType)
+		}
+
+		return nil
+	}
+
+	return ValidatorFunc(f)
+}
+
+// MatchExtension rejects files whose name extension does not agree with the
+// sniffed content type, so a script can't be passed off as a picture.
+func MatchExtension() Validator {
+	f := func(ctx context.Context, file *File) error {
+		if file.ContentType == "" {
+			file.ContentType = Sniff(file.Data)
+		}
+
+		ext := strings.ToLower(filepath.Ext(file.Name))
+		if ext == "" {
+			return reject(FieldFileName, "file name must have an extension")
+		}
+
+		exts := Extensions(file.ContentType)
+		if !slices.Contains(exts, ext) {
+			return reject(FieldFileName, "extension %s does not match file type %s", ext, file.ContentType)
+		}
+
+		return nil
+	}
+
+	return ValidatorFunc(f)
+}
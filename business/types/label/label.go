@@ -0,0 +1,93 @@
+// Package label represents a label that can be attached to a todo item.
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Label represents a label in the system. Labels are case insensitive and
+// kept in lower case.
+type Label struct {
+	value string
+}
+
+// String returns the value of the label.
+func (l Label) String() string {
+	return l.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (l Label) Equal(l2 Label) bool {
+	return l.value == l2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (l Label) MarshalText() ([]byte, error) {
+	return []byte(l.value), nil
+}
+
+// =============================================================================
+
+var labelRegEx = regexp.MustCompile("^[a-z0-9][a-z0-9_-]{0,29}$")
+
+// Parse parses the string value and returns a label if the value complies
+// with the rules for a label.
+func Parse(value string) (Label, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if !labelRegEx.MatchString(value) {
+		return Label{}, fmt.Errorf("invalid label %q", value)
+	}
+
+	return Label{value}, nil
+}
+
+// MustParse parses the string value and returns a label if the value
+// complies with the rules for a label. If an error occurs the function panics.
+func MustParse(value string) Label {
+	l, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return l
+}
+
+// ParseMany takes a collection of strings and converts them to a distinct
+// slice of labels, keeping the order they were first seen in.
+func ParseMany(values []string) ([]Label, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	labels := make([]Label, 0, len(values))
+	for _, value := range values {
+		l, err := Parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[l.value] {
+			continue
+		}
+		seen[l.value] = true
+
+		labels = append(labels, l)
+	}
+
+	return labels, nil
+}
+
+// ParseToString takes a collection of labels and converts them to a slice
+// of strings.
+func ParseToString(labels []Label) []string {
+	values := make([]string, len(labels))
+	for i, l := range labels {
+		values[i] = l.value
+	}
+
+	return values
+}
@@ -0,0 +1,47 @@
+package label_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/himynamej/todo/business/types/label"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "bug", want: "bug"},
+		{value: "  Urgent ", want: "urgent"},
+		{value: "q3-goals", want: "q3-goals"},
+		{value: "", wantErr: true},
+		{value: "-leading", wantErr: true},
+		{value: "has space", wantErr: true},
+		{value: "abcdefghijklmnopqrstuvwxyz01234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		l, err := label.Parse(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%q: got err %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+
+		if err == nil && l.String() != tt.want {
+			t.Fatalf("%q: got %q, want %q", tt.value, l.String(), tt.want)
+		}
+	}
+}
+
+func Test_ParseMany(t *testing.T) {
+	labels, err := label.ParseMany([]string{"Bug", "ui", "bug"})
+	if err != nil {
+		t.Fatalf("parse: %s", err)
+	}
+
+	got := label.ParseToString(labels)
+	if diff := cmp.Diff(got, []string{"bug", "ui"}); diff != "" {
+		t.Fatal(diff)
+	}
+}
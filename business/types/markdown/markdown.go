@@ -0,0 +1,141 @@
+// Package markdown represents sanitized markdown text in the system.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength is the largest number of characters a markdown value can hold.
+const MaxLength = 10_000
+
+// Markdown represents sanitized markdown text in the system.
+type Markdown struct {
+	value string
+}
+
+// String returns the value of the markdown.
+func (m Markdown) String() string {
+	return m.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (m Markdown) Equal(m2 Markdown) bool {
+	return m.value == m2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (m Markdown) MarshalText() ([]byte, error) {
+	return []byte(m.value), nil
+}
+
+// =============================================================================
+
+// destRegExs match the places a markdown link destination can appear: the
+// "(destination)" part of an inline "[text](destination)" link or image and
+// the destination of a "[label]: destination" reference definition. The
+// definition is matched wherever it appears since a line nested in a list or
+// block quote is still a definition to a renderer.
+var destRegExs = []*regexp.Regexp{
+	regexp.MustCompile(`\]\(\s*([^)\s]*)`),
+	regexp.MustCompile(`\]:[ \t]*\n?[ \t]*(\S+)`),
+}
+
+// safeSchemes are the URL schemes a link destination is allowed to use.
+var safeSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// Parse sanitizes the string value and returns a markdown if the value
+// complies with the rules for markdown. Raw HTML is escaped so it renders as
+// text and link destinations using a scheme other than http, https or mailto
+// are replaced.
+func Parse(value string) (Markdown, error) {
+	if !utf8.ValidString(value) {
+		return Markdown{}, fmt.Errorf("invalid markdown: not valid utf8")
+	}
+
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return Markdown{}, fmt.Errorf("invalid markdown: empty")
+	}
+
+	if n := utf8.RuneCountInString(value); n > MaxLength {
+		return Markdown{}, fmt.Errorf("invalid markdown: length %d exceeds %d", n, MaxLength)
+	}
+
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, value)
+
+	value = strings.ReplaceAll(value, "<", "&lt;")
+
+	for _, re := range destRegExs {
+		value = sanitizeDestinations(re, value)
+	}
+
+	return Markdown{value}, nil
+}
+
+// MustParse parses the string value and returns a markdown if the value
+// complies with the rules for markdown. If an error occurs the function panics.
+func MustParse(value string) Markdown {
+	md, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return md
+}
+
+// sanitizeDestinations replaces every destination captured by the expression
+// that is not safe with "#".
+func sanitizeDestinations(re *regexp.Regexp, value string) string {
+	var b strings.Builder
+	var last int
+
+	for _, idx := range re.FindAllStringSubmatchIndex(value, -1) {
+		start, end := idx[2], idx[3]
+		if isSafeDestination(value[start:end]) {
+			continue
+		}
+
+		b.WriteString(value[last:start])
+		b.WriteString("#")
+		last = end
+	}
+
+	b.WriteString(value[last:])
+
+	return b.String()
+}
+
+// isSafeDestination reports whether a link destination is relative or uses
+// one of the allowed schemes.
+func isSafeDestination(dest string) bool {
+
+	// Renderers decode entities inside destinations, so "javascript&colon;"
+	// must be checked in its decoded form.
+	scheme, _, found := strings.Cut(html.UnescapeString(dest), ":")
+	if !found {
+		return true
+	}
+
+	// A colon after a path or query separator is not a scheme.
+	if strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+
+	return safeSchemes[strings.ToLower(strings.TrimSpace(scheme))]
+}
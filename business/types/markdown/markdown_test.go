@@ -0,0 +1,61 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/himynamej/todo/business/types/markdown"
+)
+
+func Test_Parse(t *testing.T) {
+	table := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{"plain", "**bold** and _italic_", "**bold** and _italic_"},
+		{"trim", "  text\r\n", "text"},
+		{"html", "<script>alert(1)</script>", "&lt;script>alert(1)&lt;/script>"},
+		{"http-link", "[site](https://example.com)", "[site](https://example.com)"},
+		{"relative-link", "[doc](/docs/a:b)", "[doc](/docs/a:b)"},
+		{"js-link", "[x](javascript:alert(1))", "[x](#))"},
+		{"entity-link", "[x](javascript&colon;alert)", "[x](#)"},
+		{"numeric-entity-link", "[x](&#106;avascript:alert(1))", "[x](#))"},
+		{"split-link", "[x](\n javascript:alert)", "[x](\n #)"},
+		{"http-definition", "[x]\n\n[x]: https://example.com", "[x]\n\n[x]: https://example.com"},
+		{"js-definition", "[x]\n\n[x]: javascript:alert(1)", "[x]\n\n[x]: #"},
+		{"next-line-definition", "[x]\n\n[x]:\n  &#106;avascript:alert", "[x]\n\n[x]:\n  #"},
+		{"nested-definition", "> - [x]: <javascript:alert>", "> - [x]: #"},
+		{"data-image", "![x](data:text/html;base64,AAAA)", "![x](#)"},
+		{"control", "a\x00b\tc", "ab\tc"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := markdown.Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Should be able to parse the markdown: %s", err)
+			}
+
+			if md.String() != tt.exp {
+				t.Fatalf("Should get the expected markdown: got %q, exp %q", md.String(), tt.exp)
+			}
+		})
+	}
+}
+
+func Test_ParseInvalid(t *testing.T) {
+	table := map[string]string{
+		"empty":    "   ",
+		"too-long": strings.Repeat("a", markdown.MaxLength+1),
+		"bad-utf8": "\xff",
+	}
+
+	for name, in := range table {
+		t.Run(name, func(t *testing.T) {
+			if _, err := markdown.Parse(in); err == nil {
+				t.Fatal("Should NOT be able to parse the markdown")
+			}
+		})
+	}
+}
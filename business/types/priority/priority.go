@@ -0,0 +1,97 @@
+// Package priority represents the priority of a todo item in the system.
+package priority
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The set of priorities that can be used, from the most to the least urgent.
+// Items are given the lowest priority unless another one is chosen.
+var (
+	P1 = newPriority("P1")
+	P2 = newPriority("P2")
+	P3 = newPriority("P3")
+	P4 = newPriority("P4")
+)
+
+// Default is the priority of an item when no other one was chosen.
+var Default = P4
+
+// =============================================================================
+
+// Set of known priorities.
+var priorities = make(map[string]Priority)
+
+// Priority represents a todo item priority in the system.
+type Priority struct {
+	value string
+}
+
+func newPriority(priority string) Priority {
+	p := Priority{priority}
+	priorities[priority] = p
+	return p
+}
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	return p.value
+}
+
+// IsZero reports whether no priority was set.
+func (p Priority) IsZero() bool {
+	return p.value == ""
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (p Priority) Equal(p2 Priority) bool {
+	return p.value == p2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.value), nil
+}
+
+// UnmarshalText provides support for decoding the parameters of events that
+// carry a priority.
+func (p *Priority) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*p = Priority{}
+		return nil
+	}
+
+	pri, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+
+	*p = pri
+
+	return nil
+}
+
+// =============================================================================
+
+// Parse parses the string value and returns a priority if one exists. The
+// value is case insensitive.
+func Parse(value string) (Priority, error) {
+	priority, exists := priorities[strings.ToUpper(value)]
+	if !exists {
+		return Priority{}, fmt.Errorf("invalid priority %q", value)
+	}
+
+	return priority, nil
+}
+
+// MustParse parses the string value and returns a priority if one exists. If
+// an error occurs the function panics.
+func MustParse(value string) Priority {
+	priority, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return priority
+}
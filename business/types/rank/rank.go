@@ -0,0 +1,203 @@
+// Package rank represents the position of a todo item within a column of a
+// board.
+//
+// A rank is a fraction between 0 and 1 written in base 62 without the
+// leading "0.", so ranks sort the way their strings do when compared byte by
+// byte. There is always room for another rank between any two of them, which
+// lets an item be moved by changing its own rank only.
+package rank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// digits holds the digits of a rank in ascending byte order.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// MaxLength is the length above which the ranks of a column are spread out
+// again. Ranks grow by about one digit for every handful of items dropped in
+// the same spot.
+const MaxLength = 24
+
+// Rank represents the position of an item in a column. The zero value is an
+// item that has no position yet, which comes after every ranked item.
+type Rank struct {
+	value string
+}
+
+// String returns the value of the rank.
+func (r Rank) String() string {
+	return r.value
+}
+
+// IsZero reports whether the rank is unset.
+func (r Rank) IsZero() bool {
+	return r.value == ""
+}
+
+// Less reports whether the rank comes before r2. Unset ranks come last.
+func (r Rank) Less(r2 Rank) bool {
+	switch {
+	case r.value == "":
+		return false
+	case r2.value == "":
+		return true
+	default:
+		return r.value < r2.value
+	}
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Rank) Equal(r2 Rank) bool {
+	return r.value == r2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r Rank) MarshalText() ([]byte, error) {
+	return []byte(r.value), nil
+}
+
+// =============================================================================
+
+// Parse parses the string value and returns a rank if the value complies
+// with the rules for a rank. An empty value is the zero rank.
+func Parse(value string) (Rank, error) {
+	if value == "" {
+		return Rank{}, nil
+	}
+
+	for i := 0; i < len(value); i++ {
+		if strings.IndexByte(digits, value[i]) == -1 {
+			return Rank{}, fmt.Errorf("invalid rank %q", value)
+		}
+	}
+
+	// A trailing zero adds nothing to the fraction, so there would be no rank
+	// between the value with and without it.
+	if value[len(value)-1] == digits[0] {
+		return Rank{}, fmt.Errorf("invalid rank %q: trailing %c", value, digits[0])
+	}
+
+	return Rank{value}, nil
+}
+
+// MustParse parses the string value and returns a rank if the value
+// complies with the rules for a rank. If an error occurs the function panics.
+func MustParse(value string) Rank {
+	r, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Between returns a rank that comes after a and before b. A zero a stands
+// for the start of the column and a zero b for its end.
+func Between(a Rank, b Rank) (Rank, error) {
+	if !a.IsZero() && !b.IsZero() && a.value >= b.value {
+		return Rank{}, fmt.Errorf("rank %q is not before %q", a.value, b.value)
+	}
+
+	return Rank{midpoint(a.value, b.value)}, nil
+}
+
+// Spread returns n ranks in ascending order that are evenly spaced and as
+// short as the spacing allows.
+func Spread(n int) []Rank {
+	if n <= 0 {
+		return nil
+	}
+
+	// Leave room for a few dozen moves between every two ranks before they
+	// need another digit.
+	width := 1
+	for span := base; span < (n+1)*base; span *= base {
+		width++
+	}
+
+	span := 1
+	for range width {
+		span *= base
+	}
+
+	ranks := make([]Rank, n)
+	for i := range ranks {
+		ranks[i] = Rank{format((i+1)*span/(n+1), width)}
+	}
+
+	return ranks
+}
+
+// =============================================================================
+
+// midpoint returns the fraction halfway between a and b, or between a and 1
+// when b is empty. A common prefix is kept as is and the digits after it are
+// split.
+func midpoint(a string, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+
+	db := base
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+
+	if db-da > 1 {
+		return string(digits[(da+db+1)/2])
+	}
+
+	// The first digits are next to each other. A longer b can be cut short
+	// after its first digit, otherwise the next digit of a is split with 1.
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+
+	return string(digits[da]) + midpoint(rest, "")
+}
+
+// digitAt returns the digit of the fraction at position i, reading zeros
+// past the end of it.
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+// format writes v as a fraction of width digits with the trailing zeros
+// removed.
+func format(v int, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[v%base]
+		v /= base
+	}
+
+	return strings.TrimRight(string(buf), digits[:1])
+}
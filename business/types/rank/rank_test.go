@@ -0,0 +1,119 @@
+package rank_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/himynamej/todo/business/types/rank"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: ""},
+		{value: "V"},
+		{value: "0z1"},
+		{value: "V0", wantErr: true},
+		{value: "a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		r, err := rank.Parse(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%q: got err %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+
+		if err == nil && r.String() != tt.value {
+			t.Fatalf("%q: got %q", tt.value, r.String())
+		}
+	}
+}
+
+func Test_Between(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{a: "", b: "", want: "V"},
+		{a: "V", b: "", want: "l"},
+		{a: "", b: "V", want: "G"},
+		{a: "A", b: "B", want: "AV"},
+		{a: "A", b: "AV", want: "AG"},
+		{a: "z", b: "", want: "zV"},
+		{a: "", b: "01", want: "00V"},
+		{a: "Az", b: "B1", want: "B"},
+	}
+
+	for _, tt := range tests {
+		got, err := rank.Between(rank.MustParse(tt.a), rank.MustParse(tt.b))
+		if err != nil {
+			t.Fatalf("%q, %q: %s", tt.a, tt.b, err)
+		}
+
+		if got.String() != tt.want {
+			t.Errorf("%q, %q: got %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := rank.Between(rank.MustParse("B"), rank.MustParse("A")); err == nil {
+		t.Error("expected an error for ranks out of order")
+	}
+}
+
+func Test_BetweenRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// Drop items at random places in a column and check it stays in order.
+	column := []rank.Rank{}
+	for range 2000 {
+		i := rnd.Intn(len(column) + 1)
+
+		var a, b rank.Rank
+		if i > 0 {
+			a = column[i-1]
+		}
+		if i < len(column) {
+			b = column[i]
+		}
+
+		r, err := rank.Between(a, b)
+		if err != nil {
+			t.Fatalf("between %q and %q: %s", a, b, err)
+		}
+
+		if _, err := rank.Parse(r.String()); err != nil {
+			t.Fatalf("between %q and %q: %s", a, b, err)
+		}
+
+		if !a.IsZero() && !a.Less(r) || !b.IsZero() && !r.Less(b) {
+			t.Fatalf("got %q, not between %q and %q", r, a, b)
+		}
+
+		column = append(column[:i], append([]rank.Rank{r}, column[i:]...)...)
+	}
+}
+
+func Test_Spread(t *testing.T) {
+	for _, n := range []int{1, 2, 61, 62, 500, 10000} {
+		ranks := rank.Spread(n)
+		if len(ranks) != n {
+			t.Fatalf("%d: got %d ranks", n, len(ranks))
+		}
+
+		for i, r := range ranks {
+			if _, err := rank.Parse(r.String()); err != nil {
+				t.Fatalf("%d: %s", n, err)
+			}
+
+			if i > 0 && !ranks[i-1].Less(r) {
+				t.Fatalf("%d: %q is not before %q", n, ranks[i-1], r)
+			}
+
+			if len(r.String()) > rank.MaxLength {
+				t.Fatalf("%d: %q is too long", n, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package recurrence represents how often a todo item repeats.
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The set of frequencies a rule can repeat at.
+var (
+	Daily   = newFrequency("DAILY")
+	Weekly  = newFrequency("WEEKLY")
+	Monthly = newFrequency("MONTHLY")
+	Yearly  = newFrequency("YEARLY")
+)
+
+// maxInterval is the largest number of periods between two occurrences.
+const maxInterval = 999
+
+// =============================================================================
+
+// Set of known frequencies.
+var frequencies = make(map[string]Frequency)
+
+// Frequency represents the period a rule repeats at.
+type Frequency struct {
+	value string
+}
+
+func newFrequency(frequency string) Frequency {
+	f := Frequency{frequency}
+	frequencies[frequency] = f
+	return f
+}
+
+// String returns the name of the frequency.
+func (f Frequency) String() string {
+	return f.value
+}
+
+// ParseFrequency parses the string value and returns a frequency if one
+// exists.
+func ParseFrequency(value string) (Frequency, error) {
+	frequency, exists := frequencies[strings.ToUpper(value)]
+	if !exists {
+		return Frequency{}, fmt.Errorf("invalid frequency %q", value)
+	}
+
+	return frequency, nil
+}
+
+// =============================================================================
+
+// Rule represents how often a todo item repeats. Rules are written like a
+// small subset of an iCalendar RRULE, such as "FREQ=MONTHLY;INTERVAL=1;
+// BYMONTHDAY=1". The zero rule means the item does not repeat.
+type Rule struct {
+	frequency Frequency
+	interval  int
+	monthDay  int
+}
+
+// New constructs a rule that repeats every interval periods of the
+// frequency. Monthly and yearly rules may pin the day of the month, so
+// occurrences that had to move to the end of a shorter month move back
+// after it. A zero month day keeps the day of the previous occurrence.
+func New(frequency Frequency, interval int, monthDay int) (Rule, error) {
+	if _, exists := frequencies[frequency.value]; !exists {
+		return Rule{}, fmt.Errorf("invalid frequency %q", frequency.value)
+	}
+
+	if interval < 1 || interval > maxInterval {
+		return Rule{}, fmt.Errorf("interval must be between 1 and %d", maxInterval)
+	}
+
+	if monthDay < 0 || monthDay > 31 {
+		return Rule{}, fmt.Errorf("month day must be between 1 and 31")
+	}
+
+	if monthDay != 0 && frequency != Monthly && frequency != Yearly {
+		return Rule{}, fmt.Errorf("month day only applies to monthly and yearly rules")
+	}
+
+	r := Rule{
+		frequency: frequency,
+		interval:  interval,
+		monthDay:  monthDay,
+	}
+
+	return r, nil
+}
+
+// MustNew constructs a rule. If an error occurs the function panics.
+func MustNew(frequency Frequency, interval int, monthDay int) Rule {
+	r, err := New(frequency, interval, monthDay)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Valid reports whether the rule repeats at all.
+func (r Rule) Valid() bool {
+	return r.interval > 0
+}
+
+// Frequency returns the period the rule repeats at.
+func (r Rule) Frequency() Frequency {
+	return r.frequency
+}
+
+// Interval returns the number of periods between two occurrences.
+func (r Rule) Interval() int {
+	return r.interval
+}
+
+// MonthDay returns the day of the month the rule is pinned to, zero when it
+// is not.
+func (r Rule) MonthDay() int {
+	return r.monthDay
+}
+
+// String returns the rule in its written form, empty for the zero rule.
+func (r Rule) String() string {
+	if !r.Valid() {
+		return ""
+	}
+
+	s := fmt.Sprintf("FREQ=%s;INTERVAL=%d", r.frequency.value, r.interval)
+	if r.monthDay != 0 {
+		s += fmt.Sprintf(";BYMONTHDAY=%d", r.monthDay)
+	}
+
+	return s
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (r Rule) Equal(r2 Rule) bool {
+	return r == r2
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (r Rule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText provides support for decoding the parameters of events that
+// carry a rule.
+func (r *Rule) UnmarshalText(data []byte) error {
+	rule, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = rule
+
+	return nil
+}
+
+// Next returns the occurrence that follows the one at t, at the same time of
+// day in the location of t.
+func (r Rule) Next(t time.Time) time.Time {
+	switch r.frequency {
+	case Daily:
+		return t.AddDate(0, 0, r.interval)
+
+	case Weekly:
+		return t.AddDate(0, 0, 7*r.interval)
+
+	case Monthly:
+		return r.addMonths(t, r.interval)
+
+	case Yearly:
+		return r.addMonths(t, 12*r.interval)
+	}
+
+	return t
+}
+
+// addMonths moves t the number of months forward, onto the pinned day or
+// the day of t, or the last day of the month when it is shorter.
+func (r Rule) addMonths(t time.Time, months int) time.Time {
+	day := r.monthDay
+	if day == 0 {
+		day = t.Day()
+	}
+
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// =============================================================================
+
+// Parse parses the written form of a rule. An empty value is the zero rule.
+func Parse(value string) (Rule, error) {
+	if value == "" {
+		return Rule{}, nil
+	}
+
+	var frequency Frequency
+	interval := 1
+	var monthDay int
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid rule %q", value)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			frequency, err = ParseFrequency(val)
+		case "INTERVAL":
+			interval, err = strconv.Atoi(val)
+		case "BYMONTHDAY":
+			monthDay, err = strconv.Atoi(val)
+		default:
+			err = fmt.Errorf("unsupported part %q", key)
+		}
+
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid rule %q: %w", value, err)
+		}
+	}
+
+	return New(frequency, interval, monthDay)
+}
+
+// MustParse parses the written form of a rule. If an error occurs the
+// function panics.
+func MustParse(value string) Rule {
+	r, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
@@ -0,0 +1,103 @@
+package recurrence_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/business/types/recurrence"
+)
+
+func Test_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		rule recurrence.Rule
+		from time.Time
+		exp  time.Time
+	}{
+		{
+			name: "daily-dst",
+			rule: recurrence.MustNew(recurrence.Daily, 1, 0),
+			from: time.Date(2024, time.March, 9, 9, 0, 0, 0, newYork),
+			exp:  time.Date(2024, time.March, 10, 9, 0, 0, 0, newYork),
+		},
+		{
+			name: "weekly",
+			rule: recurrence.MustNew(recurrence.Weekly, 2, 0),
+			from: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+			exp:  time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly-short-month",
+			rule: recurrence.MustNew(recurrence.Monthly, 1, 31),
+			from: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC),
+			exp:  time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly-back-to-pinned-day",
+			rule: recurrence.MustNew(recurrence.Monthly, 1, 31),
+			from: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			exp:  time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly-unpinned",
+			rule: recurrence.MustNew(recurrence.Monthly, 3, 0),
+			from: time.Date(2024, time.November, 15, 0, 0, 0, 0, time.UTC),
+			exp:  time.Date(2025, time.February, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "yearly-leap-day",
+			rule: recurrence.MustNew(recurrence.Yearly, 1, 29),
+			from: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			exp:  time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.rule.Next(tt.from); !got.Equal(tt.exp) {
+			t.Errorf("%s: got %s, exp %s", tt.name, got, tt.exp)
+		}
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	valid := []string{
+		"",
+		"FREQ=DAILY;INTERVAL=1",
+		"FREQ=WEEKLY;INTERVAL=2",
+		"FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=31",
+		"FREQ=YEARLY;INTERVAL=1;BYMONTHDAY=1",
+	}
+
+	for _, value := range valid {
+		rule, err := recurrence.Parse(value)
+		if err != nil {
+			t.Errorf("%q: should parse : %s", value, err)
+			continue
+		}
+
+		if got := rule.String(); got != value {
+			t.Errorf("%q: got %q back", value, got)
+		}
+	}
+
+	invalid := []string{
+		"FREQ=HOURLY;INTERVAL=1",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=3",
+		"INTERVAL=2",
+		"daily",
+	}
+
+	for _, value := range invalid {
+		if _, err := recurrence.Parse(value); err == nil {
+			t.Errorf("%q: should not parse", value)
+		}
+	}
+}
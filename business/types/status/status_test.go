@@ -0,0 +1,31 @@
+package status_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/himynamej/todo/business/types/status"
+)
+
+func Test_JSON(t *testing.T) {
+	for _, sts := range []status.Status{status.Open, status.InProgress, status.Done} {
+		data, err := json.Marshal(sts)
+		if err != nil {
+			t.Fatalf("%s: marshal: %s", sts, err)
+		}
+
+		var got status.Status
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: unmarshal: %s", sts, err)
+		}
+
+		if !got.Equal(sts) {
+			t.Fatalf("got %s, want %s", got, sts)
+		}
+	}
+
+	var got status.Status
+	if err := json.Unmarshal([]byte(`"CLOSED"`), &got); err == nil {
+		t.Fatal("expected an error for an unknown status")
+	}
+}
@@ -0,0 +1,68 @@
+// Package timezone represents an IANA time zone in the system.
+package timezone
+
+import (
+	"fmt"
+	"time"
+
+	// The zone database is embedded so zones resolve even when the host
+	// image does not ship with one.
+	_ "time/tzdata"
+)
+
+// UTC is the zone used when a user has not picked one.
+var UTC = TimeZone{time.UTC}
+
+// TimeZone represents an IANA time zone in the system.
+type TimeZone struct {
+	loc *time.Location
+}
+
+// Parse parses the string value and returns a time zone if the value is a
+// known IANA zone name such as "Asia/Tokyo".
+func Parse(value string) (TimeZone, error) {
+	if value == "" || value == "Local" {
+		return TimeZone{}, fmt.Errorf("invalid time zone %q", value)
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return TimeZone{}, fmt.Errorf("invalid time zone %q", value)
+	}
+
+	return TimeZone{loc}, nil
+}
+
+// MustParse parses the string value and returns a time zone if the value
+// is a known IANA zone name. If an error occurs the function panics.
+func MustParse(value string) TimeZone {
+	tz, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return tz
+}
+
+// Location returns the location for the time zone. The zero value is UTC.
+func (tz TimeZone) Location() *time.Location {
+	if tz.loc == nil {
+		return time.UTC
+	}
+	return tz.loc
+}
+
+// String returns the IANA name of the time zone.
+func (tz TimeZone) String() string {
+	return tz.Location().String()
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (tz TimeZone) Equal(tz2 TimeZone) bool {
+	return tz.String() == tz2.String()
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (tz TimeZone) MarshalText() ([]byte, error) {
+	return []byte(tz.String()), nil
+}
@@ -0,0 +1,40 @@
+package timezone_test
+
+import (
+	"testing"
+
+	"github.com/himynamej/todo/business/types/timezone"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "Asia/Tokyo"},
+		{value: "America/New_York"},
+		{value: "UTC"},
+		{value: "", wantErr: true},
+		{value: "Local", wantErr: true},
+		{value: "Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tz, err := timezone.Parse(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%q: got err %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+
+		if err == nil && tz.String() != tt.value {
+			t.Fatalf("%q: got %q", tt.value, tz.String())
+		}
+	}
+}
+
+func Test_ZeroValue(t *testing.T) {
+	var tz timezone.TimeZone
+
+	if !tz.Equal(timezone.UTC) {
+		t.Fatalf("zero value should be UTC, got %q", tz.String())
+	}
+}
@@ -1,11 +1,22 @@
 // Package keystore implements the auth.KeyLookup interface. This implements
 // an in-memory keystore for JWT support.
+//
+// Each key is pending, active, retiring or retired. Pending keys are
+// published and verify tokens but don't sign yet, active keys sign new
+// tokens, retiring keys only verify the tokens they signed before, and
+// retired keys do neither. The state of the keys in a folder is kept in a
+// keys.json file next to them, keys it does not list are active. A pending
+// key becomes active at its activation time, an active key with a retiring
+// time becomes retiring once that time passes, and a retiring key with a
+// retire time becomes retired once that time passes.
 package keystore
 
 import (
 	"bytes"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
@@ -13,9 +24,65 @@ import (
 	"io"
 	"io/fs"
 	"path"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
+// Set of key states.
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusRetiring = "retiring"
+	StatusRetired  = "retired"
+)
+
+// ManifestFile is the name of the file in the keys folder that records the
+// state of each key.
+const ManifestFile = "keys.json"
+
+// KeyState represents where a key is in its lifecycle.
+type KeyState struct {
+	KID        string     `json:"kid"`
+	Status     string     `json:"status"`
+	Activated  time.Time  `json:"activated"`             // When the key becomes active, a pending key waits for it
+	RetiringAt *time.Time `json:"retiring_at,omitempty"` // When an active key stops signing
+	RetireAt   *time.Time `json:"retire_at,omitempty"`   // When a retiring key becomes retired
+}
+
+// Manifest represents the keys.json file of a keys folder.
+type Manifest struct {
+	Keys []KeyState `json:"keys"`
+}
+
+// ReadManifest reads the keys.json file of the folder. A folder without one
+// has an empty manifest.
+func ReadManifest(fsys fs.FS) (Manifest, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Manifest{}, nil
+		}
+		return Manifest{}, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("decoding manifest: %w", err)
+	}
+
+	for _, st := range m.Keys {
+		switch st.Status {
+		case StatusPending, StatusActive, StatusRetiring, StatusRetired:
+		default:
+			return Manifest{}, fmt.Errorf("kid[%s]: invalid status %q", st.KID, st.Status)
+		}
+	}
+
+	return m, nil
+}
+
 // key represents key information.
 type key struct {
 	privatePEM string
@@ -23,18 +90,35 @@ type key struct {
 }
 
 // KeyStore represents an in memory store implementation of the
-// KeyLookup interface for use with the auth package.
+// KeyLookup interface for use with the auth package. It is safe to
+// reload the keys while it is in use.
 type KeyStore struct {
-	store map[string]key
+	mu          sync.RWMutex
+	static      map[string]key // Loaded from JSON, kept across reloads
+	store       map[string]key // Loaded from the keys folder
+	states      map[string]KeyState
+	fingerprint string
+	defaultKID  string
 }
 
 // New constructs an empty KeyStore ready for use.
 func New() *KeyStore {
 	return &KeyStore{
-		store: make(map[string]key),
+		static: make(map[string]key),
+		store:  make(map[string]key),
+		states: make(map[string]KeyState),
 	}
 }
 
+// SetDefaultKID names the key to sign with when several keys are active
+// and none was activated after the others, as with keys no manifest lists.
+func (ks *KeyStore) SetDefaultKID(kid string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.defaultKID = kid
+}
+
 // LoadByJSON is given a JSON document read with two fields, key and pem
 // (private key).
 func (ks *KeyStore) LoadByJSON(document string) (int, error) {
@@ -47,7 +131,7 @@ func (ks *KeyStore) LoadByJSON(document string) (int, error) {
 		PEM string `json:"pem"`
 	}
 	if err := json.Unmarshal([]byte(document), &d); err != nil {
-		return len(ks.store), fmt.Errorf("unable to marshal document: %w", err)
+		return ks.count(), fmt.Errorf("unable to marshal document: %w", err)
 	}
 
 	publicPEM, err := toPublicPEM(d.PEM)
@@ -60,17 +144,22 @@ func (ks *KeyStore) LoadByJSON(document string) (int, error) {
 		publicPEM:  publicPEM,
 	}
 
-	ks.store[d.Key] = key
+	ks.mu.Lock()
+	ks.static[d.Key] = key
+	ks.mu.Unlock()
 
-	return len(ks.store), nil
+	return ks.count(), nil
 }
 
 // LoadByFileSystem loads a set of RSA PEM files rooted inside of a directory. The
 // name of each PEM file will be used as the key id. The function also returns
-// the total number of keys in the store.
+// the total number of keys in the store. Loading again replaces the keys
+// loaded from the folder before, along with their states.
 // Example: ks.LoadRSAKeys(os.DirFS("/zarf/keys/"))
 // Example: /zarf/keys/54bb2165-71e1-41a6-af3e-7da4a0e1e2c1.pem
 func (ks *KeyStore) LoadByFileSystem(fsys fs.FS) (int, error) {
+	store := make(map[string]key)
+
 	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("walkdir failure: %w", err)
@@ -109,7 +198,7 @@ func (ks *KeyStore) LoadByFileSystem(fsys fs.FS) (int, error) {
 			publicPEM:  publicPEM,
 		}
 
-		ks.store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = key
+		store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = key
 
 		return nil
 	}
@@ -118,29 +207,266 @@ func (ks *KeyStore) LoadByFileSystem(fsys fs.FS) (int, error) {
 		return 0, fmt.Errorf("walking directory: %w", err)
 	}
 
-	return len(ks.store), nil
+	m, err := ReadManifest(fsys)
+	if err != nil {
+		return 0, err
+	}
+
+	states := make(map[string]KeyState, len(m.Keys))
+	for _, st := range m.Keys {
+		states[st.KID] = st
+	}
+
+	fingerprint, err := fingerprint(fsys)
+	if err != nil {
+		return 0, err
+	}
+
+	ks.mu.Lock()
+	ks.store = store
+	ks.states = states
+	ks.fingerprint = fingerprint
+	ks.mu.Unlock()
+
+	return ks.count(), nil
+}
+
+// Reload loads the keys folder again if any key file or the manifest changed
+// since it was last loaded, and reports whether it did.
+func (ks *KeyStore) Reload(fsys fs.FS) (bool, error) {
+	fingerprint, err := fingerprint(fsys)
+	if err != nil {
+		return false, err
+	}
+
+	ks.mu.RLock()
+	same := fingerprint == ks.fingerprint
+	ks.mu.RUnlock()
+
+	if same {
+		return false, nil
+	}
+
+	if _, err := ks.LoadByFileSystem(fsys); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // PrivateKey searches the key store for a given kid and returns the private key.
+// Only active keys sign tokens.
 func (ks *KeyStore) PrivateKey(kid string) (string, error) {
-	key, found := ks.store[kid]
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, found := ks.lookup(kid)
 	if !found {
 		return "", errors.New("kid lookup failed")
 	}
 
+	if status := ks.status(kid, time.Now()); status != StatusActive {
+		return "", fmt.Errorf("kid is %s, only active keys sign tokens", status)
+	}
+
 	return key.privatePEM, nil
 }
 
 // PublicKey searches the key store for a given kid and returns the public key.
+// Retired keys no longer verify tokens.
 func (ks *KeyStore) PublicKey(kid string) (string, error) {
-	key, found := ks.store[kid]
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, found := ks.lookup(kid)
 	if !found {
 		return "", errors.New("kid lookup failed")
 	}
 
+	if ks.status(kid, time.Now()) == StatusRetired {
+		return "", errors.New("kid is retired")
+	}
+
 	return key.publicPEM, nil
 }
 
+// PublicKeys returns the public key of every key that is not retired, by kid.
+func (ks *KeyStore) PublicKeys() map[string]string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+	keys := make(map[string]string)
+
+	for kid, key := range ks.all() {
+		if ks.status(kid, now) != StatusRetired {
+			keys[kid] = key.publicPEM
+		}
+	}
+
+	return keys
+}
+
+// ActiveKID returns the kid new tokens are signed with, the active key that
+// was activated last.
+func (ks *KeyStore) ActiveKID() (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+
+	var newest []string
+	var activated time.Time
+
+	for kid := range ks.all() {
+		if ks.status(kid, now) != StatusActive {
+			continue
+		}
+
+		at := ks.states[kid].Activated
+
+		switch {
+		case len(newest) == 0 || at.After(activated):
+			newest = []string{kid}
+			activated = at
+		case at.Equal(activated):
+			newest = append(newest, kid)
+		}
+	}
+
+	switch {
+	case len(newest) == 0:
+		return "", errors.New("no active key")
+	case len(newest) == 1:
+		return newest[0], nil
+	case slices.Contains(newest, ks.defaultKID):
+		return ks.defaultKID, nil
+	}
+
+	slices.Sort(newest)
+
+	return "", fmt.Errorf("keys %v are all active since %s, mark the one to sign with in %s", newest, activated.Format(time.RFC3339), ManifestFile)
+}
+
+// Manifest returns the state of every key loaded from the keys folder,
+// including the keys the manifest does not list.
+func (ks *KeyStore) Manifest() Manifest {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+
+	var m Manifest
+	for kid := range ks.store {
+		st, exists := ks.states[kid]
+		if !exists {
+			st = KeyState{KID: kid}
+		}
+		st.Status = ks.status(kid, now)
+
+		m.Keys = append(m.Keys, st)
+	}
+
+	slices.SortFunc(m.Keys, func(a, b KeyState) int {
+		if c := a.Activated.Compare(b.Activated); c != 0 {
+			return c
+		}
+		return strings.Compare(a.KID, b.KID)
+	})
+
+	return m
+}
+
+// =============================================================================
+
+// count returns the total number of keys in the store.
+func (ks *KeyStore) count() int {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return len(ks.all())
+}
+
+// all returns every key, the ones from the folder replacing any from JSON
+// with the same kid. The caller must hold the lock.
+func (ks *KeyStore) all() map[string]key {
+	keys := make(map[string]key, len(ks.static)+len(ks.store))
+	for kid, key := range ks.static {
+		keys[kid] = key
+	}
+	for kid, key := range ks.store {
+		keys[kid] = key
+	}
+
+	return keys
+}
+
+// lookup finds the key with the kid. The caller must hold the lock.
+func (ks *KeyStore) lookup(kid string) (key, bool) {
+	if key, found := ks.store[kid]; found {
+		return key, true
+	}
+
+	key, found := ks.static[kid]
+	return key, found
+}
+
+// status returns the state of the key at the given time. The caller must
+// hold the lock.
+func (ks *KeyStore) status(kid string, now time.Time) string {
+	st, exists := ks.states[kid]
+	if !exists {
+		return StatusActive
+	}
+
+	status := st.Status
+
+	if status == StatusPending && !now.Before(st.Activated) {
+		status = StatusActive
+	}
+
+	if status == StatusActive && st.RetiringAt != nil && !now.Before(*st.RetiringAt) {
+		status = StatusRetiring
+	}
+
+	if status == StatusRetiring && st.RetireAt != nil && !now.Before(*st.RetireAt) {
+		status = StatusRetired
+	}
+
+	return status
+}
+
+// fingerprint summarizes the names, sizes and modification times of the key
+// files and the manifest, so changes to the folder can be noticed.
+func fingerprint(fsys fs.FS) (string, error) {
+	h := sha256.New()
+
+	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walkdir failure: %w", err)
+		}
+
+		if dirEntry.IsDir() || (path.Ext(fileName) != ".pem" && fileName != ManifestFile) {
+			return nil
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", fileName, err)
+		}
+
+		fmt.Fprintf(h, "%s %d %d\n", fileName, info.Size(), info.ModTime().UnixNano())
+
+		return nil
+	}
+
+	if err := fs.WalkDir(fsys, ".", fn); err != nil {
+		return "", fmt.Errorf("walking directory: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func toPublicPEM(privatePEM string) (string, error) {
 	block, _ := pem.Decode([]byte(privatePEM))
 	if block == nil {
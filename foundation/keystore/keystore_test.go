@@ -0,0 +1,235 @@
+package keystore_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/himynamej/todo/foundation/keystore"
+)
+
+func Test_Lifecycle(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	fsys := fstest.MapFS{
+		"old.pem":     newKeyFile(t),
+		"current.pem": newKeyFile(t),
+		"new.pem":     newKeyFile(t),
+		keystore.ManifestFile: newManifest(t, keystore.Manifest{
+			Keys: []keystore.KeyState{
+				{KID: "old", Status: keystore.StatusRetiring, RetireAt: &past},
+				{KID: "current", Status: keystore.StatusRetiring, Activated: past, RetireAt: &future},
+				{KID: "new", Status: keystore.StatusActive, Activated: time.Now()},
+			},
+		}),
+	}
+
+	ks := keystore.New()
+
+	n, err := ks.LoadByFileSystem(fsys)
+	if err != nil {
+		t.Fatalf("Should be able to load the keys : %s", err)
+	}
+
+	if n != 3 {
+		t.Errorf("Should load every key file : got %d, exp 3", n)
+	}
+
+	kid, err := ks.ActiveKID()
+	if err != nil {
+		t.Fatalf("Should be able to find the active key : %s", err)
+	}
+
+	if kid != "new" {
+		t.Errorf("Should sign with the active key : got %s, exp new", kid)
+	}
+
+	if _, err := ks.PrivateKey("current"); err == nil {
+		t.Error("Should NOT sign with a retiring key")
+	}
+
+	if _, err := ks.PublicKey("current"); err != nil {
+		t.Errorf("Should verify with a retiring key : %s", err)
+	}
+
+	if _, err := ks.PublicKey("old"); err == nil {
+		t.Error("Should NOT verify with a key past its retire time")
+	}
+
+	keys := ks.PublicKeys()
+
+	if _, exists := keys["old"]; exists || len(keys) != 2 {
+		t.Errorf("Should publish the keys that are not retired : got %d keys", len(keys))
+	}
+}
+
+func Test_Pending(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	fsys := fstest.MapFS{
+		"current.pem":  newKeyFile(t),
+		"next.pem":     newKeyFile(t),
+		"taken.pem":    newKeyFile(t),
+		"replaced.pem": newKeyFile(t),
+		keystore.ManifestFile: newManifest(t, keystore.Manifest{
+			Keys: []keystore.KeyState{
+				{KID: "replaced", Status: keystore.StatusActive, Activated: past.Add(-time.Hour), RetiringAt: &past, RetireAt: &future},
+				{KID: "taken", Status: keystore.StatusPending, Activated: past},
+				{KID: "current", Status: keystore.StatusActive, Activated: past.Add(-time.Hour), RetiringAt: &future},
+				{KID: "next", Status: keystore.StatusPending, Activated: future},
+			},
+		}),
+	}
+
+	ks := keystore.New()
+
+	if _, err := ks.LoadByFileSystem(fsys); err != nil {
+		t.Fatalf("Should be able to load the keys : %s", err)
+	}
+
+	kid, err := ks.ActiveKID()
+	if err != nil {
+		t.Fatalf("Should be able to find the active key : %s", err)
+	}
+
+	if kid != "taken" {
+		t.Errorf("Should sign with the key past its activation time : got %s, exp taken", kid)
+	}
+
+	if _, err := ks.PrivateKey("next"); err == nil {
+		t.Error("Should NOT sign with a pending key")
+	}
+
+	if _, err := ks.PublicKey("next"); err != nil {
+		t.Errorf("Should verify with a pending key : %s", err)
+	}
+
+	if _, err := ks.PrivateKey("current"); err != nil {
+		t.Errorf("Should sign with an active key before its retiring time : %s", err)
+	}
+
+	if _, err := ks.PrivateKey("replaced"); err == nil {
+		t.Error("Should NOT sign with a key past its retiring time")
+	}
+
+	if _, err := ks.PublicKey("replaced"); err != nil {
+		t.Errorf("Should verify with a key past its retiring time : %s", err)
+	}
+
+	if keys := ks.PublicKeys(); len(keys) != 4 {
+		t.Errorf("Should publish the pending key : got %d keys, exp 4", len(keys))
+	}
+}
+
+func Test_Unlisted(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.pem": newKeyFile(t),
+		"b.pem": newKeyFile(t),
+	}
+
+	ks := keystore.New()
+
+	if _, err := ks.LoadByFileSystem(fsys); err != nil {
+		t.Fatalf("Should be able to load the keys : %s", err)
+	}
+
+	if _, err := ks.ActiveKID(); err == nil {
+		t.Error("Should NOT pick between several keys activated at the same time")
+	}
+
+	ks.SetDefaultKID("b")
+
+	kid, err := ks.ActiveKID()
+	if err != nil {
+		t.Fatalf("Should be able to find the active key : %s", err)
+	}
+
+	if kid != "b" {
+		t.Errorf("Should sign with the default key : got %s, exp b", kid)
+	}
+}
+
+func Test_Reload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.pem": newKeyFile(t),
+	}
+
+	ks := keystore.New()
+
+	if _, err := ks.LoadByFileSystem(fsys); err != nil {
+		t.Fatalf("Should be able to load the keys : %s", err)
+	}
+
+	reloaded, err := ks.Reload(fsys)
+	if err != nil {
+		t.Fatalf("Should be able to reload the keys : %s", err)
+	}
+
+	if reloaded {
+		t.Error("Should NOT reload an unchanged folder")
+	}
+
+	now := time.Now()
+
+	fsys["b.pem"] = newKeyFile(t)
+	fsys[keystore.ManifestFile] = newManifest(t, keystore.Manifest{
+		Keys: []keystore.KeyState{
+			{KID: "a", Status: keystore.StatusRetiring, RetireAt: &now},
+			{KID: "b", Status: keystore.StatusActive, Activated: now},
+		},
+	})
+
+	reloaded, err = ks.Reload(fsys)
+	if err != nil {
+		t.Fatalf("Should be able to reload the keys : %s", err)
+	}
+
+	if !reloaded {
+		t.Fatal("Should reload a changed folder")
+	}
+
+	kid, err := ks.ActiveKID()
+	if err != nil {
+		t.Fatalf("Should be able to find the active key : %s", err)
+	}
+
+	if kid != "b" {
+		t.Errorf("Should sign with the new key : got %s, exp b", kid)
+	}
+}
+
+// =============================================================================
+
+func newKeyFile(t *testing.T) *fstest.MapFile {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Should be able to generate a key : %s", err)
+	}
+
+	block := pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}
+
+	return &fstest.MapFile{Data: pem.EncodeToMemory(&block), ModTime: time.Now()}
+}
+
+func newManifest(t *testing.T, m keystore.Manifest) *fstest.MapFile {
+	t.Helper()
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Should be able to encode the manifest : %s", err)
+	}
+
+	return &fstest.MapFile{Data: data, ModTime: time.Now()}
+}
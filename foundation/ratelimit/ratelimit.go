@@ -0,0 +1,74 @@
+// Package ratelimit limits how often something can be done per key, such as
+// per client address.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows a number of events per key in each window of time. Windows
+// are fixed, starting with the first event of a key, so a key can get up to
+// twice the limit across the boundary of two windows.
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu    sync.Mutex
+	keys  map[string]*entry
+	swept time.Time
+}
+
+type entry struct {
+	start time.Time
+	count int
+}
+
+// New constructs a limiter that allows limit events per key each window.
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		window: window,
+		keys:   make(map[string]*entry),
+	}
+}
+
+// Allow records an event for the key and reports whether it is within the
+// limit. Events that are not allowed are not counted.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	e, ok := l.keys[key]
+	if !ok || now.Sub(e.start) >= l.window {
+		e = &entry{start: now}
+		l.keys[key] = e
+	}
+
+	if e.count >= l.limit {
+		return false
+	}
+
+	e.count++
+
+	return true
+}
+
+// sweep drops the keys whose window has ended, at most once a window, so
+// the limiter does not grow with every key it has ever seen.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < l.window {
+		return
+	}
+
+	for key, e := range l.keys {
+		if now.Sub(e.start) >= l.window {
+			delete(l.keys, key)
+		}
+	}
+
+	l.swept = now
+}
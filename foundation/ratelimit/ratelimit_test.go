@@ -0,0 +1,34 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/foundation/ratelimit"
+)
+
+func Test_Limiter(t *testing.T) {
+	const window = 50 * time.Millisecond
+
+	l := ratelimit.New(2, window)
+
+	for i := range 2 {
+		if !l.Allow("a") {
+			t.Fatalf("Should allow event %d within the limit", i)
+		}
+	}
+
+	if l.Allow("a") {
+		t.Fatal("Should NOT allow an event over the limit")
+	}
+
+	if !l.Allow("b") {
+		t.Fatal("Should allow an event for another key")
+	}
+
+	time.Sleep(window)
+
+	if !l.Allow("a") {
+		t.Fatal("Should allow an event once the window has ended")
+	}
+}
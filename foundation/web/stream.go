@@ -0,0 +1,144 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// streamWriteTimeout bounds every write to a stream. The server WriteTimeout
+// covers the whole response, which a stream outlives, so each write gets a
+// deadline of its own instead. A client that cannot take a write in this time
+// is too slow to keep up and the stream fails.
+const streamWriteTimeout = 10 * time.Second
+
+// StreamFunc represents a function that handles a http request by streaming
+// server-sent events to the client for as long as the request lasts. Once the
+// stream has started, anything returned is only logged.
+type StreamFunc func(ctx context.Context, r *http.Request, stream *Stream) Encoder
+
+// Stream writes server-sent events to a client.
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+type Stream struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+}
+
+func newStream(w http.ResponseWriter) *Stream {
+	return &Stream{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+}
+
+// Start sends the response headers. It is called by the first Send or
+// Heartbeat, so handlers only need it to commit to streaming before there is
+// anything to send.
+func (s *Stream) Start() error {
+	if s.started {
+		return nil
+	}
+
+	// The server deadlines are set when the request is read and would end
+	// the stream once they pass. The read deadline is cleared for good and
+	// the write deadline is moved forward with every write.
+	if err := s.rc.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("stream: clear read deadline: %w", err)
+	}
+
+	if err := s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return fmt.Errorf("stream: set write deadline: %w", err)
+	}
+
+	h := s.w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	s.w.WriteHeader(http.StatusOK)
+
+	s.started = true
+
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("stream: flush: %w", err)
+	}
+
+	return nil
+}
+
+// Started reports whether the response headers have been sent.
+func (s *Stream) Started() bool {
+	return s.started
+}
+
+// Send writes an event to the client. The id is what the client sends back in
+// the Last-Event-ID header when it reconnects, and may be empty. Each line of
+// data is sent as a line of its own.
+func (s *Stream) Send(id string, event string, data []byte) error {
+	var b bytes.Buffer
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", id)
+	}
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fmt.Fprintf(&b, "data: %s\n", bytes.TrimSuffix(line, []byte("\r")))
+	}
+	b.WriteString("\n")
+
+	return s.write(b.Bytes())
+}
+
+// Heartbeat writes a comment the client ignores. It keeps proxies from
+// closing an idle stream and finds clients that have gone away.
+func (s *Stream) Heartbeat() error {
+	return s.write([]byte(": heartbeat\n\n"))
+}
+
+func (s *Stream) write(p []byte) error {
+	if err := s.Start(); err != nil {
+		return err
+	}
+
+	if err := s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return fmt.Errorf("stream: set write deadline: %w", err)
+	}
+
+	if _, err := s.w.Write(p); err != nil {
+		return fmt.Errorf("stream: write: %w", err)
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("stream: flush: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// StreamHandlerFunc sets a handler function that streams server-sent events
+// for a given HTTP method and path pair to the application server mux. The
+// middleware runs as it does for HandlerFunc, and whatever the handler returns
+// before the stream starts is the response.
+func (a *App) StreamHandlerFunc(method string, group string, path string, streamFunc StreamFunc, mw ...MidFunc) {
+	handlerFunc := func(ctx context.Context, r *http.Request) Encoder {
+		stream := newStream(GetWriter(ctx))
+
+		resp := streamFunc(ctx, r, stream)
+		if !stream.Started() {
+			return resp
+		}
+
+		if err, ok := resp.(error); ok {
+			a.log(ctx, "web-stream", "ERROR", err)
+		}
+
+		return NoResponse{}
+	}
+
+	a.HandlerFunc(method, group, path, handlerFunc, mw...)
+}
@@ -0,0 +1,70 @@
+package web_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/himynamej/todo/foundation/web"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func Test_Stream(t *testing.T) {
+	log := func(ctx context.Context, msg string, args ...any) {
+		t.Log(append([]any{msg}, args...)...)
+	}
+
+	app := web.NewApp(log, noop.NewTracerProvider().Tracer(""))
+
+	// The stream outlives the server timeouts so it only works when each
+	// write is given a deadline of its own.
+	handler := func(ctx context.Context, r *http.Request, stream *web.Stream) web.Encoder {
+		for i, data := range []string{`{"n":1}`, "line one\nline two"} {
+			time.Sleep(150 * time.Millisecond)
+
+			if err := stream.Send(string(rune('1'+i)), "change", []byte(data)); err != nil {
+				return web.NewNoResponse()
+			}
+		}
+
+		time.Sleep(150 * time.Millisecond)
+		stream.Heartbeat()
+
+		return nil
+	}
+
+	app.StreamHandlerFunc(http.MethodGet, "v1", "/stream", handler)
+
+	srv := httptest.NewUnstartedServer(app)
+	srv.Config.ReadTimeout = 100 * time.Millisecond
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/stream")
+	if err != nil {
+		t.Fatalf("Should be able to connect : %s", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Got content type %q, exp text/event-stream", ct)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Should be able to read the whole stream : %s", err)
+	}
+
+	exp := "id: 1\nevent: change\ndata: {\"n\":1}\n\n" +
+		"id: 2\nevent: change\ndata: line one\ndata: line two\n\n" +
+		": heartbeat\n\n"
+
+	if string(body) != exp {
+		t.Errorf("Got: %q", body)
+		t.Errorf("Exp: %q", exp)
+	}
+}